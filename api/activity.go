@@ -58,6 +58,15 @@ const (
 
 	// ActivitySQLEditorQuery is the type for executing query.
 	ActivitySQLEditorQuery ActivityType = "bb.sql-editor.query"
+	// ActivitySQLEditorExport is the type for exporting query result.
+	ActivitySQLEditorExport ActivityType = "bb.sql-editor.export"
+
+	// Anomaly related
+
+	// ActivityAnomalyOpen is the type for opening anomalies.
+	ActivityAnomalyOpen ActivityType = "bb.anomaly.open"
+	// ActivityAnomalyResolve is the type for resolving anomalies.
+	ActivityAnomalyResolve ActivityType = "bb.anomaly.resolve"
 )
 
 func (e ActivityType) String() string {
@@ -96,6 +105,12 @@ func (e ActivityType) String() string {
 		return "bb.project.member.role.update"
 	case ActivitySQLEditorQuery:
 		return "bb.sql-editor.query"
+	case ActivitySQLEditorExport:
+		return "bb.sql-editor.export"
+	case ActivityAnomalyOpen:
+		return "bb.anomaly.open"
+	case ActivityAnomalyResolve:
+		return "bb.anomaly.resolve"
 	}
 	return "bb.activity.unknown"
 }
@@ -249,6 +264,33 @@ type ActivitySQLEditorQueryPayload struct {
 	Error        string `json:"error"`
 }
 
+// ActivitySQLEditorExportPayload is the API message payloads for the exported query result info.
+type ActivitySQLEditorExportPayload struct {
+	// Used by activity table to display info without paying the join cost
+	Statement    string `json:"statement"`
+	DurationNs   int64  `json:"durationNs"`
+	InstanceName string `json:"instanceName"`
+	DatabaseName string `json:"databaseName"`
+	Format       string `json:"format"`
+	// The row count limit enforced on the export
+	Limit    int    `json:"limit"`
+	RowCount int    `json:"rowCount"`
+	Error    string `json:"error"`
+}
+
+// ActivityAnomalyPayload is the API message payloads for opening or resolving anomalies.
+type ActivityAnomalyPayload struct {
+	AnomalyID   int             `json:"anomalyId"`
+	AnomalyType AnomalyType     `json:"anomalyType"`
+	Severity    AnomalySeverity `json:"severity"`
+	// Used by activity table to display info without paying the join cost
+	InstanceID   int    `json:"instanceId"`
+	InstanceName string `json:"instanceName"`
+	// DatabaseID/DatabaseName only exist for the database anomalies.
+	DatabaseID   int    `json:"databaseId,omitempty"`
+	DatabaseName string `json:"databaseName,omitempty"`
+}
+
 // ActivityRaw is the store model for an Activity.
 // Fields have exactly the same meanings as Activity.
 type ActivityRaw struct {
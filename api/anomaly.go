@@ -13,14 +13,34 @@ const (
 	AnomalyInstanceConnection AnomalyType = "bb.anomaly.instance.connection"
 	// AnomalyInstanceMigrationSchema is the anomaly type for schema migrations.
 	AnomalyInstanceMigrationSchema AnomalyType = "bb.anomaly.instance.migration-schema"
+	// AnomalyInstanceReplicationLag is the anomaly type for replicas lagging behind or not replicating.
+	AnomalyInstanceReplicationLag AnomalyType = "bb.anomaly.instance.replication-lag"
+	// AnomalyInstanceConnectionUsage is the anomaly type for connections approaching max_connections.
+	AnomalyInstanceConnectionUsage AnomalyType = "bb.anomaly.instance.connection-usage"
+	// AnomalyInstanceLongRunningTransaction is the anomaly type for long-running transactions.
+	AnomalyInstanceLongRunningTransaction AnomalyType = "bb.anomaly.instance.long-running-transaction"
+	// AnomalyInstanceStorageGrowth is the anomaly type for fast growing databases and tablespaces.
+	AnomalyInstanceStorageGrowth AnomalyType = "bb.anomaly.instance.storage-growth"
 	// AnomalyDatabaseBackupPolicyViolation is the anomaly type for backup policy violations.
 	AnomalyDatabaseBackupPolicyViolation AnomalyType = "bb.anomaly.database.backup.policy-violation"
 	// AnomalyDatabaseBackupMissing is the anomaly type for missing backups.
 	AnomalyDatabaseBackupMissing AnomalyType = "bb.anomaly.database.backup.missing"
+	// AnomalyDatabaseBackupCleanupFailure is the anomaly type for backup retention cleanup failures.
+	AnomalyDatabaseBackupCleanupFailure AnomalyType = "bb.anomaly.database.backup.cleanup-failure"
+	// AnomalyDatabaseBackupVerificationFailure is the anomaly type for backup verification failures.
+	AnomalyDatabaseBackupVerificationFailure AnomalyType = "bb.anomaly.database.backup.verification-failure"
 	// AnomalyDatabaseConnection is the anomaly type for database connections.
 	AnomalyDatabaseConnection AnomalyType = "bb.anomaly.database.connection"
 	// AnomalyDatabaseSchemaDrift is the anomaly type for database schema drifts.
 	AnomalyDatabaseSchemaDrift AnomalyType = "bb.anomaly.database.schema.drift"
+	// AnomalyDatabaseIndexRedundant is the anomaly type for redundant indexes covered by another index.
+	AnomalyDatabaseIndexRedundant AnomalyType = "bb.anomaly.database.index.redundant"
+	// AnomalyDatabaseIndexUnused is the anomaly type for indexes never used since the engine statistics were reset.
+	AnomalyDatabaseIndexUnused AnomalyType = "bb.anomaly.database.index.unused"
+	// AnomalyDatabaseTableNoPrimaryKey is the anomaly type for tables without a primary key.
+	AnomalyDatabaseTableNoPrimaryKey AnomalyType = "bb.anomaly.database.table.no-primary-key"
+	// AnomalyDatabaseSlowQueryRegression is the anomaly type for statements slowing down after a schema migration.
+	AnomalyDatabaseSlowQueryRegression AnomalyType = "bb.anomaly.database.slow-query.regression"
 )
 
 // AnomalySeverity is the severity of anomaly.
@@ -35,6 +55,21 @@ const (
 	AnomalySeverityCritical AnomalySeverity = "CRITICAL"
 )
 
+// AnomalyStatus is the status of anomaly.
+type AnomalyStatus string
+
+const (
+	// AnomalyOpen is the status of an anomaly just detected.
+	AnomalyOpen AnomalyStatus = "OPEN"
+	// AnomalyAcknowledged is the status of an anomaly acknowledged by a user.
+	AnomalyAcknowledged AnomalyStatus = "ACKNOWLEDGED"
+	// AnomalySnoozed is the status of an unresolved anomaly snoozed until a given time.
+	// It's calculated from the snooze time rather than stored, and falls back once the snooze expires.
+	AnomalySnoozed AnomalyStatus = "SNOOZED"
+	// AnomalyResolved is the status of an anomaly no longer detected, or resolved by a user.
+	AnomalyResolved AnomalyStatus = "RESOLVED"
+)
+
 // AnomalySeverityFromType maps the severity from a anomaly type.
 func AnomalySeverityFromType(anomalyType AnomalyType) AnomalySeverity {
 	switch anomalyType {
@@ -42,6 +77,26 @@ func AnomalySeverityFromType(anomalyType AnomalyType) AnomalySeverity {
 		return AnomalySeverityMedium
 	case AnomalyDatabaseBackupMissing:
 		return AnomalySeverityHigh
+	case AnomalyDatabaseBackupCleanupFailure:
+		return AnomalySeverityMedium
+	case AnomalyDatabaseBackupVerificationFailure:
+		return AnomalySeverityHigh
+	case AnomalyInstanceReplicationLag:
+		return AnomalySeverityHigh
+	case AnomalyInstanceConnectionUsage:
+		return AnomalySeverityHigh
+	case AnomalyInstanceLongRunningTransaction:
+		return AnomalySeverityMedium
+	case AnomalyInstanceStorageGrowth:
+		return AnomalySeverityMedium
+	case AnomalyDatabaseIndexRedundant:
+		return AnomalySeverityMedium
+	case AnomalyDatabaseIndexUnused:
+		return AnomalySeverityMedium
+	case AnomalyDatabaseTableNoPrimaryKey:
+		return AnomalySeverityMedium
+	case AnomalyDatabaseSlowQueryRegression:
+		return AnomalySeverityMedium
 	case AnomalyInstanceConnection:
 	case AnomalyInstanceMigrationSchema:
 	case AnomalyDatabaseConnection:
@@ -57,6 +112,61 @@ type AnomalyInstanceConnectionPayload struct {
 	Detail string `json:"detail,omitempty"`
 }
 
+// AnomalyInstanceReplicationLagPayload is the API message for replication lag payloads.
+type AnomalyInstanceReplicationLagPayload struct {
+	ThresholdSeconds int                   `json:"thresholdSeconds,omitempty"`
+	ItemList         []*AnomalyReplicaItem `json:"itemList,omitempty"`
+}
+
+// AnomalyReplicaItem is the API message for a replica lagging behind or not replicating.
+type AnomalyReplicaItem struct {
+	Replica    string `json:"replica"`
+	Running    bool   `json:"running"`
+	LagSeconds int64  `json:"lagSeconds,omitempty"`
+	// Replication error detail
+	Detail string `json:"detail,omitempty"`
+}
+
+// AnomalyInstanceConnectionUsagePayload is the API message for connection usage payloads.
+type AnomalyInstanceConnectionUsagePayload struct {
+	ThresholdPercent int `json:"thresholdPercent,omitempty"`
+	Connections      int `json:"connections,omitempty"`
+	MaxConnections   int `json:"maxConnections,omitempty"`
+}
+
+// AnomalyInstanceLongRunningTransactionPayload is the API message for long-running transaction payloads.
+type AnomalyInstanceLongRunningTransactionPayload struct {
+	ThresholdSeconds int                       `json:"thresholdSeconds,omitempty"`
+	ItemList         []*AnomalyTransactionItem `json:"itemList,omitempty"`
+}
+
+// AnomalyTransactionItem is the API message for a long-running transaction.
+type AnomalyTransactionItem struct {
+	ProcessID       string `json:"processId"`
+	Database        string `json:"database,omitempty"`
+	User            string `json:"user,omitempty"`
+	DurationSeconds int64  `json:"durationSeconds"`
+	// The statement currently executed by the transaction, truncated if too long
+	Query string `json:"query,omitempty"`
+}
+
+// AnomalyInstanceStorageGrowthPayload is the API message for storage growth payloads.
+type AnomalyInstanceStorageGrowthPayload struct {
+	ThresholdPercent int                   `json:"thresholdPercent,omitempty"`
+	ItemList         []*AnomalyStorageItem `json:"itemList,omitempty"`
+}
+
+// AnomalyStorageItem is the API message for a fast growing database or tablespace.
+type AnomalyStorageItem struct {
+	// DATABASE or TABLESPACE
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"sizeBytes"`
+	// The size at the beginning of the growth window
+	PreviousSizeBytes int64 `json:"previousSizeBytes"`
+	PreviousTs        int64 `json:"previousTs"`
+}
+
 // AnomalyDatabaseBackupPolicyViolationPayload is the API message for backup policy violation payloads.
 type AnomalyDatabaseBackupPolicyViolationPayload struct {
 	EnvironmentID          int                      `json:"environmentId,omitempty"`
@@ -71,6 +181,24 @@ type AnomalyDatabaseBackupMissingPayload struct {
 	LastBackupTs int64 `json:"lastBackupTs,omitempty"`
 }
 
+// AnomalyDatabaseBackupCleanupFailurePayload is the API message for backup cleanup failure payloads.
+type AnomalyDatabaseBackupCleanupFailurePayload struct {
+	// The backup failed to be cleaned up
+	BackupID   int    `json:"backupId,omitempty"`
+	BackupName string `json:"backupName,omitempty"`
+	// Cleanup failure detail
+	Detail string `json:"detail,omitempty"`
+}
+
+// AnomalyDatabaseBackupVerificationFailurePayload is the API message for backup verification failure payloads.
+type AnomalyDatabaseBackupVerificationFailurePayload struct {
+	// The backup failed the verification
+	BackupID   int    `json:"backupId,omitempty"`
+	BackupName string `json:"backupName,omitempty"`
+	// Verification failure detail
+	Detail string `json:"detail,omitempty"`
+}
+
 // AnomalyDatabaseConnectionPayload is the API message for database connection payloads.
 type AnomalyDatabaseConnectionPayload struct {
 	// Connection failure detail
@@ -81,10 +209,146 @@ type AnomalyDatabaseConnectionPayload struct {
 type AnomalyDatabaseSchemaDriftPayload struct {
 	// The schema version corresponds to the expected schema
 	Version string `json:"version,omitempty"`
-	// The expected latest schema stored in the migration history table
+	// The expected latest schema stored in the migration history table.
+	// It's not stored in the anomaly, but fetched on demand by GET /anomaly/:anomalyID/schema-drift.
 	Expect string `json:"expect,omitempty"`
-	// The actual schema dumped from the database
+	// The actual schema dumped from the database.
+	// It's not stored in the anomaly, but dumped on demand by GET /anomaly/:anomalyID/schema-drift.
 	Actual string `json:"actual,omitempty"`
+	// The structured changes from the expected schema to the actual schema
+	ItemList []*SchemaDriftItem `json:"itemList,omitempty"`
+	// The unified text diff from the expected schema to the actual schema
+	Diff string `json:"diff,omitempty"`
+}
+
+// AnomalyDatabaseIndexRedundantPayload is the API message for redundant index payloads.
+type AnomalyDatabaseIndexRedundantPayload struct {
+	ItemList []*AnomalyIndexItem `json:"itemList,omitempty"`
+	// The suggested statement dropping all the redundant indexes
+	Statement string `json:"statement,omitempty"`
+}
+
+// AnomalyDatabaseIndexUnusedPayload is the API message for unused index payloads.
+type AnomalyDatabaseIndexUnusedPayload struct {
+	ItemList []*AnomalyIndexItem `json:"itemList,omitempty"`
+	// Time since when the index usage is collected by the engine
+	StatsSinceTs int64 `json:"statsSinceTs,omitempty"`
+	// The suggested statement dropping all the unused indexes
+	Statement string `json:"statement,omitempty"`
+}
+
+// AnomalyIndexItem is the API message for an index flagged by the index anomalies.
+type AnomalyIndexItem struct {
+	Table      string   `json:"table"`
+	Index      string   `json:"index"`
+	ColumnList []string `json:"columnList,omitempty"`
+	// The index whose leading columns cover the redundant index, only for redundant indexes
+	CoveringIndex string `json:"coveringIndex,omitempty"`
+	// The suggested statement dropping the index
+	Statement string `json:"statement,omitempty"`
+}
+
+// AnomalyDatabaseTableNoPrimaryKeyPayload is the API message for payloads of tables without a primary key.
+type AnomalyDatabaseTableNoPrimaryKeyPayload struct {
+	ItemList []*AnomalyTableItem `json:"itemList,omitempty"`
+	// The suggested statement adding the primary keys for all the tables
+	Statement string `json:"statement,omitempty"`
+}
+
+// AnomalyTableItem is the API message for a table flagged by the table anomalies.
+type AnomalyTableItem struct {
+	Table string `json:"table"`
+	// The suggested statement adding the primary key
+	Statement string `json:"statement,omitempty"`
+}
+
+// AnomalyDatabaseSlowQueryRegressionPayload is the API message for slow query regression payloads.
+type AnomalyDatabaseSlowQueryRegressionPayload struct {
+	// The schema version the statements slow down in
+	Version string `json:"version,omitempty"`
+	// The schema version the statements are compared against
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	// The minimum increase percentage of the mean latency to be flagged
+	ThresholdPercent int                     `json:"thresholdPercent,omitempty"`
+	ItemList         []*AnomalySlowQueryItem `json:"itemList,omitempty"`
+}
+
+// AnomalySlowQueryItem is the API message for a statement whose mean latency regresses.
+type AnomalySlowQueryItem struct {
+	Digest    string `json:"digest"`
+	Statement string `json:"statement"`
+	// The mean latency and the calls in the current schema version
+	MeanLatencyUs int64 `json:"meanLatencyUs"`
+	Calls         int64 `json:"calls"`
+	// The mean latency and the calls in the previous schema version
+	PreviousMeanLatencyUs int64 `json:"previousMeanLatencyUs"`
+	PreviousCalls         int64 `json:"previousCalls"`
+}
+
+// SchemaDriftObjectType is the type of a schema object in the schema drift.
+type SchemaDriftObjectType string
+
+const (
+	// SchemaDriftObjectTable is the schema drift object type for tables.
+	SchemaDriftObjectTable SchemaDriftObjectType = "TABLE"
+	// SchemaDriftObjectColumn is the schema drift object type for columns.
+	SchemaDriftObjectColumn SchemaDriftObjectType = "COLUMN"
+	// SchemaDriftObjectIndex is the schema drift object type for indexes and key constraints.
+	SchemaDriftObjectIndex SchemaDriftObjectType = "INDEX"
+	// SchemaDriftObjectView is the schema drift object type for views.
+	SchemaDriftObjectView SchemaDriftObjectType = "VIEW"
+	// SchemaDriftObjectFunction is the schema drift object type for functions.
+	SchemaDriftObjectFunction SchemaDriftObjectType = "FUNCTION"
+	// SchemaDriftObjectProcedure is the schema drift object type for procedures.
+	SchemaDriftObjectProcedure SchemaDriftObjectType = "PROCEDURE"
+	// SchemaDriftObjectTrigger is the schema drift object type for triggers.
+	SchemaDriftObjectTrigger SchemaDriftObjectType = "TRIGGER"
+	// SchemaDriftObjectEvent is the schema drift object type for events.
+	SchemaDriftObjectEvent SchemaDriftObjectType = "EVENT"
+	// SchemaDriftObjectSequence is the schema drift object type for sequences.
+	SchemaDriftObjectSequence SchemaDriftObjectType = "SEQUENCE"
+)
+
+// SchemaDriftAction is the action of a schema object change in the schema drift.
+type SchemaDriftAction string
+
+const (
+	// SchemaDriftActionAdded means the object exists in the actual schema but not the expected one.
+	SchemaDriftActionAdded SchemaDriftAction = "ADDED"
+	// SchemaDriftActionRemoved means the object exists in the expected schema but not the actual one.
+	SchemaDriftActionRemoved SchemaDriftAction = "REMOVED"
+	// SchemaDriftActionChanged means the object exists in both schemas with different definitions.
+	SchemaDriftActionChanged SchemaDriftAction = "CHANGED"
+)
+
+// SchemaDriftItem is the API message for a schema object change in the schema drift.
+type SchemaDriftItem struct {
+	Type   SchemaDriftObjectType `json:"type"`
+	Action SchemaDriftAction     `json:"action"`
+	// The table containing the column or index, empty for tables and views
+	Table string `json:"table,omitempty"`
+	Name  string `json:"name"`
+	// The definition in the expected schema, empty if added
+	Expect string `json:"expect,omitempty"`
+	// The definition in the actual schema, empty if removed
+	Actual string `json:"actual,omitempty"`
+}
+
+// SchemaDriftResolveAction is the action to resolve a schema drift anomaly.
+type SchemaDriftResolveAction string
+
+const (
+	// SchemaDriftResolveBaseline accepts the drift by establishing a new baseline from the actual schema.
+	SchemaDriftResolveBaseline SchemaDriftResolveAction = "BASELINE"
+	// SchemaDriftResolveRevert reverts the drift by applying a schema update back to the expected schema.
+	SchemaDriftResolveRevert SchemaDriftResolveAction = "REVERT"
+)
+
+// AnomalySchemaDriftResolve is the API message for creating an issue to resolve a schema drift, index or table anomaly.
+// The action is only required for the schema drift anomaly.
+type AnomalySchemaDriftResolve struct {
+	Action     SchemaDriftResolveAction `jsonapi:"attr,action"`
+	AssigneeID int                      `jsonapi:"attr,assigneeId"`
 }
 
 // AnomalyRaw is the store model for an Anomaly.
@@ -107,6 +371,16 @@ type AnomalyRaw struct {
 	// Calculated field derived from type
 	Severity AnomalySeverity
 	Payload  string
+	// Calculated field derived from the stored status and the snooze time
+	Status AnomalyStatus
+	// AcknowledgerID is nil if the anomaly has never been acknowledged
+	AcknowledgerID *int
+	AcknowledgedTs int64
+	Comment        string
+	// SnoozeUntilTs is 0 if the anomaly has never been snoozed
+	SnoozeUntilTs int64
+	// ResolvedTs is 0 if the anomaly is unresolved
+	ResolvedTs int64
 }
 
 // ToAnomaly creates an instance of Anomaly based on the AnomalyRaw.
@@ -128,8 +402,14 @@ func (raw *AnomalyRaw) ToAnomaly() *Anomaly {
 		// Domain specific fields
 		Type: raw.Type,
 		// Calculated field derived from type
-		Severity: raw.Severity,
-		Payload:  raw.Payload,
+		Severity:       raw.Severity,
+		Payload:        raw.Payload,
+		Status:         raw.Status,
+		AcknowledgerID: raw.AcknowledgerID,
+		AcknowledgedTs: raw.AcknowledgedTs,
+		Comment:        raw.Comment,
+		SnoozeUntilTs:  raw.SnoozeUntilTs,
+		ResolvedTs:     raw.ResolvedTs,
 	}
 }
 
@@ -155,6 +435,14 @@ type Anomaly struct {
 	// Calculated field derived from type
 	Severity AnomalySeverity `jsonapi:"attr,severity"`
 	Payload  string          `jsonapi:"attr,payload"`
+	// Calculated field derived from the stored status and the snooze time
+	Status         AnomalyStatus `jsonapi:"attr,status"`
+	AcknowledgerID *int
+	Acknowledger   *Principal `jsonapi:"relation,acknowledger"`
+	AcknowledgedTs int64      `jsonapi:"attr,acknowledgedTs"`
+	Comment        string     `jsonapi:"attr,comment"`
+	SnoozeUntilTs  int64      `jsonapi:"attr,snoozeUntilTs"`
+	ResolvedTs     int64      `jsonapi:"attr,resolvedTs"`
 }
 
 // AnomalyUpsert is the API message for creating an anomaly.
@@ -173,6 +461,8 @@ type AnomalyUpsert struct {
 
 // AnomalyFind is the API message for finding anomalies.
 type AnomalyFind struct {
+	ID *int
+
 	// Standard fields
 	RowStatus *RowStatus
 
@@ -182,6 +472,10 @@ type AnomalyFind struct {
 	Type       *AnomalyType
 	// Only applicable if InstanceID is specified, if true, then we only return instance anomaly (database_id is NULL)
 	InstanceOnly bool
+	// If true, then we exclude the anomalies snoozed at the moment
+	ExcludeSnoozed bool
+	// Return the most recent anomalies first if specified
+	Limit *int
 }
 
 func (find *AnomalyFind) String() string {
@@ -199,10 +493,38 @@ type AnomalyArchive struct {
 	Type       AnomalyType
 }
 
+// AnomalyStatusPatch is the API message for changing the status of an unresolved anomaly.
+// Changing to OPEN clears the acknowledgment and the snooze, changing to ACKNOWLEDGED clears the snooze,
+// and changing to SNOOZED requires SnoozeUntilTs.
+type AnomalyStatusPatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Status        AnomalyStatus `jsonapi:"attr,status"`
+	Comment       *string       `jsonapi:"attr,comment"`
+	SnoozeUntilTs int64         `jsonapi:"attr,snoozeUntilTs"`
+}
+
+// AnomalySummary is the API message for the anomaly history summary of an instance or a database.
+type AnomalySummary struct {
+	OpenCount     int `jsonapi:"attr,openCount"`
+	ResolvedCount int `jsonapi:"attr,resolvedCount"`
+	// MeanTimeToResolveSeconds is the mean duration from detected to resolved of the resolved anomalies,
+	// 0 if there is no resolved anomaly.
+	MeanTimeToResolveSeconds int64 `jsonapi:"attr,meanTimeToResolveSeconds"`
+}
+
 // AnomalyService is the service for anomaly.
 type AnomalyService interface {
 	// UpsertActiveAnomaly would update the existing active anomaly if both database id and type match, otherwise create a new one.
 	UpsertActiveAnomaly(ctx context.Context, upsert *AnomalyUpsert) (*AnomalyRaw, error)
 	FindAnomalyList(ctx context.Context, find *AnomalyFind) ([]*AnomalyRaw, error)
+	// PatchAnomalyStatus changes the status of an unresolved anomaly, and archives the anomaly once RESOLVED.
+	PatchAnomalyStatus(ctx context.Context, patch *AnomalyStatusPatch) (*AnomalyRaw, error)
+	// ArchiveAnomaly archives the unresolved anomaly as RESOLVED.
 	ArchiveAnomaly(ctx context.Context, archive *AnomalyArchive) error
 }
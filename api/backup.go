@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 )
 
 // BackupStatus is the status of a backup.
@@ -16,6 +19,9 @@ const (
 	BackupStatusDone BackupStatus = "DONE"
 	// BackupStatusFailed is the status for FAILED.
 	BackupStatusFailed BackupStatus = "FAILED"
+	// BackupStatusExpired is the status for EXPIRED.
+	// The backup file has been removed by the retention policy of the backup plan.
+	BackupStatusExpired BackupStatus = "EXPIRED"
 )
 
 func (e BackupStatus) String() string {
@@ -26,6 +32,8 @@ func (e BackupStatus) String() string {
 		return "DONE"
 	case BackupStatusFailed:
 		return "FAILED"
+	case BackupStatusExpired:
+		return "EXPIRED"
 	}
 	return "UNKNOWN"
 }
@@ -50,13 +58,57 @@ func (e BackupType) String() string {
 	return "UNKNOWN"
 }
 
+// BackupVerificationStatus is the verification status of a backup.
+type BackupVerificationStatus string
+
+const (
+	// BackupVerificationStatusUnverified is the verification status for UNVERIFIED.
+	BackupVerificationStatusUnverified BackupVerificationStatus = "UNVERIFIED"
+	// BackupVerificationStatusPassed is the verification status for PASSED.
+	BackupVerificationStatusPassed BackupVerificationStatus = "PASSED"
+	// BackupVerificationStatusFailed is the verification status for FAILED.
+	BackupVerificationStatusFailed BackupVerificationStatus = "FAILED"
+)
+
+func (e BackupVerificationStatus) String() string {
+	switch e {
+	case BackupVerificationStatusUnverified:
+		return "UNVERIFIED"
+	case BackupVerificationStatusPassed:
+		return "PASSED"
+	case BackupVerificationStatusFailed:
+		return "FAILED"
+	}
+	return "UNKNOWN"
+}
+
+// BackupPayload is the payload recorded when taking a backup.
+type BackupPayload struct {
+	// TableRowCount is the number of rows of each table in the backup, keyed by the table name.
+	// For Postgres, the table name is qualified by the schema name.
+	TableRowCount map[string]int64 `json:"tableRowCount"`
+	// BinlogInfo is the binlog coordinate of a MySQL backup taken with binlog archiving enabled.
+	// The point-in-time recovery replays the binlog events from this coordinate on top of the backup.
+	BinlogInfo *BackupBinlogInfo `json:"binlogInfo,omitempty"`
+}
+
+// BackupBinlogInfo is the binlog coordinate where a MySQL backup is taken.
+type BackupBinlogInfo struct {
+	FileName string `json:"fileName"`
+	Position int64  `json:"position"`
+	// Ts is the unix timestamp of the backup snapshot in the clock of the MySQL server.
+	Ts int64 `json:"ts"`
+	// ServerUUID is the server UUID of the MySQL server, which tells the binlog files of a rebuilt server apart.
+	ServerUUID string `json:"serverUuid,omitempty"`
+}
+
 // BackupStorageBackend is the storage backend of a backup.
 type BackupStorageBackend string
 
 const (
 	// BackupStorageBackendLocal is the local storage backend for a backup.
 	BackupStorageBackendLocal BackupStorageBackend = "LOCAL"
-	// BackupStorageBackendS3 is the AWS S3 storage backend for a backup. Not used yet.
+	// BackupStorageBackendS3 is the AWS S3 or S3-compatible (e.g. MinIO) storage backend for a backup.
 	BackupStorageBackendS3 BackupStorageBackend = "S3"
 	// BackupStorageBackendGCS is the Google Cloud Storage (GCS) storage backend for a backup. Not used yet.
 	BackupStorageBackendGCS BackupStorageBackend = "GCS"
@@ -78,6 +130,167 @@ func (e BackupStorageBackend) String() string {
 	return "UNKNOWN"
 }
 
+// BackupCompression is the compression algorithm of a backup.
+type BackupCompression string
+
+const (
+	// BackupCompressionNone is the backup without compression.
+	BackupCompressionNone BackupCompression = "NONE"
+	// BackupCompressionGzip is the backup compressed with gzip.
+	BackupCompressionGzip BackupCompression = "GZIP"
+	// BackupCompressionZstd is the backup compressed with zstd.
+	BackupCompressionZstd BackupCompression = "ZSTD"
+)
+
+func (e BackupCompression) String() string {
+	switch e {
+	case BackupCompressionNone:
+		return "NONE"
+	case BackupCompressionGzip:
+		return "GZIP"
+	case BackupCompressionZstd:
+		return "ZSTD"
+	}
+	return "UNKNOWN"
+}
+
+// BackupEncryption is the encryption algorithm of a backup.
+type BackupEncryption string
+
+const (
+	// BackupEncryptionNone is the backup without encryption.
+	BackupEncryptionNone BackupEncryption = "NONE"
+	// BackupEncryptionAES256GCM is the backup encrypted with AES-256 in GCM mode.
+	BackupEncryptionAES256GCM BackupEncryption = "AES_256_GCM"
+)
+
+func (e BackupEncryption) String() string {
+	switch e {
+	case BackupEncryptionNone:
+		return "NONE"
+	case BackupEncryptionAES256GCM:
+		return "AES_256_GCM"
+	}
+	return "UNKNOWN"
+}
+
+// BackupStorageS3Setting is the configuration of the S3-compatible backup storage.
+type BackupStorageS3Setting struct {
+	// Endpoint is the URL of the S3-compatible service. Leave it empty to use AWS S3.
+	Endpoint        string `json:"endpoint"`
+	Region          string `json:"region"`
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	// UsePathStyle addresses the bucket with path style, which is required by most self hosted services.
+	UsePathStyle bool `json:"usePathStyle"`
+}
+
+// BackupStorageSetting is the workspace setting of where and how to store new backups.
+// The setting row holds the S3 secret access key and the encryption keys in plaintext, so that they're redacted in the
+// API responses. Prefer EncryptionKeyFile to keep the encryption key out of the metadata database.
+type BackupStorageSetting struct {
+	Backend BackupStorageBackend    `json:"backend"`
+	S3      *BackupStorageS3Setting `json:"s3,omitempty"`
+
+	// Compression is the compression algorithm of new backups, default to NONE.
+	Compression BackupCompression `json:"compression,omitempty"`
+	// Encryption is the encryption algorithm of new backups, default to NONE.
+	Encryption BackupEncryption `json:"encryption,omitempty"`
+	// EncryptionKey is the base64 encoded 256-bit encryption key.
+	EncryptionKey string `json:"encryptionKey,omitempty"`
+	// EncryptionKeyFile is the path of the file containing the base64 encoded 256-bit encryption key.
+	// It's preferred over EncryptionKey if both are set, so that the key doesn't need to be stored in the metadata database.
+	EncryptionKeyFile string `json:"encryptionKeyFile,omitempty"`
+	// PreviousEncryptionKeyList is the base64 encoded keys rotated out, which are kept to restore the backups and
+	// the binlog files encrypted by them. Each one is selected by the fingerprint recorded with the encrypted artifact.
+	PreviousEncryptionKeyList []string `json:"previousEncryptionKeyList,omitempty"`
+}
+
+// GetBackupEncryptionKeyFingerprint returns the fingerprint identifying the backup encryption key, which is the hex encoded
+// first 8 bytes of the SHA-256 of the key, so that the key can't be derived from it.
+func GetBackupEncryptionKeyFingerprint(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// UnmarshalBackupStorageSetting will unmarshal and validate the backup storage setting value.
+func UnmarshalBackupStorageSetting(value string) (*BackupStorageSetting, error) {
+	var setting BackupStorageSetting
+	if err := json.Unmarshal([]byte(value), &setting); err != nil {
+		// The value isn't included in the error since it contains secrets.
+		return nil, fmt.Errorf("failed to unmarshal backup storage setting: %w", err)
+	}
+	switch setting.Backend {
+	case BackupStorageBackendLocal:
+	case BackupStorageBackendS3:
+		if setting.S3 == nil || setting.S3.Bucket == "" {
+			return nil, fmt.Errorf("bucket is required for backup storage backend %q", setting.Backend)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported backup storage backend %q", setting.Backend)
+	}
+	switch setting.Compression {
+	case "":
+		setting.Compression = BackupCompressionNone
+	case BackupCompressionNone, BackupCompressionGzip, BackupCompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported backup compression %q", setting.Compression)
+	}
+	switch setting.Encryption {
+	case "":
+		setting.Encryption = BackupEncryptionNone
+	case BackupEncryptionNone:
+	case BackupEncryptionAES256GCM:
+		if setting.EncryptionKey == "" && setting.EncryptionKeyFile == "" {
+			return nil, fmt.Errorf("encryption key or key file is required for backup encryption %q", setting.Encryption)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported backup encryption %q", setting.Encryption)
+	}
+	return &setting, nil
+}
+
+// Redact returns the copy of the setting with the secrets replaced by RedactedSecret.
+func (setting *BackupStorageSetting) Redact() *BackupStorageSetting {
+	redacted := *setting
+	if setting.S3 != nil {
+		s3 := *setting.S3
+		s3.SecretAccessKey = redactSecret(s3.SecretAccessKey)
+		redacted.S3 = &s3
+	}
+	redacted.EncryptionKey = redactSecret(setting.EncryptionKey)
+	redacted.PreviousEncryptionKeyList = nil
+	for _, key := range setting.PreviousEncryptionKeyList {
+		redacted.PreviousEncryptionKeyList = append(redacted.PreviousEncryptionKeyList, redactSecret(key))
+	}
+	return &redacted
+}
+
+// RestoreRedacted replaces the secrets left as RedactedSecret with the ones of the old setting, so that the client can
+// send back the redacted setting with only the other fields changed. The previous encryption keys are matched by position.
+func (setting *BackupStorageSetting) RestoreRedacted(old *BackupStorageSetting) {
+	if setting.S3 != nil && setting.S3.SecretAccessKey == RedactedSecret && old.S3 != nil {
+		setting.S3.SecretAccessKey = old.S3.SecretAccessKey
+	}
+	if setting.EncryptionKey == RedactedSecret {
+		setting.EncryptionKey = old.EncryptionKey
+	}
+	for i, key := range setting.PreviousEncryptionKeyList {
+		if key == RedactedSecret && i < len(old.PreviousEncryptionKeyList) {
+			setting.PreviousEncryptionKeyList[i] = old.PreviousEncryptionKeyList[i]
+		}
+	}
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return RedactedSecret
+}
+
 // BackupRaw is the store model for an Backup.
 // Fields have exactly the same meanings as Backup.
 type BackupRaw struct {
@@ -93,13 +306,22 @@ type BackupRaw struct {
 	DatabaseID int
 
 	// Domain specific fields
-	Name                    string
-	Status                  BackupStatus
-	Type                    BackupType
-	StorageBackend          BackupStorageBackend
-	MigrationHistoryVersion string
-	Path                    string
-	Comment                 string
+	Name                     string
+	Status                   BackupStatus
+	Type                     BackupType
+	StorageBackend           BackupStorageBackend
+	MigrationHistoryVersion  string
+	Path                     string
+	Comment                  string
+	Compression              BackupCompression
+	Encryption               BackupEncryption
+	EncryptionKeyFingerprint string
+	Size                     int64
+	Checksum                 string
+	Payload                  string
+	VerificationStatus       BackupVerificationStatus
+	VerifiedTs               int64
+	VerificationDetail       string
 }
 
 // ToBackup creates an instance of Backup based on the BackupRaw.
@@ -118,13 +340,22 @@ func (raw *BackupRaw) ToBackup() *Backup {
 		DatabaseID: raw.DatabaseID,
 
 		// Domain specific fields
-		Name:                    raw.Name,
-		Status:                  raw.Status,
-		Type:                    raw.Type,
-		StorageBackend:          raw.StorageBackend,
-		MigrationHistoryVersion: raw.MigrationHistoryVersion,
-		Path:                    raw.Path,
-		Comment:                 raw.Comment,
+		Name:                     raw.Name,
+		Status:                   raw.Status,
+		Type:                     raw.Type,
+		StorageBackend:           raw.StorageBackend,
+		MigrationHistoryVersion:  raw.MigrationHistoryVersion,
+		Path:                     raw.Path,
+		Comment:                  raw.Comment,
+		Compression:              raw.Compression,
+		Encryption:               raw.Encryption,
+		EncryptionKeyFingerprint: raw.EncryptionKeyFingerprint,
+		Size:                     raw.Size,
+		Checksum:                 raw.Checksum,
+		Payload:                  raw.Payload,
+		VerificationStatus:       raw.VerificationStatus,
+		VerifiedTs:               raw.VerifiedTs,
+		VerificationDetail:       raw.VerificationDetail,
 	}
 }
 
@@ -153,6 +384,23 @@ type Backup struct {
 	MigrationHistoryVersion string `jsonapi:"attr,migrationHistoryVersion"`
 	Path                    string `jsonapi:"attr,path"`
 	Comment                 string `jsonapi:"attr,comment"`
+	// Compression and Encryption are applied in order when taking the backup, and reverted when restoring.
+	Compression BackupCompression `jsonapi:"attr,compression"`
+	Encryption  BackupEncryption  `jsonapi:"attr,encryption"`
+	// EncryptionKeyFingerprint identifies the key encrypting the backup, see GetBackupEncryptionKeyFingerprint.
+	// It's empty if the backup isn't encrypted, or is encrypted before the fingerprint is recorded.
+	EncryptionKeyFingerprint string `jsonapi:"attr,encryptionKeyFingerprint"`
+	// Size is the size in bytes of the stored backup after compression and encryption.
+	Size int64 `jsonapi:"attr,size"`
+	// Checksum is the hex encoded SHA-256 checksum of the stored backup after compression and encryption.
+	Checksum string `jsonapi:"attr,checksum"`
+	// Payload is the BackupPayload recorded when taking the backup.
+	Payload string `jsonapi:"attr,payload"`
+	// VerificationStatus is the result of the latest verification, which restores the backup into a scratch database
+	// and compares the restored tables and rows with the ones recorded in the payload.
+	VerificationStatus BackupVerificationStatus `jsonapi:"attr,verificationStatus"`
+	VerifiedTs         int64                    `jsonapi:"attr,verifiedTs"`
+	VerificationDetail string                   `jsonapi:"attr,verificationDetail"`
 }
 
 // BackupCreate is the API message for creating a backup.
@@ -165,11 +413,14 @@ type BackupCreate struct {
 	DatabaseID int `jsonapi:"attr,databaseId"`
 
 	// Domain specific fields
-	Name                    string               `jsonapi:"attr,name"`
-	Type                    BackupType           `jsonapi:"attr,type"`
-	StorageBackend          BackupStorageBackend `jsonapi:"attr,storageBackend"`
-	MigrationHistoryVersion string
-	Path                    string
+	Name                     string               `jsonapi:"attr,name"`
+	Type                     BackupType           `jsonapi:"attr,type"`
+	StorageBackend           BackupStorageBackend `jsonapi:"attr,storageBackend"`
+	MigrationHistoryVersion  string
+	Path                     string
+	Compression              BackupCompression
+	Encryption               BackupEncryption
+	EncryptionKeyFingerprint string
 }
 
 // BackupFind is the API message for finding backups.
@@ -182,6 +433,7 @@ type BackupFind struct {
 	// Domain specific fields
 	Name   *string
 	Status *BackupStatus
+	Type   *BackupType
 }
 
 func (find *BackupFind) String() string {
@@ -201,8 +453,14 @@ type BackupPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	Status  string
-	Comment string
+	Status             string
+	Comment            string
+	Size               *int64
+	Checksum           *string
+	Payload            *string
+	VerificationStatus *BackupVerificationStatus
+	VerifiedTs         *int64
+	VerificationDetail *string
 }
 
 // BackupSettingRaw is the store model for an BackupSetting.
@@ -324,4 +582,9 @@ type BackupService interface {
 	UpsertBackupSetting(ctx context.Context, upsert *BackupSettingUpsert) (*BackupSettingRaw, error)
 	UpsertBackupSettingTx(ctx context.Context, tx *sql.Tx, upsert *BackupSettingUpsert) (*BackupSettingRaw, error)
 	FindBackupSettingsMatch(ctx context.Context, match *BackupSettingsMatch) ([]*BackupSettingRaw, error)
+	// FindEncryptionKeyFingerprintList returns the distinct key fingerprints of the encrypted backups neither failed nor expired,
+	// which includes the empty one if any is encrypted before the fingerprint is recorded.
+	FindEncryptionKeyFingerprintList(ctx context.Context) ([]string, error)
+	// PatchEncryptionKeyFingerprint records the key fingerprint of the encrypted backups without the fingerprint recorded.
+	PatchEncryptionKeyFingerprint(ctx context.Context, fingerprint string) error
 }
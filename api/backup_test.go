@@ -0,0 +1,45 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackupStorageSettingRedact(t *testing.T) {
+	setting := &BackupStorageSetting{
+		S3: &BackupStorageS3Setting{
+			AccessKeyID:     "access-key",
+			SecretAccessKey: "secret-key",
+		},
+		EncryptionKey:             "key",
+		PreviousEncryptionKeyList: []string{"previous-key", ""},
+	}
+	redacted := setting.Redact()
+	want := &BackupStorageSetting{
+		S3: &BackupStorageS3Setting{
+			AccessKeyID:     "access-key",
+			SecretAccessKey: RedactedSecret,
+		},
+		EncryptionKey:             RedactedSecret,
+		PreviousEncryptionKeyList: []string{RedactedSecret, ""},
+	}
+	if !reflect.DeepEqual(redacted, want) {
+		t.Errorf("Redact() = %+v, want %+v", redacted, want)
+	}
+	if setting.S3.SecretAccessKey != "secret-key" || setting.EncryptionKey != "key" || setting.PreviousEncryptionKeyList[0] != "previous-key" {
+		t.Errorf("Redact() modified the original setting: %+v", setting)
+	}
+
+	// The client changes the key and sends back the other redacted secrets.
+	redacted.EncryptionKey = "new-key"
+	redacted.RestoreRedacted(setting)
+	if redacted.S3.SecretAccessKey != "secret-key" {
+		t.Errorf("RestoreRedacted() secret access key = %q, want %q", redacted.S3.SecretAccessKey, "secret-key")
+	}
+	if redacted.EncryptionKey != "new-key" {
+		t.Errorf("RestoreRedacted() encryption key = %q, want %q", redacted.EncryptionKey, "new-key")
+	}
+	if !reflect.DeepEqual(redacted.PreviousEncryptionKeyList, setting.PreviousEncryptionKeyList) {
+		t.Errorf("RestoreRedacted() previous encryption keys = %v, want %v", redacted.PreviousEncryptionKeyList, setting.PreviousEncryptionKeyList)
+	}
+}
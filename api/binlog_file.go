@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// BinlogFile is the API message for a MySQL binlog file archived to the backup storage.
+// The archived binlog files are replayed on top of a backup for the point-in-time recovery.
+type BinlogFile struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	// Name is the binlog file name on the MySQL server, e.g. "binlog.000012".
+	Name string
+	// Seq is the sequence number in the binlog file name, which increases with each new binlog file.
+	Seq int64
+	// ServerUUID and FileSize tell apart the binlog files with the same name, which is reused after RESET MASTER
+	// or the MySQL server is rebuilt. They're empty for the binlog files archived before they're recorded.
+	ServerUUID string
+	// FileSize is the size of the binlog file on the MySQL server, while Size is the one in the backup storage.
+	FileSize       int64
+	StorageBackend BackupStorageBackend
+	Path           string
+	Compression    BackupCompression
+	Encryption     BackupEncryption
+	// EncryptionKeyFingerprint identifies the key encrypting the binlog file, see GetBackupEncryptionKeyFingerprint.
+	EncryptionKeyFingerprint string
+	Size                     int64
+	Checksum                 string
+}
+
+// BinlogFileCreate is the API message for creating a binlog file.
+type BinlogFileCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	Name           string
+	Seq            int64
+	ServerUUID     string
+	FileSize       int64
+	StorageBackend BackupStorageBackend
+	Path           string
+	Compression    BackupCompression
+	Encryption     BackupEncryption
+	// EncryptionKeyFingerprint identifies the key encrypting the binlog file, see GetBackupEncryptionKeyFingerprint.
+	EncryptionKeyFingerprint string
+	Size                     int64
+	Checksum                 string
+}
+
+// BinlogFileFind is the API message for finding binlog files.
+type BinlogFileFind struct {
+	ID *int
+
+	// Related fields
+	InstanceID *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *BinlogFileFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// BinlogFileDelete is the API message for deleting a binlog file.
+type BinlogFileDelete struct {
+	ID int
+
+	// Standard fields
+	DeleterID int
+}
+
+// BinlogFileService is the service for binlog files.
+type BinlogFileService interface {
+	CreateBinlogFile(ctx context.Context, create *BinlogFileCreate) (*BinlogFile, error)
+	// FindBinlogFileList returns the binlog files ordered by the sequence number, and then the archiving order.
+	FindBinlogFileList(ctx context.Context, find *BinlogFileFind) ([]*BinlogFile, error)
+	DeleteBinlogFile(ctx context.Context, delete *BinlogFileDelete) error
+	// FindEncryptionKeyFingerprintList returns the distinct key fingerprints of the encrypted binlog files,
+	// which includes the empty one if any is encrypted before the fingerprint is recorded.
+	FindEncryptionKeyFingerprintList(ctx context.Context) ([]string, error)
+	// PatchEncryptionKeyFingerprint records the key fingerprint of the encrypted binlog files without the fingerprint recorded.
+	PatchEncryptionKeyFingerprint(ctx context.Context, fingerprint string) error
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// CheckConstraint is the API message for a check constraint.
+type CheckConstraint struct {
+	ID int `jsonapi:"primary,checkConstraint"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// CheckConstraintCreate is the API message for creating a check constraint.
+type CheckConstraintCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name       string
+	Expression string
+}
+
+// CheckConstraintFind is the API message for finding check constraints.
+type CheckConstraintFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+	TableID    *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *CheckConstraintFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// CheckConstraintService is the service for check constraints.
+type CheckConstraintService interface {
+	CreateCheckConstraint(ctx context.Context, create *CheckConstraintCreate) (*CheckConstraint, error)
+	FindCheckConstraintList(ctx context.Context, find *CheckConstraintFind) ([]*CheckConstraint, error)
+}
@@ -56,11 +56,16 @@ type ColumnFind struct {
 	ID *int
 
 	// Related fields
-	DatabaseID *int
-	TableID    *int
+	DatabaseID     *int
+	DatabaseIDList *[]int
+	TableID        *int
 
 	// Domain specific fields
 	Name *string
+	// Pattern is the case-insensitive POSIX regular expression matching the name, the type or the comment.
+	Pattern *string
+	// If specified, then it will only fetch "Limit" columns
+	Limit *int
 }
 
 func (find *ColumnFind) String() string {
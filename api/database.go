@@ -25,6 +25,8 @@ const (
 	OK SyncStatus = "OK"
 	// NotFound is the NOT_FOUND sync status.
 	NotFound SyncStatus = "NOT_FOUND"
+	// SyncFailed is the FAILED sync status, the error is recorded in the SyncError of the database.
+	SyncFailed SyncStatus = "FAILED"
 )
 
 func (e SyncStatus) String() string {
@@ -33,6 +35,8 @@ func (e SyncStatus) String() string {
 		return "OK"
 	case NotFound:
 		return "NOT_FOUND"
+	case SyncFailed:
+		return "FAILED"
 	}
 	return ""
 }
@@ -60,6 +64,9 @@ type DatabaseRaw struct {
 	SchemaVersion        string
 	SyncStatus           SyncStatus
 	LastSuccessfulSyncTs int64
+	SyncError            string
+	SyncDurationMs       int64
+	SchemaFingerprint    string
 }
 
 // ToDatabase creates an instance of Database based on the DatabaseRaw.
@@ -86,6 +93,9 @@ func (raw *DatabaseRaw) ToDatabase() *Database {
 		SchemaVersion:        raw.SchemaVersion,
 		SyncStatus:           raw.SyncStatus,
 		LastSuccessfulSyncTs: raw.LastSuccessfulSyncTs,
+		SyncError:            raw.SyncError,
+		SyncDurationMs:       raw.SyncDurationMs,
+		SchemaFingerprint:    raw.SchemaFingerprint,
 	}
 }
 
@@ -119,6 +129,12 @@ type Database struct {
 	SchemaVersion        string     `jsonapi:"attr,schemaVersion"`
 	SyncStatus           SyncStatus `jsonapi:"attr,syncStatus"`
 	LastSuccessfulSyncTs int64      `jsonapi:"attr,lastSuccessfulSyncTs"`
+	// SyncError is the error of the last sync if SyncStatus is FAILED.
+	SyncError string `jsonapi:"attr,syncError"`
+	// SyncDurationMs is the duration of the last sync in milliseconds.
+	SyncDurationMs int64 `jsonapi:"attr,syncDurationMs"`
+	// SchemaFingerprint is the DDL fingerprint of the last successful sync, the sync is skipped if it's unchanged.
+	SchemaFingerprint string
 	// Labels is a json-encoded string from a list of DatabaseLabel,
 	// e.g. "[{"key":"bb.location","value":"earth"},{"key":"bb.tenant","value":"bytebase"}]".
 	Labels string `jsonapi:"attr,labels,omitempty"`
@@ -187,6 +203,9 @@ type DatabasePatch struct {
 	SchemaVersion        *string
 	SyncStatus           *SyncStatus
 	LastSuccessfulSyncTs *int64
+	SyncError            *string
+	SyncDurationMs       *int64
+	SchemaFingerprint    *string
 }
 
 // DatabaseService is the service for databases.
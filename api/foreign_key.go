@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ForeignKey is the API message for a foreign key.
+// Similar to Index, there is one ForeignKey for each column of the constraint.
+type ForeignKey struct {
+	ID int `jsonapi:"primary,foreignKey"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name             string `json:"name"`
+	Column           string `json:"column"`
+	Position         int    `json:"position"`
+	ReferencedTable  string `json:"referencedTable"`
+	ReferencedColumn string `json:"referencedColumn"`
+	OnUpdate         string `json:"onUpdate"`
+	OnDelete         string `json:"onDelete"`
+}
+
+// ForeignKeyCreate is the API message for creating a foreign key.
+type ForeignKeyCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name             string
+	Column           string
+	Position         int
+	ReferencedTable  string
+	ReferencedColumn string
+	OnUpdate         string
+	OnDelete         string
+}
+
+// ForeignKeyFind is the API message for finding foreign keys.
+type ForeignKeyFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+	TableID    *int
+
+	// Domain specific fields
+	Name   *string
+	Column *string
+}
+
+func (find *ForeignKeyFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// ForeignKeyService is the service for foreign keys.
+type ForeignKeyService interface {
+	CreateForeignKey(ctx context.Context, create *ForeignKeyCreate) (*ForeignKey, error)
+	FindForeignKeyList(ctx context.Context, find *ForeignKeyFind) ([]*ForeignKey, error)
+	FindForeignKey(ctx context.Context, find *ForeignKeyFind) (*ForeignKey, error)
+}
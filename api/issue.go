@@ -127,6 +127,11 @@ type CreateDatabaseContext struct {
 	Collation string `json:"collation"`
 	// BackupID is the ID of the backup.
 	BackupID int `json:"backupId"`
+	// SourceDatabaseID is the ID of the database to recover to PointInTimeTs, mutually exclusive to BackupID.
+	SourceDatabaseID int `json:"sourceDatabaseId"`
+	// PointInTimeTs is the unix timestamp to recover the source database to. The closest backup taken before this
+	// point is restored, and the archived binlog is replayed up to this point. Only MySQL is supported.
+	PointInTimeTs int64 `json:"pointInTimeTs"`
 	// Labels is a json-encoded string from a list of DatabaseLabel.
 	// See definition in api.Database.
 	Labels string `jsonapi:"attr,labels,omitempty"`
@@ -156,6 +161,15 @@ type UpdateSchemaContext struct {
 	VCSPushEvent *vcs.PushEvent
 }
 
+// DataSourceRequestContext is the issue create context for requesting to query a database in the SQL editor.
+// It's persisted as the issue payload, and the request is approved once the assignee resolves the issue.
+type DataSourceRequestContext struct {
+	// DatabaseID is the ID of the database to query.
+	DatabaseID int `json:"databaseId"`
+	// ExpireTs is the unix timestamp when the approved access expires.
+	ExpireTs int64 `json:"expireTs"`
+}
+
 // IssueFind is the API message for finding issues.
 type IssueFind struct {
 	ID *int
@@ -164,6 +178,7 @@ type IssueFind struct {
 	ProjectID *int
 
 	// Domain specific fields
+	Type       *IssueType
 	PipelineID *int
 	// Find issue where principalID is either creator, assignee or subscriber
 	PrincipalID *int
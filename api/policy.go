@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+
+	"github.com/bytebase/bytebase/plugin/db/masking"
 )
 
 // PolicyType is the type or name of a policy.
@@ -20,6 +23,14 @@ const (
 	PolicyTypePipelineApproval PolicyType = "bb.policy.pipeline-approval"
 	// PolicyTypeBackupPlan is the backup plan policy type.
 	PolicyTypeBackupPlan PolicyType = "bb.policy.backup-plan"
+	// PolicyTypeInstanceHealth is the instance health policy type.
+	PolicyTypeInstanceHealth PolicyType = "bb.policy.instance-health"
+	// PolicyTypeSQLExport is the SQL editor export policy type.
+	PolicyTypeSQLExport PolicyType = "bb.policy.sql-export"
+	// PolicyTypeDataMasking is the data masking policy type.
+	PolicyTypeDataMasking PolicyType = "bb.policy.data-masking"
+	// PolicyTypeSQLQueryAccess is the SQL editor query access policy type.
+	PolicyTypeSQLQueryAccess PolicyType = "bb.policy.sql-query-access"
 
 	// PipelineApprovalValueManualNever is MANUAL_APPROVAL_NEVER approval policy value.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -39,6 +50,10 @@ var (
 	PolicyTypes = map[PolicyType]bool{
 		PolicyTypePipelineApproval: true,
 		PolicyTypeBackupPlan:       true,
+		PolicyTypeInstanceHealth:   true,
+		PolicyTypeSQLExport:        true,
+		PolicyTypeDataMasking:      true,
+		PolicyTypeSQLQueryAccess:   true,
 	}
 )
 
@@ -139,6 +154,10 @@ type PolicyService interface {
 	UpsertPolicy(ctx context.Context, upsert *PolicyUpsert) (*PolicyRaw, error)
 	GetBackupPlanPolicy(ctx context.Context, environmentID int) (*BackupPlanPolicy, error)
 	GetPipelineApprovalPolicy(ctx context.Context, environmentID int) (*PipelineApprovalPolicy, error)
+	GetInstanceHealthPolicy(ctx context.Context, environmentID int) (*InstanceHealthPolicy, error)
+	GetSQLExportPolicy(ctx context.Context, environmentID int) (*SQLExportPolicy, error)
+	GetDataMaskingPolicy(ctx context.Context, environmentID int) (*DataMaskingPolicy, error)
+	GetSQLQueryAccessPolicy(ctx context.Context, environmentID int) (*SQLQueryAccessPolicy, error)
 }
 
 // PipelineApprovalPolicy is the policy configuration for pipeline approval
@@ -166,6 +185,26 @@ func UnmarshalPipelineApprovalPolicy(payload string) (*PipelineApprovalPolicy, e
 // BackupPlanPolicy is the policy configuration for backup plan.
 type BackupPlanPolicy struct {
 	Schedule BackupPlanPolicySchedule `json:"schedule"`
+	// RetentionCount is the number of latest automatic backups to keep. 0 means no count based retention.
+	RetentionCount int `json:"retentionCount,omitempty"`
+	// RetentionDays is the number of days to keep automatic backups. 0 means no age based retention.
+	RetentionDays int `json:"retentionDays,omitempty"`
+	// KeepWeekly keeps the latest automatic backup of every week regardless of the count and age based retention.
+	KeepWeekly bool `json:"keepWeekly,omitempty"`
+	// KeepMonthly keeps the latest automatic backup of every month regardless of the count and age based retention.
+	KeepMonthly bool `json:"keepMonthly,omitempty"`
+	// VerificationSchedule is how often to verify the latest backup of each database by restoring it into a scratch
+	// database. Empty or UNSET means no verification.
+	VerificationSchedule BackupPlanPolicySchedule `json:"verificationSchedule,omitempty"`
+	// ArchiveBinlog archives the binlog of the MySQL instances to the backup storage for the point-in-time recovery.
+	// It requires the binlog to be enabled in ROW format, and the admin data source to have the RELOAD and
+	// REPLICATION SLAVE privileges.
+	ArchiveBinlog bool `json:"archiveBinlog,omitempty"`
+}
+
+// HasRetention returns whether the backup plan policy expires any automatic backup.
+func (bp BackupPlanPolicy) HasRetention() bool {
+	return bp.RetentionCount > 0 || bp.RetentionDays > 0
 }
 
 func (bp BackupPlanPolicy) String() (string, error) {
@@ -185,6 +224,162 @@ func UnmarshalBackupPlanPolicy(payload string) (*BackupPlanPolicy, error) {
 	return &bp, nil
 }
 
+// InstanceHealthPolicy is the policy configuration for the instance health anomaly thresholds.
+// A threshold of 0 disables the check.
+type InstanceHealthPolicy struct {
+	// ReplicationLagSeconds is the replication lag of any replica to raise the anomaly.
+	ReplicationLagSeconds int `json:"replicationLagSeconds"`
+	// ConnectionUsagePercent is the percentage of the connections against max_connections to raise the anomaly.
+	ConnectionUsagePercent int `json:"connectionUsagePercent"`
+	// LongRunningTransactionSeconds is the duration of any running transaction to raise the anomaly.
+	LongRunningTransactionSeconds int `json:"longRunningTransactionSeconds"`
+	// StorageGrowthPercent is the size growth percentage of any database or tablespace within a day to raise the anomaly.
+	StorageGrowthPercent int `json:"storageGrowthPercent"`
+}
+
+func (ih InstanceHealthPolicy) String() (string, error) {
+	s, err := json.Marshal(ih)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalInstanceHealthPolicy will unmarshal payload to instance health policy.
+func UnmarshalInstanceHealthPolicy(payload string) (*InstanceHealthPolicy, error) {
+	var ih InstanceHealthPolicy
+	if err := json.Unmarshal([]byte(payload), &ih); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal instance health policy %q: %q", payload, err)
+	}
+	return &ih, nil
+}
+
+// SQLExportPolicy is the policy configuration for exporting the SQL editor query result.
+type SQLExportPolicy struct {
+	// MaxRowCount is the maximum number of rows of an export. 0 disables the export.
+	MaxRowCount int `json:"maxRowCount"`
+}
+
+func (se SQLExportPolicy) String() (string, error) {
+	s, err := json.Marshal(se)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalSQLExportPolicy will unmarshal payload to SQL export policy.
+func UnmarshalSQLExportPolicy(payload string) (*SQLExportPolicy, error) {
+	var se SQLExportPolicy
+	if err := json.Unmarshal([]byte(payload), &se); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SQL export policy %q: %q", payload, err)
+	}
+	return &se, nil
+}
+
+// DataMaskingPolicy is the policy configuration for masking the sensitive data in the SQL editor query and export results.
+// The columns are sensitive if they are marked in the metadata of the database, or their names match any of the patterns.
+type DataMaskingPolicy struct {
+	// ExemptRoleList is the roles seeing the sensitive data in clear text.
+	ExemptRoleList []Role `json:"exemptRoleList"`
+	// PatternList marks the columns sensitive by name in all databases of the environment.
+	PatternList []*DataMaskingPattern `json:"patternList"`
+}
+
+// DataMaskingPattern marks the columns sensitive if the column name matches the case-insensitive regular expression.
+type DataMaskingPattern struct {
+	ColumnPattern string       `json:"columnPattern"`
+	MaskingType   masking.Type `json:"maskingType"`
+}
+
+func (dm DataMaskingPolicy) String() (string, error) {
+	s, err := json.Marshal(dm)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalDataMaskingPolicy will unmarshal payload to data masking policy.
+func UnmarshalDataMaskingPolicy(payload string) (*DataMaskingPolicy, error) {
+	var dm DataMaskingPolicy
+	if err := json.Unmarshal([]byte(payload), &dm); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data masking policy %q: %q", payload, err)
+	}
+	return &dm, nil
+}
+
+// IsExempted returns whether the role sees the sensitive data in clear text.
+func (dm *DataMaskingPolicy) IsExempted(role Role) bool {
+	for _, exempted := range dm.ExemptRoleList {
+		if exempted == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CompilePatternList compiles the column patterns case-insensitively.
+func (dm *DataMaskingPolicy) CompilePatternList() ([]*masking.Pattern, error) {
+	var patternList []*masking.Pattern
+	for _, pattern := range dm.PatternList {
+		re, err := regexp.Compile("(?i)" + pattern.ColumnPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data masking policy column pattern %q: %w", pattern.ColumnPattern, err)
+		}
+		patternList = append(patternList, &masking.Pattern{Regexp: re, Type: pattern.MaskingType})
+	}
+	return patternList, nil
+}
+
+// SQLQueryAccessPolicy is the policy configuration for querying and exporting the databases of the environment in the SQL editor.
+type SQLQueryAccessPolicy struct {
+	// ExemptRoleList is the roles not restricted by the policy.
+	ExemptRoleList []Role `json:"exemptRoleList"`
+	// RequireProjectMember requires the principal to be a member of the project of the queried database.
+	RequireProjectMember bool `json:"requireProjectMember"`
+	// RequireAccessRequest requires the principal to have an approved and unexpired data source request issue of the queried database.
+	RequireAccessRequest bool `json:"requireAccessRequest"`
+	// MaxAccessRequestDurationSeconds is the maximum duration of the access granted by a data source request issue since it's created.
+	// The request expiring later is rejected, and the approved one is cut short if the policy is tightened. 0 means no limit.
+	MaxAccessRequestDurationSeconds int64 `json:"maxAccessRequestDurationSeconds"`
+	// MaxRowCount caps the row limit of a query. 0 means no cap.
+	MaxRowCount int `json:"maxRowCount"`
+	// MaxQueryCountPerDay is the maximum number of queries and exports of a principal in the environment per day in UTC.
+	// 0 means no limit.
+	MaxQueryCountPerDay int `json:"maxQueryCountPerDay"`
+	// StatementTimeoutSeconds kills the query running longer than it, including the time browsing its pages. 0 means no timeout.
+	// Unlike the other restrictions, it applies to the exempted roles as well.
+	StatementTimeoutSeconds int `json:"statementTimeoutSeconds"`
+}
+
+func (qa SQLQueryAccessPolicy) String() (string, error) {
+	s, err := json.Marshal(qa)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalSQLQueryAccessPolicy will unmarshal payload to SQL query access policy.
+func UnmarshalSQLQueryAccessPolicy(payload string) (*SQLQueryAccessPolicy, error) {
+	var qa SQLQueryAccessPolicy
+	if err := json.Unmarshal([]byte(payload), &qa); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SQL query access policy %q: %q", payload, err)
+	}
+	return &qa, nil
+}
+
+// IsExempted returns whether the role is not restricted by the policy.
+func (qa *SQLQueryAccessPolicy) IsExempted(role Role) bool {
+	for _, exempted := range qa.ExemptRoleList {
+		if exempted == role {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidatePolicy will validate the policy type and payload values.
 func ValidatePolicy(pType PolicyType, payload string) error {
 	if !PolicyTypes[pType] {
@@ -211,6 +406,83 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 		if bp.Schedule != BackupPlanPolicyScheduleUnset && bp.Schedule != BackupPlanPolicyScheduleDaily && bp.Schedule != BackupPlanPolicyScheduleWeekly {
 			return fmt.Errorf("invalid backup plan policy schedule: %q", bp.Schedule)
 		}
+		if bp.VerificationSchedule != "" && bp.VerificationSchedule != BackupPlanPolicyScheduleUnset && bp.VerificationSchedule != BackupPlanPolicyScheduleDaily && bp.VerificationSchedule != BackupPlanPolicyScheduleWeekly {
+			return fmt.Errorf("invalid backup plan policy verification schedule: %q", bp.VerificationSchedule)
+		}
+		if bp.RetentionCount < 0 {
+			return fmt.Errorf("invalid backup plan policy retention count: %d", bp.RetentionCount)
+		}
+		if bp.RetentionDays < 0 {
+			return fmt.Errorf("invalid backup plan policy retention days: %d", bp.RetentionDays)
+		}
+	case PolicyTypeInstanceHealth:
+		ih, err := UnmarshalInstanceHealthPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if ih.ReplicationLagSeconds < 0 {
+			return fmt.Errorf("invalid instance health policy replication lag seconds: %d", ih.ReplicationLagSeconds)
+		}
+		if ih.ConnectionUsagePercent < 0 || ih.ConnectionUsagePercent > 100 {
+			return fmt.Errorf("invalid instance health policy connection usage percent: %d", ih.ConnectionUsagePercent)
+		}
+		if ih.LongRunningTransactionSeconds < 0 {
+			return fmt.Errorf("invalid instance health policy long running transaction seconds: %d", ih.LongRunningTransactionSeconds)
+		}
+		if ih.StorageGrowthPercent < 0 {
+			return fmt.Errorf("invalid instance health policy storage growth percent: %d", ih.StorageGrowthPercent)
+		}
+	case PolicyTypeSQLExport:
+		se, err := UnmarshalSQLExportPolicy(payload)
+		if err != nil {
+			return err
+		}
+		if se.MaxRowCount < 0 {
+			return fmt.Errorf("invalid SQL export policy max row count: %d", se.MaxRowCount)
+		}
+	case PolicyTypeDataMasking:
+		dm, err := UnmarshalDataMaskingPolicy(payload)
+		if err != nil {
+			return err
+		}
+		for _, role := range dm.ExemptRoleList {
+			if role != Owner && role != DBA && role != Developer {
+				return fmt.Errorf("invalid data masking policy exempt role: %q", role)
+			}
+		}
+		for _, pattern := range dm.PatternList {
+			if pattern.ColumnPattern == "" {
+				return fmt.Errorf("invalid data masking policy column pattern: empty")
+			}
+			if !pattern.MaskingType.Valid() {
+				return fmt.Errorf("invalid data masking policy masking type %q of column pattern %q", pattern.MaskingType, pattern.ColumnPattern)
+			}
+		}
+		if _, err := dm.CompilePatternList(); err != nil {
+			return err
+		}
+	case PolicyTypeSQLQueryAccess:
+		qa, err := UnmarshalSQLQueryAccessPolicy(payload)
+		if err != nil {
+			return err
+		}
+		for _, role := range qa.ExemptRoleList {
+			if role != Owner && role != DBA && role != Developer {
+				return fmt.Errorf("invalid SQL query access policy exempt role: %q", role)
+			}
+		}
+		if qa.MaxAccessRequestDurationSeconds < 0 {
+			return fmt.Errorf("invalid SQL query access policy max access request duration seconds: %d", qa.MaxAccessRequestDurationSeconds)
+		}
+		if qa.MaxRowCount < 0 {
+			return fmt.Errorf("invalid SQL query access policy max row count: %d", qa.MaxRowCount)
+		}
+		if qa.MaxQueryCountPerDay < 0 {
+			return fmt.Errorf("invalid SQL query access policy max query count per day: %d", qa.MaxQueryCountPerDay)
+		}
+		if qa.StatementTimeoutSeconds < 0 {
+			return fmt.Errorf("invalid SQL query access policy statement timeout seconds: %d", qa.StatementTimeoutSeconds)
+		}
 	}
 	return nil
 }
@@ -227,6 +499,27 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 		return BackupPlanPolicy{
 			Schedule: BackupPlanPolicyScheduleUnset,
 		}.String()
+	case PolicyTypeInstanceHealth:
+		return InstanceHealthPolicy{
+			ReplicationLagSeconds:         300,
+			ConnectionUsagePercent:        80,
+			LongRunningTransactionSeconds: 3600,
+			StorageGrowthPercent:          20,
+		}.String()
+	case PolicyTypeSQLExport:
+		return SQLExportPolicy{
+			MaxRowCount: 100000,
+		}.String()
+	case PolicyTypeDataMasking:
+		return DataMaskingPolicy{
+			ExemptRoleList: []Role{Owner, DBA},
+		}.String()
+	case PolicyTypeSQLQueryAccess:
+		return SQLQueryAccessPolicy{
+			ExemptRoleList: []Role{Owner, DBA},
+			// 7 days.
+			MaxAccessRequestDurationSeconds: 7 * 24 * 60 * 60,
+		}.String()
 	}
 	return "", nil
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/dictionary"
 )
 
 // DefaultProjectID is the ID for the default project.
@@ -245,10 +246,27 @@ func ValidateRepositoryFilePathTemplate(filePathTemplate string, tenantMode Proj
 
 // ValidateRepositorySchemaPathTemplate validates the repository schema path template.
 func ValidateRepositorySchemaPathTemplate(schemaPathTemplate string, tenantMode ProjectTenantMode) error {
-	if schemaPathTemplate == "" {
+	return validateRepositoryLatestPathTemplate(schemaPathTemplate, "schema path template", tenantMode)
+}
+
+// ValidateRepositoryDictionaryPathTemplate validates the repository data dictionary path template.
+// It has the same tokens as the schema path template, and the file extension decides the data dictionary format.
+func ValidateRepositoryDictionaryPathTemplate(dictionaryPathTemplate string, tenantMode ProjectTenantMode) error {
+	if dictionaryPathTemplate == "" {
 		return nil
 	}
-	tokens := getTemplateTokens(schemaPathTemplate)
+	if _, err := dictionary.FormatFromPath(dictionaryPathTemplate); err != nil {
+		return err
+	}
+	return validateRepositoryLatestPathTemplate(dictionaryPathTemplate, "dictionary path template", tenantMode)
+}
+
+// validateRepositoryLatestPathTemplate validates the path template of the file auto-generated by Bytebase after migration.
+func validateRepositoryLatestPathTemplate(template, templateName string, tenantMode ProjectTenantMode) error {
+	if template == "" {
+		return nil
+	}
+	tokens := getTemplateTokens(template)
 	tokenMap := make(map[string]bool)
 	for _, token := range tokens {
 		tokenMap[token] = true
@@ -262,13 +280,13 @@ func ValidateRepositorySchemaPathTemplate(schemaPathTemplate string, tenantMode
 	for token, required := range schemaPathTemplateTokens {
 		if required {
 			if _, ok := tokenMap[token]; !ok {
-				return fmt.Errorf("missing %s in schema path template", token)
+				return fmt.Errorf("missing %s in %s", token, templateName)
 			}
 		}
 	}
 	for token := range tokenMap {
 		if _, ok := schemaPathTemplateTokens[token]; !ok {
-			return fmt.Errorf("unknown token %s in schema path template", token)
+			return fmt.Errorf("unknown token %s in %s", token, templateName)
 		}
 	}
 	return nil
@@ -298,3 +298,54 @@ func TestGetBaseDatabaseName(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRepositoryDictionaryPathTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		tenantMode ProjectTenantMode
+		errPart    string
+	}{
+		{
+			"OK",
+			"{{ENV_NAME}}/.{{DB_NAME}}__DICTIONARY.md",
+			TenantModeDisabled,
+			"",
+		}, {
+			"Empty",
+			"",
+			TenantModeDisabled,
+			"",
+		}, {
+			"Unsupported extension",
+			"{{DB_NAME}}__DICTIONARY.txt",
+			TenantModeDisabled,
+			"unsupported data dictionary file extension",
+		}, {
+			"Missing {{DB_NAME}}",
+			"{{ENV_NAME}}/DICTIONARY.html",
+			TenantModeDisabled,
+			"missing {{DB_NAME}} in dictionary path template",
+		}, {
+			"Tenant mode {{ENV_NAME}}",
+			"{{ENV_NAME}}/{{DB_NAME}}.json",
+			TenantModeTenant,
+			"not allowed in the template",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateRepositoryDictionaryPathTemplate(test.template, test.tenantMode)
+		if err != nil {
+			if test.errPart == "" {
+				t.Errorf("%q: ValidateRepositoryDictionaryPathTemplate(%q) got error %q, want OK.", test.name, test.template, err.Error())
+			} else if !strings.Contains(err.Error(), test.errPart) {
+				t.Errorf("%q: ValidateRepositoryDictionaryPathTemplate(%q) got error %q, want errPart %q.", test.name, test.template, err.Error(), test.errPart)
+			}
+		} else {
+			if test.errPart != "" {
+				t.Errorf("%q: ValidateRepositoryDictionaryPathTemplate(%q) got no error, want errPart %q.", test.name, test.template, test.errPart)
+			}
+		}
+	}
+}
@@ -24,6 +24,8 @@ type ProjectWebhookRaw struct {
 	Name         string
 	URL          string
 	ActivityList []string
+	// AnomalySeverityList is the anomaly severities posted to the webhook for the anomaly activities.
+	AnomalySeverityList []string
 }
 
 // ToProjectWebhook creates an instance of ProjectWebhook based on the ProjectWebhookRaw.
@@ -47,6 +49,7 @@ func (raw *ProjectWebhookRaw) ToProjectWebhook() *ProjectWebhook {
 		URL:  raw.URL,
 	}
 	projectWebhook.ActivityList = append(projectWebhook.ActivityList, raw.ActivityList...)
+	projectWebhook.AnomalySeverityList = append(projectWebhook.AnomalySeverityList, raw.AnomalySeverityList...)
 	return &projectWebhook
 }
 
@@ -71,6 +74,8 @@ type ProjectWebhook struct {
 	Name         string   `jsonapi:"attr,name"`
 	URL          string   `jsonapi:"attr,url"`
 	ActivityList []string `jsonapi:"attr,activityList"`
+	// AnomalySeverityList is the anomaly severities posted to the webhook for the anomaly activities.
+	AnomalySeverityList []string `jsonapi:"attr,anomalySeverityList"`
 }
 
 // ProjectWebhookCreate is the API message for creating a project webhook.
@@ -87,6 +92,8 @@ type ProjectWebhookCreate struct {
 	Name         string   `jsonapi:"attr,name"`
 	URL          string   `jsonapi:"attr,url"`
 	ActivityList []string `jsonapi:"attr,activityList"`
+	// AnomalySeverityList is the anomaly severities posted to the webhook for the anomaly activities.
+	AnomalySeverityList []string `jsonapi:"attr,anomalySeverityList"`
 }
 
 // ProjectWebhookFind is the API message for finding project webhooks.
@@ -96,6 +103,8 @@ type ProjectWebhookFind struct {
 	// Related fields
 	ProjectID    *int
 	ActivityType *ActivityType
+	// Only return the webhooks posting anomaly activities of the severity.
+	AnomalySeverity *AnomalySeverity
 }
 
 func (find *ProjectWebhookFind) String() string {
@@ -118,6 +127,8 @@ type ProjectWebhookPatch struct {
 	Name         *string `jsonapi:"attr,name"`
 	URL          *string `jsonapi:"attr,url"`
 	ActivityList *string `jsonapi:"attr,activityList"`
+	// AnomalySeverityList is a comma-separated list of anomaly severities.
+	AnomalySeverityList *string `jsonapi:"attr,anomalySeverityList"`
 }
 
 // ProjectWebhookDelete is the API message for deleting a project webhook.
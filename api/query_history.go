@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// QueryHistoryRaw is the store model for a QueryHistory.
+// Fields have exactly the same meanings as QueryHistory.
+type QueryHistoryRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	DatabaseName  string
+	Statement     string
+	ParameterList []SQLParameter
+	DurationNs    int64
+	Error         string
+}
+
+// ToQueryHistory creates an instance of QueryHistory based on the QueryHistoryRaw.
+// This is intended to be called when we need to compose a QueryHistory relationship.
+func (raw *QueryHistoryRaw) ToQueryHistory() *QueryHistory {
+	return &QueryHistory{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		InstanceID: raw.InstanceID,
+
+		// Domain specific fields
+		DatabaseName:  raw.DatabaseName,
+		Statement:     raw.Statement,
+		ParameterList: raw.ParameterList,
+		DurationNs:    raw.DurationNs,
+		Error:         raw.Error,
+	}
+}
+
+// QueryHistory is the API message for a query executed in the SQL editor.
+// It's only visible to the creator, who can search and re-run it.
+type QueryHistory struct {
+	ID int `jsonapi:"primary,queryHistory"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	InstanceID int `jsonapi:"attr,instanceId"`
+
+	// Domain specific fields
+	// For engines like MySQL, databaseName can be empty.
+	DatabaseName string `jsonapi:"attr,databaseName"`
+	// Statement is the statement as executed, with the named :param placeholders unbound.
+	Statement     string         `jsonapi:"attr,statement"`
+	ParameterList []SQLParameter `jsonapi:"attr,parameterList"`
+	DurationNs    int64          `jsonapi:"attr,durationNs"`
+	// Error is empty if the query succeeded.
+	Error string `jsonapi:"attr,error"`
+}
+
+// QueryHistoryCreate is the API message for creating a query history.
+type QueryHistoryCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	InstanceID int
+
+	// Domain specific fields
+	DatabaseName  string
+	Statement     string
+	ParameterList []SQLParameter
+	DurationNs    int64
+	Error         string
+}
+
+// QueryHistoryFind is the API message for finding query histories, ordered by the execution time descendingly.
+type QueryHistoryFind struct {
+	ID *int
+
+	// Standard fields
+	CreatorID *int
+
+	// Related fields
+	InstanceID *int
+	// Find the query histories of all instances in the environment.
+	EnvironmentID *int
+
+	// Domain specific fields
+	DatabaseName *string
+	// Search matches the statement containing the text case-insensitively.
+	Search         *string
+	CreatedTsAfter *int64
+	Limit          *int
+}
+
+func (find *QueryHistoryFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// QueryHistoryService is the service for query histories.
+type QueryHistoryService interface {
+	CreateQueryHistory(ctx context.Context, create *QueryHistoryCreate) (*QueryHistoryRaw, error)
+	FindQueryHistoryList(ctx context.Context, find *QueryHistoryFind) ([]*QueryHistoryRaw, error)
+	FindQueryHistory(ctx context.Context, find *QueryHistoryFind) (*QueryHistoryRaw, error)
+}
@@ -21,21 +21,22 @@ type RepositoryRaw struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string
-	FullPath           string
-	WebURL             string
-	BranchFilter       string
-	BaseDirectory      string
-	FilePathTemplate   string
-	SchemaPathTemplate string
-	ExternalID         string
-	ExternalWebhookID  string
-	WebhookURLHost     string
-	WebhookEndpointID  string
-	WebhookSecretToken string
-	AccessToken        string
-	ExpiresTs          int64
-	RefreshToken       string
+	Name                   string
+	FullPath               string
+	WebURL                 string
+	BranchFilter           string
+	BaseDirectory          string
+	FilePathTemplate       string
+	SchemaPathTemplate     string
+	DictionaryPathTemplate string
+	ExternalID             string
+	ExternalWebhookID      string
+	WebhookURLHost         string
+	WebhookEndpointID      string
+	WebhookSecretToken     string
+	AccessToken            string
+	ExpiresTs              int64
+	RefreshToken           string
 }
 
 // ToRepository creates an instance of Repository based on the RepositoryRaw.
@@ -52,21 +53,22 @@ func (raw *RepositoryRaw) ToRepository() *Repository {
 		VCSID:     raw.VCSID,
 		ProjectID: raw.ProjectID,
 
-		Name:               raw.Name,
-		FullPath:           raw.FullPath,
-		WebURL:             raw.WebURL,
-		BranchFilter:       raw.BranchFilter,
-		BaseDirectory:      raw.BaseDirectory,
-		FilePathTemplate:   raw.FilePathTemplate,
-		SchemaPathTemplate: raw.SchemaPathTemplate,
-		ExternalID:         raw.ExternalID,
-		ExternalWebhookID:  raw.ExternalWebhookID,
-		WebhookURLHost:     raw.WebhookURLHost,
-		WebhookEndpointID:  raw.WebhookEndpointID,
-		WebhookSecretToken: raw.WebhookSecretToken,
-		AccessToken:        raw.AccessToken,
-		ExpiresTs:          raw.ExpiresTs,
-		RefreshToken:       raw.RefreshToken,
+		Name:                   raw.Name,
+		FullPath:               raw.FullPath,
+		WebURL:                 raw.WebURL,
+		BranchFilter:           raw.BranchFilter,
+		BaseDirectory:          raw.BaseDirectory,
+		FilePathTemplate:       raw.FilePathTemplate,
+		SchemaPathTemplate:     raw.SchemaPathTemplate,
+		DictionaryPathTemplate: raw.DictionaryPathTemplate,
+		ExternalID:             raw.ExternalID,
+		ExternalWebhookID:      raw.ExternalWebhookID,
+		WebhookURLHost:         raw.WebhookURLHost,
+		WebhookEndpointID:      raw.WebhookEndpointID,
+		WebhookSecretToken:     raw.WebhookSecretToken,
+		AccessToken:            raw.AccessToken,
+		ExpiresTs:              raw.ExpiresTs,
+		RefreshToken:           raw.RefreshToken,
 	}
 }
 
@@ -99,11 +101,15 @@ type Repository struct {
 	// The file path template for storing the latest schema auto-generated by Bytebase after migration.
 	// If empty, then Bytebase won't auto generate it.
 	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	ExternalID         string `jsonapi:"attr,externalId"`
-	ExternalWebhookID  string
-	WebhookURLHost     string
-	WebhookEndpointID  string
-	WebhookSecretToken string
+	// The file path template for storing the data dictionary auto-generated by Bytebase after migration.
+	// The file extension decides the format, i.e. ".md", ".html" or ".json".
+	// If empty, then Bytebase won't auto generate it.
+	DictionaryPathTemplate string `jsonapi:"attr,dictionaryPathTemplate"`
+	ExternalID             string `jsonapi:"attr,externalId"`
+	ExternalWebhookID      string
+	WebhookURLHost         string
+	WebhookEndpointID      string
+	WebhookSecretToken     string
 	// These will be exclusively used on the server side and we don't return it to the client.
 	AccessToken  string
 	ExpiresTs    int64
@@ -121,14 +127,15 @@ type RepositoryCreate struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string `jsonapi:"attr,name"`
-	FullPath           string `jsonapi:"attr,fullPath"`
-	WebURL             string `jsonapi:"attr,webUrl"`
-	BranchFilter       string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	ExternalID         string `jsonapi:"attr,externalId"`
+	Name                   string `jsonapi:"attr,name"`
+	FullPath               string `jsonapi:"attr,fullPath"`
+	WebURL                 string `jsonapi:"attr,webUrl"`
+	BranchFilter           string `jsonapi:"attr,branchFilter"`
+	BaseDirectory          string `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate       string `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate     string `jsonapi:"attr,schemaPathTemplate"`
+	DictionaryPathTemplate string `jsonapi:"attr,dictionaryPathTemplate"`
+	ExternalID             string `jsonapi:"attr,externalId"`
 	// Token belonged by the user linking the project to the VCS repository. We store this token together
 	// with the refresh token in the new repository record so we can use it to call VCS API on
 	// behalf of that user to perform tasks like webhook CRUD later.
@@ -170,13 +177,14 @@ type RepositoryPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	BranchFilter       *string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      *string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   *string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate *string `jsonapi:"attr,schemaPathTemplate"`
-	AccessToken        *string
-	ExpiresTs          *int64
-	RefreshToken       *string
+	BranchFilter           *string `jsonapi:"attr,branchFilter"`
+	BaseDirectory          *string `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate       *string `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate     *string `jsonapi:"attr,schemaPathTemplate"`
+	DictionaryPathTemplate *string `jsonapi:"attr,dictionaryPathTemplate"`
+	AccessToken            *string
+	ExpiresTs              *int64
+	RefreshToken           *string
 }
 
 // RepositoryDelete is the API message for deleting a repository.
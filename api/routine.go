@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Routine is the API message for a routine, either a function or a procedure.
+type Routine struct {
+	ID int `jsonapi:"primary,routine"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Name       string `jsonapi:"attr,name"`
+	Type       string `jsonapi:"attr,type"`
+	Definition string `jsonapi:"attr,definition"`
+	Comment    string `jsonapi:"attr,comment"`
+}
+
+// RoutineCreate is the API message for creating a routine, either a function or a procedure.
+type RoutineCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name       string
+	Type       string
+	Definition string
+	Comment    string
+}
+
+// RoutineFind is the API message for finding routines.
+type RoutineFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *RoutineFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// RoutineDelete is the API message for deleting routines.
+type RoutineDelete struct {
+	// Related fields
+	DatabaseID int
+}
+
+// RoutineService is the service for routines.
+type RoutineService interface {
+	CreateRoutine(ctx context.Context, create *RoutineCreate) (*Routine, error)
+	FindRoutineList(ctx context.Context, find *RoutineFind) ([]*Routine, error)
+	DeleteRoutine(ctx context.Context, delete *RoutineDelete) error
+}
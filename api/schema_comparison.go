@@ -0,0 +1,47 @@
+package api
+
+// SchemaComparisonStatus is the status of a database compared with the reference database in its group.
+type SchemaComparisonStatus string
+
+const (
+	// SchemaComparisonInSync is the status when the database has the same schema version and schema as the reference database.
+	SchemaComparisonInSync SchemaComparisonStatus = "IN_SYNC"
+	// SchemaComparisonBehind is the status when the database has an earlier schema version than the reference database.
+	SchemaComparisonBehind SchemaComparisonStatus = "BEHIND"
+	// SchemaComparisonDifferent is the status when the database has the same schema version but a different schema from the reference database.
+	SchemaComparisonDifferent SchemaComparisonStatus = "DIFFERENT"
+)
+
+// SchemaComparisonReport is the API message for comparing the schemas of the same database across environments in a project.
+type SchemaComparisonReport struct {
+	ProjectID int                      `jsonapi:"attr,projectId"`
+	GroupList []*SchemaComparisonGroup `jsonapi:"attr,groupList"`
+}
+
+// SchemaComparisonGroup is the group of databases which are supposed to have the same schema.
+// Databases are grouped by name, or by the base database name across tenants in tenant mode.
+type SchemaComparisonGroup struct {
+	Name string `json:"name"`
+	// ReferenceDatabaseID is the database with the latest schema version, the others are compared against it.
+	// The earliest environment wins if multiple databases have the latest schema version.
+	ReferenceDatabaseID int                      `json:"referenceDatabaseId"`
+	EntryList           []*SchemaComparisonEntry `json:"entryList"`
+}
+
+// SchemaComparisonEntry is a cell in the comparison matrix, which is a database in a group.
+type SchemaComparisonEntry struct {
+	DatabaseID      int    `json:"databaseId"`
+	DatabaseName    string `json:"databaseName"`
+	InstanceID      int    `json:"instanceId"`
+	EnvironmentID   int    `json:"environmentId"`
+	EnvironmentName string `json:"environmentName"`
+	// Deployment and Tenant are only set in tenant mode.
+	Deployment    string                 `json:"deployment,omitempty"`
+	Tenant        string                 `json:"tenant,omitempty"`
+	SchemaVersion string                 `json:"schemaVersion"`
+	SyncStatus    SyncStatus             `json:"syncStatus"`
+	Status        SchemaComparisonStatus `json:"status"`
+	// ItemList is the changes from the reference database to this database,
+	// where Expect is the definition in the reference database and Actual is the one in this database.
+	ItemList []*SchemaDriftItem `json:"itemList"`
+}
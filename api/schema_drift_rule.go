@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SchemaDriftRuleType is the type of a schema drift ignore rule.
+type SchemaDriftRuleType string
+
+const (
+	// SchemaDriftRuleTableGlob ignores the tables and views whose name matches the glob pattern, e.g. "_*_gho".
+	// The indexes, constraints and triggers of the matching tables are ignored as well.
+	SchemaDriftRuleTableGlob SchemaDriftRuleType = "TABLE_GLOB"
+	// SchemaDriftRuleObjectType ignores all objects of the schema drift object type in the pattern, e.g. "TRIGGER".
+	SchemaDriftRuleObjectType SchemaDriftRuleType = "OBJECT_TYPE"
+	// SchemaDriftRuleNormalizer replaces the matches of the regular expression pattern with the replacement
+	// in both the expected and actual schema before comparison, e.g. " AUTO_INCREMENT=\d+" with "".
+	SchemaDriftRuleNormalizer SchemaDriftRuleType = "NORMALIZER"
+)
+
+// SchemaDriftRuleRaw is the store model for a SchemaDriftRule.
+// Fields have exactly the same meanings as SchemaDriftRule.
+type SchemaDriftRuleRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	ProjectID  int
+	DatabaseID *int
+
+	// Domain specific fields
+	Type        SchemaDriftRuleType
+	Pattern     string
+	Replacement string
+}
+
+// ToSchemaDriftRule creates an instance of SchemaDriftRule based on the SchemaDriftRuleRaw.
+// This is intended to be called when we need to compose a SchemaDriftRule relationship.
+func (raw *SchemaDriftRuleRaw) ToSchemaDriftRule() *SchemaDriftRule {
+	return &SchemaDriftRule{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		ProjectID:  raw.ProjectID,
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Type:        raw.Type,
+		Pattern:     raw.Pattern,
+		Replacement: raw.Replacement,
+	}
+}
+
+// SchemaDriftRule is the API message for a schema drift ignore rule.
+type SchemaDriftRule struct {
+	ID int `jsonapi:"primary,schemaDriftRule"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	// Just returns ProjectID since it always operates within the project context
+	ProjectID int `jsonapi:"attr,projectId"`
+	// The rule applies to all databases in the project if DatabaseID is nil
+	DatabaseID *int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Type        SchemaDriftRuleType `jsonapi:"attr,type"`
+	Pattern     string              `jsonapi:"attr,pattern"`
+	Replacement string              `jsonapi:"attr,replacement"`
+}
+
+// SchemaDriftRuleCreate is the API message for creating a schema drift ignore rule.
+type SchemaDriftRuleCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	ProjectID  int
+	DatabaseID *int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Type        SchemaDriftRuleType `jsonapi:"attr,type"`
+	Pattern     string              `jsonapi:"attr,pattern"`
+	Replacement string              `jsonapi:"attr,replacement"`
+}
+
+// SchemaDriftRuleFind is the API message for finding schema drift ignore rules.
+type SchemaDriftRuleFind struct {
+	ID *int
+
+	// Related fields
+	ProjectID *int
+	// Find the rules applying to the database, that is the rules of the database and the project-wide rules.
+	// Only applicable if ProjectID is specified.
+	DatabaseID *int
+}
+
+func (find *SchemaDriftRuleFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SchemaDriftRulePatch is the API message for patching a schema drift ignore rule.
+type SchemaDriftRulePatch struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Pattern     *string `jsonapi:"attr,pattern"`
+	Replacement *string `jsonapi:"attr,replacement"`
+}
+
+// SchemaDriftRuleDelete is the API message for deleting a schema drift ignore rule.
+type SchemaDriftRuleDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// SchemaDriftRuleService is the service for schema drift ignore rules.
+type SchemaDriftRuleService interface {
+	CreateSchemaDriftRule(ctx context.Context, create *SchemaDriftRuleCreate) (*SchemaDriftRuleRaw, error)
+	FindSchemaDriftRuleList(ctx context.Context, find *SchemaDriftRuleFind) ([]*SchemaDriftRuleRaw, error)
+	FindSchemaDriftRule(ctx context.Context, find *SchemaDriftRuleFind) (*SchemaDriftRuleRaw, error)
+	PatchSchemaDriftRule(ctx context.Context, patch *SchemaDriftRulePatch) (*SchemaDriftRuleRaw, error)
+	DeleteSchemaDriftRule(ctx context.Context, delete *SchemaDriftRuleDelete) error
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// SchemaSearchMode is the matching mode of the schema search query.
+type SchemaSearchMode string
+
+const (
+	// SchemaSearchWildcard matches the whole text case-insensitively, where "*" matches any characters and "?" matches a single character.
+	SchemaSearchWildcard SchemaSearchMode = "WILDCARD"
+	// SchemaSearchRegex matches any part of the text with the case-insensitive regular expression.
+	SchemaSearchRegex SchemaSearchMode = "REGEX"
+)
+
+// SchemaSearchObjectType is the type of the synced schema object to search.
+type SchemaSearchObjectType string
+
+const (
+	// SchemaSearchTable searches the table names.
+	SchemaSearchTable SchemaSearchObjectType = "TABLE"
+	// SchemaSearchColumn searches the column names, types and comments.
+	SchemaSearchColumn SchemaSearchObjectType = "COLUMN"
+	// SchemaSearchIndex searches the index names.
+	SchemaSearchIndex SchemaSearchObjectType = "INDEX"
+	// SchemaSearchView searches the view names.
+	SchemaSearchView SchemaSearchObjectType = "VIEW"
+)
+
+// SchemaSearchField is the field of the schema object matching the query.
+type SchemaSearchField string
+
+const (
+	// SchemaSearchName is the object name.
+	SchemaSearchName SchemaSearchField = "NAME"
+	// SchemaSearchType is the column type.
+	SchemaSearchType SchemaSearchField = "TYPE"
+	// SchemaSearchComment is the column comment.
+	SchemaSearchComment SchemaSearchField = "COMMENT"
+)
+
+// SchemaSearchFind is the API message for searching the synced schema objects across the workspace.
+type SchemaSearchFind struct {
+	Query string
+	Mode  SchemaSearchMode
+	// ObjectTypeList searches all object types if empty.
+	ObjectTypeList []SchemaSearchObjectType
+
+	// Related fields
+	ProjectID     *int
+	EnvironmentID *int
+	InstanceID    *int
+
+	// Domain specific fields
+	Engine *db.Type
+	Limit  int
+}
+
+// SchemaSearchResult is the API message for the schema search result.
+type SchemaSearchResult struct {
+	Query     string               `jsonapi:"attr,query"`
+	Mode      SchemaSearchMode     `jsonapi:"attr,mode"`
+	MatchList []*SchemaSearchMatch `jsonapi:"attr,matchList"`
+	// Truncated is true if there are more matches than the limit.
+	Truncated bool `jsonapi:"attr,truncated"`
+}
+
+// SchemaSearchMatch is a schema object matching the query.
+type SchemaSearchMatch struct {
+	DatabaseID      int     `json:"databaseId"`
+	DatabaseName    string  `json:"databaseName"`
+	ProjectID       int     `json:"projectId"`
+	ProjectName     string  `json:"projectName"`
+	EnvironmentID   int     `json:"environmentId"`
+	EnvironmentName string  `json:"environmentName"`
+	InstanceID      int     `json:"instanceId"`
+	InstanceName    string  `json:"instanceName"`
+	Engine          db.Type `json:"engine"`
+
+	ObjectType SchemaSearchObjectType `json:"objectType"`
+	// TableName is the table of the column or index.
+	TableName string            `json:"tableName,omitempty"`
+	Name      string            `json:"name"`
+	Field     SchemaSearchField `json:"field"`
+	// Value is the text of the field matching the query.
+	Value string `json:"value"`
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SchemaSnapshotRaw is the store model for a SchemaSnapshot.
+// Fields have exactly the same meanings as SchemaSnapshot.
+type SchemaSnapshotRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	SchemaVersion string
+	Checksum      string
+	Metadata      string
+}
+
+// ToSchemaSnapshot creates an instance of SchemaSnapshot based on the SchemaSnapshotRaw.
+// This is intended to be called when we need to compose a SchemaSnapshot relationship.
+func (raw *SchemaSnapshotRaw) ToSchemaSnapshot() *SchemaSnapshot {
+	return &SchemaSnapshot{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		SchemaVersion: raw.SchemaVersion,
+		Checksum:      raw.Checksum,
+		Metadata:      raw.Metadata,
+	}
+}
+
+// SchemaSnapshot is the API message for a schema snapshot of a database.
+// A snapshot is taken after a schema sync or migration only if the schema changes since the latest snapshot.
+type SchemaSnapshot struct {
+	ID int `jsonapi:"primary,schemaSnapshot"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// SchemaVersion is the version of the latest migration history when the snapshot is taken.
+	SchemaVersion string `jsonapi:"attr,schemaVersion"`
+	// Checksum is the SHA-256 checksum of the metadata.
+	Checksum string `jsonapi:"attr,checksum"`
+	// Metadata is the JSON encoded SchemaSnapshotMetadata, omitted in the snapshot list.
+	Metadata string `jsonapi:"attr,metadata"`
+}
+
+// SchemaSnapshotMetadata is the schema metadata in a snapshot.
+// The statistics such as the row count are excluded, since they change without a schema change.
+type SchemaSnapshotMetadata struct {
+	CharacterSet string                 `json:"characterSet,omitempty"`
+	Collation    string                 `json:"collation,omitempty"`
+	TableList    []*SchemaSnapshotTable `json:"tableList"`
+	ViewList     []*SchemaSnapshotView  `json:"viewList"`
+}
+
+// SchemaSnapshotTable is the table in a schema snapshot.
+type SchemaSnapshotTable struct {
+	Name          string                  `json:"name"`
+	Type          string                  `json:"type,omitempty"`
+	Engine        string                  `json:"engine,omitempty"`
+	Collation     string                  `json:"collation,omitempty"`
+	CreateOptions string                  `json:"createOptions,omitempty"`
+	Comment       string                  `json:"comment,omitempty"`
+	ColumnList    []*SchemaSnapshotColumn `json:"columnList"`
+	IndexList     []*SchemaSnapshotIndex  `json:"indexList"`
+}
+
+// SchemaSnapshotColumn is the table column in a schema snapshot.
+type SchemaSnapshotColumn struct {
+	Name         string  `json:"name"`
+	Position     int     `json:"position"`
+	Default      *string `json:"default,omitempty"`
+	Nullable     bool    `json:"nullable"`
+	Type         string  `json:"type"`
+	CharacterSet string  `json:"characterSet,omitempty"`
+	Collation    string  `json:"collation,omitempty"`
+	Comment      string  `json:"comment,omitempty"`
+}
+
+// SchemaSnapshotIndex is the table index in a schema snapshot.
+// There is one entry for each expression of the index, ordered by the position.
+type SchemaSnapshotIndex struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Position   int    `json:"position"`
+	Type       string `json:"type,omitempty"`
+	Unique     bool   `json:"unique"`
+	Visible    bool   `json:"visible"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// SchemaSnapshotView is the view in a schema snapshot.
+type SchemaSnapshotView struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// SchemaSnapshotCreate is the API message for creating a schema snapshot.
+type SchemaSnapshotCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	SchemaVersion string
+	Checksum      string
+	Metadata      string
+}
+
+// SchemaSnapshotFind is the API message for finding schema snapshots.
+// The snapshots are ordered from the latest to the earliest.
+type SchemaSnapshotFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Limit *int
+}
+
+func (find *SchemaSnapshotFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SchemaSnapshotDiff is the API message for the difference between two schema snapshots.
+type SchemaSnapshotDiff struct {
+	FromSnapshotID int `jsonapi:"attr,fromSnapshotId"`
+	ToSnapshotID   int `jsonapi:"attr,toSnapshotId"`
+	// ItemList is the structured changes from the "from" snapshot to the "to" snapshot,
+	// where Expect is the definition in the "from" snapshot and Actual is the one in the "to" snapshot.
+	ItemList []*SchemaDriftItem `jsonapi:"attr,itemList"`
+	// Diff is the unified text diff from the "from" snapshot to the "to" snapshot.
+	Diff string `jsonapi:"attr,diff"`
+}
+
+// SchemaSnapshotService is the service for schema snapshots.
+type SchemaSnapshotService interface {
+	CreateSchemaSnapshot(ctx context.Context, create *SchemaSnapshotCreate) (*SchemaSnapshotRaw, error)
+	FindSchemaSnapshotList(ctx context.Context, find *SchemaSnapshotFind) ([]*SchemaSnapshotRaw, error)
+	FindSchemaSnapshot(ctx context.Context, find *SchemaSnapshotFind) (*SchemaSnapshotRaw, error)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bytebase/bytebase/plugin/db/masking"
+)
+
+// SensitiveColumnRaw is the store model for a SensitiveColumn.
+// Fields have exactly the same meanings as SensitiveColumn.
+type SensitiveColumnRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	TableName   string
+	ColumnName  string
+	MaskingType masking.Type
+}
+
+// ToSensitiveColumn creates an instance of SensitiveColumn based on the SensitiveColumnRaw.
+// This is intended to be called when we need to compose a SensitiveColumn relationship.
+func (raw *SensitiveColumnRaw) ToSensitiveColumn() *SensitiveColumn {
+	return &SensitiveColumn{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		TableName:   raw.TableName,
+		ColumnName:  raw.ColumnName,
+		MaskingType: raw.MaskingType,
+	}
+}
+
+// SensitiveColumn is the API message for a column marked sensitive.
+// Its values are masked in the SQL editor query and export results unless the caller's role is exempted
+// by the data masking policy of the environment.
+type SensitiveColumn struct {
+	ID int `jsonapi:"primary,sensitiveColumn"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	// Just returns DatabaseID since it always operates within the database context
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// TableName is qualified by the schema for Postgres, e.g. "public.user".
+	TableName   string       `jsonapi:"attr,tableName"`
+	ColumnName  string       `jsonapi:"attr,columnName"`
+	MaskingType masking.Type `jsonapi:"attr,maskingType"`
+}
+
+// SensitiveColumnUpsert is the API message for marking a column sensitive.
+// The masking type is updated if the column is already marked.
+type SensitiveColumnUpsert struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	TableName   string       `jsonapi:"attr,tableName"`
+	ColumnName  string       `jsonapi:"attr,columnName"`
+	MaskingType masking.Type `jsonapi:"attr,maskingType"`
+}
+
+// SensitiveColumnFind is the API message for finding sensitive columns.
+type SensitiveColumnFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+	// Find the sensitive columns of all databases in the instance.
+	InstanceID *int
+}
+
+func (find *SensitiveColumnFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SensitiveColumnDelete is the API message for unmarking a sensitive column.
+type SensitiveColumnDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// SensitiveColumnService is the service for sensitive columns.
+type SensitiveColumnService interface {
+	UpsertSensitiveColumn(ctx context.Context, upsert *SensitiveColumnUpsert) (*SensitiveColumnRaw, error)
+	FindSensitiveColumnList(ctx context.Context, find *SensitiveColumnFind) ([]*SensitiveColumnRaw, error)
+	FindSensitiveColumn(ctx context.Context, find *SensitiveColumnFind) (*SensitiveColumnRaw, error)
+	DeleteSensitiveColumn(ctx context.Context, delete *SensitiveColumnDelete) error
+}
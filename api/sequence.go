@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Sequence is the API message for a sequence.
+type Sequence struct {
+	ID int `jsonapi:"primary,sequence"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	Database   *Database `jsonapi:"relation,database"`
+
+	// Domain specific fields
+	Name       string `jsonapi:"attr,name"`
+	DataType   string `jsonapi:"attr,dataType"`
+	StartValue string `jsonapi:"attr,startValue"`
+	Increment  string `jsonapi:"attr,increment"`
+	MinValue   string `jsonapi:"attr,minValue"`
+	MaxValue   string `jsonapi:"attr,maxValue"`
+	Cycle      bool   `jsonapi:"attr,cycle"`
+	CacheSize  string `jsonapi:"attr,cacheSize"`
+}
+
+// SequenceCreate is the API message for creating a sequence.
+type SequenceCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Name       string
+	DataType   string
+	StartValue string
+	Increment  string
+	MinValue   string
+	MaxValue   string
+	Cycle      bool
+	CacheSize  string
+}
+
+// SequenceFind is the API message for finding sequences.
+type SequenceFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *SequenceFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SequenceDelete is the API message for deleting sequences.
+type SequenceDelete struct {
+	// Related fields
+	DatabaseID int
+}
+
+// SequenceService is the service for sequences.
+type SequenceService interface {
+	CreateSequence(ctx context.Context, create *SequenceCreate) (*Sequence, error)
+	FindSequenceList(ctx context.Context, find *SequenceFind) ([]*Sequence, error)
+	DeleteSequence(ctx context.Context, delete *SequenceDelete) error
+}
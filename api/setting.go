@@ -11,8 +11,14 @@ type SettingName string
 const (
 	// SettingAuthSecret is the setting name for auth secret.
 	SettingAuthSecret SettingName = "bb.auth.secret"
+	// SettingBackupStorage is the setting name for the backup storage.
+	// Its value holds secrets, see BackupStorageSetting.
+	SettingBackupStorage SettingName = "bb.backup.storage"
 )
 
+// RedactedSecret replaces the secrets in the setting values returned to the client.
+const RedactedSecret = "******"
+
 // SettingRaw is the store model for an Setting.
 // Fields have exactly the same meanings as Setting.
 type SettingRaw struct {
@@ -104,6 +104,8 @@ type Sheet struct {
 	Name       string          `jsonapi:"attr,name"`
 	Statement  string          `jsonapi:"attr,statement"`
 	Visibility SheetVisibility `jsonapi:"attr,visibility"`
+	// ParameterList is the names of the :param placeholders in the statement, whose values are bound when the sheet is executed.
+	ParameterList []string `jsonapi:"attr,parameterList"`
 }
 
 // SheetCreate is the API message for creating a sheet.
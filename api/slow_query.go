@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SlowQueryRaw is the store model for a SlowQuery.
+// Fields have exactly the same meanings as SlowQuery.
+type SlowQueryRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	SchemaVersion  string
+	Digest         string
+	Statement      string
+	Calls          int64
+	TotalLatencyUs int64
+	RowsExamined   int64
+}
+
+// ToSlowQuery creates an instance of SlowQuery based on the SlowQueryRaw.
+// This is intended to be called when we need to compose a SlowQuery relationship.
+func (raw *SlowQueryRaw) ToSlowQuery() *SlowQuery {
+	return &SlowQuery{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		SchemaVersion:  raw.SchemaVersion,
+		Digest:         raw.Digest,
+		Statement:      raw.Statement,
+		Calls:          raw.Calls,
+		TotalLatencyUs: raw.TotalLatencyUs,
+		RowsExamined:   raw.RowsExamined,
+	}
+}
+
+// SlowQuery is the API message for the statistics of a top statement of a database within a collection interval,
+// which ends at the creation time.
+type SlowQuery struct {
+	ID int `jsonapi:"primary,slowQuery"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	UpdatedTs int64 `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	// SchemaVersion is the schema version of the database when the statistics are collected.
+	SchemaVersion string `jsonapi:"attr,schemaVersion"`
+	// Digest identifies the normalized statement within the database.
+	Digest         string `jsonapi:"attr,digest"`
+	Statement      string `jsonapi:"attr,statement"`
+	Calls          int64  `jsonapi:"attr,calls"`
+	TotalLatencyUs int64  `jsonapi:"attr,totalLatencyUs"`
+	// RowsExamined is the rows read for MySQL, and the rows retrieved or affected for Postgres.
+	RowsExamined int64 `jsonapi:"attr,rowsExamined"`
+}
+
+// SlowQueryCreate is the API message for creating a slow query.
+type SlowQueryCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	SchemaVersion  string
+	Digest         string
+	Statement      string
+	Calls          int64
+	TotalLatencyUs int64
+	RowsExamined   int64
+}
+
+// SlowQueryFind is the API message for finding slow queries.
+// The slow queries are ordered from the latest to the earliest.
+type SlowQueryFind struct {
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Digest *string
+	// The slow queries collected within [CreatedTsAfter, CreatedTsBefore).
+	CreatedTsAfter  *int64
+	CreatedTsBefore *int64
+	Limit           *int
+}
+
+func (find *SlowQueryFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// SlowQueryDelete is the API message for deleting the slow queries collected before a given time.
+type SlowQueryDelete struct {
+	CreatedTsBefore int64
+}
+
+// SlowQueryReport is the API message for the top statements of a database within a time window.
+type SlowQueryReport struct {
+	DatabaseID int                    `jsonapi:"attr,databaseId"`
+	FromTs     int64                  `jsonapi:"attr,fromTs"`
+	ToTs       int64                  `jsonapi:"attr,toTs"`
+	ItemList   []*SlowQueryReportItem `jsonapi:"attr,itemList"`
+}
+
+// SlowQueryReportItem is the API message for the statistics of a statement summed up within the report window.
+type SlowQueryReportItem struct {
+	Digest         string `json:"digest"`
+	Statement      string `json:"statement"`
+	Calls          int64  `json:"calls"`
+	TotalLatencyUs int64  `json:"totalLatencyUs"`
+	MeanLatencyUs  int64  `json:"meanLatencyUs"`
+	RowsExamined   int64  `json:"rowsExamined"`
+	// The schema version when the statement is collected the last time
+	SchemaVersion string `json:"schemaVersion"`
+	LastSeenTs    int64  `json:"lastSeenTs"`
+}
+
+// SlowQueryTrend is the API message for the statistics of the top statements of a database over time.
+type SlowQueryTrend struct {
+	DatabaseID int `jsonapi:"attr,databaseId"`
+	// The digest of the statement, or empty for all the top statements
+	Digest    string                 `jsonapi:"attr,digest"`
+	PointList []*SlowQueryTrendPoint `jsonapi:"attr,pointList"`
+}
+
+// SlowQueryTrendPoint is the API message for the statistics within a collection interval ending at Ts.
+type SlowQueryTrendPoint struct {
+	Ts             int64  `json:"ts"`
+	SchemaVersion  string `json:"schemaVersion"`
+	Calls          int64  `json:"calls"`
+	TotalLatencyUs int64  `json:"totalLatencyUs"`
+	MeanLatencyUs  int64  `json:"meanLatencyUs"`
+	RowsExamined   int64  `json:"rowsExamined"`
+}
+
+// SlowQueryService is the service for slow queries.
+type SlowQueryService interface {
+	// CreateSlowQueryList creates the slow queries in a single transaction, so they share the same creation time.
+	CreateSlowQueryList(ctx context.Context, createList []*SlowQueryCreate) ([]*SlowQueryRaw, error)
+	FindSlowQueryList(ctx context.Context, find *SlowQueryFind) ([]*SlowQueryRaw, error)
+	// DeleteSlowQuery deletes the slow queries collected before the given time, and returns the number of deleted slow queries.
+	DeleteSlowQuery(ctx context.Context, delete *SlowQueryDelete) (int64, error)
+}
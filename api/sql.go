@@ -34,6 +34,39 @@ type SQLExecute struct {
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+	// Explain returns the estimated execution plan of the SELECT statement instead of running it.
+	// Only MySQL and Postgres are supported.
+	Explain bool `jsonapi:"attr,explain"`
+	// The values bound to the named :param placeholders of the statement, e.g. the parameterized sheet statement.
+	// Every placeholder must have a value, and the values are passed to the database as query arguments.
+	ParameterList []SQLParameter `jsonapi:"attr,parameterList"`
+	// QueryID identifies the query to cancel it while it's running, or to fetch its next pages. It's generated if empty.
+	// The client can assign it to cancel the query before the response.
+	QueryID string `jsonapi:"attr,queryId"`
+	// If pageSize > 0, only the first page of at most pageSize rows is returned, and the next pages are fetched
+	// by the query ID without re-running the query, until Limit rows in total.
+	PageSize int `jsonapi:"attr,pageSize"`
+}
+
+// SQLParameter is the API message for the value of a named :param placeholder.
+// The json tags are used when it's marshalled as an attribute, e.g. in the query history.
+type SQLParameter struct {
+	Name  string `jsonapi:"attr,name" json:"name"`
+	Value string `jsonapi:"attr,value" json:"value"`
+}
+
+// SQLExport is the API message for exporting the result of a readonly / SELECT query.
+// The result is streamed as a file attachment instead of a JSON:API payload.
+type SQLExport struct {
+	InstanceID int `jsonapi:"attr,instanceId"`
+	// For engines like MySQL, databaseName can be empty.
+	DatabaseName string `jsonapi:"attr,databaseName"`
+	Statement    string `jsonapi:"attr,statement"`
+	// One of CSV, JSONL and XLSX, case-insensitive.
+	Format string `jsonapi:"attr,format"`
+	// The maximum row count exported, capped by the SQL export policy of the instance environment.
+	// The policy limit is used if limit <= 0.
+	Limit int `jsonapi:"attr,limit"`
 }
 
 // SQLResultSet is the API message for SQL results.
@@ -42,6 +75,28 @@ type SQLResultSet struct {
 	Data string `jsonapi:"attr,data"`
 	// SQL operation may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
 	Error string `jsonapi:"attr,error"`
+	// The normalized execution plan in the explain mode, where Data is the plan in the JSON format of the engine.
+	Plan *SQLPlanNode `jsonapi:"attr,plan,omitempty"`
+	// The tables read by full table scans in the plan, only in the explain mode.
+	FullTableScanList []string `jsonapi:"attr,fullTableScanList,omitempty"`
+	// The ID of the query, not set in the explain mode.
+	QueryID string `jsonapi:"attr,queryId,omitempty"`
+	// HasMore is true if there are more rows to fetch by the query ID, only if pageSize > 0.
+	HasMore bool `jsonapi:"attr,hasMore"`
+}
+
+// SQLPlanNode is the API message for a node of the execution plan normalized across the engines.
+type SQLPlanNode struct {
+	// The operation of the node, e.g. "Full Table Scan" for MySQL or "Seq Scan" for Postgres
+	NodeType string `json:"nodeType"`
+	Table    string `json:"table,omitempty"`
+	Index    string `json:"index,omitempty"`
+	// The estimated rows examined by the node, 0 if unknown
+	EstimatedRows float64 `json:"estimatedRows"`
+	// The estimated cost in the unit of the engine, which isn't comparable across the engines
+	Cost          float64        `json:"cost"`
+	FullTableScan bool           `json:"fullTableScan"`
+	ChildList     []*SQLPlanNode `json:"childList,omitempty"`
 }
 
 // SQLService is the service for SQL.
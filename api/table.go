@@ -79,18 +79,21 @@ type Table struct {
 	Database   *Database `jsonapi:"relation,database"`
 
 	// Domain specific fields
-	Name          string    `jsonapi:"attr,name"`
-	Type          string    `jsonapi:"attr,type"`
-	Engine        string    `jsonapi:"attr,engine"`
-	Collation     string    `jsonapi:"attr,collation"`
-	RowCount      int64     `jsonapi:"attr,rowCount"`
-	DataSize      int64     `jsonapi:"attr,dataSize"`
-	IndexSize     int64     `jsonapi:"attr,indexSize"`
-	DataFree      int64     `jsonapi:"attr,dataFree"`
-	CreateOptions string    `jsonapi:"attr,createOptions"`
-	Comment       string    `jsonapi:"attr,comment"`
-	ColumnList    []*Column `jsonapi:"attr,columnList"`
-	IndexList     []*Index  `jsonapi:"attr,indexList"`
+	Name                string             `jsonapi:"attr,name"`
+	Type                string             `jsonapi:"attr,type"`
+	Engine              string             `jsonapi:"attr,engine"`
+	Collation           string             `jsonapi:"attr,collation"`
+	RowCount            int64              `jsonapi:"attr,rowCount"`
+	DataSize            int64              `jsonapi:"attr,dataSize"`
+	IndexSize           int64              `jsonapi:"attr,indexSize"`
+	DataFree            int64              `jsonapi:"attr,dataFree"`
+	CreateOptions       string             `jsonapi:"attr,createOptions"`
+	Comment             string             `jsonapi:"attr,comment"`
+	ColumnList          []*Column          `jsonapi:"attr,columnList"`
+	IndexList           []*Index           `jsonapi:"attr,indexList"`
+	ForeignKeyList      []*ForeignKey      `jsonapi:"attr,foreignKeyList"`
+	CheckConstraintList []*CheckConstraint `jsonapi:"attr,checkConstraintList"`
+	TriggerList         []*Trigger         `jsonapi:"attr,triggerList"`
 }
 
 // TableCreate is the API message for creating a table.
@@ -119,13 +122,19 @@ type TableCreate struct {
 
 // TableFind is the API message for finding tables.
 type TableFind struct {
-	ID *int
+	ID     *int
+	IDList *[]int
 
 	// Related fields
-	DatabaseID *int
+	DatabaseID     *int
+	DatabaseIDList *[]int
 
 	// Domain specific fields
 	Name *string
+	// NamePattern is the case-insensitive POSIX regular expression matching the name.
+	NamePattern *string
+	// If specified, then it will only fetch "Limit" tables
+	Limit *int
 }
 
 func (find *TableFind) String() string {
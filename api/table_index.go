@@ -25,6 +25,7 @@ type Index struct {
 	Position   int    `json:"position"`
 	Type       string `json:"type"`
 	Unique     bool   `json:"unique"`
+	Primary    bool   `json:"primary"`
 	Visible    bool   `json:"visible"`
 	Comment    string `json:"comment"`
 }
@@ -45,6 +46,7 @@ type IndexCreate struct {
 	Position   int
 	Type       string
 	Unique     bool
+	Primary    bool
 	Visible    bool
 	Comment    string
 }
@@ -54,12 +56,17 @@ type IndexFind struct {
 	ID *int
 
 	// Related fields
-	DatabaseID *int
-	TableID    *int
+	DatabaseID     *int
+	DatabaseIDList *[]int
+	TableID        *int
 
 	// Domain specific fields
 	Name       *string
 	Expression *string
+	// NamePattern is the case-insensitive POSIX regular expression matching the name.
+	NamePattern *string
+	// If specified, then it will only fetch "Limit" index rows, one for each expression
+	Limit *int
 }
 
 func (find *IndexFind) String() string {
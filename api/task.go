@@ -113,6 +113,9 @@ type TaskDatabaseRestorePayload struct {
 	// and don't have the database id upon constructing the task yet.
 	DatabaseName string `json:"databaseName,omitempty"`
 	BackupID     int    `json:"backupId,omitempty"`
+	// PointInTimeTs is the unix timestamp up to which the binlog is replayed after restoring the backup.
+	// 0 means restoring the backup only.
+	PointInTimeTs int64 `json:"pointInTimeTs,omitempty"`
 }
 
 // TaskRaw is the store model for an Task.
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Trigger is the API message for a trigger.
+type Trigger struct {
+	ID int `jsonapi:"primary,trigger"`
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name      string `json:"name"`
+	Timing    string `json:"timing"`
+	Event     string `json:"event"`
+	Statement string `json:"statement"`
+}
+
+// TriggerCreate is the API message for creating a trigger.
+type TriggerCreate struct {
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+	TableID    int
+
+	// Domain specific fields
+	Name      string
+	Timing    string
+	Event     string
+	Statement string
+}
+
+// TriggerFind is the API message for finding triggers.
+type TriggerFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+	TableID    *int
+
+	// Domain specific fields
+	Name *string
+}
+
+func (find *TriggerFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// TriggerService is the service for triggers.
+type TriggerService interface {
+	CreateTrigger(ctx context.Context, create *TriggerCreate) (*Trigger, error)
+	FindTriggerList(ctx context.Context, find *TriggerFind) ([]*Trigger, error)
+}
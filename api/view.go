@@ -49,10 +49,15 @@ type ViewFind struct {
 	ID *int
 
 	// Related fields
-	DatabaseID *int
+	DatabaseID     *int
+	DatabaseIDList *[]int
 
 	// Domain specific fields
 	Name *string
+	// NamePattern is the case-insensitive POSIX regular expression matching the name.
+	NamePattern *string
+	// If specified, then it will only fetch "Limit" views
+	Limit *int
 }
 
 func (find *ViewFind) String() string {
@@ -0,0 +1,132 @@
+// Package cmd is the command surface of Bytebase bb tool provided by bytebase.com.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/dictionary"
+
+	// install mysql driver.
+	_ "github.com/bytebase/bytebase/plugin/db/mysql"
+	"github.com/spf13/cobra"
+)
+
+func newDictionaryCmd() *cobra.Command {
+	var (
+		databaseType string
+		username     string
+		password     string
+		hostname     string
+		port         string
+		database     string
+		format       string
+		file         string
+
+		// SSL flags.
+		sslCA   string // server-ca.pem
+		sslCert string // client-cert.pem
+		sslKey  string // client-key.pem
+	)
+	dictionaryCmd := &cobra.Command{
+		Use:   "dictionary",
+		Short: "Exports the data dictionary of a database instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tlsCfg := db.TLSConfig{
+				SslCA:   sslCA,
+				SslCert: sslCert,
+				SslKey:  sslKey,
+			}
+			f, err := dictionary.ParseFormat(format)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if file != "" {
+				fd, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("failed to create data dictionary file %s, got error: %w", file, err)
+				}
+				defer fd.Close()
+				out = fd
+			}
+			return exportDictionary(context.Background(), databaseType, username, password, hostname, port, database, out, tlsCfg, f)
+		},
+	}
+	dictionaryCmd.Flags().StringVar(&databaseType, "type", "mysql", "Database type. (mysql or pg).")
+	dictionaryCmd.Flags().StringVar(&username, "username", "", "Username to login database. (default mysql:root pg:postgres).")
+	dictionaryCmd.Flags().StringVar(&password, "password", "", "Password to login database.")
+	dictionaryCmd.Flags().StringVar(&hostname, "hostname", "", "Hostname of database.")
+	dictionaryCmd.Flags().StringVar(&port, "port", "", "Port of database. (default mysql:3306 pg:5432).")
+	dictionaryCmd.Flags().StringVar(&database, "database", "", "Database to export. Export all databases of the instance if unspecified.")
+	dictionaryCmd.Flags().StringVar(&format, "format", "markdown", "Format of the data dictionary. (markdown, html or json).")
+	dictionaryCmd.Flags().StringVar(&file, "file", "", "File to store the data dictionary. Output to stdout if unspecified")
+
+	// tls flags for SSL connection.
+	dictionaryCmd.Flags().StringVar(&sslCA, "ssl-ca", "", "CA file in PEM format.")
+	dictionaryCmd.Flags().StringVar(&sslCert, "ssl-cert", "", "X509 cert in PEM format.")
+	dictionaryCmd.Flags().StringVar(&sslKey, "ssl-key", "", "X509 key in PEM format.")
+
+	return dictionaryCmd
+}
+
+// exportDictionary exports the data dictionary of the database, or all databases of the instance if database is empty.
+func exportDictionary(ctx context.Context, databaseType, username, password, hostname, port, database string, out io.Writer, tlsCfg db.TLSConfig, format dictionary.Format) error {
+	var dbType db.Type
+	switch databaseType {
+	case "mysql":
+		dbType = db.MySQL
+		if username == "" {
+			username = "root"
+		}
+	case "pg":
+		dbType = db.Postgres
+	default:
+		return fmt.Errorf("database type %q not supported; supported types: mysql, pg", databaseType)
+	}
+
+	driver, err := db.Open(ctx, dbType, db.DriverConfig{Logger: logger}, db.ConnectionConfig{
+		Host:      hostname,
+		Port:      port,
+		Username:  username,
+		Password:  password,
+		Database:  database,
+		TLSConfig: tlsCfg,
+	}, db.ConnectionContext{})
+	if err != nil {
+		return fmt.Errorf("failed to open database, got error: %w", err)
+	}
+	defer driver.Close(ctx)
+
+	var databaseList []string
+	if database != "" {
+		databaseList = append(databaseList, database)
+	}
+	schemaList, err := driver.SyncSchema(ctx, databaseList...)
+	if err != nil {
+		return fmt.Errorf("failed to sync schema, got error: %w", err)
+	}
+
+	title := fmt.Sprintf("Data dictionary of %s", hostname)
+	if database != "" {
+		title = fmt.Sprintf("Data dictionary of %s", database)
+	}
+	dict := &dictionary.Dictionary{Title: title}
+	for _, schema := range schemaList {
+		if database != "" && schema.Name != database {
+			continue
+		}
+		dict.DatabaseList = append(dict.DatabaseList, dictionary.NewDatabase(schema))
+	}
+	if database != "" && len(dict.DatabaseList) == 0 {
+		return fmt.Errorf("database %q not found", database)
+	}
+
+	if err := dictionary.Render(out, format, dict); err != nil {
+		return fmt.Errorf("failed to render data dictionary, got error: %w", err)
+	}
+	return nil
+}
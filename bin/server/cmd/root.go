@@ -16,6 +16,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	enterprise "github.com/bytebase/bytebase/enterprise/service"
 	dbdriver "github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/resources/mysqlutil"
 	"github.com/bytebase/bytebase/resources/postgres"
 	"github.com/bytebase/bytebase/server"
 	"github.com/bytebase/bytebase/store"
@@ -89,6 +90,8 @@ var (
 	frontendHost string
 	frontendPort int
 	dataDir      string
+	// mysqlBinDir is the directory of the MySQL client utilities used by the point-in-time recovery for MySQL.
+	mysqlBinDir string
 	// When we are running in readonly mode:
 	// - The data file will be opened in readonly mode, no applicable migration or seeding will be applied.
 	// - Requests other than GET will be rejected
@@ -126,6 +129,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&frontendHost, "frontend-host", "", "host where Bytebase frontend is accessed from, must start with http:// or https://. This is used by Bytebase to compose the frontend link when posting the webhook event. Default is the same as --host")
 	rootCmd.PersistentFlags().IntVar(&frontendPort, "frontend-port", 0, "port where Bytebase frontend is accessed from. This is used by Bytebase to compose the frontend link when posting the webhook event. Default is the same as --port")
 	rootCmd.PersistentFlags().StringVar(&dataDir, "data", ".", "directory where Bytebase stores data. If relative path is supplied, then the path is relative to the directory where bytebase is under")
+	rootCmd.PersistentFlags().StringVar(&mysqlBinDir, "mysql-bin-dir", "", "directory of the MySQL client utilities mysqlbinlog and mysql used by the point-in-time recovery for MySQL. Default is the directory of mysqlbinlog on PATH")
 	rootCmd.PersistentFlags().BoolVar(&readonly, "readonly", false, "whether to run in read-only mode")
 	rootCmd.PersistentFlags().BoolVar(&demo, "demo", false, "whether to run using demo data")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "whether to enable debug level logging")
@@ -181,6 +185,8 @@ type Main struct {
 
 	pg        *postgres.Instance
 	pgStarted bool
+	// mysqlutil is nil if the MySQL utilities are not found.
+	mysqlutil *mysqlutil.Instance
 	// db is a connection to the database storing Bytebase data.
 	db *store.DB
 }
@@ -288,6 +294,7 @@ func NewMain(activeProfile Profile, logger *zap.Logger) (*Main, error) {
 	fmt.Printf("resourceDir=%s\n", resourceDir)
 	fmt.Printf("pgdataDir=%s\n", pgDataDir)
 	fmt.Printf("seedDir=%s\n", activeProfile.seedDir)
+	fmt.Printf("mysqlBinDir=%s\n", mysqlBinDir)
 	fmt.Printf("readonly=%t\n", readonly)
 	fmt.Printf("demo=%t\n", demo)
 	fmt.Printf("debug=%t\n", debug)
@@ -298,10 +305,17 @@ func NewMain(activeProfile Profile, logger *zap.Logger) (*Main, error) {
 		return nil, err
 	}
 
+	// The MySQL utilities are only required by the point-in-time recovery, so we don't fail the startup.
+	mysqlutilInstance, err := mysqlutil.Find(mysqlBinDir)
+	if err != nil {
+		logger.Warn("MySQL utilities are not available, the point-in-time recovery for MySQL is disabled", zap.Error(err))
+	}
+
 	return &Main{
-		profile: &activeProfile,
-		l:       logger,
-		pg:      pgInstance,
+		profile:   &activeProfile,
+		l:         logger,
+		pg:        pgInstance,
+		mysqlutil: mysqlutilInstance,
 	}, nil
 }
 
@@ -320,6 +334,17 @@ func initSetting(ctx context.Context, settingService api.SettingService) (*confi
 		}
 		result.secret = config.Value
 	}
+	{
+		configCreate := &api.SettingCreate{
+			CreatorID:   api.SystemBotID,
+			Name:        api.SettingBackupStorage,
+			Value:       fmt.Sprintf(`{"backend":%q}`, api.BackupStorageBackendLocal),
+			Description: "The storage backend where new backups are stored.",
+		}
+		if _, err := settingService.CreateSettingIfNotExist(ctx, configCreate); err != nil {
+			return nil, err
+		}
+	}
 
 	return result, nil
 }
@@ -354,7 +379,11 @@ func (m *Main) Run(ctx context.Context) error {
 
 	m.db = db
 
-	s := server.NewServer(m.l, m.lvl, version, host, m.profile.port, frontendHost, frontendPort, m.profile.datastorePort, m.profile.mode, m.profile.dataDir, m.profile.backupRunnerInterval, config.secret, readonly, demo, debug)
+	mysqlBinDir := ""
+	if m.mysqlutil != nil {
+		mysqlBinDir = m.mysqlutil.BinDir()
+	}
+	s := server.NewServer(m.l, m.lvl, version, host, m.profile.port, frontendHost, frontendPort, m.profile.datastorePort, m.profile.mode, m.profile.dataDir, mysqlBinDir, m.profile.backupRunnerInterval, config.secret, readonly, demo, debug)
 	s.SettingService = settingService
 	s.PrincipalService = store.NewPrincipalService(m.l, db, s.CacheService)
 	s.MemberService = store.NewMemberService(m.l, db, s.CacheService)
@@ -372,6 +401,11 @@ func (m *Main) Run(ctx context.Context) error {
 	s.ColumnService = store.NewColumnService(m.l, db)
 	s.ViewService = store.NewViewService(m.l, db)
 	s.IndexService = store.NewIndexService(m.l, db)
+	s.ForeignKeyService = store.NewForeignKeyService(m.l, db)
+	s.CheckConstraintService = store.NewCheckConstraintService(m.l, db)
+	s.TriggerService = store.NewTriggerService(m.l, db)
+	s.RoutineService = store.NewRoutineService(m.l, db)
+	s.SequenceService = store.NewSequenceService(m.l, db)
 	s.IssueService = store.NewIssueService(m.l, db, s.CacheService)
 	s.IssueSubscriberService = store.NewIssueSubscriberService(m.l, db)
 	s.PipelineService = store.NewPipelineService(m.l, db, s.CacheService)
@@ -387,6 +421,12 @@ func (m *Main) Run(ctx context.Context) error {
 	s.LabelService = store.NewLabelService(m.l, db)
 	s.DeploymentConfigService = store.NewDeploymentConfigService(m.l, db)
 	s.SheetService = store.NewSheetService(m.l, db)
+	s.BinlogFileService = store.NewBinlogFileService(m.l, db)
+	s.SchemaDriftRuleService = store.NewSchemaDriftRuleService(m.l, db)
+	s.SchemaSnapshotService = store.NewSchemaSnapshotService(m.l, db)
+	s.SlowQueryService = store.NewSlowQueryService(m.l, db)
+	s.SensitiveColumnService = store.NewSensitiveColumnService(m.l, db)
+	s.QueryHistoryService = store.NewQueryHistoryService(m.l, db)
 
 	s.ActivityManager = server.NewActivityManager(s, s.ActivityService)
 
@@ -1,4 +1,11 @@
-import { Anomaly, AnomalyState, ResourceObject } from "../../types";
+import axios from "axios";
+import {
+  Anomaly,
+  AnomalyDatabaseSchemaDriftPayload,
+  AnomalyId,
+  AnomalyState,
+  ResourceObject,
+} from "../../types";
 import { getPrincipalFromIncludedList } from "./principal";
 
 function convert(
@@ -37,8 +44,18 @@ const getters = {
     },
 };
 
+const actions = {
+  async fetchSchemaDriftById(
+    {}: any,
+    anomalyId: AnomalyId
+  ): Promise<AnomalyDatabaseSchemaDriftPayload> {
+    return (await axios.get(`/api/anomaly/${anomalyId}/schema-drift`)).data;
+  },
+};
+
 export default {
   namespaced: true,
   state,
   getters,
+  actions,
 };
@@ -1,5 +1,6 @@
 import { FieldId } from "../plugins";
 import { ActivityId, ContainerId, IssueId, PrincipalId, TaskId } from "./id";
+import { AnomalySeverity, AnomalyType } from "./anomaly";
 import { IssueStatus } from "./issue";
 import { MemberStatus, RoleType } from "./member";
 import { TaskStatus } from "./pipeline";
@@ -30,10 +31,13 @@ export type ProjectActivityType =
   | "bb.project.member.delete"
   | "bb.project.member.role.update";
 
+export type AnomalyActivityType = "bb.anomaly.open" | "bb.anomaly.resolve";
+
 export type ActivityType =
   | IssueActivityType
   | MemberActivityType
-  | ProjectActivityType;
+  | ProjectActivityType
+  | AnomalyActivityType;
 
 export function activityName(type: ActivityType): string {
   switch (type) {
@@ -71,6 +75,10 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.project-member-delete");
     case "bb.project.member.role.update":
       return t("activity.type.project-member-role-update");
+    case "bb.anomaly.open":
+      return t("activity.type.anomaly-open");
+    case "bb.anomaly.resolve":
+      return t("activity.type.anomaly-resolve");
   }
 }
 
@@ -164,6 +172,16 @@ export type ActivityProjectDatabaseTransferPayload = {
   databaseName: string;
 };
 
+export type ActivityAnomalyPayload = {
+  anomalyId: number;
+  anomalyType: AnomalyType;
+  severity: AnomalySeverity;
+  instanceId: number;
+  instanceName: string;
+  databaseId?: number;
+  databaseName?: string;
+};
+
 export type ActionPayloadType =
   | ActivityIssueCreatePayload
   | ActivityIssueCommentCreatePayload
@@ -177,7 +195,8 @@ export type ActionPayloadType =
   | ActivityMemberRoleUpdatePayload
   | ActivityMemberActivateDeactivatePayload
   | ActivityProjectRepositoryPushPayload
-  | ActivityProjectDatabaseTransferPayload;
+  | ActivityProjectDatabaseTransferPayload
+  | ActivityAnomalyPayload;
 
 export type Activity = {
   id: ActivityId;
@@ -7,20 +7,77 @@ import {
   Instance,
   InstanceId,
   Principal,
+  PrincipalId,
+  ProjectId,
+  SchemaDriftRuleId,
 } from ".";
 
 export type AnomalyType =
   | "bb.anomaly.instance.connection"
   | "bb.anomaly.instance.migration-schema"
+  | "bb.anomaly.instance.replication-lag"
+  | "bb.anomaly.instance.connection-usage"
+  | "bb.anomaly.instance.long-running-transaction"
+  | "bb.anomaly.instance.storage-growth"
   | "bb.anomaly.database.backup.policy-violation"
   | "bb.anomaly.database.backup.missing"
+  | "bb.anomaly.database.backup.cleanup-failure"
+  | "bb.anomaly.database.backup.verification-failure"
   | "bb.anomaly.database.connection"
-  | "bb.anomaly.database.schema.drift";
+  | "bb.anomaly.database.schema.drift"
+  | "bb.anomaly.database.index.redundant"
+  | "bb.anomaly.database.index.unused"
+  | "bb.anomaly.database.table.no-primary-key"
+  | "bb.anomaly.database.slow-query.regression";
 
 export type AnomalyInstanceConnectionPayload = {
   detail: string;
 };
 
+export type AnomalyReplicaItem = {
+  replica: string;
+  running: boolean;
+  lagSeconds?: number;
+  detail?: string;
+};
+
+export type AnomalyInstanceReplicationLagPayload = {
+  thresholdSeconds: number;
+  itemList?: AnomalyReplicaItem[];
+};
+
+export type AnomalyInstanceConnectionUsagePayload = {
+  thresholdPercent: number;
+  connections: number;
+  maxConnections: number;
+};
+
+export type AnomalyTransactionItem = {
+  processId: string;
+  database?: string;
+  user?: string;
+  durationSeconds: number;
+  query?: string;
+};
+
+export type AnomalyInstanceLongRunningTransactionPayload = {
+  thresholdSeconds: number;
+  itemList?: AnomalyTransactionItem[];
+};
+
+export type AnomalyStorageItem = {
+  type: "DATABASE" | "TABLESPACE";
+  name: string;
+  sizeBytes: number;
+  previousSizeBytes: number;
+  previousTs: number;
+};
+
+export type AnomalyInstanceStorageGrowthPayload = {
+  thresholdPercent: number;
+  itemList?: AnomalyStorageItem[];
+};
+
 export type AnomalyDatabaseBackupPolicyViolationPayload = {
   environmentId: EnvironmentId;
   expectedSchedule: BackupPlanPolicySchedule;
@@ -32,24 +89,164 @@ export type AnomalyDatabaseBackupMissingPayload = {
   lastBackupTs: number;
 };
 
+export type AnomalyDatabaseBackupCleanupFailurePayload = {
+  backupId: number;
+  backupName: string;
+  detail: string;
+};
+
+export type AnomalyDatabaseBackupVerificationFailurePayload = {
+  backupId: number;
+  backupName: string;
+  detail: string;
+};
+
 export type AnomalyDatabaseConnectionPayload = {
   detail: string;
 };
 
+export type SchemaDriftObjectType =
+  | "TABLE"
+  | "COLUMN"
+  | "INDEX"
+  | "VIEW"
+  | "FUNCTION"
+  | "PROCEDURE"
+  | "TRIGGER"
+  | "EVENT"
+  | "SEQUENCE";
+
+export type SchemaDriftAction = "ADDED" | "REMOVED" | "CHANGED";
+
+export type SchemaDriftItem = {
+  type: SchemaDriftObjectType;
+  action: SchemaDriftAction;
+  table?: string;
+  name: string;
+  expect?: string;
+  actual?: string;
+};
+
 export type AnomalyDatabaseSchemaDriftPayload = {
   version: string;
-  expect: string;
-  actual: string;
+  // The schemas are not stored in the anomaly, but fetched on demand by anomaly/fetchSchemaDriftById.
+  expect?: string;
+  actual?: string;
+  itemList?: SchemaDriftItem[];
+  diff?: string;
+};
+
+export type AnomalyIndexItem = {
+  table: string;
+  index: string;
+  columnList?: string[];
+  // Only for redundant indexes
+  coveringIndex?: string;
+  statement?: string;
+};
+
+export type AnomalyDatabaseIndexRedundantPayload = {
+  itemList?: AnomalyIndexItem[];
+  statement?: string;
+};
+
+export type AnomalyDatabaseIndexUnusedPayload = {
+  itemList?: AnomalyIndexItem[];
+  statsSinceTs?: number;
+  statement?: string;
+};
+
+export type AnomalyTableItem = {
+  table: string;
+  statement?: string;
+};
+
+export type AnomalyDatabaseTableNoPrimaryKeyPayload = {
+  itemList?: AnomalyTableItem[];
+  statement?: string;
+};
+
+export type AnomalySlowQueryItem = {
+  digest: string;
+  statement: string;
+  // The mean latency and the calls in the current schema version
+  meanLatencyUs: number;
+  calls: number;
+  // The mean latency and the calls in the previous schema version
+  previousMeanLatencyUs: number;
+  previousCalls: number;
+};
+
+export type AnomalyDatabaseSlowQueryRegressionPayload = {
+  version?: string;
+  previousVersion?: string;
+  thresholdPercent?: number;
+  itemList?: AnomalySlowQueryItem[];
+};
+
+export type SchemaDriftResolveAction = "BASELINE" | "REVERT";
+
+// The action is only required for the schema drift anomaly.
+export type AnomalySchemaDriftResolve = {
+  action?: SchemaDriftResolveAction;
+  assigneeId: PrincipalId;
+};
+
+export type SchemaDriftRuleType = "TABLE_GLOB" | "OBJECT_TYPE" | "NORMALIZER";
+
+export type SchemaDriftRule = {
+  id: SchemaDriftRuleId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  projectId: ProjectId;
+  // Applies to all databases in the project if undefined
+  databaseId?: DatabaseId;
+
+  // Domain specific fields
+  type: SchemaDriftRuleType;
+  pattern: string;
+  replacement: string;
+};
+
+export type SchemaDriftRuleCreate = {
+  databaseId?: DatabaseId;
+  type: SchemaDriftRuleType;
+  pattern: string;
+  replacement?: string;
+};
+
+export type SchemaDriftRulePatch = {
+  pattern?: string;
+  replacement?: string;
 };
 
 export type AnomalyPayload =
+  | AnomalyInstanceReplicationLagPayload
+  | AnomalyInstanceConnectionUsagePayload
+  | AnomalyInstanceLongRunningTransactionPayload
+  | AnomalyInstanceStorageGrowthPayload
   | AnomalyDatabaseBackupPolicyViolationPayload
   | AnomalyDatabaseBackupMissingPayload
+  | AnomalyDatabaseBackupCleanupFailurePayload
+  | AnomalyDatabaseBackupVerificationFailurePayload
   | AnomalyDatabaseConnectionPayload
-  | AnomalyDatabaseSchemaDriftPayload;
+  | AnomalyDatabaseSchemaDriftPayload
+  | AnomalyDatabaseIndexRedundantPayload
+  | AnomalyDatabaseIndexUnusedPayload
+  | AnomalyDatabaseTableNoPrimaryKeyPayload
+  | AnomalyDatabaseSlowQueryRegressionPayload;
 
 export type AnomalySeverity = "MEDIUM" | "HIGH" | "CRITICAL";
 
+// SNOOZED is calculated from snoozeUntilTs, and falls back to OPEN or ACKNOWLEDGED once the snooze expires.
+export type AnomalyStatus = "OPEN" | "ACKNOWLEDGED" | "SNOOZED" | "RESOLVED";
+
 export type Anomaly = {
   id: AnomalyId;
 
@@ -68,4 +265,23 @@ export type Anomaly = {
   type: AnomalyType;
   severity: AnomalySeverity;
   payload: AnomalyPayload;
+  status: AnomalyStatus;
+  acknowledger?: Principal;
+  acknowledgedTs: number;
+  comment: string;
+  snoozeUntilTs: number;
+  resolvedTs: number;
+};
+
+export type AnomalyStatusPatch = {
+  status: AnomalyStatus;
+  comment?: string;
+  // Required if status is SNOOZED
+  snoozeUntilTs?: number;
+};
+
+export type AnomalySummary = {
+  openCount: number;
+  resolvedCount: number;
+  meanTimeToResolveSeconds: number;
 };
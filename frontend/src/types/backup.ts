@@ -1,11 +1,21 @@
 import { BackupId, BackupSettingId, DatabaseId } from "./id";
 import { Principal } from "./principal";
 
-export type BackupStatus = "PENDING_CREATE" | "DONE" | "FAILED";
+export type BackupStatus =
+  | "PENDING_CREATE"
+  | "DONE"
+  | "FAILED"
+  | "EXPIRED";
 
 export type BackupType = "MANUAL" | "AUTOMATIC";
 
-export type BackupStorageBackend = "LOCAL";
+export type BackupStorageBackend = "LOCAL" | "S3";
+
+export type BackupVerificationStatus = "UNVERIFIED" | "PASSED" | "FAILED";
+
+export type BackupCompression = "NONE" | "GZIP" | "ZSTD";
+
+export type BackupEncryption = "NONE" | "AES_256_GCM";
 
 // Backup
 export type Backup = {
@@ -27,6 +37,17 @@ export type Backup = {
   migrationHistoryVersion: string;
   path: string;
   comment: string;
+  compression: BackupCompression;
+  encryption: BackupEncryption;
+  // Fingerprint of the key encrypting the backup, empty if not encrypted.
+  encryptionKeyFingerprint: string;
+  // Size and SHA-256 checksum of the stored backup.
+  size: number;
+  checksum: string;
+  payload: string;
+  verificationStatus: BackupVerificationStatus;
+  verifiedTs: number;
+  verificationDetail: string;
 };
 
 export type BackupCreate = {
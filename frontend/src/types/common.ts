@@ -227,6 +227,7 @@ export const unknown = (
     name: "",
     url: "",
     activityList: [],
+    anomalySeverityList: [],
   };
 
   const UNKNOWN_PROJECT_MEMBER: ProjectMember = {
@@ -276,6 +277,8 @@ export const unknown = (
     collation: "",
     syncStatus: "NOT_FOUND",
     lastSuccessfulSyncTs: 0,
+    syncError: "",
+    syncDurationMs: 0,
     schemaVersion: "",
   };
 
@@ -433,6 +436,7 @@ export const unknown = (
     branchFilter: "",
     filePathTemplate: "",
     schemaPathTemplate: "",
+    dictionaryPathTemplate: "",
     externalId: UNKNOWN_ID.toString(),
   };
 
@@ -453,6 +457,11 @@ export const unknown = (
       expectedSchedule: "DAILY",
       actualSchedule: "UNSET",
     },
+    status: "OPEN",
+    acknowledgedTs: 0,
+    comment: "",
+    snoozeUntilTs: 0,
+    resolvedTs: 0,
   };
 
   const UNKNOWN_DEPLOYMENT_CONFIG: DeploymentConfig = {
@@ -621,6 +630,7 @@ export const empty = (
     name: "",
     url: "",
     activityList: [],
+    anomalySeverityList: [],
   };
 
   const EMPTY_PROJECT_MEMBER: ProjectMember = {
@@ -670,6 +680,8 @@ export const empty = (
     collation: "",
     syncStatus: "NOT_FOUND",
     lastSuccessfulSyncTs: 0,
+    syncError: "",
+    syncDurationMs: 0,
     schemaVersion: "",
   };
 
@@ -827,6 +839,7 @@ export const empty = (
     branchFilter: "",
     filePathTemplate: "",
     schemaPathTemplate: "",
+    dictionaryPathTemplate: "",
     externalId: EMPTY_ID.toString(),
   };
 
@@ -847,6 +860,11 @@ export const empty = (
       expectedSchedule: "DAILY",
       actualSchedule: "UNSET",
     },
+    status: "OPEN",
+    acknowledgedTs: 0,
+    comment: "",
+    snoozeUntilTs: 0,
+    resolvedTs: 0,
   };
 
   const EMPTY_DEPLOYMENT_CONFIG: DeploymentConfig = {
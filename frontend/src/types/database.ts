@@ -13,7 +13,8 @@ import { DatabaseLabel } from "./label";
 
 // "OK" means we find the database with the same name.
 // "NOT_FOUND" means no matching database name found, this usually means someone changes the underlying db name without Bytebase knowledge.
-export type DatabaseSyncStatus = "OK" | "NOT_FOUND";
+// "FAILED" means the last sync failed, the error is in syncError.
+export type DatabaseSyncStatus = "OK" | "NOT_FOUND" | "FAILED";
 // Database
 export type Database = {
   id: DatabaseId;
@@ -36,6 +37,8 @@ export type Database = {
   // Domain specific fields
   syncStatus: DatabaseSyncStatus;
   lastSuccessfulSyncTs: number;
+  syncError: string;
+  syncDurationMs: number;
   name: string;
   characterSet: string;
   collation: string;
@@ -19,6 +19,14 @@ export type ProjectId = IdType;
 
 export type ProjectWebhookId = IdType;
 
+export type SchemaDriftRuleId = IdType;
+
+export type SchemaSnapshotId = IdType;
+
+export type SensitiveColumnId = IdType;
+
+export type QueryHistoryId = IdType;
+
 export type IssueId = IdType;
 
 export type PipelineId = IdType;
@@ -53,6 +61,16 @@ export type ColumnId = IdType;
 
 export type TableIndexId = IdType;
 
+export type TableForeignKeyId = IdType;
+
+export type TableCheckConstraintId = IdType;
+
+export type TableTriggerId = IdType;
+
+export type RoutineId = IdType;
+
+export type SequenceId = IdType;
+
 export type VCSId = IdType;
 
 export type RepositoryId = IdType;
@@ -31,6 +31,8 @@ export * from "./sql";
 export * from "./store";
 export * from "./table";
 export * from "./tableIndex";
+export * from "./tableConstraint";
+export * from "./routine";
 export * from "./vcs";
 export * from "./view";
 export * from "./label";
@@ -39,3 +41,8 @@ export * from "./sqlEditor";
 export * from "./tab";
 export * from "./subscription";
 export * from "./sheet";
+export * from "./schemaSnapshot";
+export * from "./slowQuery";
+export * from "./sensitiveColumn";
+export * from "./queryHistory";
+export * from "./schemaSearch";
@@ -35,6 +35,9 @@ export type CreateDatabaseContext = {
   collation: string;
   backupId: BackupId;
   backupName: string;
+  // For the point-in-time recovery, mutually exclusive to backupId.
+  sourceDatabaseId?: DatabaseId;
+  pointInTimeTs?: number;
   labels?: string; // JSON encoded
 };
 
@@ -50,12 +53,19 @@ export type UpdateSchemaContext = {
   updateSchemaDetailList: UpdateSchemaDetail[];
 };
 
+// The access to query the database expires at expireTs.
+export type DataSourceRequestContext = {
+  databaseId: DatabaseId;
+  expireTs: number;
+};
+
 // eslint-disable-next-line @typescript-eslint/ban-types
 export type EmptyContext = {};
 
 export type IssueCreateContext =
   | CreateDatabaseContext
   | UpdateSchemaContext
+  | DataSourceRequestContext
   | EmptyContext;
 
 export type IssuePayload = { [key: string]: any };
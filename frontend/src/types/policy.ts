@@ -1,8 +1,18 @@
-import { Environment, PolicyId, Principal } from ".";
+import {
+  Environment,
+  MaskingType,
+  PolicyId,
+  Principal,
+  RoleType,
+} from ".";
 
 export type PolicyType =
   | "bb.policy.pipeline-approval"
-  | "bb.policy.backup-plan";
+  | "bb.policy.backup-plan"
+  | "bb.policy.instance-health"
+  | "bb.policy.sql-export"
+  | "bb.policy.data-masking"
+  | "bb.policy.sql-query-access";
 
 export type PipelineApprovalPolicyValue =
   | "MANUAL_APPROVAL_NEVER"
@@ -19,13 +29,59 @@ export type BackupPlanPolicySchedule = "UNSET" | "DAILY" | "WEEKLY";
 
 export type PolicyBackupPlanPolicyPayload = {
   schedule: BackupPlanPolicySchedule;
+  retentionCount?: number;
+  retentionDays?: number;
+  keepWeekly?: boolean;
+  keepMonthly?: boolean;
+  verificationSchedule?: BackupPlanPolicySchedule;
+  archiveBinlog?: boolean;
 };
 
 export const DefaultSchedulePolicy: BackupPlanPolicySchedule = "UNSET";
 
+// A threshold of 0 disables the check.
+export type InstanceHealthPolicyPayload = {
+  replicationLagSeconds: number;
+  connectionUsagePercent: number;
+  longRunningTransactionSeconds: number;
+  storageGrowthPercent: number;
+};
+
+// A maxRowCount of 0 disables the export.
+export type SQLExportPolicyPayload = {
+  maxRowCount: number;
+};
+
+// The columns are sensitive if marked in the database, or their names match any of the case-insensitive regular expressions.
+export type DataMaskingPolicyPayload = {
+  exemptRoleList: RoleType[];
+  patternList: {
+    columnPattern: string;
+    maskingType: MaskingType;
+  }[];
+};
+
+// A maxRowCount or maxQueryCountPerDay of 0 means no limit.
+export type SQLQueryAccessPolicyPayload = {
+  exemptRoleList: RoleType[];
+  requireProjectMember: boolean;
+  requireAccessRequest: boolean;
+  // The maximum duration of the access granted by a data source request since it's created. 0 means no limit.
+  maxAccessRequestDurationSeconds: number;
+  maxRowCount: number;
+  // It counts both the queries and the exports, by the day in UTC.
+  maxQueryCountPerDay: number;
+  // 0 means no timeout. It applies to the exempted roles as well.
+  statementTimeoutSeconds: number;
+};
+
 export type PolicyPayload =
   | PipelineApporvalPolicyPayload
-  | PolicyBackupPlanPolicyPayload;
+  | PolicyBackupPlanPolicyPayload
+  | InstanceHealthPolicyPayload
+  | SQLExportPolicyPayload
+  | DataMaskingPolicyPayload
+  | SQLQueryAccessPolicyPayload;
 
 export type Policy = {
   id: PolicyId;
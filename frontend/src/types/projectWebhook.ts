@@ -1,4 +1,5 @@
 import { ActivityType } from "./activity";
+import { AnomalySeverity } from "./anomaly";
 import { MemberId, ProjectId } from "./id";
 import { Principal } from "./principal";
 import { t } from "../plugins/i18n";
@@ -76,6 +77,16 @@ export const PROJECT_HOOK_ACTIVITY_ITEM_LIST: () => ProjectWebhookActivityItem[]
       label: t("project.webhook.activity-item.issue-comment-creation.label"),
       activity: "bb.issue.comment.create",
     },
+    {
+      title: t("project.webhook.activity-item.anomaly-open.title"),
+      label: t("project.webhook.activity-item.anomaly-open.label"),
+      activity: "bb.anomaly.open",
+    },
+    {
+      title: t("project.webhook.activity-item.anomaly-resolve.title"),
+      label: t("project.webhook.activity-item.anomaly-resolve.label"),
+      activity: "bb.anomaly.resolve",
+    },
   ];
 
 // Project Member
@@ -96,6 +107,7 @@ export type ProjectWebhook = {
   name: string;
   url: string;
   activityList: ActivityType[];
+  anomalySeverityList: AnomalySeverity[];
 };
 
 export type ProjectWebhookCreate = {
@@ -104,6 +116,7 @@ export type ProjectWebhookCreate = {
   name: string;
   url: string;
   activityList: ActivityType[];
+  anomalySeverityList?: AnomalySeverity[];
 };
 
 export type ProjectWebhookPatch = {
@@ -112,6 +125,8 @@ export type ProjectWebhookPatch = {
   url?: string;
   // Comma separated list. Server doesn't support deserialize into pointer to string array (*[]string in Golang)
   activityList?: string;
+  // Comma separated list
+  anomalySeverityList?: string;
 };
 
 export type ProjectWebhookTestResult = {
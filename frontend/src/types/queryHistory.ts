@@ -0,0 +1,34 @@
+import { InstanceId, QueryHistoryId } from "./id";
+import { Principal } from "./principal";
+import { SqlParameter } from "./sql";
+
+// The query executed in the SQL editor, only visible to the creator.
+export type QueryHistory = {
+  id: QueryHistoryId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  instanceId: InstanceId;
+
+  // Domain specific fields
+  databaseName: string;
+  // The statement as executed, with the named :param placeholders unbound.
+  statement: string;
+  parameterList: SqlParameter[];
+  durationNs: number;
+  // Empty if the query succeeded.
+  error: string;
+};
+
+export type QueryHistoryFind = {
+  instanceId?: InstanceId;
+  databaseName?: string;
+  // Matches the statement containing the text case-insensitively.
+  search?: string;
+  limit?: number;
+};
@@ -28,6 +28,8 @@ export type Repository = {
   branchFilter: string;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  // The file extension decides the data dictionary format, i.e. ".md", ".html" or ".json".
+  dictionaryPathTemplate: string;
   // e.g. In GitLab, this is the corresponding project id.
   externalId: string;
 };
@@ -44,6 +46,7 @@ export type RepositoryCreate = {
   baseDirectory: string;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  dictionaryPathTemplate?: string;
   externalId: string;
   accessToken: string;
   expiresTs: number;
@@ -55,6 +58,7 @@ export type RepositoryPatch = {
   branchFilter?: string;
   filePathTemplate?: string;
   schemaPathTemplate?: string;
+  dictionaryPathTemplate?: string;
 };
 
 export type RepositoryConfig = {
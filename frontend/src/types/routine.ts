@@ -0,0 +1,49 @@
+import { Database } from "./database";
+import { RoutineId, SequenceId } from "./id";
+import { Principal } from "./principal";
+
+export type RoutineType = "FUNCTION" | "PROCEDURE";
+
+// Routine
+export type Routine = {
+  id: RoutineId;
+
+  // Related fields
+  database: Database;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  type: RoutineType;
+  definition: string;
+  comment: string;
+};
+
+// Sequence
+export type Sequence = {
+  id: SequenceId;
+
+  // Related fields
+  database: Database;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  dataType: string;
+  startValue: string;
+  increment: string;
+  minValue: string;
+  maxValue: string;
+  cycle: boolean;
+  cacheSize: string;
+};
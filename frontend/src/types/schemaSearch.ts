@@ -0,0 +1,35 @@
+import { DatabaseId, EnvironmentId, InstanceId, ProjectId } from "./id";
+import { EngineType } from "./instance";
+
+export type SchemaSearchMode = "WILDCARD" | "REGEX";
+
+export type SchemaSearchObjectType = "TABLE" | "COLUMN" | "INDEX" | "VIEW";
+
+export type SchemaSearchField = "NAME" | "TYPE" | "COMMENT";
+
+export type SchemaSearchMatch = {
+  databaseId: DatabaseId;
+  databaseName: string;
+  projectId: ProjectId;
+  projectName: string;
+  environmentId: EnvironmentId;
+  environmentName: string;
+  instanceId: InstanceId;
+  instanceName: string;
+  engine: EngineType;
+
+  objectType: SchemaSearchObjectType;
+  // The table of the column or index
+  tableName?: string;
+  name: string;
+  field: SchemaSearchField;
+  value: string;
+};
+
+export type SchemaSearchResult = {
+  query: string;
+  mode: SchemaSearchMode;
+  matchList: SchemaSearchMatch[];
+  // True if there are more matches than the limit
+  truncated: boolean;
+};
@@ -0,0 +1,113 @@
+import {
+  DatabaseId,
+  EnvironmentId,
+  InstanceId,
+  ProjectId,
+  SchemaSnapshotId,
+} from "./id";
+import { Principal } from "./principal";
+import { SchemaDriftItem } from "./anomaly";
+
+// SchemaSnapshot is taken after a schema sync or migration only if the schema changes.
+export type SchemaSnapshot = {
+  id: SchemaSnapshotId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+
+  // Domain specific fields
+  // The version of the latest migration history when the snapshot is taken.
+  schemaVersion: string;
+  checksum: string;
+  // JSON encoded SchemaSnapshotMetadata, empty in the snapshot list.
+  metadata: string;
+};
+
+export type SchemaSnapshotMetadata = {
+  characterSet?: string;
+  collation?: string;
+  tableList: SchemaSnapshotTable[];
+  viewList: SchemaSnapshotView[];
+};
+
+export type SchemaSnapshotTable = {
+  name: string;
+  type?: string;
+  engine?: string;
+  collation?: string;
+  createOptions?: string;
+  comment?: string;
+  columnList: SchemaSnapshotColumn[];
+  indexList: SchemaSnapshotIndex[];
+};
+
+export type SchemaSnapshotColumn = {
+  name: string;
+  position: number;
+  default?: string;
+  nullable: boolean;
+  type: string;
+  characterSet?: string;
+  collation?: string;
+  comment?: string;
+};
+
+export type SchemaSnapshotIndex = {
+  name: string;
+  expression: string;
+  position: number;
+  type?: string;
+  unique: boolean;
+  visible: boolean;
+  comment?: string;
+};
+
+export type SchemaSnapshotView = {
+  name: string;
+  definition: string;
+  comment?: string;
+};
+
+// In the itemList, "expect" is the definition in the "from" snapshot and "actual" is the one in the "to" snapshot.
+export type SchemaSnapshotDiff = {
+  fromSnapshotId: SchemaSnapshotId;
+  toSnapshotId: SchemaSnapshotId;
+  itemList: SchemaDriftItem[];
+  diff: string;
+};
+
+export type SchemaComparisonStatus = "IN_SYNC" | "BEHIND" | "DIFFERENT";
+
+// In the itemList, "expect" is the definition in the reference database and "actual" is the one in this database.
+export type SchemaComparisonEntry = {
+  databaseId: DatabaseId;
+  databaseName: string;
+  instanceId: InstanceId;
+  environmentId: EnvironmentId;
+  environmentName: string;
+  // Only set in tenant mode.
+  deployment?: string;
+  tenant?: string;
+  schemaVersion: string;
+  syncStatus: string;
+  status: SchemaComparisonStatus;
+  itemList: SchemaDriftItem[];
+};
+
+export type SchemaComparisonGroup = {
+  // The database name, or the base database name in tenant mode.
+  name: string;
+  referenceDatabaseId: DatabaseId;
+  entryList: SchemaComparisonEntry[];
+};
+
+export type SchemaComparisonReport = {
+  projectId: ProjectId;
+  groupList: SchemaComparisonGroup[];
+};
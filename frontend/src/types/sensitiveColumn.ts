@@ -0,0 +1,32 @@
+import { DatabaseId, SensitiveColumnId } from "./id";
+import { Principal } from "./principal";
+
+export type MaskingType = "FULL" | "PARTIAL" | "HASH";
+
+// The values of the sensitive column are masked in the SQL editor query and export results,
+// unless the role is exempted by the data masking policy of the environment.
+export type SensitiveColumn = {
+  id: SensitiveColumnId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+
+  // Domain specific fields
+  // Qualified by the schema for PostgreSQL, e.g. "public.user".
+  tableName: string;
+  columnName: string;
+  maskingType: MaskingType;
+};
+
+// The masking type is updated if the column is already marked.
+export type SensitiveColumnUpsert = {
+  tableName: string;
+  columnName: string;
+  maskingType: MaskingType;
+};
@@ -28,11 +28,13 @@ export interface Sheet {
   name: string;
   statement: string;
   visibility: SheetVisibility;
+  // The names of the :param placeholders in the statement, computed by the server.
+  parameterList: string[];
 }
 
 export type CreateSheetState = Omit<
   Sheet,
-  "id" | "creator" | "createdTs" | "updater" | "updatedTs"
+  "id" | "creator" | "createdTs" | "updater" | "updatedTs" | "parameterList"
 >;
 
 export type SheetPatch = Partial<
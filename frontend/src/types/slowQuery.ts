@@ -0,0 +1,39 @@
+import { DatabaseId } from "./id";
+
+// SlowQueryReport is the top statements of a database within [fromTs, toTs).
+export type SlowQueryReport = {
+  databaseId: DatabaseId;
+  fromTs: number;
+  toTs: number;
+  itemList: SlowQueryReportItem[];
+};
+
+export type SlowQueryReportItem = {
+  digest: string;
+  statement: string;
+  calls: number;
+  totalLatencyUs: number;
+  meanLatencyUs: number;
+  // The rows read for MySQL, and the rows retrieved or affected for Postgres.
+  rowsExamined: number;
+  // The schema version when the statement is collected the last time.
+  schemaVersion: string;
+  lastSeenTs: number;
+};
+
+// SlowQueryTrend is the statistics of the top statements over time, or a single statement if digest is set.
+export type SlowQueryTrend = {
+  databaseId: DatabaseId;
+  digest: string;
+  pointList: SlowQueryTrendPoint[];
+};
+
+// SlowQueryTrendPoint is the statistics within the collection interval ending at ts.
+export type SlowQueryTrendPoint = {
+  ts: number;
+  schemaVersion: string;
+  calls: number;
+  totalLatencyUs: number;
+  meanLatencyUs: number;
+  rowsExamined: number;
+};
@@ -21,9 +21,56 @@ export type QueryInfo = {
   databaseName?: string;
   statement: string;
   limit?: number;
+  // Return the estimated execution plan of the SELECT statement instead of running it, MySQL and Postgres only.
+  explain?: boolean;
+  // The values of the named :param placeholders in the statement, e.g. of a parameterized sheet.
+  parameterList?: SqlParameter[];
+  // Identifies the query to cancel it while running, or to fetch its next pages. Generated by the server if unset.
+  queryId?: string;
+  // Only return the first page of at most pageSize rows if set, the next pages are fetched by queryId.
+  pageSize?: number;
+};
+
+export type SqlParameter = {
+  name: string;
+  value: string;
+};
+
+export type SqlExportFormat = "CSV" | "JSONL" | "XLSX";
+
+// The result is downloaded as an attachment, with the row count capped by the SQL export policy.
+export type SqlExportInfo = {
+  instanceId: InstanceId;
+  databaseName?: string;
+  statement: string;
+  format: SqlExportFormat;
+  // The policy limit is used if unset.
+  limit?: number;
+};
+
+// The execution plan normalized across the engines.
+export type SqlPlanNode = {
+  // e.g. "Full Table Scan" for MySQL or "Seq Scan" for Postgres.
+  nodeType: string;
+  table?: string;
+  index?: string;
+  // 0 if unknown.
+  estimatedRows: number;
+  // In the unit of the engine, which isn't comparable across the engines.
+  cost: number;
+  fullTableScan: boolean;
+  childList?: SqlPlanNode[];
 };
 
 export type SqlResultSet = {
+  // The plan in the JSON format of the engine in the explain mode.
   data: string;
   error: string;
+  // Only in the explain mode.
+  plan?: SqlPlanNode;
+  fullTableScanList?: string[];
+  // Not set in the explain mode.
+  queryId?: string;
+  // More rows can be fetched by queryId, only if pageSize is set.
+  hasMore?: boolean;
 };
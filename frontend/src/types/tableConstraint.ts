@@ -0,0 +1,74 @@
+import {
+  DatabaseId,
+  TableCheckConstraintId,
+  TableForeignKeyId,
+  TableId,
+  TableTriggerId,
+} from "./id";
+
+// ForeignKey, one for each column of the constraint
+export type TableForeignKey = {
+  id: TableForeignKeyId;
+
+  // Related fields
+  databaseId: DatabaseId;
+  tableId: TableId;
+
+  // Standard fields
+  creatorId: number;
+  createdTs: number;
+  updaterId: number;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  column: string;
+  position: number;
+  referencedTable: string;
+  referencedColumn: string;
+  onUpdate: string;
+  onDelete: string;
+};
+
+// CheckConstraint
+export type TableCheckConstraint = {
+  id: TableCheckConstraintId;
+
+  // Related fields
+  databaseId: DatabaseId;
+  tableId: TableId;
+
+  // Standard fields
+  creatorId: number;
+  createdTs: number;
+  updaterId: number;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  expression: string;
+};
+
+export type TableTriggerTiming = "BEFORE" | "AFTER" | "INSTEAD OF";
+
+// Trigger
+export type TableTrigger = {
+  id: TableTriggerId;
+
+  // Related fields
+  databaseId: DatabaseId;
+  tableId: TableId;
+
+  // Standard fields
+  creatorId: number;
+  createdTs: number;
+  updaterId: number;
+  updatedTs: number;
+
+  // Domain specific fields
+  name: string;
+  timing: TableTriggerTiming;
+  // One or more of INSERT, UPDATE, DELETE and TRUNCATE joined with " OR "
+  event: string;
+  statement: string;
+};
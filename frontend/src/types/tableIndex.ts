@@ -20,6 +20,7 @@ export type TableIndex = {
   position: number;
   type: string;
   unique: boolean;
+  primary: boolean;
   visible: boolean;
   comment: string;
 };
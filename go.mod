@@ -5,6 +5,10 @@ go 1.16
 require (
 	github.com/ClickHouse/clickhouse-go/v2 v2.0.7
 	github.com/VictoriaMetrics/fastcache v1.6.0
+	github.com/aws/aws-sdk-go-v2 v1.8.0
+	github.com/aws/aws-sdk-go-v2/credentials v1.3.2
+	github.com/aws/aws-sdk-go-v2/feature/s3/manager v1.4.0
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.12.0
 	github.com/casbin/casbin/v2 v2.40.6
 	github.com/github/gh-ost v1.1.4
 	github.com/go-sql-driver/mysql v1.6.0
@@ -13,6 +17,7 @@ require (
 	github.com/google/jsonapi v1.0.0
 	github.com/google/uuid v1.3.0
 	github.com/gosimple/slug v1.10.0
+	github.com/klauspost/compress v1.13.4
 	github.com/kr/pretty v0.2.1
 	github.com/labstack/echo/v4 v4.6.1
 	github.com/lib/pq v1.10.2
@@ -20,6 +25,7 @@ require (
 	github.com/pingcap/tidb v1.1.0-beta.0.20211209055157-9f744cdf8266
 	github.com/pingcap/tidb/parser v0.0.0-20211209055157-9f744cdf8266
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/qiangmzsx/string-adapter/v2 v2.1.0
 	github.com/snowflakedb/gosnowflake v1.6.3
 	github.com/spf13/cobra v1.2.0
@@ -124,26 +124,17 @@ func (driver *Driver) GetVersion(ctx context.Context) (string, error) {
 	return version, nil
 }
 
-// SyncSchema syncs the schema.
-func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema, error) {
-	excludedDatabaseList := []string{
-		// Skip our internal "bytebase" database
-		"'bytebase'",
-	}
-
-	// Skip all system databases
-	for k := range systemDatabases {
-		excludedDatabaseList = append(excludedDatabaseList, fmt.Sprintf("'%s'", k))
-	}
+// SyncUser syncs the users of the instance.
+func (driver *Driver) SyncUser(ctx context.Context) ([]*db.User, error) {
+	return driver.getUserList(ctx)
+}
 
-	// Query user info
-	userList, err := driver.getUserList(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
+// SyncSchema syncs the schema.
+func (driver *Driver) SyncSchema(ctx context.Context, databaseList ...string) ([]*db.Schema, error) {
+	excludedDatabaseList := getExcludedDatabaseList()
 
 	// Query column info
-	columnWhere := fmt.Sprintf("LOWER(database) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	columnWhere := getDatabaseWhere("database", excludedDatabaseList, databaseList)
 	query := `
 			SELECT
 				database,
@@ -157,7 +148,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			WHERE ` + columnWhere
 	columnRows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer columnRows.Close()
 
@@ -176,7 +167,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&column.Type,
 			&column.Comment,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		key := fmt.Sprintf("%s/%s", dbName, tableName)
@@ -188,7 +179,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 	}
 
 	// Query table info
-	tableWhere := fmt.Sprintf("LOWER(database) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	tableWhere := getDatabaseWhere("database", excludedDatabaseList, databaseList)
 	query = `
 			SELECT
 				database,
@@ -203,7 +194,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			WHERE ` + tableWhere
 	tableRows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer tableRows.Close()
 
@@ -226,7 +217,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&definition,
 			&comment,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		if engine == "View" {
@@ -253,7 +244,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 
 	var schemaList []*db.Schema
 	// Query db info
-	where := fmt.Sprintf("name NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	where := getDatabaseWhere("name", excludedDatabaseList, databaseList)
 	query = `
 		SELECT
 			name
@@ -261,7 +252,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		WHERE ` + where
 	rows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer rows.Close()
 	for rows.Next() {
@@ -269,7 +260,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		if err := rows.Scan(
 			&schema.Name,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		schema.TableList = tableMap[schema.Name]
 		schema.ViewList = viewMap[schema.Name]
@@ -277,10 +268,90 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		schemaList = append(schemaList, &schema)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return schemaList, nil
+}
+
+// getExcludedDatabaseList returns the quoted names of our internal "bytebase" database and the system databases, which are not synced.
+func getExcludedDatabaseList() []string {
+	excludedDatabaseList := []string{
+		// Skip our internal "bytebase" database
+		"'bytebase'",
+	}
+
+	// Skip all system databases
+	for k := range systemDatabases {
+		excludedDatabaseList = append(excludedDatabaseList, fmt.Sprintf("'%s'", k))
+	}
+	return excludedDatabaseList
+}
+
+// getDatabaseWhere returns the WHERE condition on the database column excluding excludedDatabaseList,
+// and also limiting to databaseList if it's not empty.
+func getDatabaseWhere(column string, excludedDatabaseList []string, databaseList []string) string {
+	where := fmt.Sprintf("LOWER(%s) NOT IN (%s)", column, strings.Join(excludedDatabaseList, ", "))
+	if len(databaseList) == 0 {
+		return where
+	}
+	var quotedList []string
+	for _, database := range databaseList {
+		quotedList = append(quotedList, fmt.Sprintf("'%s'", strings.ReplaceAll(strings.ReplaceAll(database, `\`, `\\`), "'", `\'`)))
+	}
+	return fmt.Sprintf("%s AND %s IN (%s)", where, column, strings.Join(quotedList, ", "))
+}
+
+// GetInstanceHealth returns nil as ClickHouse doesn't support it.
+func (driver *Driver) GetInstanceHealth(ctx context.Context, transactionDuration time.Duration) (*db.InstanceHealth, error) {
+	return nil, nil
+}
+
+// GetQueryDigestList returns nil as ClickHouse doesn't support it.
+func (driver *Driver) GetQueryDigestList(ctx context.Context) ([]*db.QueryDigest, error) {
+	return nil, nil
+}
+
+// Explain returns nil as ClickHouse doesn't support it.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	return nil, nil
+}
+
+// GetSchemaFingerprint returns the DDL fingerprint of each database,
+// which hashes the metadata modification time of the tables as ClickHouse updates it on every DDL.
+func (driver *Driver) GetSchemaFingerprint(ctx context.Context) (map[string]string, error) {
+	excludedDatabaseList := getExcludedDatabaseList()
+	query := `
+		SELECT name, ''
+		FROM system.databases
+		WHERE ` + getDatabaseWhere("name", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT database, concat(name, '|', engine, '|', toString(metadata_modification_time))
+		FROM system.tables
+		WHERE ` + getDatabaseWhere("database", excludedDatabaseList, nil)
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	lineMap := make(map[string][]string)
+	for rows.Next() {
+		var dbName, line string
+		if err := rows.Scan(&dbName, &line); err != nil {
+			return nil, err
+		}
+		lineMap[dbName] = append(lineMap[dbName], line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return userList, schemaList, nil
+	fingerprintMap := make(map[string]string)
+	for dbName, lineList := range lineMap {
+		fingerprintMap[dbName] = util.GetSchemaFingerprint(lineList)
+	}
+	return fingerprintMap, nil
 }
 
 func (driver *Driver) getUserList(ctx context.Context) ([]*db.User, error) {
@@ -363,8 +434,19 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, limit int, args ...interface{}) ([]interface{}, error) {
+	return util.Query(ctx, driver.l, driver.db, statement, limit, args...)
+}
+
+// QueryStream queries a SQL statement and streams the rows to the writer.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, limit int, writer db.QueryRowWriter, args ...interface{}) (int, error) {
+	return util.QueryStream(ctx, driver.l, driver.db, statement, limit, writer, args...)
+}
+
+// QueryCursor queries a SQL statement and returns the cursor to fetch the rows page by page.
+// The statement timeout is enforced by the context deadline, and the driver cancels the statement once the context is done.
+func (driver *Driver) QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (db.QueryCursor, error) {
+	return util.QueryCursor(ctx, driver.l, driver.db, statement, timeout, util.QueryCursorOption{}, args...)
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -660,6 +742,11 @@ const (
 		"%s;\n"
 )
 
+// DumpWithTableRowCount dumps the database without counting the table rows, which isn't supported.
+func (driver *Driver) DumpWithTableRowCount(ctx context.Context, database string, out io.Writer) (map[string]int64, error) {
+	return nil, driver.Dump(ctx, database, out, false /* schemaOnly */)
+}
+
 // Dump dumps the database.
 func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) error {
 	txn, err := driver.db.BeginTx(ctx, &sql.TxOptions{})
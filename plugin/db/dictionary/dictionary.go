@@ -0,0 +1,369 @@
+// Package dictionary renders the data dictionary of database schemas as Markdown, HTML or JSON.
+package dictionary
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Format is the output format of the data dictionary.
+type Format string
+
+const (
+	// Markdown is the Markdown format.
+	Markdown Format = "MARKDOWN"
+	// HTML is the self-contained HTML format.
+	HTML Format = "HTML"
+	// JSON is the JSON format.
+	JSON Format = "JSON"
+)
+
+// ParseFormat parses the format name case-insensitively, "md" is accepted as Markdown.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToUpper(name) {
+	case "MARKDOWN", "MD":
+		return Markdown, nil
+	case "HTML":
+		return HTML, nil
+	case "JSON":
+		return JSON, nil
+	}
+	return "", fmt.Errorf("unsupported data dictionary format %q; supported formats: markdown, html, json", name)
+}
+
+// FormatFromPath returns the format based on the file extension of the path.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md":
+		return Markdown, nil
+	case ".html", ".htm":
+		return HTML, nil
+	case ".json":
+		return JSON, nil
+	}
+	return "", fmt.Errorf("unsupported data dictionary file extension %q; supported extensions: .md, .html, .json", filepath.Ext(path))
+}
+
+// Extension returns the file extension of the format.
+func (f Format) Extension() string {
+	switch f {
+	case Markdown:
+		return ".md"
+	case HTML:
+		return ".html"
+	case JSON:
+		return ".json"
+	}
+	return ""
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case Markdown:
+		return "text/markdown; charset=UTF-8"
+	case HTML:
+		return "text/html; charset=UTF-8"
+	case JSON:
+		return "application/json; charset=UTF-8"
+	}
+	return "text/plain; charset=UTF-8"
+}
+
+// Dictionary is the data dictionary of a list of databases.
+type Dictionary struct {
+	Title        string      `json:"title"`
+	DatabaseList []*Database `json:"databaseList"`
+}
+
+// Database is the data dictionary of a database.
+type Database struct {
+	Name string `json:"name"`
+	// Environment is set when exporting the databases with the same name in different environments.
+	Environment  string   `json:"environment,omitempty"`
+	CharacterSet string   `json:"characterSet,omitempty"`
+	Collation    string   `json:"collation,omitempty"`
+	TableList    []*Table `json:"tableList"`
+	ViewList     []*View  `json:"viewList"`
+}
+
+// Table is the data dictionary of a table.
+type Table struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type,omitempty"`
+	Engine     string    `json:"engine,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	ColumnList []*Column `json:"columnList"`
+	IndexList  []*Index  `json:"indexList"`
+}
+
+// Column is the data dictionary of a column.
+type Column struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Nullable bool    `json:"nullable"`
+	Default  *string `json:"default,omitempty"`
+	Comment  string  `json:"comment,omitempty"`
+}
+
+// Index is the data dictionary of an index.
+type Index struct {
+	Name           string   `json:"name"`
+	ExpressionList []string `json:"expressionList"`
+	Type           string   `json:"type,omitempty"`
+	Unique         bool     `json:"unique"`
+	Comment        string   `json:"comment,omitempty"`
+}
+
+// View is the data dictionary of a view.
+type View struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// NewDatabase converts the synced schema to the data dictionary of the database.
+// Tables and views are sorted by name, columns by position and index expressions by position.
+func NewDatabase(schema *db.Schema) *Database {
+	database := &Database{
+		Name:         schema.Name,
+		CharacterSet: schema.CharacterSet,
+		Collation:    schema.Collation,
+		TableList:    []*Table{},
+		ViewList:     []*View{},
+	}
+	for _, t := range schema.TableList {
+		table := &Table{
+			Name:       t.Name,
+			Type:       t.Type,
+			Engine:     t.Engine,
+			Comment:    t.Comment,
+			ColumnList: []*Column{},
+			IndexList:  []*Index{},
+		}
+		columnList := append([]db.Column{}, t.ColumnList...)
+		sort.SliceStable(columnList, func(i, j int) bool {
+			return columnList[i].Position < columnList[j].Position
+		})
+		for _, column := range columnList {
+			table.ColumnList = append(table.ColumnList, &Column{
+				Name:     column.Name,
+				Type:     column.Type,
+				Nullable: column.Nullable,
+				Default:  column.Default,
+				Comment:  column.Comment,
+			})
+		}
+
+		indexList := append([]db.Index{}, t.IndexList...)
+		sort.SliceStable(indexList, func(i, j int) bool {
+			if indexList[i].Name != indexList[j].Name {
+				return indexList[i].Name < indexList[j].Name
+			}
+			return indexList[i].Position < indexList[j].Position
+		})
+		indexMap := make(map[string]*Index)
+		for _, index := range indexList {
+			if _, ok := indexMap[index.Name]; !ok {
+				indexMap[index.Name] = &Index{
+					Name:    index.Name,
+					Type:    index.Type,
+					Unique:  index.Unique,
+					Comment: index.Comment,
+				}
+				table.IndexList = append(table.IndexList, indexMap[index.Name])
+			}
+			indexMap[index.Name].ExpressionList = append(indexMap[index.Name].ExpressionList, index.Expression)
+		}
+		database.TableList = append(database.TableList, table)
+	}
+	sort.Slice(database.TableList, func(i, j int) bool {
+		return database.TableList[i].Name < database.TableList[j].Name
+	})
+	for _, view := range schema.ViewList {
+		database.ViewList = append(database.ViewList, &View{
+			Name:       view.Name,
+			Definition: view.Definition,
+			Comment:    view.Comment,
+		})
+	}
+	sort.Slice(database.ViewList, func(i, j int) bool {
+		return database.ViewList[i].Name < database.ViewList[j].Name
+	})
+	return database
+}
+
+// Render writes the data dictionary to out in the format.
+func Render(out io.Writer, format Format, dictionary *Dictionary) error {
+	switch format {
+	case Markdown:
+		return renderMarkdown(out, dictionary)
+	case HTML:
+		return htmlTemplate.Execute(out, dictionary)
+	case JSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(dictionary)
+	}
+	return fmt.Errorf("unsupported data dictionary format %q", format)
+}
+
+func renderMarkdown(out io.Writer, dictionary *Dictionary) error {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "# %s\n", escapeMarkdown(dictionary.Title))
+	for _, database := range dictionary.DatabaseList {
+		fmt.Fprintf(&buf, "\n## %s\n\n", escapeMarkdown(database.title()))
+		if database.CharacterSet != "" || database.Collation != "" {
+			fmt.Fprintf(&buf, "- Character set: %s\n- Collation: %s\n\n", escapeMarkdown(database.CharacterSet), escapeMarkdown(database.Collation))
+		}
+		if len(database.TableList) == 0 && len(database.ViewList) == 0 {
+			buf.WriteString("No table or view.\n")
+		}
+		for _, table := range database.TableList {
+			fmt.Fprintf(&buf, "### Table %s\n\n", escapeMarkdown(table.Name))
+			if table.Comment != "" {
+				fmt.Fprintf(&buf, "%s\n\n", escapeMarkdown(table.Comment))
+			}
+			buf.WriteString("| Column | Type | Nullable | Default | Comment |\n")
+			buf.WriteString("| --- | --- | --- | --- | --- |\n")
+			for _, column := range table.ColumnList {
+				fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s |\n",
+					escapeMarkdownCell(column.Name),
+					escapeMarkdownCell(column.Type),
+					yesNo(column.Nullable),
+					escapeMarkdownCell(column.defaultValue()),
+					escapeMarkdownCell(column.Comment),
+				)
+			}
+			buf.WriteString("\n")
+			if len(table.IndexList) > 0 {
+				buf.WriteString("| Index | Expressions | Unique | Type | Comment |\n")
+				buf.WriteString("| --- | --- | --- | --- | --- |\n")
+				for _, index := range table.IndexList {
+					fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s |\n",
+						escapeMarkdownCell(index.Name),
+						escapeMarkdownCell(strings.Join(index.ExpressionList, ", ")),
+						yesNo(index.Unique),
+						escapeMarkdownCell(index.Type),
+						escapeMarkdownCell(index.Comment),
+					)
+				}
+				buf.WriteString("\n")
+			}
+		}
+		for _, view := range database.ViewList {
+			fmt.Fprintf(&buf, "### View %s\n\n", escapeMarkdown(view.Name))
+			if view.Comment != "" {
+				fmt.Fprintf(&buf, "%s\n\n", escapeMarkdown(view.Comment))
+			}
+			fmt.Fprintf(&buf, "```sql\n%s\n```\n\n", strings.TrimSpace(view.Definition))
+		}
+	}
+	_, err := io.WriteString(out, strings.TrimRight(buf.String(), "\n")+"\n")
+	return err
+}
+
+func (d *Database) title() string {
+	if d.Environment != "" {
+		return fmt.Sprintf("%s (%s)", d.Name, d.Environment)
+	}
+	return d.Name
+}
+
+func (c *Column) defaultValue() string {
+	if c.Default == nil {
+		return ""
+	}
+	return *c.Default
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "YES"
+	}
+	return "NO"
+}
+
+var markdownReplacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"*", "\\*",
+	"_", "\\_",
+	"`", "\\`",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// escapeMarkdownCell escapes the text in a table cell, which can't contain the pipe or the line break.
+func escapeMarkdownCell(s string) string {
+	s = escapeMarkdown(s)
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+var htmlTemplate = template.Must(template.New("dictionary").Funcs(template.FuncMap{
+	"join":  strings.Join,
+	"yesNo": yesNo,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+pre { background: #f6f8fa; padding: 1em; overflow: auto; }
+.comment { color: #57606a; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .DatabaseList}}
+<h2>{{.Name}}{{if .Environment}} ({{.Environment}}){{end}}</h2>
+{{- if or .CharacterSet .Collation}}
+<p>Character set: {{.CharacterSet}}, Collation: {{.Collation}}</p>
+{{- end}}
+{{- range .TableList}}
+<h3>Table {{.Name}}</h3>
+{{- if .Comment}}
+<p class="comment">{{.Comment}}</p>
+{{- end}}
+<table>
+<tr><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Comment</th></tr>
+{{- range .ColumnList}}
+<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{yesNo .Nullable}}</td><td>{{if .Default}}{{.Default}}{{end}}</td><td>{{.Comment}}</td></tr>
+{{- end}}
+</table>
+{{- if .IndexList}}
+<table>
+<tr><th>Index</th><th>Expressions</th><th>Unique</th><th>Type</th><th>Comment</th></tr>
+{{- range .IndexList}}
+<tr><td>{{.Name}}</td><td>{{join .ExpressionList ", "}}</td><td>{{yesNo .Unique}}</td><td>{{.Type}}</td><td>{{.Comment}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- range .ViewList}}
+<h3>View {{.Name}}</h3>
+{{- if .Comment}}
+<p class="comment">{{.Comment}}</p>
+{{- end}}
+<pre>{{.Definition}}</pre>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,130 @@
+package dictionary
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func newTestDictionary() *Dictionary {
+	defaultValue := "0"
+	schema := &db.Schema{
+		Name:         "library",
+		CharacterSet: "utf8mb4",
+		Collation:    "utf8mb4_general_ci",
+		TableList: []db.Table{
+			{
+				Name:    "book",
+				Comment: "All the books",
+				ColumnList: []db.Column{
+					{Name: "title", Position: 2, Type: "varchar(64)", Comment: "Title | subtitle"},
+					{Name: "id", Position: 1, Type: "int"},
+					{Name: "stock", Position: 3, Type: "int", Nullable: true, Default: &defaultValue},
+				},
+				IndexList: []db.Index{
+					{Name: "idx_title_stock", Expression: "stock", Position: 2},
+					{Name: "PRIMARY", Expression: "id", Position: 1, Unique: true},
+					{Name: "idx_title_stock", Expression: "title", Position: 1},
+				},
+			},
+		},
+		ViewList: []db.View{{Name: "book_in_stock", Definition: "select * from book where stock > 0", Comment: "Books <in stock>"}},
+	}
+	database := NewDatabase(schema)
+	database.Environment = "Prod"
+	return &Dictionary{Title: "Data dictionary", DatabaseList: []*Database{database}}
+}
+
+func TestNewDatabase(t *testing.T) {
+	database := newTestDictionary().DatabaseList[0]
+	var columnNames []string
+	for _, column := range database.TableList[0].ColumnList {
+		columnNames = append(columnNames, column.Name)
+	}
+	if got, want := strings.Join(columnNames, ","), "id,title,stock"; got != want {
+		t.Errorf("NewDatabase() columns = %q, want %q", got, want)
+	}
+	indexList := database.TableList[0].IndexList
+	if len(indexList) != 2 {
+		t.Fatalf("NewDatabase() has %d indexes, want 2", len(indexList))
+	}
+	if got, want := strings.Join(indexList[1].ExpressionList, ","), "title,stock"; indexList[1].Name != "idx_title_stock" || got != want {
+		t.Errorf("NewDatabase() index %q expressions = %q, want idx_title_stock with %q", indexList[1].Name, got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		format   Format
+		contains []string
+	}{
+		{
+			format: Markdown,
+			contains: []string{
+				"# Data dictionary\n",
+				"## library (Prod)\n",
+				"### Table book\n\nAll the books\n",
+				"| title | varchar(64) | NO |  | Title \\| subtitle |\n",
+				"| stock | int | YES | 0 |  |\n",
+				"| idx\\_title\\_stock | title, stock | NO |  |  |\n",
+				"### View book\\_in\\_stock\n\nBooks &lt;in stock&gt;\n",
+				"```sql\nselect * from book where stock > 0\n```\n",
+			},
+		},
+		{
+			format: HTML,
+			contains: []string{
+				"<h2>library (Prod)</h2>",
+				"<tr><td>stock</td><td>int</td><td>YES</td><td>0</td><td></td></tr>",
+				"<tr><td>idx_title_stock</td><td>title, stock</td><td>NO</td><td></td><td></td></tr>",
+				"<p class=\"comment\">Books &lt;in stock&gt;</p>",
+				"<pre>select * from book where stock &gt; 0</pre>",
+			},
+		},
+		{
+			format: JSON,
+			contains: []string{
+				`"environment": "Prod"`,
+				`"default": "0"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := Render(&buf, test.format, newTestDictionary()); err != nil {
+			t.Fatalf("Render(%s) error: %v", test.format, err)
+		}
+		for _, s := range test.contains {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("Render(%s) = %s, want containing %q", test.format, buf.String(), s)
+			}
+		}
+		if test.format == JSON {
+			var dictionary Dictionary
+			if err := json.Unmarshal(buf.Bytes(), &dictionary); err != nil {
+				t.Errorf("Render(%s) is not valid JSON: %v", test.format, err)
+			}
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, name := range []string{"md", "Markdown"} {
+		if format, err := ParseFormat(name); err != nil || format != Markdown {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", name, format, err, Markdown)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Errorf("ParseFormat(%q) got no error", "pdf")
+	}
+	if format, err := FormatFromPath("bytebase/prod/.library__DICTIONARY.html"); err != nil || format != HTML {
+		t.Errorf("FormatFromPath() = %q, %v, want %q", format, err, HTML)
+	}
+	if _, err := FormatFromPath("bytebase/prod/.library__DICTIONARY.txt"); err == nil {
+		t.Errorf("FormatFromPath() got no error for .txt")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bytebase/bytebase/plugin/vcs"
 	"go.uber.org/zap"
@@ -57,12 +58,70 @@ type Index struct {
 	// Type isn't supported for SQLite.
 	Type   string
 	Unique bool
+	// Primary is true if the index backs the primary key.
+	// Primary isn't supported for SQLite.
+	Primary bool
 	// Visible isn't supported for Postgres, SQLite.
 	Visible bool
 	// Comment isn't supported for SQLite.
 	Comment string
 }
 
+// ForeignKey is the database foreign key.
+// Similar to Index, there is one ForeignKey for each column of the constraint.
+type ForeignKey struct {
+	Name     string
+	Column   string
+	Position int
+	// ReferencedTable follows the Table name convention of the engine, and is qualified with the database name
+	// if the referenced table is in another MySQL database.
+	ReferencedTable  string
+	ReferencedColumn string
+	// OnUpdate isn't supported for ClickHouse, Snowflake.
+	OnUpdate string
+	// OnDelete isn't supported for ClickHouse, Snowflake.
+	OnDelete string
+}
+
+// CheckConstraint is the database check constraint.
+type CheckConstraint struct {
+	Name       string
+	Expression string
+}
+
+// Trigger is the database table trigger.
+type Trigger struct {
+	Name string
+	// Timing is BEFORE, AFTER or INSTEAD OF.
+	Timing string
+	// Event is one or more of INSERT, UPDATE, DELETE and TRUNCATE joined with " OR ".
+	Event string
+	// Statement is the trigger body for MySQL, and the whole CREATE TRIGGER statement for Postgres, SQLite.
+	Statement string
+}
+
+// Routine is the database function or procedure.
+type Routine struct {
+	Name string
+	// Type is either FUNCTION or PROCEDURE.
+	Type string
+	// Definition is the routine body for MySQL, and the whole CREATE statement for Postgres.
+	Definition string
+	Comment    string
+}
+
+// Sequence is the database sequence.
+type Sequence struct {
+	Name       string
+	DataType   string
+	StartValue string
+	Increment  string
+	MinValue   string
+	MaxValue   string
+	Cycle      bool
+	CacheSize  string
+}
+
 // Column the database table column.
 type Column struct {
 	Name     string
@@ -105,6 +164,12 @@ type Table struct {
 	ColumnList []Column
 	// IndexList isn't supported for ClickHouse, Snowflake.
 	IndexList []Index
+	// ForeignKeyList isn't supported for ClickHouse, Snowflake.
+	ForeignKeyList []ForeignKey
+	// CheckConstraintList isn't supported for MySQL 5.7, TiDB, ClickHouse, Snowflake.
+	CheckConstraintList []CheckConstraint
+	// TriggerList isn't supported for ClickHouse, Snowflake.
+	TriggerList []Trigger
 }
 
 // Schema is the database schema.
@@ -117,6 +182,117 @@ type Schema struct {
 	UserList  []User
 	TableList []Table
 	ViewList  []View
+	// RoutineList isn't supported for ClickHouse, Snowflake, SQLite.
+	RoutineList []Routine
+	// SequenceList isn't supported for MySQL, ClickHouse, Snowflake, SQLite.
+	SequenceList []Sequence
+}
+
+// InstanceHealth is the health status of an instance collected from the engine statistics.
+type InstanceHealth struct {
+	// ReplicaList is the replication channels of the instance as a replica for MySQL,
+	// or the replicas connected to the instance as a primary for Postgres.
+	ReplicaList []Replica
+	Connections int
+	// MaxConnections is 0 if the connections are unlimited.
+	MaxConnections int
+	// TransactionList is the transactions running longer than the requested duration, ordered by the start time.
+	TransactionList []Transaction
+	// StorageList is the size of the databases, and the tablespaces for Postgres.
+	StorageList []Storage
+}
+
+// Replica is the replication status of a replica.
+type Replica struct {
+	Name    string
+	Running bool
+	// LagSeconds is 0 if the replication isn't running.
+	LagSeconds int64
+	// Detail is the last replication error if any.
+	Detail string
+}
+
+// Transaction is a running transaction.
+type Transaction struct {
+	ProcessID       string
+	Database        string
+	User            string
+	DurationSeconds int64
+	// Query is the statement currently executed by the transaction, which can be empty.
+	Query string
+}
+
+// StorageType is the type of a storage.
+type StorageType string
+
+const (
+	// StorageDatabase is the storage of a database.
+	StorageDatabase StorageType = "DATABASE"
+	// StorageTablespace is the storage of a tablespace.
+	StorageTablespace StorageType = "TABLESPACE"
+)
+
+// Storage is the disk usage of a database or a tablespace.
+type Storage struct {
+	Type      StorageType
+	Name      string
+	SizeBytes int64
+}
+
+// QueryDigest is the cumulative statistics of a normalized statement since the engine statistics were reset.
+type QueryDigest struct {
+	Database string
+	// Digest identifies the normalized statement within the database.
+	Digest string
+	// Statement is the normalized statement text.
+	Statement      string
+	Calls          int64
+	TotalLatencyUs int64
+	// RowsExamined is the rows read for MySQL, and the rows retrieved or affected for Postgres since it doesn't track the rows read.
+	RowsExamined int64
+}
+
+// QueryRowWriter receives the streamed result of a readonly query.
+type QueryRowWriter interface {
+	// WriteHeader is called once with the column names and the column type names before any row.
+	WriteHeader(columnNameList []string, columnTypeNameList []string) error
+	// WriteRow is called for each row, with the values converted the same as Query.
+	WriteRow(row []interface{}) error
+}
+
+// QueryCursor is the open result of a readonly query, whose rows are fetched page by page instead of being held in memory.
+// It holds a database connection until closed.
+type QueryCursor interface {
+	// Header returns the column names and the column type names.
+	Header() ([]string, []string)
+	// Next returns at most n rows converted the same as Query, or all the remaining rows if n <= 0,
+	// and whether there are more rows after them.
+	Next(n int) ([]interface{}, bool, error)
+	// Close closes the cursor and releases the connection. It's safe to call more than once.
+	Close() error
+}
+
+// QueryPlan is the estimated execution plan of a statement.
+type QueryPlan struct {
+	// Raw is the plan in the JSON format of the engine.
+	Raw  string
+	Root *QueryPlanNode
+}
+
+// QueryPlanNode is a node of the execution plan normalized across the engines.
+type QueryPlanNode struct {
+	// NodeType is the operation of the node, e.g. "Full Table Scan" for MySQL or "Seq Scan" for Postgres.
+	NodeType string
+	// Table and Index are the table and the index accessed by the node, if any.
+	Table string
+	Index string
+	// EstimatedRows is the estimated rows examined by the node, 0 if unknown.
+	EstimatedRows float64
+	// Cost is the estimated cost of the node in the unit of the engine, including its children for Postgres.
+	Cost float64
+	// FullTableScan is true if the node reads all the rows of the table.
+	FullTableScan bool
+	ChildList     []*QueryPlanNode
 }
 
 var (
@@ -382,11 +558,37 @@ type Driver interface {
 	Ping(ctx context.Context) error
 	GetDbConnection(ctx context.Context, database string) (*sql.DB, error)
 	GetVersion(ctx context.Context) (string, error)
-	SyncSchema(ctx context.Context) ([]*User, []*Schema, error)
+	// SyncUser syncs the users of the instance.
+	SyncUser(ctx context.Context) ([]*User, error)
+	// SyncSchema syncs the schema of the databases in databaseList, or all databases if databaseList is empty.
+	SyncSchema(ctx context.Context, databaseList ...string) ([]*Schema, error)
+	// GetSchemaFingerprint returns the DDL fingerprint of every database to sync keyed by the database name, which changes whenever
+	// the database schema changes. It's much cheaper than SyncSchema, so the databases with unchanged fingerprints can skip the sync.
+	// The fingerprint is empty if the engine doesn't support it, in which case the database should always be synced.
+	GetSchemaFingerprint(ctx context.Context) (map[string]string, error)
+	// GetInstanceHealth returns the health status of the instance, with the transactions running longer than transactionDuration.
+	// It returns nil if the engine doesn't support it.
+	GetInstanceHealth(ctx context.Context, transactionDuration time.Duration) (*InstanceHealth, error)
+	// GetQueryDigestList returns the cumulative statement statistics of all databases,
+	// MySQL performance_schema.events_statements_summary_by_digest or Postgres pg_stat_statements.
+	// It returns nil if the engine doesn't support it or the statistics aren't enabled.
+	GetQueryDigestList(ctx context.Context) ([]*QueryDigest, error)
+	// Explain returns the estimated execution plan of the readonly statement without running it.
+	// It returns nil if the engine doesn't support it, or an error if the engine only doesn't support the plan format, e.g. TiDB.
+	Explain(ctx context.Context, statement string) (*QueryPlan, error)
 	Execute(ctx context.Context, statement string, useTransaction bool) error
 	// Used for execute readonly SELECT statement
 	// limit is the maximum row count returned. No limit enforced if limit <= 0
-	Query(ctx context.Context, statement string, limit int) ([]interface{}, error)
+	// args are bound to the positional parameters of the statement, "$n" for Postgres and "?" otherwise.
+	Query(ctx context.Context, statement string, limit int, args ...interface{}) ([]interface{}, error)
+	// QueryStream is the same as Query, except that it streams the rows to the writer instead of holding all of them.
+	// It returns the number of rows written.
+	QueryStream(ctx context.Context, statement string, limit int, writer QueryRowWriter, args ...interface{}) (int, error)
+	// QueryCursor runs the readonly statement and returns the cursor to fetch the rows page by page.
+	// Once ctx is done, the statement is killed by the database and the cursor is closed.
+	// If timeout > 0, the statement is killed once it runs longer than timeout, including the time fetching the rows,
+	// which is enforced by the database if supported, e.g. MySQL max_execution_time and Postgres statement_timeout.
+	QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (QueryCursor, error)
 
 	// Migration related
 	// Check whether we need to setup migration (e.g. creating/upgrading the migration related tables)
@@ -403,6 +605,10 @@ type Driver interface {
 	// Dump and restore
 	// Dump the database, if dbName is empty, then dump all databases.
 	Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) error
+	// DumpWithTableRowCount dumps the data of the database like Dump, and returns the number of rows of each base table
+	// counted by COUNT(*) in the same snapshot as the dump, keyed by the table name, qualified by the schema for Postgres.
+	// It returns nil table row count if the engine doesn't support it.
+	DumpWithTableRowCount(ctx context.Context, database string, out io.Writer) (map[string]int64, error)
 	// Restore the database from sc.
 	Restore(ctx context.Context, sc *bufio.Scanner) error
 }
@@ -0,0 +1,308 @@
+// Package erd renders the entity relationship diagram of a database schema as Mermaid or Graphviz DOT.
+package erd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Format is the output format of the ER diagram.
+type Format string
+
+const (
+	// Mermaid is the Mermaid erDiagram format.
+	Mermaid Format = "MERMAID"
+	// DOT is the Graphviz DOT format.
+	DOT Format = "DOT"
+)
+
+// ParseFormat parses the format name case-insensitively, "graphviz" is accepted as DOT.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToUpper(name) {
+	case "MERMAID":
+		return Mermaid, nil
+	case "DOT", "GRAPHVIZ":
+		return DOT, nil
+	}
+	return "", fmt.Errorf("unsupported ER diagram format %q; supported formats: mermaid, dot", name)
+}
+
+// Extension returns the file extension of the format.
+func (f Format) Extension() string {
+	switch f {
+	case Mermaid:
+		return ".mmd"
+	case DOT:
+		return ".dot"
+	}
+	return ""
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case DOT:
+		return "text/vnd.graphviz; charset=UTF-8"
+	}
+	return "text/plain; charset=UTF-8"
+}
+
+// Diagram is the ER diagram of a database.
+type Diagram struct {
+	Name             string
+	TableList        []*Table
+	RelationshipList []*Relationship
+}
+
+// Table is an entity of the ER diagram.
+type Table struct {
+	Name       string
+	ColumnList []*Column
+}
+
+// Column is an attribute of the entity.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// KeyList is the subset of PK, FK and UK, in that order.
+	KeyList []string
+}
+
+// Relationship is a foreign key from Table to ReferencedTable.
+type Relationship struct {
+	Name                 string
+	Table                string
+	ColumnList           []string
+	ReferencedTable      string
+	ReferencedColumnList []string
+	// Nullable is true if any of the foreign key columns is nullable, meaning the referenced row is optional.
+	Nullable bool
+}
+
+// NewDiagram builds the ER diagram from the synced schema.
+// If tableList is not empty, only these tables and the relationships among them are included.
+// Tables are sorted by name, columns by position, and relationships by table and name.
+func NewDiagram(schema *db.Schema, tableList []string) (*Diagram, error) {
+	tableMap := make(map[string]bool)
+	for _, table := range schema.TableList {
+		tableMap[table.Name] = true
+	}
+	selected := make(map[string]bool)
+	for _, name := range tableList {
+		if !tableMap[name] {
+			return nil, fmt.Errorf("table %q not found in database %q", name, schema.Name)
+		}
+		selected[name] = true
+	}
+	include := func(name string) bool {
+		return len(selected) == 0 || selected[name]
+	}
+
+	diagram := &Diagram{
+		Name:             schema.Name,
+		TableList:        []*Table{},
+		RelationshipList: []*Relationship{},
+	}
+	for _, table := range schema.TableList {
+		if !include(table.Name) {
+			continue
+		}
+
+		keyMap := make(map[string]map[string]bool)
+		addKey := func(column, key string) {
+			if keyMap[column] == nil {
+				keyMap[column] = make(map[string]bool)
+			}
+			keyMap[column][key] = true
+		}
+		for _, index := range table.IndexList {
+			if isPrimaryIndex(table.Name, index.Name) {
+				addKey(index.Expression, "PK")
+			} else if index.Unique {
+				addKey(index.Expression, "UK")
+			}
+		}
+		for _, fk := range table.ForeignKeyList {
+			addKey(fk.Column, "FK")
+		}
+
+		nullableMap := make(map[string]bool)
+		columnList := append([]db.Column{}, table.ColumnList...)
+		sort.SliceStable(columnList, func(i, j int) bool {
+			return columnList[i].Position < columnList[j].Position
+		})
+		t := &Table{Name: table.Name}
+		for _, column := range columnList {
+			c := &Column{
+				Name:     column.Name,
+				Type:     column.Type,
+				Nullable: column.Nullable,
+			}
+			for _, key := range []string{"PK", "FK", "UK"} {
+				if keyMap[column.Name][key] {
+					c.KeyList = append(c.KeyList, key)
+				}
+			}
+			nullableMap[column.Name] = column.Nullable
+			t.ColumnList = append(t.ColumnList, c)
+		}
+		diagram.TableList = append(diagram.TableList, t)
+
+		// Group the foreign key columns by the constraint name.
+		relationshipMap := make(map[string]*Relationship)
+		var relationshipList []*Relationship
+		fkList := append([]db.ForeignKey{}, table.ForeignKeyList...)
+		sort.SliceStable(fkList, func(i, j int) bool {
+			return fkList[i].Position < fkList[j].Position
+		})
+		for _, fk := range fkList {
+			if !tableMap[fk.ReferencedTable] || !include(fk.ReferencedTable) {
+				continue
+			}
+			relationship, ok := relationshipMap[fk.Name]
+			if !ok {
+				relationship = &Relationship{
+					Name:            fk.Name,
+					Table:           table.Name,
+					ReferencedTable: fk.ReferencedTable,
+				}
+				relationshipMap[fk.Name] = relationship
+				relationshipList = append(relationshipList, relationship)
+			}
+			relationship.ColumnList = append(relationship.ColumnList, fk.Column)
+			relationship.ReferencedColumnList = append(relationship.ReferencedColumnList, fk.ReferencedColumn)
+			if nullableMap[fk.Column] {
+				relationship.Nullable = true
+			}
+		}
+		diagram.RelationshipList = append(diagram.RelationshipList, relationshipList...)
+	}
+
+	sort.Slice(diagram.TableList, func(i, j int) bool {
+		return diagram.TableList[i].Name < diagram.TableList[j].Name
+	})
+	sort.SliceStable(diagram.RelationshipList, func(i, j int) bool {
+		if diagram.RelationshipList[i].Table != diagram.RelationshipList[j].Table {
+			return diagram.RelationshipList[i].Table < diagram.RelationshipList[j].Table
+		}
+		return diagram.RelationshipList[i].Name < diagram.RelationshipList[j].Name
+	})
+	return diagram, nil
+}
+
+// isPrimaryIndex returns whether the index is the primary key.
+// MySQL names the primary key PRIMARY, and Postgres names it "<table>_pkey" by default.
+func isPrimaryIndex(tableName, indexName string) bool {
+	if indexName == "PRIMARY" {
+		return true
+	}
+	if i := strings.LastIndex(tableName, "."); i >= 0 {
+		tableName = tableName[i+1:]
+	}
+	return indexName == tableName+"_pkey"
+}
+
+// Render writes the ER diagram in the format.
+func Render(out io.Writer, format Format, diagram *Diagram) error {
+	switch format {
+	case Mermaid:
+		return renderMermaid(out, diagram)
+	case DOT:
+		return renderDOT(out, diagram)
+	}
+	return fmt.Errorf("unsupported ER diagram format %q", format)
+}
+
+func renderMermaid(out io.Writer, diagram *Diagram) error {
+	var buf strings.Builder
+	buf.WriteString("erDiagram\n")
+	for _, table := range diagram.TableList {
+		fmt.Fprintf(&buf, "    %s {\n", mermaidIdentifier(table.Name))
+		for _, column := range table.ColumnList {
+			fmt.Fprintf(&buf, "        %s %s", mermaidIdentifier(column.Type), mermaidIdentifier(column.Name))
+			if len(column.KeyList) > 0 {
+				fmt.Fprintf(&buf, " %s", strings.Join(column.KeyList, ", "))
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("    }\n")
+	}
+	for _, relationship := range diagram.RelationshipList {
+		// The table has zero or more rows referencing exactly one, or at most one if nullable, referenced row.
+		cardinality := "||"
+		if relationship.Nullable {
+			cardinality = "o|"
+		}
+		fmt.Fprintf(&buf, "    %s }o--%s %s : %q\n",
+			mermaidIdentifier(relationship.Table),
+			cardinality,
+			mermaidIdentifier(relationship.ReferencedTable),
+			strings.ReplaceAll(relationship.Name, `"`, "'"),
+		)
+	}
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
+
+var mermaidInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+
+// mermaidIdentifier replaces the characters unsupported by the Mermaid entity, attribute type and name with "_",
+// e.g. "public.user" becomes "public_user" and "decimal(10,2) unsigned" becomes "decimal(10_2)_unsigned".
+func mermaidIdentifier(s string) string {
+	s = mermaidInvalidChars.ReplaceAllString(strings.ReplaceAll(s, `"`, ""), "_")
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+func renderDOT(out io.Writer, diagram *Diagram) error {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "digraph %s {\n", dotID(diagram.Name))
+	buf.WriteString("    graph [rankdir=LR];\n")
+	buf.WriteString("    node [shape=plaintext];\n")
+	for _, table := range diagram.TableList {
+		fmt.Fprintf(&buf, "    %s [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">", dotID(table.Name))
+		fmt.Fprintf(&buf, "<TR><TD BGCOLOR=\"lightgrey\" COLSPAN=\"2\"><B>%s</B></TD></TR>", html.EscapeString(table.Name))
+		for _, column := range table.ColumnList {
+			name := html.EscapeString(column.Name)
+			if len(column.KeyList) > 0 {
+				name = fmt.Sprintf("%s (%s)", name, strings.Join(column.KeyList, ", "))
+			}
+			fmt.Fprintf(&buf, "<TR><TD PORT=%q ALIGN=\"LEFT\">%s</TD><TD ALIGN=\"LEFT\">%s</TD></TR>",
+				html.EscapeString(column.Name), name, html.EscapeString(column.Type))
+		}
+		buf.WriteString("</TABLE>>];\n")
+	}
+	for _, relationship := range diagram.RelationshipList {
+		// Points the edge from the first foreign key column to the first referenced column.
+		from := dotID(relationship.Table)
+		if len(relationship.ColumnList) > 0 {
+			from = fmt.Sprintf("%s:%s", from, dotID(relationship.ColumnList[0]))
+		}
+		to := dotID(relationship.ReferencedTable)
+		if len(relationship.ReferencedColumnList) > 0 && relationship.ReferencedColumnList[0] != "" {
+			to = fmt.Sprintf("%s:%s", to, dotID(relationship.ReferencedColumnList[0]))
+		}
+		style := "solid"
+		if relationship.Nullable {
+			style = "dashed"
+		}
+		fmt.Fprintf(&buf, "    %s -> %s [label=%s, style=%s];\n", from, to, dotID(relationship.Name), style)
+	}
+	buf.WriteString("}\n")
+	_, err := io.WriteString(out, buf.String())
+	return err
+}
+
+// dotID returns the double-quoted DOT ID.
+func dotID(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
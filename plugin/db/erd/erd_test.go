@@ -0,0 +1,146 @@
+package erd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func newTestSchema() *db.Schema {
+	return &db.Schema{
+		Name: "library",
+		TableList: []db.Table{
+			{
+				Name: "loan",
+				ColumnList: []db.Column{
+					{Name: "book_id", Position: 2, Type: "int"},
+					{Name: "id", Position: 1, Type: "int"},
+					{Name: "member_id", Position: 3, Type: "int", Nullable: true},
+				},
+				IndexList: []db.Index{
+					{Name: "PRIMARY", Expression: "id", Position: 1, Unique: true},
+				},
+				ForeignKeyList: []db.ForeignKey{
+					{Name: "fk_loan_member", Column: "member_id", Position: 1, ReferencedTable: "member", ReferencedColumn: "id"},
+					{Name: "fk_loan_book", Column: "book_id", Position: 1, ReferencedTable: "book", ReferencedColumn: "id"},
+				},
+			},
+			{
+				Name: "book",
+				ColumnList: []db.Column{
+					{Name: "id", Position: 1, Type: "int"},
+					{Name: "isbn", Position: 2, Type: "varchar(13)"},
+					{Name: "price", Position: 3, Type: "decimal(10,2) unsigned"},
+				},
+				IndexList: []db.Index{
+					{Name: "PRIMARY", Expression: "id", Position: 1, Unique: true},
+					{Name: "uk_isbn", Expression: "isbn", Position: 1, Unique: true},
+				},
+			},
+			{
+				Name: "member",
+				ColumnList: []db.Column{
+					{Name: "id", Position: 1, Type: "int"},
+				},
+				IndexList: []db.Index{
+					{Name: "member_pkey", Expression: "id", Position: 1, Unique: true},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	diagram, err := NewDiagram(newTestSchema(), nil)
+	if err != nil {
+		t.Fatalf("NewDiagram() got error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, Mermaid, diagram); err != nil {
+		t.Fatalf("Render() got error: %v", err)
+	}
+	want := `erDiagram
+    book {
+        int id PK
+        varchar(13) isbn UK
+        decimal(10_2)_unsigned price
+    }
+    loan {
+        int id PK
+        int book_id FK
+        int member_id FK
+    }
+    member {
+        int id PK
+    }
+    loan }o--|| book : "fk_loan_book"
+    loan }o--o| member : "fk_loan_member"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Render() got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderDOT(t *testing.T) {
+	diagram, err := NewDiagram(newTestSchema(), nil)
+	if err != nil {
+		t.Fatalf("NewDiagram() got error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, DOT, diagram); err != nil {
+		t.Fatalf("Render() got error: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`digraph "library" {`,
+		`<TD PORT="isbn" ALIGN="LEFT">isbn (UK)</TD><TD ALIGN="LEFT">varchar(13)</TD>`,
+		`"loan":"book_id" -> "book":"id" [label="fk_loan_book", style=solid];`,
+		`"loan":"member_id" -> "member":"id" [label="fk_loan_member", style=dashed];`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() got:\n%s\nwant to contain: %s", got, want)
+		}
+	}
+}
+
+func TestNewDiagramTableFilter(t *testing.T) {
+	diagram, err := NewDiagram(newTestSchema(), []string{"loan", "book"})
+	if err != nil {
+		t.Fatalf("NewDiagram() got error: %v", err)
+	}
+	if len(diagram.TableList) != 2 {
+		t.Fatalf("NewDiagram() got %d tables, want 2", len(diagram.TableList))
+	}
+	if len(diagram.RelationshipList) != 1 || diagram.RelationshipList[0].Name != "fk_loan_book" {
+		t.Errorf("NewDiagram() got relationships %+v, want only fk_loan_book", diagram.RelationshipList)
+	}
+
+	if _, err := NewDiagram(newTestSchema(), []string{"author"}); err == nil {
+		t.Errorf("NewDiagram() with unknown table got nil error, want error")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{name: "mermaid", want: Mermaid},
+		{name: "DOT", want: DOT},
+		{name: "graphviz", want: DOT},
+		{name: "svg", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseFormat(test.name)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseFormat(%q) got error %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseFormat(%q) got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
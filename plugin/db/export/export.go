@@ -0,0 +1,307 @@
+// Package export writes the streamed query result as CSV, JSON Lines or XLSX.
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Format is the file format of the exported query result.
+type Format string
+
+const (
+	// CSV is the CSV format with a header row.
+	CSV Format = "CSV"
+	// JSONL is the JSON Lines format with one JSON object per row.
+	JSONL Format = "JSONL"
+	// XLSX is the Excel workbook format with a single sheet.
+	XLSX Format = "XLSX"
+
+	// xlsxMaxRowCount is the maximum number of rows in an Excel sheet, including the header row.
+	xlsxMaxRowCount = 1048576
+	// maxSafeInteger is the maximum integer represented exactly by a double, which Excel and JavaScript use for numbers.
+	maxSafeInteger = 1<<53 - 1
+)
+
+// ParseFormat parses the format name case-insensitively, "NDJSON" is accepted as JSONL.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToUpper(name) {
+	case "CSV":
+		return CSV, nil
+	case "JSONL", "NDJSON":
+		return JSONL, nil
+	case "XLSX":
+		return XLSX, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q; supported formats: csv, jsonl, xlsx", name)
+}
+
+// Extension returns the file extension of the format.
+func (f Format) Extension() string {
+	switch f {
+	case CSV:
+		return ".csv"
+	case JSONL:
+		return ".jsonl"
+	case XLSX:
+		return ".xlsx"
+	}
+	return ""
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=UTF-8"
+	case JSONL:
+		return "application/x-ndjson; charset=UTF-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// MaxRowCount returns the maximum number of rows the format can hold, 0 if unlimited.
+func (f Format) MaxRowCount() int {
+	if f == XLSX {
+		return xlsxMaxRowCount - 1
+	}
+	return 0
+}
+
+// Writer writes the streamed query result in a format.
+type Writer interface {
+	db.QueryRowWriter
+	// Close writes the end of the file and flushes the buffered data, but doesn't close the underlying writer.
+	Close() error
+}
+
+// NewWriter returns a writer writing the query result to w in the format.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case JSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w)}, nil
+	case XLSX:
+		return &xlsxWriter{zw: zip.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// formatValue formats the value converted by db.Driver.Query as text, nil as the empty string.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvWriter) WriteHeader(columnNameList []string, _ []string) error {
+	return w.w.Write(columnNameList)
+}
+
+func (w *csvWriter) WriteRow(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = formatValue(value)
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlWriter struct {
+	w *bufio.Writer
+	// The JSON encoded column names, so that each object keeps the column order.
+	keyList [][]byte
+}
+
+func (w *jsonlWriter) WriteHeader(columnNameList []string, _ []string) error {
+	for _, name := range columnNameList {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		w.keyList = append(w.keyList, key)
+	}
+	return nil
+}
+
+func (w *jsonlWriter) WriteRow(row []interface{}) error {
+	w.w.WriteByte('{')
+	for i, value := range row {
+		if i > 0 {
+			w.w.WriteByte(',')
+		}
+		if i < len(w.keyList) {
+			w.w.Write(w.keyList[i])
+		} else {
+			w.w.WriteString(strconv.Quote(strconv.Itoa(i)))
+		}
+		w.w.WriteByte(':')
+		// JSON has no representation of NaN and infinity.
+		if v, ok := value.(float64); ok && (math.IsNaN(v) || math.IsInf(v, 0)) {
+			value = formatValue(v)
+		}
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		w.w.Write(bytes)
+	}
+	w.w.WriteByte('}')
+	// bufio.Writer keeps the first error, which is returned by the last write.
+	return w.w.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.w.Flush()
+}
+
+// xlsxStaticFileList is the workbook files other than the sheet, in the order written.
+var xlsxStaticFileList = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Result" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// xlsxWriter writes a minimal workbook by hand, where the sheet is streamed with inline strings
+// so that no shared string table has to be held in memory.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+}
+
+func (w *xlsxWriter) WriteHeader(columnNameList []string, _ []string) error {
+	for _, file := range xlsxStaticFileList {
+		fw, err := w.zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, file.content); err != nil {
+			return err
+		}
+	}
+	fw, err := w.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.sheet = bufio.NewWriter(fw)
+	w.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	row := make([]interface{}, len(columnNameList))
+	for i, name := range columnNameList {
+		row[i] = name
+	}
+	return w.WriteRow(row)
+}
+
+func (w *xlsxWriter) WriteRow(row []interface{}) error {
+	w.sheet.WriteString("<row>")
+	for _, value := range row {
+		switch v := value.(type) {
+		case nil:
+			w.sheet.WriteString("<c/>")
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			w.sheet.WriteString(`<c t="b"><v>` + b + `</v></c>`)
+		case int64:
+			w.writeNumber(v > maxSafeInteger || v < -maxSafeInteger, formatValue(v))
+		case int32:
+			w.writeNumber(false, formatValue(v))
+		case float64:
+			w.writeNumber(math.IsNaN(v) || math.IsInf(v, 0), formatValue(v))
+		default:
+			w.writeString(formatValue(v))
+		}
+	}
+	// bufio.Writer keeps the first error, which is returned by the last write.
+	_, err := w.sheet.WriteString("</row>")
+	return err
+}
+
+// writeNumber writes a numeric cell, or a text cell if Excel can't represent the number exactly.
+func (w *xlsxWriter) writeNumber(asString bool, s string) {
+	if asString {
+		w.writeString(s)
+		return
+	}
+	w.sheet.WriteString(`<c t="n"><v>` + s + `</v></c>`)
+}
+
+func (w *xlsxWriter) writeString(s string) {
+	w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	// EscapeText only fails on the write error, which is kept by bufio.Writer.
+	_ = xml.EscapeText(w.sheet, []byte(s))
+	w.sheet.WriteString(`</t></is></c>`)
+}
+
+func (w *xlsxWriter) Close() error {
+	if w.sheet != nil {
+		w.sheet.WriteString(`</sheetData></worksheet>`)
+		if err := w.sheet.Flush(); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+var (
+	testColumnNameList     = []string{"id", "name", "active", "score"}
+	testColumnTypeNameList = []string{"INT", "VARCHAR", "BOOL", "FLOAT"}
+	testRowList            = [][]interface{}{
+		{int64(1), "Alice, \"A\"", true, 1.5},
+		{int64(2), "<Bob>", false, nil},
+		{int64(1 << 60), nil, nil, math.NaN()},
+	}
+)
+
+func writeTestResult(t *testing.T, format Format) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("NewWriter(%s) returns error: %v", format, err)
+	}
+	if err := w.WriteHeader(testColumnNameList, testColumnTypeNameList); err != nil {
+		t.Fatalf("WriteHeader() returns error: %v", err)
+	}
+	for _, row := range testRowList {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() returns error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() returns error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{
+		"csv":    CSV,
+		"JSONL":  JSONL,
+		"ndjson": JSONL,
+		"Xlsx":   XLSX,
+	}
+	for name, want := range tests {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xls"); err == nil {
+		t.Errorf("ParseFormat(%q) returns no error", "xls")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	want := "id,name,active,score\n" +
+		"1,\"Alice, \"\"A\"\"\",true,1.5\n" +
+		"2,<Bob>,false,\n" +
+		"1152921504606846976,,,NaN\n"
+	if got := string(writeTestResult(t, CSV)); got != want {
+		t.Errorf("got CSV %q, want %q", got, want)
+	}
+}
+
+func TestJSONLWriter(t *testing.T) {
+	want := `{"id":1,"name":"Alice, \"A\"","active":true,"score":1.5}` + "\n" +
+		`{"id":2,"name":"\u003cBob\u003e","active":false,"score":null}` + "\n" +
+		`{"id":1152921504606846976,"name":null,"active":null,"score":"NaN"}` + "\n"
+	if got := string(writeTestResult(t, JSONL)); got != want {
+		t.Errorf("got JSONL %q, want %q", got, want)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	content := writeTestResult(t, XLSX)
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("failed to open the workbook: %v", err)
+	}
+
+	var nameList []string
+	var sheet string
+	for _, file := range zr.File {
+		nameList = append(nameList, file.Name)
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open the sheet: %v", err)
+		}
+		bytes, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to read the sheet: %v", err)
+		}
+		sheet = string(bytes)
+	}
+	if got, want := strings.Join(nameList, ","), "[Content_Types].xml,_rels/.rels,xl/workbook.xml,xl/_rels/workbook.xml.rels,xl/worksheets/sheet1.xml"; got != want {
+		t.Errorf("got workbook files %q, want %q", got, want)
+	}
+
+	for _, want := range []string{
+		`<row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`,
+		`<c t="n"><v>1</v></c><c t="inlineStr"><is><t xml:space="preserve">Alice, &#34;A&#34;</t></is></c><c t="b"><v>1</v></c><c t="n"><v>1.5</v></c></row>`,
+		`<c t="inlineStr"><is><t xml:space="preserve">&lt;Bob&gt;</t></is></c><c t="b"><v>0</v></c><c/></row>`,
+		// Not representable by Excel numbers.
+		`<row><c t="inlineStr"><is><t xml:space="preserve">1152921504606846976</t></is></c><c/><c/><c t="inlineStr"><is><t xml:space="preserve">NaN</t></is></c></row>`,
+		`</sheetData></worksheet>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet %q doesn't contain %q", sheet, want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Package masking masks the sensitive columns in the query result, where the result columns are resolved
+// back to the source columns by parsing the statement.
+package masking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Type is the algorithm masking a sensitive value.
+type Type string
+
+const (
+	// None leaves the value in clear text.
+	None Type = ""
+	// Full replaces the value with a fixed mask regardless of its length.
+	Full Type = "FULL"
+	// Partial keeps the first and the last quarter of the value, or the first character and the domain of an email.
+	Partial Type = "PARTIAL"
+	// Hash replaces the value with its SHA-256 digest, so that equal values are still recognizable.
+	Hash Type = "HASH"
+
+	// fullMask is the value masked by Full.
+	fullMask = "******"
+)
+
+// typeStrength is the order of the types from the least to the most secure,
+// where Hash only reveals the equality while Partial reveals a part of the value.
+var typeStrength = map[Type]int{
+	None:    0,
+	Partial: 1,
+	Hash:    2,
+	Full:    3,
+}
+
+// Valid returns whether the type is a masking algorithm.
+func (t Type) Valid() bool {
+	return t == Full || t == Partial || t == Hash
+}
+
+// stronger returns the more secure type of the two.
+func stronger(a, b Type) Type {
+	if typeStrength[b] > typeStrength[a] {
+		return b
+	}
+	return a
+}
+
+// Mask returns the value masked by the type, nil is kept as is.
+func Mask(value interface{}, t Type) interface{} {
+	if value == nil || t == None {
+		return value
+	}
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+	switch t {
+	case Partial:
+		return maskPartial(s)
+	case Hash:
+		digest := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(digest[:])
+	}
+	return fullMask
+}
+
+func maskPartial(s string) string {
+	if at := strings.LastIndex(s, "@"); at > 0 {
+		local := []rune(s[:at])
+		return string(local[0]) + strings.Repeat("*", len(local)-1) + s[at:]
+	}
+	runes := []rune(s)
+	keep := len(runes) / 4
+	return string(runes[:keep]) + strings.Repeat("*", len(runes)-2*keep) + string(runes[len(runes)-keep:])
+}
+
+// MaskRow masks the row in place by the type of each column.
+func MaskRow(row []interface{}, typeList []Type) {
+	for i := range row {
+		if i < len(typeList) {
+			row[i] = Mask(row[i], typeList[i])
+		}
+	}
+}
+
+// SensitiveColumn is a column marked sensitive in the metadata.
+type SensitiveColumn struct {
+	Database string
+	// Table is qualified by the schema for Postgres, e.g. "public.user".
+	Table  string
+	Column string
+	Type   Type
+}
+
+// Pattern marks the columns sensitive if the column name matches the regular expression.
+type Pattern struct {
+	Regexp *regexp.Regexp
+	Type   Type
+}
+
+// Catalog returns the column names of the table ordered by position, false if the table is unknown.
+type Catalog func(database, table string) ([]string, bool)
+
+// Config is the configuration of a masker.
+type Config struct {
+	Engine db.Type
+	// Database is the database connected, which can be empty for MySQL.
+	Database            string
+	SensitiveColumnList []*SensitiveColumn
+	PatternList         []*Pattern
+	// Catalog is used to expand SELECT *.
+	Catalog Catalog
+}
+
+// Masker masks the query result of a statement.
+type Masker struct {
+	config           *Config
+	resultColumnList []*resultColumn
+	// err is the reason that the result columns can't be resolved, in which case all the columns are masked.
+	err error
+}
+
+// NewMasker returns a masker of the statement.
+func NewMasker(config *Config, statement string) *Masker {
+	resultColumnList, err := resolveResultColumnList(config, statement)
+	return &Masker{
+		config:           config,
+		resultColumnList: resultColumnList,
+		err:              err,
+	}
+}
+
+// Err returns the reason that the result columns can't be resolved, nil if resolved.
+func (m *Masker) Err() error {
+	return m.err
+}
+
+// TypeList returns the masking type of each result column.
+// Every column is masked by Full if the result columns can't be resolved to the source columns.
+func (m *Masker) TypeList(columnNameList []string) []Type {
+	typeList := make([]Type, len(columnNameList))
+	if m.err != nil || len(m.resultColumnList) != len(columnNameList) {
+		for i := range typeList {
+			typeList[i] = Full
+		}
+		return typeList
+	}
+	for i, column := range m.resultColumnList {
+		t := None
+		for _, source := range column.sourceList {
+			t = stronger(t, m.getSourceType(source))
+		}
+		// A computed value may reveal any part of the source, e.g. SUBSTR(email, 2, 3).
+		if t != None && column.computed {
+			t = Full
+		}
+		typeList[i] = t
+	}
+	return typeList
+}
+
+// getSourceType returns the masking type of the source column, where the empty database or table matches any.
+func (m *Masker) getSourceType(source *sourceColumn) Type {
+	t := None
+	for _, column := range m.config.SensitiveColumnList {
+		if !strings.EqualFold(column.Column, source.column) {
+			continue
+		}
+		if source.table != "" && !strings.EqualFold(column.Table, source.table) {
+			continue
+		}
+		if source.database != "" && !strings.EqualFold(column.Database, source.database) {
+			continue
+		}
+		t = stronger(t, column.Type)
+	}
+	for _, pattern := range m.config.PatternList {
+		if pattern.Regexp.MatchString(source.column) {
+			t = stronger(t, pattern.Type)
+		}
+	}
+	return t
+}
+
+// NewWriter returns a writer masking the rows before writing them to w.
+func (m *Masker) NewWriter(w db.QueryRowWriter) db.QueryRowWriter {
+	return &maskingWriter{masker: m, w: w}
+}
+
+type maskingWriter struct {
+	masker   *Masker
+	w        db.QueryRowWriter
+	typeList []Type
+}
+
+func (w *maskingWriter) WriteHeader(columnNameList []string, columnTypeNameList []string) error {
+	w.typeList = w.masker.TypeList(columnNameList)
+	return w.w.WriteHeader(columnNameList, columnTypeNameList)
+}
+
+func (w *maskingWriter) WriteRow(row []interface{}) error {
+	MaskRow(row, w.typeList)
+	return w.w.WriteRow(row)
+}
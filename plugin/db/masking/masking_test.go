@@ -0,0 +1,198 @@
+package masking
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		t     Type
+		want  interface{}
+	}{
+		{value: "alice@example.com", t: None, want: "alice@example.com"},
+		{value: nil, t: Full, want: nil},
+		{value: "alice@example.com", t: Full, want: "******"},
+		{value: int64(42), t: Full, want: "******"},
+		{value: "alice@example.com", t: Partial, want: "a****@example.com"},
+		{value: "110101199003077777", t: Partial, want: "1101**********7777"},
+		{value: "abc", t: Partial, want: "***"},
+		{value: int64(12345678), t: Partial, want: "12****78"},
+		{value: "alice", t: Hash, want: "2bd806c97f0e00af1a1fc3328fa763a9269723c8db8fac4f93af71db186d6e90"},
+	}
+	for _, test := range tests {
+		if got := Mask(test.value, test.t); got != test.want {
+			t.Errorf("Mask(%v, %q) = %v, want %v", test.value, test.t, got, test.want)
+		}
+	}
+}
+
+func newTestConfig(engine db.Type, database string) *Config {
+	catalog := map[string][]string{
+		"hr.employee":          {"id", "name", "email", "dept_id"},
+		"hr.dept":              {"id", "name"},
+		"app.public.user":      {"id", "email", "phone"},
+		"app.billing.customer": {"id", "card_no"},
+	}
+	return &Config{
+		Engine:   engine,
+		Database: database,
+		SensitiveColumnList: []*SensitiveColumn{
+			{Database: "hr", Table: "employee", Column: "email", Type: Partial},
+			{Database: "app", Table: "public.user", Column: "email", Type: Hash},
+		},
+		PatternList: []*Pattern{
+			{Regexp: regexp.MustCompile(`(?i)^(phone|card_no)$`), Type: Full},
+		},
+		Catalog: func(database, table string) ([]string, bool) {
+			columnList, ok := catalog[database+"."+table]
+			return columnList, ok
+		},
+	}
+}
+
+func TestMaskerTypeList(t *testing.T) {
+	tests := []struct {
+		engine         db.Type
+		database       string
+		statement      string
+		columnNameList []string
+		want           []Type
+	}{
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT id, email FROM employee",
+			columnNameList: []string{"id", "email"},
+			want:           []Type{None, Partial},
+		},
+		// Aliasing.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT e.email AS contact, d.name FROM employee e JOIN dept d ON e.dept_id = d.id",
+			columnNameList: []string{"contact", "name"},
+			want:           []Type{Partial, None},
+		},
+		// SELECT * across the joined tables.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT * FROM employee, dept",
+			columnNameList: []string{"id", "name", "email", "dept_id", "id", "name"},
+			want:           []Type{None, None, Partial, None, None, None},
+		},
+		// The database qualified table without a connected database.
+		{
+			engine:         db.MySQL,
+			database:       "",
+			statement:      "SELECT `e`.* FROM hr.employee AS e",
+			columnNameList: []string{"id", "name", "email", "dept_id"},
+			want:           []Type{None, None, Partial, None},
+		},
+		// Derived table, CTE and set operation.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "WITH c AS (SELECT email AS mail FROM employee) SELECT t.x FROM (SELECT mail AS x FROM c UNION ALL SELECT name FROM dept) t",
+			columnNameList: []string{"x"},
+			want:           []Type{Partial},
+		},
+		// The computed value is masked fully, except COUNT.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT SUBSTR(email, 2, 3), COUNT(email), (SELECT MAX(email) FROM employee) FROM employee",
+			columnNameList: []string{"SUBSTR(email, 2, 3)", "COUNT(email)", "(SELECT MAX(email) FROM employee)"},
+			want:           []Type{Full, None, Full},
+		},
+		// The columns of a table unknown to the catalog.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT phone, note FROM contact",
+			columnNameList: []string{"phone", "note"},
+			want:           []Type{Full, None},
+		},
+		// Postgres schema qualified tables and quoted identifiers.
+		{
+			engine:         db.Postgres,
+			database:       "app",
+			statement:      `SELECT u."email", c.card_no FROM "user" u JOIN billing.customer c ON u.id = c.id`,
+			columnNameList: []string{"email", "card_no"},
+			want:           []Type{Hash, Full},
+		},
+		// Unable to expand SELECT * of the unknown table.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT * FROM contact",
+			columnNameList: []string{"id", "phone"},
+			want:           []Type{Full, Full},
+		},
+		// Syntax not supported by the parser.
+		{
+			engine:         db.Postgres,
+			database:       "app",
+			statement:      "SELECT id::text FROM public.user",
+			columnNameList: []string{"id"},
+			want:           []Type{Full},
+		},
+		// The result doesn't match the resolved columns.
+		{
+			engine:         db.MySQL,
+			database:       "hr",
+			statement:      "SELECT id FROM employee",
+			columnNameList: []string{"id", "name"},
+			want:           []Type{Full, Full},
+		},
+	}
+
+	for _, test := range tests {
+		masker := NewMasker(newTestConfig(test.engine, test.database), test.statement)
+		if got := masker.TypeList(test.columnNameList); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("TypeList() of %q = %q, want %q, resolve error: %v", test.statement, got, test.want, masker.Err())
+		}
+	}
+}
+
+type testRowWriter struct {
+	rowList [][]interface{}
+}
+
+func (w *testRowWriter) WriteHeader(_ []string, _ []string) error {
+	return nil
+}
+
+func (w *testRowWriter) WriteRow(row []interface{}) error {
+	w.rowList = append(w.rowList, row)
+	return nil
+}
+
+func TestMaskingWriter(t *testing.T) {
+	masker := NewMasker(newTestConfig(db.MySQL, "hr"), "SELECT id, email FROM employee")
+	w := &testRowWriter{}
+	mw := masker.NewWriter(w)
+	if err := mw.WriteHeader([]string{"id", "email"}, []string{"INT", "VARCHAR"}); err != nil {
+		t.Fatalf("WriteHeader() returns error: %v", err)
+	}
+	if err := mw.WriteRow([]interface{}{int64(1), "bob@example.com"}); err != nil {
+		t.Fatalf("WriteRow() returns error: %v", err)
+	}
+	if err := mw.WriteRow([]interface{}{int64(2), nil}); err != nil {
+		t.Fatalf("WriteRow() returns error: %v", err)
+	}
+	want := [][]interface{}{
+		{int64(1), "b**@example.com"},
+		{int64(2), nil},
+	}
+	if !reflect.DeepEqual(w.rowList, want) {
+		t.Errorf("got rows %v, want %v", w.rowList, want)
+	}
+}
@@ -0,0 +1,358 @@
+package masking
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+)
+
+// sourceColumn is a column of a table, where the database and the table are empty if unknown.
+type sourceColumn struct {
+	database string
+	table    string
+	column   string
+}
+
+// resultColumn is a column of a result set and the source columns making up its value.
+type resultColumn struct {
+	// name is used to reference the column from the outer query, empty if it's an expression without an alias.
+	name       string
+	sourceList []*sourceColumn
+	// computed is true if the value is computed from the source columns instead of being one of them.
+	computed bool
+}
+
+// tableRef is a table or a derived table in the FROM clause.
+type tableRef struct {
+	// name is the alias or the table name used to qualify the column references.
+	name     string
+	database string
+	table    string
+	// columnList is nil if the columns of the table are unknown.
+	columnList []*resultColumn
+}
+
+// scope is the tables and the common table expressions visible to a query block.
+type scope struct {
+	parent    *scope
+	tableList []*tableRef
+	cteMap    map[string][]*resultColumn
+}
+
+func (s *scope) findCTE(name string) ([]*resultColumn, bool) {
+	for ; s != nil; s = s.parent {
+		if columnList, ok := s.cteMap[name]; ok {
+			return columnList, true
+		}
+	}
+	return nil, false
+}
+
+type resolver struct {
+	config *Config
+}
+
+// resolveResultColumnList parses the SELECT statement and resolves its result columns to the source columns.
+func resolveResultColumnList(config *Config, statement string) ([]*resultColumn, error) {
+	p := parser.New()
+	p.EnableWindowFunc(true)
+	if config.Engine == db.Postgres {
+		// Postgres quotes the identifiers with double quotes.
+		p.SetSQLMode(mysql.ModeANSIQuotes)
+	}
+	nodeList, _, err := p.Parse(statement, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if len(nodeList) != 1 {
+		return nil, fmt.Errorf("expect a single statement, got %d", len(nodeList))
+	}
+	r := &resolver{config: config}
+	return r.resolveResultSet(nodeList[0], nil)
+}
+
+func (r *resolver) resolveResultSet(node ast.Node, parent *scope) ([]*resultColumn, error) {
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		return r.resolveSelect(n, parent)
+	case *ast.SetOprStmt:
+		s := &scope{parent: parent}
+		if err := r.resolveWith(n.With, s); err != nil {
+			return nil, err
+		}
+		return r.resolveResultSet(n.SelectList, s)
+	case *ast.SetOprSelectList:
+		s := &scope{parent: parent}
+		if err := r.resolveWith(n.With, s); err != nil {
+			return nil, err
+		}
+		var columnList []*resultColumn
+		for i, selectNode := range n.Selects {
+			selectColumnList, err := r.resolveResultSet(selectNode, s)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				columnList = selectColumnList
+				continue
+			}
+			if len(selectColumnList) != len(columnList) {
+				return nil, fmt.Errorf("the set operation has %d columns in the first query but %d columns in the query %d", len(columnList), len(selectColumnList), i+1)
+			}
+			for j, column := range selectColumnList {
+				columnList[j] = &resultColumn{
+					name:       columnList[j].name,
+					sourceList: append(append([]*sourceColumn{}, columnList[j].sourceList...), column.sourceList...),
+					computed:   columnList[j].computed || column.computed,
+				}
+			}
+		}
+		return columnList, nil
+	}
+	return nil, fmt.Errorf("unsupported statement %T", node)
+}
+
+func (r *resolver) resolveWith(with *ast.WithClause, s *scope) error {
+	if with == nil {
+		return nil
+	}
+	s.cteMap = make(map[string][]*resultColumn)
+	for _, cte := range with.CTEs {
+		columnList, err := r.resolveResultSet(cte.Query.Query, s)
+		if err != nil {
+			return err
+		}
+		if len(cte.ColNameList) > 0 {
+			if len(cte.ColNameList) != len(columnList) {
+				return fmt.Errorf("common table expression %q has %d column names but %d columns", cte.Name.O, len(cte.ColNameList), len(columnList))
+			}
+			for i, name := range cte.ColNameList {
+				columnList[i] = &resultColumn{name: name.L, sourceList: columnList[i].sourceList, computed: columnList[i].computed}
+			}
+		}
+		s.cteMap[cte.Name.L] = columnList
+	}
+	return nil
+}
+
+func (r *resolver) resolveSelect(sel *ast.SelectStmt, parent *scope) ([]*resultColumn, error) {
+	if sel.Fields == nil {
+		return nil, fmt.Errorf("unsupported SELECT statement without fields")
+	}
+	s := &scope{parent: parent}
+	if err := r.resolveWith(sel.With, s); err != nil {
+		return nil, err
+	}
+	if sel.From != nil {
+		tableList, err := r.resolveTableRefs(sel.From.TableRefs, s)
+		if err != nil {
+			return nil, err
+		}
+		s.tableList = tableList
+	}
+
+	var columnList []*resultColumn
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard != nil {
+			expanded := false
+			for _, table := range s.tableList {
+				if field.WildCard.Table.L != "" && field.WildCard.Table.L != table.name {
+					continue
+				}
+				if table.columnList == nil {
+					return nil, fmt.Errorf("unknown columns of table %q", table.name)
+				}
+				columnList = append(columnList, table.columnList...)
+				expanded = true
+			}
+			if !expanded {
+				return nil, fmt.Errorf("no table matches %s.*", field.WildCard.Table.O)
+			}
+			continue
+		}
+
+		column, err := r.resolveExpr(field.Expr, s)
+		if err != nil {
+			return nil, err
+		}
+		name := field.AsName.L
+		if name == "" {
+			if columnNameExpr, ok := field.Expr.(*ast.ColumnNameExpr); ok {
+				name = columnNameExpr.Name.Name.L
+			}
+		}
+		columnList = append(columnList, &resultColumn{name: name, sourceList: column.sourceList, computed: column.computed})
+	}
+	return columnList, nil
+}
+
+func (r *resolver) resolveTableRefs(node ast.ResultSetNode, s *scope) ([]*tableRef, error) {
+	switch n := node.(type) {
+	case nil:
+		return nil, nil
+	case *ast.Join:
+		left, err := r.resolveTableRefs(n.Left, s)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.resolveTableRefs(n.Right, s)
+		if err != nil {
+			return nil, err
+		}
+		return append(left, right...), nil
+	case *ast.TableSource:
+		switch source := n.Source.(type) {
+		case *ast.TableName:
+			table := r.resolveTableName(source, s)
+			if n.AsName.L != "" {
+				table.name = n.AsName.L
+			}
+			return []*tableRef{table}, nil
+		case *ast.Join:
+			return r.resolveTableRefs(source, s)
+		default:
+			columnList, err := r.resolveResultSet(source, s)
+			if err != nil {
+				return nil, err
+			}
+			return []*tableRef{{name: n.AsName.L, columnList: columnList}}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported table reference %T", node)
+}
+
+func (r *resolver) resolveTableName(tableName *ast.TableName, s *scope) *tableRef {
+	if tableName.Schema.L == "" {
+		if columnList, ok := s.findCTE(tableName.Name.L); ok {
+			return &tableRef{name: tableName.Name.L, columnList: columnList}
+		}
+	}
+
+	table := &tableRef{name: tableName.Name.L}
+	if r.config.Engine == db.Postgres {
+		// The tables are qualified by the schema in Postgres, and the database is always the connected one.
+		schema := tableName.Schema.L
+		if schema == "" {
+			schema = "public"
+		}
+		table.database = r.config.Database
+		table.table = schema + "." + tableName.Name.L
+	} else {
+		table.database = tableName.Schema.L
+		if table.database == "" {
+			table.database = r.config.Database
+		}
+		table.table = tableName.Name.L
+	}
+
+	if r.config.Catalog != nil {
+		if columnNameList, ok := r.config.Catalog(table.database, table.table); ok {
+			for _, name := range columnNameList {
+				name = strings.ToLower(name)
+				table.columnList = append(table.columnList, &resultColumn{
+					name:       name,
+					sourceList: []*sourceColumn{{database: table.database, table: table.table, column: name}},
+				})
+			}
+		}
+	}
+	return table
+}
+
+func (r *resolver) resolveExpr(expr ast.ExprNode, s *scope) (*resultColumn, error) {
+	for {
+		parentheses, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			break
+		}
+		expr = parentheses.Expr
+	}
+	if columnNameExpr, ok := expr.(*ast.ColumnNameExpr); ok {
+		return r.resolveColumnName(columnNameExpr.Name, s)
+	}
+
+	v := &exprVisitor{resolver: r, scope: s}
+	expr.Accept(v)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return &resultColumn{sourceList: v.sourceList, computed: true}, nil
+}
+
+// resolveColumnName finds the column in the scope, falling back to the outer scope for correlated subqueries.
+// The column of a table with unknown columns is assumed to exist in the table.
+func (r *resolver) resolveColumnName(columnName *ast.ColumnName, s *scope) (*resultColumn, error) {
+	for current := s; current != nil; current = current.parent {
+		var unknownList []*sourceColumn
+		for _, table := range current.tableList {
+			if columnName.Table.L != "" && columnName.Table.L != table.name {
+				continue
+			}
+			if table.columnList == nil {
+				unknownList = append(unknownList, &sourceColumn{database: table.database, table: table.table, column: columnName.Name.L})
+				continue
+			}
+			for _, column := range table.columnList {
+				if column.name == columnName.Name.L {
+					return column, nil
+				}
+			}
+		}
+		if len(unknownList) > 0 {
+			return &resultColumn{name: columnName.Name.L, sourceList: unknownList}, nil
+		}
+	}
+	if columnName.Table.L != "" {
+		return nil, fmt.Errorf("unknown column %s.%s", columnName.Table.O, columnName.Name.O)
+	}
+	// It can be a reference to a SELECT field alias, so match the columns of the same name in any table.
+	return &resultColumn{name: columnName.Name.L, sourceList: []*sourceColumn{{column: columnName.Name.L}}}, nil
+}
+
+// exprVisitor collects the source columns of an expression.
+type exprVisitor struct {
+	resolver   *resolver
+	scope      *scope
+	sourceList []*sourceColumn
+	err        error
+}
+
+func (v *exprVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if v.err != nil {
+		return in, true
+	}
+	switch node := in.(type) {
+	case *ast.ColumnNameExpr:
+		column, err := v.resolver.resolveColumnName(node.Name, v.scope)
+		if err != nil {
+			v.err = err
+			return in, true
+		}
+		v.sourceList = append(v.sourceList, column.sourceList...)
+		return in, true
+	case *ast.SubqueryExpr:
+		columnList, err := v.resolver.resolveResultSet(node.Query, v.scope)
+		if err != nil {
+			v.err = err
+			return in, true
+		}
+		for _, column := range columnList {
+			v.sourceList = append(v.sourceList, column.sourceList...)
+		}
+		return in, true
+	case *ast.AggregateFuncExpr:
+		// COUNT doesn't reveal the values.
+		if strings.EqualFold(node.F, ast.AggFuncCount) {
+			return in, true
+		}
+	}
+	return in, false
+}
+
+func (v *exprVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,187 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// BinlogInfo is the binlog coordinate of a consistent snapshot.
+type BinlogInfo struct {
+	// FileName is the binlog file name, e.g. "binlog.000012".
+	FileName string
+	// Position is the position in the binlog file where the snapshot is taken.
+	Position int64
+	// Ts is the unix timestamp of the snapshot in the clock of the MySQL server.
+	Ts int64
+	// ServerUUID is the server UUID of the MySQL server.
+	ServerUUID string
+}
+
+// BinlogFile is a binlog file of the MySQL server.
+type BinlogFile struct {
+	Name string
+	Size int64
+}
+
+// connQueryer runs the queries on a single connection, so that they share the session transaction.
+type connQueryer struct {
+	ctx  context.Context
+	conn *sql.Conn
+}
+
+func (q *connQueryer) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return q.conn.QueryContext(q.ctx, query, args...)
+}
+
+// DumpWithBinlogInfo dumps the database with a consistent snapshot, and returns the binlog coordinate of the snapshot
+// along with the number of rows of each base table counted in the snapshot, see DumpWithTableRowCount.
+// Replaying the binlog events from the coordinate on top of the dump recovers the database to a later point in time.
+// It requires the RELOAD privilege to acquire the global read lock, and the binlog must be enabled.
+func (driver *Driver) DumpWithBinlogInfo(ctx context.Context, database string, out io.Writer) (*BinlogInfo, map[string]int64, error) {
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	// This follows mysqldump --single-transaction --master-data. The global read lock is only held
+	// until the snapshot is started, so that the binlog coordinate matches the snapshot.
+	if _, err := conn.ExecContext(ctx, "FLUSH TABLES WITH READ LOCK"); err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire the global read lock: %w", err)
+	}
+	// Never return the connection to the pool with the lock or the snapshot transaction left behind.
+	defer conn.ExecContext(ctx, "UNLOCK TABLES")
+	defer conn.ExecContext(ctx, "ROLLBACK")
+	binlogInfo, err := driver.startSnapshotWithBinlogInfo(ctx, conn)
+	if _, unlockErr := conn.ExecContext(ctx, "UNLOCK TABLES"); unlockErr != nil && err == nil {
+		err = fmt.Errorf("failed to release the global read lock: %w", unlockErr)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tableRowCount, err := dumpTxnWithTableRowCount(ctx, &connQueryer{ctx: ctx, conn: conn}, database, out)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return nil, nil, err
+	}
+	return binlogInfo, tableRowCount, nil
+}
+
+// startSnapshot starts the transaction with a consistent snapshot on the connection.
+func (driver *Driver) startSnapshot(ctx context.Context, conn *sql.Conn) error {
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+		return err
+	}
+	stmt := "START TRANSACTION WITH CONSISTENT SNAPSHOT"
+	// TiDB does not support readonly, so we only set for MySQL.
+	if driver.dbType == db.MySQL {
+		stmt += ", READ ONLY"
+	}
+	_, err := conn.ExecContext(ctx, stmt)
+	return err
+}
+
+func (driver *Driver) startSnapshotWithBinlogInfo(ctx context.Context, conn *sql.Conn) (*BinlogInfo, error) {
+	if err := driver.startSnapshot(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("binlog is not enabled")
+	}
+	// The columns vary across versions, but the file and the position always come first.
+	binlogInfo := &BinlogInfo{}
+	values := make([]interface{}, len(cols))
+	values[0] = &binlogInfo.FileName
+	values[1] = &binlogInfo.Position
+	for i := 2; i < len(cols); i++ {
+		values[i] = new(sql.RawBytes)
+	}
+	if err := rows.Scan(values...); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := conn.QueryRowContext(ctx, "SELECT UNIX_TIMESTAMP(), @@server_uuid").Scan(&binlogInfo.Ts, &binlogInfo.ServerUUID); err != nil {
+		return nil, err
+	}
+	return binlogInfo, nil
+}
+
+// GetBinlogFileList returns the binlog files of the MySQL server in order.
+// The last file is the one being written.
+func (driver *Driver) GetBinlogFileList(ctx context.Context) ([]*BinlogFile, error) {
+	rows, err := driver.db.QueryContext(ctx, "SHOW BINARY LOGS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var fileList []*BinlogFile
+	for rows.Next() {
+		// MySQL 8.0 has an additional "Encrypted" column.
+		file := &BinlogFile{}
+		values := make([]interface{}, len(cols))
+		values[0] = &file.Name
+		values[1] = &file.Size
+		for i := 2; i < len(cols); i++ {
+			values[i] = new(sql.RawBytes)
+		}
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		fileList = append(fileList, file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fileList, nil
+}
+
+// GetServerUUID returns the server UUID of the MySQL server, which is generated when the data directory is initialized.
+func (driver *Driver) GetServerUUID(ctx context.Context) (string, error) {
+	var serverUUID string
+	if err := driver.db.QueryRowContext(ctx, "SELECT @@server_uuid").Scan(&serverUUID); err != nil {
+		return "", err
+	}
+	return serverUUID, nil
+}
+
+// GetBinlogFileSeq returns the sequence number in the binlog file name, e.g. 12 for "binlog.000012".
+// The sequence number increases with each new binlog file.
+func GetBinlogFileSeq(name string) (int64, error) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid binlog file name %q", name)
+	}
+	seq, err := strconv.ParseInt(name[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid binlog file name %q: %w", name, err)
+	}
+	return seq, nil
+}
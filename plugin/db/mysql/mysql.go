@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	// embed will embeds the migration schema.
 	_ "embed"
@@ -143,33 +147,24 @@ func (driver *Driver) GetVersion(ctx context.Context) (string, error) {
 	return version, nil
 }
 
+// SyncUser syncs the users of the instance.
+func (driver *Driver) SyncUser(ctx context.Context) ([]*db.User, error) {
+	return driver.getUserList(ctx)
+}
+
 // SyncSchema synces the schema.
-func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema, error) {
+func (driver *Driver) SyncSchema(ctx context.Context, databaseList ...string) ([]*db.Schema, error) {
 	// Query MySQL version
 	version, err := driver.GetVersion(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	isMySQL8 := strings.HasPrefix(version, "8.0")
 
-	excludedDatabaseList := []string{
-		// Skip our internal "bytebase" database
-		"'bytebase'",
-	}
-
-	// Skip all system databases
-	for k := range systemDatabases {
-		excludedDatabaseList = append(excludedDatabaseList, fmt.Sprintf("'%s'", k))
-	}
-
-	// Query user info
-	userList, err := driver.getUserList(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
+	excludedDatabaseList := getExcludedDatabaseList()
 
 	// Query index info
-	indexWhere := fmt.Sprintf("LOWER(TABLE_SCHEMA) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	indexWhere := getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, databaseList)
 	query := `
 			SELECT
 				TABLE_SCHEMA,
@@ -202,7 +197,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 	}
 	indexRows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer indexRows.Close()
 
@@ -226,7 +221,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&index.Visible,
 			&index.Comment,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		if columnName.Valid {
@@ -234,6 +229,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		} else if expression.Valid {
 			index.Expression = expression.String
 		}
+		index.Primary = index.Name == "PRIMARY"
 
 		key := fmt.Sprintf("%s/%s", dbName, tableName)
 		if indexList, ok := indexMap[key]; ok {
@@ -244,7 +240,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 	}
 
 	// Query column info
-	columnWhere := fmt.Sprintf("LOWER(TABLE_SCHEMA) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	columnWhere := getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, databaseList)
 	query = `
 			SELECT
 				TABLE_SCHEMA,
@@ -261,7 +257,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			WHERE ` + columnWhere
 	columnRows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer columnRows.Close()
 
@@ -285,7 +281,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&column.Collation,
 			&column.Comment,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		if defaultStr.Valid {
@@ -300,8 +296,179 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		}
 	}
 
+	// Query foreign key info
+	foreignKeyWhere := getDatabaseWhere("k.TABLE_SCHEMA", excludedDatabaseList, databaseList)
+	query = `
+			SELECT
+				k.TABLE_SCHEMA,
+				k.TABLE_NAME,
+				k.CONSTRAINT_NAME,
+				k.COLUMN_NAME,
+				k.ORDINAL_POSITION,
+				k.REFERENCED_TABLE_SCHEMA,
+				k.REFERENCED_TABLE_NAME,
+				k.REFERENCED_COLUMN_NAME,
+				r.UPDATE_RULE,
+				r.DELETE_RULE
+			FROM information_schema.KEY_COLUMN_USAGE k
+			JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+				ON k.CONSTRAINT_SCHEMA = r.CONSTRAINT_SCHEMA AND k.TABLE_NAME = r.TABLE_NAME AND k.CONSTRAINT_NAME = r.CONSTRAINT_NAME
+			WHERE k.REFERENCED_TABLE_NAME IS NOT NULL AND ` + foreignKeyWhere + `
+			ORDER BY k.TABLE_SCHEMA, k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION`
+	foreignKeyRows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer foreignKeyRows.Close()
+
+	// dbName/tableName -> foreignKeyList map
+	foreignKeyMap := make(map[string][]db.ForeignKey)
+	for foreignKeyRows.Next() {
+		var dbName string
+		var tableName string
+		var referencedDBName string
+		var foreignKey db.ForeignKey
+		if err := foreignKeyRows.Scan(
+			&dbName,
+			&tableName,
+			&foreignKey.Name,
+			&foreignKey.Column,
+			&foreignKey.Position,
+			&referencedDBName,
+			&foreignKey.ReferencedTable,
+			&foreignKey.ReferencedColumn,
+			&foreignKey.OnUpdate,
+			&foreignKey.OnDelete,
+		); err != nil {
+			return nil, err
+		}
+
+		if referencedDBName != dbName {
+			foreignKey.ReferencedTable = fmt.Sprintf("%s.%s", referencedDBName, foreignKey.ReferencedTable)
+		}
+
+		key := fmt.Sprintf("%s/%s", dbName, tableName)
+		foreignKeyMap[key] = append(foreignKeyMap[key], foreignKey)
+	}
+
+	// dbName/tableName -> checkConstraintList map
+	checkConstraintMap := make(map[string][]db.CheckConstraint)
+	if isCheckConstraintSupported(version) {
+		// Query check constraint info
+		checkConstraintWhere := getDatabaseWhere("t.CONSTRAINT_SCHEMA", excludedDatabaseList, databaseList)
+		query = `
+			SELECT
+				t.CONSTRAINT_SCHEMA,
+				t.TABLE_NAME,
+				t.CONSTRAINT_NAME,
+				c.CHECK_CLAUSE
+			FROM information_schema.TABLE_CONSTRAINTS t
+			JOIN information_schema.CHECK_CONSTRAINTS c
+				ON t.CONSTRAINT_SCHEMA = c.CONSTRAINT_SCHEMA AND t.CONSTRAINT_NAME = c.CONSTRAINT_NAME
+			WHERE t.CONSTRAINT_TYPE = 'CHECK' AND ` + checkConstraintWhere + `
+			ORDER BY t.CONSTRAINT_SCHEMA, t.TABLE_NAME, t.CONSTRAINT_NAME`
+		checkConstraintRows, err := driver.db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, util.FormatErrorWithQuery(err, query)
+		}
+		defer checkConstraintRows.Close()
+
+		for checkConstraintRows.Next() {
+			var dbName string
+			var tableName string
+			var checkConstraint db.CheckConstraint
+			if err := checkConstraintRows.Scan(
+				&dbName,
+				&tableName,
+				&checkConstraint.Name,
+				&checkConstraint.Expression,
+			); err != nil {
+				return nil, err
+			}
+
+			key := fmt.Sprintf("%s/%s", dbName, tableName)
+			checkConstraintMap[key] = append(checkConstraintMap[key], checkConstraint)
+		}
+	}
+
+	// Query trigger info
+	triggerWhere := getDatabaseWhere("TRIGGER_SCHEMA", excludedDatabaseList, databaseList)
+	query = `
+			SELECT
+				TRIGGER_SCHEMA,
+				EVENT_OBJECT_TABLE,
+				TRIGGER_NAME,
+				ACTION_TIMING,
+				EVENT_MANIPULATION,
+				ACTION_STATEMENT
+			FROM information_schema.TRIGGERS
+			WHERE ` + triggerWhere + `
+			ORDER BY TRIGGER_SCHEMA, EVENT_OBJECT_TABLE, TRIGGER_NAME`
+	triggerRows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer triggerRows.Close()
+
+	// dbName/tableName -> triggerList map
+	triggerMap := make(map[string][]db.Trigger)
+	for triggerRows.Next() {
+		var dbName string
+		var tableName string
+		var trigger db.Trigger
+		if err := triggerRows.Scan(
+			&dbName,
+			&tableName,
+			&trigger.Name,
+			&trigger.Timing,
+			&trigger.Event,
+			&trigger.Statement,
+		); err != nil {
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s/%s", dbName, tableName)
+		triggerMap[key] = append(triggerMap[key], trigger)
+	}
+
+	// Query routine info
+	routineWhere := getDatabaseWhere("ROUTINE_SCHEMA", excludedDatabaseList, databaseList)
+	query = `
+			SELECT
+				ROUTINE_SCHEMA,
+				ROUTINE_NAME,
+				ROUTINE_TYPE,
+				IFNULL(ROUTINE_DEFINITION, ''),
+				ROUTINE_COMMENT
+			FROM information_schema.ROUTINES
+			WHERE ` + routineWhere + `
+			ORDER BY ROUTINE_SCHEMA, ROUTINE_NAME`
+	routineRows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer routineRows.Close()
+
+	// dbName -> routineList map
+	routineMap := make(map[string][]db.Routine)
+	for routineRows.Next() {
+		var dbName string
+		var routine db.Routine
+		if err := routineRows.Scan(
+			&dbName,
+			&routine.Name,
+			&routine.Type,
+			&routine.Definition,
+			&routine.Comment,
+		); err != nil {
+			return nil, err
+		}
+
+		routineMap[dbName] = append(routineMap[dbName], routine)
+	}
+
 	// Query table info
-	tableWhere := fmt.Sprintf("LOWER(TABLE_SCHEMA) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	tableWhere := getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, databaseList)
 	query = `
 			SELECT
 				TABLE_SCHEMA,
@@ -321,7 +488,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			WHERE ` + tableWhere
 	tableRows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer tableRows.Close()
 
@@ -354,7 +521,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&table.CreateOptions,
 			&table.Comment,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		if table.Type == baseTableType {
@@ -365,6 +532,9 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			key := fmt.Sprintf("%s/%s", dbName, table.Name)
 			table.ColumnList = columnMap[key]
 			table.IndexList = indexMap[key]
+			table.ForeignKeyList = foreignKeyMap[key]
+			table.CheckConstraintList = checkConstraintMap[key]
+			table.TriggerList = triggerMap[key]
 
 			if tableList, ok := tableMap[dbName]; ok {
 				tableMap[dbName] = append(tableList, table)
@@ -381,7 +551,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 	}
 
 	// Query view info
-	viewWhere := fmt.Sprintf("LOWER(TABLE_SCHEMA) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	viewWhere := getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, databaseList)
 	query = `
 			SELECT
 				TABLE_SCHEMA,
@@ -391,7 +561,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			WHERE ` + viewWhere
 	viewRows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer viewRows.Close()
 
@@ -405,7 +575,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&view.Name,
 			&view.Definition,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		info := viewInfoMap[fmt.Sprintf("%s/%s", dbName, view.Name)]
@@ -421,7 +591,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 	}
 
 	// Query db info
-	where := fmt.Sprintf("LOWER(SCHEMA_NAME) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
+	where := getDatabaseWhere("SCHEMA_NAME", excludedDatabaseList, databaseList)
 	query = `
 			SELECT
 		    SCHEMA_NAME,
@@ -431,7 +601,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		WHERE ` + where
 	rows, err := driver.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, nil, util.FormatErrorWithQuery(err, query)
+		return nil, util.FormatErrorWithQuery(err, query)
 	}
 	defer rows.Close()
 
@@ -443,19 +613,315 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&schema.CharacterSet,
 			&schema.Collation,
 		); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		schema.TableList = tableMap[schema.Name]
 		schema.ViewList = viewMap[schema.Name]
+		schema.RoutineList = routineMap[schema.Name]
 
 		schemaList = append(schemaList, &schema)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return schemaList, err
+}
+
+// getExcludedDatabaseList returns the quoted names of our internal "bytebase" database and the system databases, which are not synced.
+func getExcludedDatabaseList() []string {
+	excludedDatabaseList := []string{
+		// Skip our internal "bytebase" database
+		"'bytebase'",
+	}
+
+	// Skip all system databases
+	for k := range systemDatabases {
+		excludedDatabaseList = append(excludedDatabaseList, fmt.Sprintf("'%s'", k))
+	}
+	return excludedDatabaseList
+}
+
+// getDatabaseWhere returns the WHERE condition on the database column excluding excludedDatabaseList,
+// and also limiting to databaseList if it's not empty.
+func getDatabaseWhere(column string, excludedDatabaseList []string, databaseList []string) string {
+	where := fmt.Sprintf("LOWER(%s) NOT IN (%s)", column, strings.Join(excludedDatabaseList, ", "))
+	if len(databaseList) == 0 {
+		return where
+	}
+	var quotedList []string
+	for _, database := range databaseList {
+		quotedList = append(quotedList, fmt.Sprintf("'%s'", strings.ReplaceAll(strings.ReplaceAll(database, `\`, `\\`), "'", "''")))
+	}
+	return fmt.Sprintf("%s AND %s IN (%s)", where, column, strings.Join(quotedList, ", "))
+}
+
+// GetSchemaFingerprint returns the DDL fingerprint of each database.
+// It hashes the table, column, index, constraint, view, trigger and routine definitions from information_schema,
+// which is much cheaper than syncing the schema as the statistics like the row count are not involved.
+func (driver *Driver) GetSchemaFingerprint(ctx context.Context) (map[string]string, error) {
+	excludedDatabaseList := getExcludedDatabaseList()
+	query := `
+		SELECT SCHEMA_NAME, CONCAT_WS('|', 'S', DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME)
+		FROM information_schema.SCHEMATA
+		WHERE ` + getDatabaseWhere("SCHEMA_NAME", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TABLE_SCHEMA, CONCAT_WS('|', 'T', TABLE_NAME, TABLE_TYPE, ENGINE, CREATE_TIME, TABLE_COLLATION, CREATE_OPTIONS, TABLE_COMMENT)
+		FROM information_schema.TABLES
+		WHERE ` + getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TABLE_SCHEMA, CONCAT_WS('|', 'C', TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, COLLATION_NAME, COLUMN_COMMENT)
+		FROM information_schema.COLUMNS
+		WHERE ` + getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TABLE_SCHEMA, CONCAT_WS('|', 'I', TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, NON_UNIQUE, INDEX_TYPE, INDEX_COMMENT)
+		FROM information_schema.STATISTICS
+		WHERE ` + getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TABLE_SCHEMA, CONCAT_WS('|', 'K', TABLE_NAME, CONSTRAINT_NAME, CONSTRAINT_TYPE)
+		FROM information_schema.TABLE_CONSTRAINTS
+		WHERE ` + getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TABLE_SCHEMA, CONCAT_WS('|', 'F', TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME)
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE REFERENCED_TABLE_NAME IS NOT NULL AND ` + getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TABLE_SCHEMA, CONCAT_WS('|', 'V', TABLE_NAME, MD5(VIEW_DEFINITION))
+		FROM information_schema.VIEWS
+		WHERE ` + getDatabaseWhere("TABLE_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT TRIGGER_SCHEMA, CONCAT_WS('|', 'G', TRIGGER_NAME, EVENT_OBJECT_TABLE, ACTION_TIMING, EVENT_MANIPULATION, MD5(ACTION_STATEMENT))
+		FROM information_schema.TRIGGERS
+		WHERE ` + getDatabaseWhere("TRIGGER_SCHEMA", excludedDatabaseList, nil) + `
+		UNION ALL
+		SELECT ROUTINE_SCHEMA, CONCAT_WS('|', 'R', ROUTINE_NAME, ROUTINE_TYPE, LAST_ALTERED)
+		FROM information_schema.ROUTINES
+		WHERE ` + getDatabaseWhere("ROUTINE_SCHEMA", excludedDatabaseList, nil)
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	lineMap := make(map[string][]string)
+	for rows.Next() {
+		var dbName, line string
+		if err := rows.Scan(&dbName, &line); err != nil {
+			return nil, err
+		}
+		lineMap[dbName] = append(lineMap[dbName], line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fingerprintMap := make(map[string]string)
+	for dbName, lineList := range lineMap {
+		fingerprintMap[dbName] = util.GetSchemaFingerprint(lineList)
+	}
+	return fingerprintMap, nil
+}
+
+// GetInstanceHealth returns the health status of the instance from the replica status, the global status,
+// information_schema.INNODB_TRX and information_schema.TABLES.
+func (driver *Driver) GetInstanceHealth(ctx context.Context, transactionDuration time.Duration) (*db.InstanceHealth, error) {
+	// TiDB doesn't support the replica status and INNODB_TRX.
+	if driver.dbType == db.TiDB {
+		return nil, nil
+	}
+
+	health := &db.InstanceHealth{}
+	replicaList, err := driver.getReplicaList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	health.ReplicaList = replicaList
+
+	query := "SELECT @@max_connections"
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&health.MaxConnections); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	query = "SHOW GLOBAL STATUS LIKE 'Threads_connected'"
+	var variableName string
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&variableName, &health.Connections); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+
+	query = `
+		SELECT
+			t.trx_mysql_thread_id,
+			COALESCE(p.DB, ''),
+			COALESCE(p.USER, ''),
+			TIMESTAMPDIFF(SECOND, t.trx_started, NOW()),
+			COALESCE(t.trx_query, '')
+		FROM information_schema.INNODB_TRX t
+		LEFT JOIN information_schema.PROCESSLIST p ON p.ID = t.trx_mysql_thread_id
+		WHERE t.trx_started <= NOW() - INTERVAL ? SECOND
+		ORDER BY t.trx_started`
+	trxRows, err := driver.db.QueryContext(ctx, query, int64(transactionDuration.Seconds()))
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer trxRows.Close()
+	for trxRows.Next() {
+		var transaction db.Transaction
+		if err := trxRows.Scan(
+			&transaction.ProcessID,
+			&transaction.Database,
+			&transaction.User,
+			&transaction.DurationSeconds,
+			&transaction.Query,
+		); err != nil {
+			return nil, err
+		}
+		health.TransactionList = append(health.TransactionList, transaction)
+	}
+	if err := trxRows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT TABLE_SCHEMA, CAST(COALESCE(SUM(DATA_LENGTH + INDEX_LENGTH), 0) AS SIGNED)
+		FROM information_schema.TABLES
+		WHERE ` + getDatabaseWhere("TABLE_SCHEMA", getExcludedDatabaseList(), nil) + `
+		GROUP BY TABLE_SCHEMA`
+	sizeRows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer sizeRows.Close()
+	for sizeRows.Next() {
+		storage := db.Storage{Type: db.StorageDatabase}
+		if err := sizeRows.Scan(&storage.Name, &storage.SizeBytes); err != nil {
+			return nil, err
+		}
+		health.StorageList = append(health.StorageList, storage)
+	}
+	if err := sizeRows.Err(); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// GetQueryDigestList returns the cumulative statement statistics from performance_schema.events_statements_summary_by_digest,
+// which is empty if the performance schema is disabled.
+func (driver *Driver) GetQueryDigestList(ctx context.Context) ([]*db.QueryDigest, error) {
+	// TiDB doesn't support the events_statements_summary_by_digest table.
+	if driver.dbType == db.TiDB {
+		return nil, nil
+	}
+
+	// The timer is in picoseconds.
+	query := `
+		SELECT
+			SCHEMA_NAME,
+			DIGEST,
+			COALESCE(DIGEST_TEXT, ''),
+			COUNT_STAR,
+			CAST(SUM_TIMER_WAIT / 1000000 AS SIGNED),
+			SUM_ROWS_EXAMINED
+		FROM performance_schema.events_statements_summary_by_digest
+		WHERE DIGEST IS NOT NULL AND ` + getDatabaseWhere("SCHEMA_NAME", getExcludedDatabaseList(), nil)
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var digestList []*db.QueryDigest
+	for rows.Next() {
+		var digest db.QueryDigest
+		if err := rows.Scan(
+			&digest.Database,
+			&digest.Digest,
+			&digest.Statement,
+			&digest.Calls,
+			&digest.TotalLatencyUs,
+			&digest.RowsExamined,
+		); err != nil {
+			return nil, err
+		}
+		digestList = append(digestList, &digest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
+	return digestList, nil
+}
 
-	return userList, schemaList, err
+// getReplicaList returns the status of each replication channel, which is empty if the instance isn't a replica.
+func (driver *Driver) getReplicaList(ctx context.Context) ([]db.Replica, error) {
+	// SHOW REPLICA STATUS is introduced in MySQL 8.0.22 to replace SHOW SLAVE STATUS.
+	query := "SHOW REPLICA STATUS"
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		query = "SHOW SLAVE STATUS"
+		rows, err = driver.db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, util.FormatErrorWithQuery(err, query)
+		}
+	}
+	defer rows.Close()
+
+	columnList, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	// The "Slave" and "Master" in the column names are renamed to "Replica" and "Source" since MySQL 8.0.22.
+	replacer := strings.NewReplacer("Slave", "Replica", "Master", "Source")
+	var replicaList []db.Replica
+	for rows.Next() {
+		valueList := make([]sql.NullString, len(columnList))
+		ptrList := make([]interface{}, len(columnList))
+		for i := range valueList {
+			ptrList[i] = &valueList[i]
+		}
+		if err := rows.Scan(ptrList...); err != nil {
+			return nil, err
+		}
+		status := make(map[string]string)
+		for i, column := range columnList {
+			status[replacer.Replace(column)] = valueList[i].String
+		}
+		replicaList = append(replicaList, getReplica(status))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return replicaList, nil
+}
+
+// getReplica returns the replica from the replica status keyed by the MySQL 8.0.22 column names.
+func getReplica(status map[string]string) db.Replica {
+	replica := db.Replica{
+		Name:    fmt.Sprintf("%s:%s", status["Source_Host"], status["Source_Port"]),
+		Running: status["Replica_IO_Running"] == "Yes" && status["Replica_SQL_Running"] == "Yes",
+	}
+	if channel := status["Channel_Name"]; channel != "" {
+		replica.Name = fmt.Sprintf("%s (%s)", channel, replica.Name)
+	}
+	if replica.Running {
+		// Seconds_Behind_Source is NULL if the replication SQL thread isn't running.
+		replica.LagSeconds, _ = strconv.ParseInt(status["Seconds_Behind_Source"], 10, 64)
+	}
+	var errorList []string
+	for _, key := range []string{"Last_IO_Error", "Last_SQL_Error"} {
+		if status[key] != "" {
+			errorList = append(errorList, status[key])
+		}
+	}
+	replica.Detail = strings.Join(errorList, "; ")
+	return replica
+}
+
+// isCheckConstraintSupported returns whether information_schema.CHECK_CONSTRAINTS exists, which is introduced in MySQL 8.0.16.
+func isCheckConstraintSupported(version string) bool {
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &patch); err != nil {
+		return false
+	}
+	return major > 8 || (major == 8 && (minor > 0 || patch >= 16))
 }
 
 func (driver *Driver) getUserList(ctx context.Context) ([]*db.User, error) {
@@ -535,8 +1001,172 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, limit int, args ...interface{}) ([]interface{}, error) {
+	return util.Query(ctx, driver.l, driver.db, statement, limit, args...)
+}
+
+// QueryStream queries a SQL statement and streams the rows to the writer.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, limit int, writer db.QueryRowWriter, args ...interface{}) (int, error) {
+	return util.QueryStream(ctx, driver.l, driver.db, statement, limit, writer, args...)
+}
+
+// QueryCursor queries a SQL statement and returns the cursor to fetch the rows page by page.
+// The statement timeout is enforced by max_execution_time, and the statement is killed by KILL QUERY.
+func (driver *Driver) QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (db.QueryCursor, error) {
+	option := util.QueryCursorOption{
+		TimeoutStatementFormat: "SET SESSION max_execution_time = %d",
+		ResetTimeoutStatement:  "SET SESSION max_execution_time = DEFAULT",
+		ConnectionIDQuery:      "SELECT CONNECTION_ID()",
+		KillStatementFormat:    "KILL QUERY %s",
+	}
+	if driver.dbType == db.TiDB {
+		option.KillStatementFormat = "KILL TIDB QUERY %s"
+	}
+	return util.QueryCursor(ctx, driver.l, driver.db, statement, timeout, option, args...)
+}
+
+var (
+	// mysqlPlanOperationMap maps the operations wrapping the tables in the MySQL JSON plan to the node types.
+	mysqlPlanOperationMap = map[string]string{
+		"query_block":        "Query Block",
+		"nested_loop":        "Nested Loop",
+		"ordering_operation": "Ordering",
+		"grouping_operation": "Grouping",
+		"duplicates_removal": "Duplicates Removal",
+		"union_result":       "Union",
+		"windowing":          "Window",
+	}
+	// mysqlPlanAccessTypeMap maps the table access types in the MySQL JSON plan to the node types.
+	mysqlPlanAccessTypeMap = map[string]string{
+		"system":          "Constant Lookup",
+		"const":           "Constant Lookup",
+		"eq_ref":          "Unique Index Lookup",
+		"ref":             "Index Lookup",
+		"ref_or_null":     "Index Lookup",
+		"fulltext":        "Fulltext Index Lookup",
+		"unique_subquery": "Unique Index Lookup",
+		"index_subquery":  "Index Lookup",
+		"index_merge":     "Index Merge",
+		"range":           "Index Range Scan",
+		"index":           "Full Index Scan",
+		"ALL":             "Full Table Scan",
+	}
+)
+
+// Explain returns the estimated execution plan from EXPLAIN FORMAT=JSON.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	// TiDB doesn't support EXPLAIN FORMAT=JSON.
+	if driver.dbType == db.TiDB {
+		return nil, fmt.Errorf("explain is not supported for %s", db.TiDB)
+	}
+
+	// EXPLAIN doesn't run the statement, and the readonly transaction guards it anyway.
+	tx, err := driver.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "EXPLAIN FORMAT=JSON " + statement
+	var raw string
+	if err := tx.QueryRowContext(ctx, query).Scan(&raw); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	root, err := parseQueryPlan(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the query plan: %w", err)
+	}
+	return &db.QueryPlan{
+		Raw:  raw,
+		Root: root,
+	}, nil
+}
+
+// parseQueryPlan parses the MySQL JSON plan into the normalized plan nodes.
+func parseQueryPlan(raw string) (*db.QueryPlanNode, error) {
+	var plan map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+		return nil, err
+	}
+	nodeList := getPlanChildList(plan)
+	if len(nodeList) != 1 {
+		return nil, fmt.Errorf("expect 1 root node in the plan, got %d", len(nodeList))
+	}
+	return nodeList[0], nil
+}
+
+// getPlanNodeList returns the plan nodes from the value of the key in the MySQL JSON plan.
+// The value of an operation or a table is a node, and the nodes nested in any other value are flattened into the parent.
+func getPlanNodeList(key string, value interface{}) []*db.QueryPlanNode {
+	fields, isObject := value.(map[string]interface{})
+	if key == "table" && isObject {
+		accessType, _ := fields["access_type"].(string)
+		nodeType, ok := mysqlPlanAccessTypeMap[accessType]
+		if !ok {
+			nodeType = accessType
+		}
+		tableName, _ := fields["table_name"].(string)
+		index, _ := fields["key"].(string)
+		costInfo, _ := fields["cost_info"].(map[string]interface{})
+		return []*db.QueryPlanNode{
+			{
+				NodeType:      nodeType,
+				Table:         tableName,
+				Index:         index,
+				EstimatedRows: getPlanNumber(fields["rows_examined_per_scan"]),
+				Cost:          getPlanNumber(costInfo["read_cost"]) + getPlanNumber(costInfo["eval_cost"]),
+				FullTableScan: accessType == "ALL",
+				ChildList:     getPlanChildList(value),
+			},
+		}
+	}
+
+	nodeType, ok := mysqlPlanOperationMap[key]
+	if !ok {
+		return getPlanChildList(value)
+	}
+	node := &db.QueryPlanNode{
+		NodeType:  nodeType,
+		ChildList: getPlanChildList(value),
+	}
+	if isObject {
+		costInfo, _ := fields["cost_info"].(map[string]interface{})
+		node.Cost = getPlanNumber(costInfo["query_cost"])
+	}
+	return []*db.QueryPlanNode{node}
+}
+
+// getPlanChildList returns the plan nodes nested in the value, ordered by the keys for objects.
+func getPlanChildList(value interface{}) []*db.QueryPlanNode {
+	var nodeList []*db.QueryPlanNode
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var keyList []string
+		for key := range v {
+			keyList = append(keyList, key)
+		}
+		sort.Strings(keyList)
+		for _, key := range keyList {
+			nodeList = append(nodeList, getPlanNodeList(key, v[key])...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			nodeList = append(nodeList, getPlanChildList(item)...)
+		}
+	}
+	return nodeList
+}
+
+// getPlanNumber returns the number in the MySQL JSON plan, where the costs are strings and the rows are numbers.
+func getPlanNumber(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		number, _ := strconv.ParseFloat(v, 64)
+		return number
+	}
+	return 0
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -870,6 +1500,66 @@ func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer,
 	return nil
 }
 
+// DumpWithTableRowCount dumps the database with a consistent snapshot, and returns the number of rows of each base table
+// counted in the snapshot.
+func (driver *Driver) DumpWithTableRowCount(ctx context.Context, database string, out io.Writer) (map[string]int64, error) {
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Never return the connection to the pool with the snapshot transaction left behind.
+	defer conn.ExecContext(ctx, "ROLLBACK")
+	if err := driver.startSnapshot(ctx, conn); err != nil {
+		return nil, err
+	}
+	tableRowCount, err := dumpTxnWithTableRowCount(ctx, &connQueryer{ctx: ctx, conn: conn}, database, out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return nil, err
+	}
+	return tableRowCount, nil
+}
+
+// dumpTxnWithTableRowCount counts the rows of each base table before dumping the database in the same transaction.
+func dumpTxnWithTableRowCount(ctx context.Context, txn queryer, database string, out io.Writer) (map[string]int64, error) {
+	tables, err := getTables(txn, database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables of database %q: %s", database, err)
+	}
+	tableRowCount := make(map[string]int64)
+	for _, tbl := range tables {
+		if tbl.tableType != baseTableType {
+			continue
+		}
+		rows, err := txn.Query(fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`;", database, tbl.name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count rows of table %q: %w", tbl.name, err)
+		}
+		var count int64
+		for rows.Next() {
+			if err := rows.Scan(&count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+		tableRowCount[tbl.name] = count
+	}
+
+	if err := dumpTxn(ctx, txn, database, out, false /* schemaOnly */); err != nil {
+		return nil, err
+	}
+	return tableRowCount, nil
+}
+
 // Restore restores a database.
 func (driver *Driver) Restore(ctx context.Context, sc *bufio.Scanner) (err error) {
 	txn, err := driver.db.BeginTx(ctx, nil)
@@ -896,7 +1586,12 @@ func (driver *Driver) Restore(ctx context.Context, sc *bufio.Scanner) (err error
 	return nil
 }
 
-func dumpTxn(ctx context.Context, txn *sql.Tx, database string, out io.Writer, schemaOnly bool) error {
+// queryer is the query interface used by dump, implemented by *sql.Tx and connQueryer.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func dumpTxn(ctx context.Context, txn queryer, database string, out io.Writer, schemaOnly bool) error {
 	// Find all dumpable databases
 	dbNames, err := getDatabases(txn)
 	if err != nil {
@@ -1012,7 +1707,7 @@ func excludeSchemaAutoIncrementValue(s string) string {
 }
 
 // getDatabases gets all databases of an instance.
-func getDatabases(txn *sql.Tx) ([]string, error) {
+func getDatabases(txn queryer) ([]string, error) {
 	var dbNames []string
 	rows, err := txn.Query("SHOW DATABASES;")
 	if err != nil {
@@ -1031,7 +1726,7 @@ func getDatabases(txn *sql.Tx) ([]string, error) {
 }
 
 // getDatabaseStmt gets the create statement of a database.
-func getDatabaseStmt(txn *sql.Tx, dbName string) (string, error) {
+func getDatabaseStmt(txn queryer, dbName string) (string, error) {
 	query := fmt.Sprintf("SHOW CREATE DATABASE IF NOT EXISTS %s;", dbName)
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -1076,7 +1771,7 @@ type triggerSchema struct {
 }
 
 // getTables gets all tables of a database.
-func getTables(txn *sql.Tx, dbName string) ([]*tableSchema, error) {
+func getTables(txn queryer, dbName string) ([]*tableSchema, error) {
 	var tables []*tableSchema
 	query := fmt.Sprintf("SHOW FULL TABLES FROM `%s`;", dbName)
 	rows, err := txn.Query(query)
@@ -1103,7 +1798,7 @@ func getTables(txn *sql.Tx, dbName string) ([]*tableSchema, error) {
 }
 
 // getTableStmt gets the create statement of a table.
-func getTableStmt(txn *sql.Tx, dbName, tblName, tblType string) (string, error) {
+func getTableStmt(txn queryer, dbName, tblName, tblType string) (string, error) {
 	switch tblType {
 	case baseTableType:
 		query := fmt.Sprintf("SHOW CREATE TABLE `%s`.`%s`;", dbName, tblName)
@@ -1145,7 +1840,7 @@ func getTableStmt(txn *sql.Tx, dbName, tblName, tblType string) (string, error)
 }
 
 // exportTableData gets the data of a table.
-func exportTableData(txn *sql.Tx, dbName, tblName string, includeDbPrefix bool, out io.Writer) error {
+func exportTableData(txn queryer, dbName, tblName string, includeDbPrefix bool, out io.Writer) error {
 	query := fmt.Sprintf("SELECT * FROM `%s`.`%s`;", dbName, tblName)
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -1202,7 +1897,7 @@ func isNumeric(t string) bool {
 }
 
 // getRoutines gets all routines of a database.
-func getRoutines(txn *sql.Tx, dbName string) ([]*routineSchema, error) {
+func getRoutines(txn queryer, dbName string) ([]*routineSchema, error) {
 	var routines []*routineSchema
 	for _, routineType := range []string{"FUNCTION", "PROCEDURE"} {
 		query := fmt.Sprintf("SHOW %s STATUS WHERE Db = ?;", routineType)
@@ -1243,7 +1938,7 @@ func getRoutines(txn *sql.Tx, dbName string) ([]*routineSchema, error) {
 }
 
 // getRoutineStmt gets the create statement of a routine.
-func getRoutineStmt(txn *sql.Tx, dbName, routineName, routineType string) (string, error) {
+func getRoutineStmt(txn queryer, dbName, routineName, routineType string) (string, error) {
 	query := fmt.Sprintf("SHOW CREATE %s `%s`.`%s`;", routineType, dbName, routineName)
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -1275,7 +1970,7 @@ func getReadableRoutineType(s string) string {
 }
 
 // getEvents gets all events of a database.
-func getEvents(txn *sql.Tx, dbName string) ([]*eventSchema, error) {
+func getEvents(txn queryer, dbName string) ([]*eventSchema, error) {
 	var events []*eventSchema
 	rows, err := txn.Query(fmt.Sprintf("SHOW EVENTS FROM `%s`;", dbName))
 	if err != nil {
@@ -1311,7 +2006,7 @@ func getEvents(txn *sql.Tx, dbName string) ([]*eventSchema, error) {
 }
 
 // getEventStmt gets the create statement of an event.
-func getEventStmt(txn *sql.Tx, dbName, eventName string) (string, error) {
+func getEventStmt(txn queryer, dbName, eventName string) (string, error) {
 	query := fmt.Sprintf("SHOW CREATE EVENT `%s`.`%s`;", dbName, eventName)
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -1330,7 +2025,7 @@ func getEventStmt(txn *sql.Tx, dbName, eventName string) (string, error) {
 }
 
 // getTriggers gets all triggers of a database.
-func getTriggers(txn *sql.Tx, dbName string) ([]*triggerSchema, error) {
+func getTriggers(txn queryer, dbName string) ([]*triggerSchema, error) {
 	var triggers []*triggerSchema
 	rows, err := txn.Query(fmt.Sprintf("SHOW TRIGGERS FROM `%s`;", dbName))
 	if err != nil {
@@ -1365,7 +2060,7 @@ func getTriggers(txn *sql.Tx, dbName string) ([]*triggerSchema, error) {
 }
 
 // getTriggerStmt gets the create statement of a trigger.
-func getTriggerStmt(txn *sql.Tx, dbName, triggerName string) (string, error) {
+func getTriggerStmt(txn queryer, dbName, triggerName string) (string, error) {
 	query := fmt.Sprintf("SHOW CREATE TRIGGER `%s`.`%s`;", dbName, triggerName)
 	rows, err := txn.Query(query)
 	if err != nil {
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestParseQueryPlan(t *testing.T) {
+	raw := `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "12.50"},
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "t1",
+            "access_type": "ALL",
+            "possible_keys": ["idx_a"],
+            "rows_examined_per_scan": 100,
+            "filtered": "10.00",
+            "cost_info": {"read_cost": "9.00", "eval_cost": "1.00", "prefix_cost": "10.00"}
+          }
+        },
+        {
+          "table": {
+            "table_name": "t2",
+            "access_type": "eq_ref",
+            "key": "PRIMARY",
+            "rows_examined_per_scan": 1,
+            "cost_info": {"read_cost": "2.00", "eval_cost": "0.50", "prefix_cost": "12.50"},
+            "attached_subqueries": [
+              {
+                "dependent": true,
+                "query_block": {
+                  "select_id": 2,
+                  "cost_info": {"query_cost": "0.35"},
+                  "table": {"table_name": "t3", "access_type": "ref", "key": "idx_b", "rows_examined_per_scan": 1}
+                }
+              }
+            ]
+          }
+        }
+      ]
+    }
+  }
+}`
+	want := &db.QueryPlanNode{
+		NodeType: "Query Block",
+		Cost:     12.5,
+		ChildList: []*db.QueryPlanNode{
+			{
+				NodeType: "Ordering",
+				ChildList: []*db.QueryPlanNode{
+					{
+						NodeType: "Nested Loop",
+						ChildList: []*db.QueryPlanNode{
+							{NodeType: "Full Table Scan", Table: "t1", EstimatedRows: 100, Cost: 10, FullTableScan: true},
+							{
+								NodeType:      "Unique Index Lookup",
+								Table:         "t2",
+								Index:         "PRIMARY",
+								EstimatedRows: 1,
+								Cost:          2.5,
+								ChildList: []*db.QueryPlanNode{
+									{
+										NodeType: "Query Block",
+										Cost:     0.35,
+										ChildList: []*db.QueryPlanNode{
+											{NodeType: "Index Lookup", Table: "t3", Index: "idx_b", EstimatedRows: 1},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := parseQueryPlan(raw)
+	if err != nil {
+		t.Fatalf("parseQueryPlan() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseQueryPlan() = %+v, want %+v", got, want)
+	}
+}
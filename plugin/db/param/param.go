@@ -0,0 +1,164 @@
+// Package param binds the named :param placeholders of a SQL statement to the positional parameters of the engine,
+// so that the values are sent to the database separately from the statement instead of being spliced into it.
+package param
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// placeholder is a named placeholder in the statement, where statement[start:end] is ":name".
+type placeholder struct {
+	name  string
+	start int
+	end   int
+}
+
+// GetNameList returns the distinct names of the :param placeholders in the order of the first appearance.
+// The placeholders in the string literals, quoted identifiers and comments are ignored, so are the Postgres "::" casts.
+func GetNameList(engine db.Type, statement string) []string {
+	var nameList []string
+	seen := make(map[string]bool)
+	for _, p := range scan(engine, statement) {
+		if !seen[p.name] {
+			seen[p.name] = true
+			nameList = append(nameList, p.name)
+		}
+	}
+	return nameList
+}
+
+// Bind replaces the :param placeholders with the positional parameters of the engine, which is "$n" for Postgres and "?" otherwise,
+// and returns the rewritten statement along with the arguments in the order of the positional parameters.
+// For Postgres, the placeholders of the same name share the positional parameter.
+// It returns an error if the value of a placeholder is missing, or a value doesn't match any placeholder.
+func Bind(engine db.Type, statement string, valueMap map[string]string) (string, []interface{}, error) {
+	placeholderList := scan(engine, statement)
+	used := make(map[string]bool)
+	for _, p := range placeholderList {
+		if _, ok := valueMap[p.name]; !ok {
+			return "", nil, fmt.Errorf("missing value for parameter :%s", p.name)
+		}
+		used[p.name] = true
+	}
+	var unknownList []string
+	for name := range valueMap {
+		if !used[name] {
+			unknownList = append(unknownList, ":"+name)
+		}
+	}
+	if len(unknownList) > 0 {
+		sort.Strings(unknownList)
+		return "", nil, fmt.Errorf("unknown parameter %s", strings.Join(unknownList, ", "))
+	}
+
+	var args []interface{}
+	positionMap := make(map[string]int)
+	bound := replace(statement, placeholderList, func(name string) string {
+		if engine == db.Postgres {
+			position, ok := positionMap[name]
+			if !ok {
+				args = append(args, valueMap[name])
+				position = len(args)
+				positionMap[name] = position
+			}
+			return fmt.Sprintf("$%d", position)
+		}
+		args = append(args, valueMap[name])
+		return "?"
+	})
+	return bound, args, nil
+}
+
+// Normalize replaces the :param placeholders with "?" regardless of the engine, which is accepted by the TiDB parser
+// for the callers only interested in the structure of the statement, e.g. the data masking.
+func Normalize(engine db.Type, statement string) string {
+	return replace(statement, scan(engine, statement), func(string) string {
+		return "?"
+	})
+}
+
+// replace replaces each placeholder with the text returned by f.
+func replace(statement string, placeholderList []placeholder, f func(name string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, p := range placeholderList {
+		b.WriteString(statement[last:p.start])
+		b.WriteString(f(p.name))
+		last = p.end
+	}
+	b.WriteString(statement[last:])
+	return b.String()
+}
+
+// scan returns the named placeholders outside of the string literals, quoted identifiers and comments.
+// Backslash escapes in the quoted text are honored except for Postgres, whose standard conforming strings treat
+// backslash literally.
+func scan(engine db.Type, statement string) []placeholder {
+	var placeholderList []placeholder
+	backslashEscape := engine != db.Postgres
+	for i := 0; i < len(statement); i++ {
+		switch c := statement[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(statement, i, backslashEscape && c != '`')
+		case c == '-' && strings.HasPrefix(statement[i:], "--"):
+			if end := strings.IndexByte(statement[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(statement)
+			}
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			if end := strings.Index(statement[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(statement)
+			}
+		case c == ':':
+			// Skip the Postgres type cast, e.g. "id::text".
+			if i+1 < len(statement) && statement[i+1] == ':' {
+				i++
+				continue
+			}
+			end := i + 1
+			for end < len(statement) && isNameChar(statement[end], end == i+1) {
+				end++
+			}
+			if end > i+1 {
+				placeholderList = append(placeholderList, placeholder{name: statement[i+1 : end], start: i, end: end})
+				i = end - 1
+			}
+		}
+	}
+	return placeholderList
+}
+
+// skipQuoted returns the index of the closing quote of the quoted text starting at start, or the end of the statement if unclosed.
+// A doubled quote is an escaped quote.
+func skipQuoted(statement string, start int, backslashEscape bool) int {
+	quote := statement[start]
+	for i := start + 1; i < len(statement); i++ {
+		switch statement[i] {
+		case '\\':
+			if backslashEscape {
+				i++
+			}
+		case quote:
+			if i+1 < len(statement) && statement[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return len(statement)
+}
+
+func isNameChar(c byte, first bool) bool {
+	if c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') {
+		return true
+	}
+	return !first && '0' <= c && c <= '9'
+}
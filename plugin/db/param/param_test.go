@@ -0,0 +1,89 @@
+package param
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetNameList(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      []string
+	}{
+		{
+			engine:    db.MySQL,
+			statement: "SELECT * FROM t WHERE a = :a AND b > :b_1 OR a = :a",
+			want:      []string{"a", "b_1"},
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SELECT ':x', \":y\", `:z`, 'it\\'s :w' FROM t -- :c\nWHERE id = :id /* :d */",
+			want:      []string{"id"},
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SELECT id::text, 'C:\\' FROM t WHERE name = :name AND '' = ''",
+			want:      []string{"name"},
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SELECT @v := 1, :1, a : b FROM t",
+			want:      nil,
+		},
+	}
+	for _, test := range tests {
+		if got := GetNameList(test.engine, test.statement); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("GetNameList(%s, %q) = %v, want %v", test.engine, test.statement, got, test.want)
+		}
+	}
+}
+
+func TestBind(t *testing.T) {
+	valueMap := map[string]string{"name": "x' OR '1' = '1", "id": "3"}
+	tests := []struct {
+		engine        db.Type
+		statement     string
+		wantStatement string
+		wantArgs      []interface{}
+	}{
+		{
+			engine:        db.MySQL,
+			statement:     "SELECT * FROM t WHERE name = :name AND (id = :id OR parent_id = :id)",
+			wantStatement: "SELECT * FROM t WHERE name = ? AND (id = ? OR parent_id = ?)",
+			wantArgs:      []interface{}{"x' OR '1' = '1", "3", "3"},
+		},
+		{
+			engine:        db.Postgres,
+			statement:     "SELECT * FROM t WHERE name = :name AND (id = :id::int OR parent_id = :id)",
+			wantStatement: "SELECT * FROM t WHERE name = $1 AND (id = $2::int OR parent_id = $2)",
+			wantArgs:      []interface{}{"x' OR '1' = '1", "3"},
+		},
+	}
+	for _, test := range tests {
+		statement, args, err := Bind(test.engine, test.statement, valueMap)
+		if err != nil {
+			t.Fatalf("Bind(%s, %q) returns error: %v", test.engine, test.statement, err)
+		}
+		if statement != test.wantStatement || !reflect.DeepEqual(args, test.wantArgs) {
+			t.Errorf("Bind(%s, %q) = %q, %v, want %q, %v", test.engine, test.statement, statement, args, test.wantStatement, test.wantArgs)
+		}
+	}
+
+	if _, _, err := Bind(db.MySQL, "SELECT * FROM t WHERE id = :id AND name = :name", map[string]string{"id": "1"}); err == nil {
+		t.Errorf("Bind() returns no error for the missing value")
+	}
+	if _, _, err := Bind(db.MySQL, "SELECT * FROM t WHERE id = :id", map[string]string{"id": "1", "ids": "2"}); err == nil {
+		t.Errorf("Bind() returns no error for the unknown parameter")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	statement := "SELECT id::text FROM t WHERE name = :name AND id IN (:id, :id)"
+	want := "SELECT id::text FROM t WHERE name = ? AND id IN (?, ?)"
+	if got := Normalize(db.Postgres, statement); got != want {
+		t.Errorf("Normalize(%q) = %q, want %q", statement, got, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
@@ -186,8 +187,9 @@ func (driver *Driver) GetVersion(ctx context.Context) (string, error) {
 	return version, nil
 }
 
-// SyncSchema synces the schema.
-func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema, error) {
+// getExcludedDatabaseList returns our internal "bytebase" database, the internal databases from cloud service providers
+// and the system databases, which are not synced.
+func getExcludedDatabaseList() map[string]bool {
 	excludedDatabaseList := map[string]bool{
 		// Skip our internal "bytebase" database
 		"bytebase": true,
@@ -202,17 +204,26 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 	for k := range systemDatabases {
 		excludedDatabaseList[k] = true
 	}
+	return excludedDatabaseList
+}
 
-	// Query user info
-	userList, err := driver.getUserList(ctx)
-	if err != nil {
-		return nil, nil, err
+// SyncUser syncs the users of the instance.
+func (driver *Driver) SyncUser(ctx context.Context) ([]*db.User, error) {
+	return driver.getUserList(ctx)
+}
+
+// SyncSchema synces the schema.
+func (driver *Driver) SyncSchema(ctx context.Context, databaseList ...string) ([]*db.Schema, error) {
+	excludedDatabaseList := getExcludedDatabaseList()
+	includedDatabaseList := make(map[string]bool)
+	for _, database := range databaseList {
+		includedDatabaseList[database] = true
 	}
 
 	// Query db info
 	databases, err := driver.getDatabases()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get databases: %s", err)
+		return nil, fmt.Errorf("failed to get databases: %s", err)
 	}
 
 	var schemaList []*db.Schema
@@ -221,6 +232,9 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		if _, ok := excludedDatabaseList[dbName]; ok {
 			continue
 		}
+		if len(includedDatabaseList) > 0 && !includedDatabaseList[dbName] {
+			continue
+		}
 
 		var schema db.Schema
 		schema.Name = dbName
@@ -229,11 +243,11 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 
 		sqldb, err := driver.GetDbConnection(ctx, dbName)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get database connection for %q: %s", dbName, err)
+			return nil, fmt.Errorf("failed to get database connection for %q: %s", dbName, err)
 		}
 		txn, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		defer txn.Rollback()
 
@@ -241,17 +255,35 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		indicesMap := make(map[string][]*indexSchema)
 		indices, err := getIndices(txn)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get indices from database %q: %s", dbName, err)
+			return nil, fmt.Errorf("failed to get indices from database %q: %s", dbName, err)
 		}
 		for _, idx := range indices {
 			key := fmt.Sprintf("%s.%s", idx.schemaName, idx.tableName)
 			indicesMap[key] = append(indicesMap[key], idx)
 		}
 
+		// Foreign key statements.
+		foreignKeysMap, err := getForeignKeys(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get foreign keys from database %q: %s", dbName, err)
+		}
+
+		// Check constraint statements.
+		checkConstraintsMap, err := getCheckConstraints(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get check constraints from database %q: %s", dbName, err)
+		}
+
+		// Trigger statements.
+		triggersMap, err := getTableTriggers(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get triggers from database %q: %s", dbName, err)
+		}
+
 		// Table statements.
 		tables, err := getPgTables(txn)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get tables from database %q: %s", dbName, err)
+			return nil, fmt.Errorf("failed to get tables from database %q: %s", dbName, err)
 		}
 		for _, tbl := range tables {
 			var dbTable db.Table
@@ -281,17 +313,21 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 					dbIndex.Position = i + 1
 					dbIndex.Type = idx.methodType
 					dbIndex.Unique = idx.unique
+					dbIndex.Primary = idx.primary
 					dbIndex.Comment = idx.comment
 					dbTable.IndexList = append(dbTable.IndexList, dbIndex)
 				}
 			}
+			dbTable.ForeignKeyList = foreignKeysMap[dbTable.Name]
+			dbTable.CheckConstraintList = checkConstraintsMap[dbTable.Name]
+			dbTable.TriggerList = triggersMap[dbTable.Name]
 
 			schema.TableList = append(schema.TableList, dbTable)
 		}
 		// View statements.
 		views, err := getViews(txn)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get views from database %q: %s", dbName, err)
+			return nil, fmt.Errorf("failed to get views from database %q: %s", dbName, err)
 		}
 		for _, view := range views {
 			var dbView db.View
@@ -303,15 +339,276 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 
 			schema.ViewList = append(schema.ViewList, dbView)
 		}
+		// Routine statements.
+		routines, err := getRoutines(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get routines from database %q: %s", dbName, err)
+		}
+		schema.RoutineList = routines
+		// Sequence statements.
+		sequences, err := getSequences(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sequences from database %q: %s", dbName, err)
+		}
+		for _, seq := range sequences {
+			schema.SequenceList = append(schema.SequenceList, db.Sequence{
+				Name:       fmt.Sprintf("%s.%s", seq.schemaName, seq.name),
+				DataType:   seq.dataType,
+				StartValue: seq.startValue,
+				Increment:  seq.increment,
+				MinValue:   seq.minimumValue,
+				MaxValue:   seq.maximumValue,
+				Cycle:      seq.cycleOption == "YES",
+				CacheSize:  seq.cache,
+			})
+		}
 
 		if err := txn.Commit(); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		schemaList = append(schemaList, &schema)
 	}
 
-	return userList, schemaList, err
+	return schemaList, err
+}
+
+// schemaFingerprintQuery hashes the catalog definitions of the user objects in a database.
+// Unlike the schema sync, it doesn't touch the statistics like the table size, so it's cheap to run for every database.
+const schemaFingerprintQuery = `
+	SELECT md5(COALESCE(string_agg(line, E'\n' ORDER BY line), '')) FROM (
+		SELECT concat_ws('|', 'T', n.nspname, c.relname, c.relkind, obj_description(c.oid, 'pg_class')) AS line
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f') AND ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'C', n.nspname, c.relname, a.attname, a.attnum, format_type(a.atttypid, a.atttypmod), a.attnotnull, pg_get_expr(d.adbin, d.adrelid), col_description(c.oid, a.attnum))
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attnum > 0 AND NOT a.attisdropped AND c.relkind IN ('r', 'p', 'v', 'm', 'f') AND ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'I', n.nspname, pg_get_indexdef(i.indexrelid), obj_description(i.indexrelid, 'pg_class'))
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'K', n.nspname, con.conrelid::regclass, con.conname, pg_get_constraintdef(con.oid))
+		FROM pg_constraint con JOIN pg_namespace n ON n.oid = con.connamespace
+		WHERE ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'V', n.nspname, c.relname, pg_get_viewdef(c.oid))
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('v', 'm') AND ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'G', n.nspname, c.relname, t.tgname, pg_get_triggerdef(t.oid))
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT t.tgisinternal AND ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'R', n.nspname, p.proname, pg_get_function_identity_arguments(p.oid), md5(p.prosrc), obj_description(p.oid, 'pg_proc'))
+		FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE ` + userNamespaceWhere + `
+		UNION ALL
+		SELECT concat_ws('|', 'Q', sequence_schema, sequence_name, data_type, start_value, minimum_value, maximum_value, increment, cycle_option)
+		FROM information_schema.sequences
+		WHERE sequence_schema NOT IN ('pg_catalog', 'information_schema')
+	) lines`
+
+// userNamespaceWhere excludes the system namespaces aliased as n.
+const userNamespaceWhere = `n.nspname NOT IN ('pg_catalog', 'information_schema') AND n.nspname NOT LIKE 'pg_toast%' AND n.nspname NOT LIKE 'pg_temp_%'`
+
+// GetSchemaFingerprint returns the DDL fingerprint of each database, which is the checksum of its catalog definitions.
+func (driver *Driver) GetSchemaFingerprint(ctx context.Context) (map[string]string, error) {
+	excludedDatabaseList := getExcludedDatabaseList()
+	databases, err := driver.getDatabases()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get databases: %s", err)
+	}
+
+	fingerprintMap := make(map[string]string)
+	for _, database := range databases {
+		dbName := database.name
+		if _, ok := excludedDatabaseList[dbName]; ok {
+			continue
+		}
+		sqldb, err := driver.GetDbConnection(ctx, dbName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database connection for %q: %s", dbName, err)
+		}
+		var fingerprint string
+		if err := sqldb.QueryRowContext(ctx, schemaFingerprintQuery).Scan(&fingerprint); err != nil {
+			return nil, fmt.Errorf("failed to get schema fingerprint from database %q: %w", dbName, util.FormatErrorWithQuery(err, schemaFingerprintQuery))
+		}
+		// The encoding and collation are database properties outside the catalog of the database.
+		fingerprintMap[dbName] = fmt.Sprintf("%s/%s/%s", fingerprint, database.encoding, database.collate)
+	}
+	return fingerprintMap, nil
+}
+
+// GetInstanceHealth returns the health status of the instance from pg_stat_replication, pg_stat_activity,
+// and the size of the databases and the tablespaces.
+func (driver *Driver) GetInstanceHealth(ctx context.Context, transactionDuration time.Duration) (*db.InstanceHealth, error) {
+	health := &db.InstanceHealth{}
+
+	// The replay lag is NULL if the replica has caught up and there is no activity on the primary.
+	query := `
+		SELECT
+			COALESCE(NULLIF(application_name, ''), client_addr::TEXT, ''),
+			state,
+			COALESCE(EXTRACT(EPOCH FROM replay_lag), 0)::BIGINT
+		FROM pg_catalog.pg_stat_replication`
+	replicaRows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer replicaRows.Close()
+	for replicaRows.Next() {
+		var replica db.Replica
+		var state string
+		if err := replicaRows.Scan(&replica.Name, &state, &replica.LagSeconds); err != nil {
+			return nil, err
+		}
+		replica.Running = state == "streaming"
+		if !replica.Running {
+			replica.LagSeconds = 0
+			replica.Detail = fmt.Sprintf("replica is in %s state", state)
+		}
+		health.ReplicaList = append(health.ReplicaList, replica)
+	}
+	if err := replicaRows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = "SELECT COUNT(*), current_setting('max_connections')::INT FROM pg_catalog.pg_stat_activity WHERE backend_type = 'client backend'"
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&health.Connections, &health.MaxConnections); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+
+	query = `
+		SELECT
+			pid::TEXT,
+			COALESCE(datname, ''),
+			COALESCE(usename, ''),
+			EXTRACT(EPOCH FROM now() - xact_start)::BIGINT,
+			COALESCE(query, '')
+		FROM pg_catalog.pg_stat_activity
+		WHERE xact_start <= now() - $1 * INTERVAL '1 second' AND pid != pg_backend_pid()
+		ORDER BY xact_start`
+	trxRows, err := driver.db.QueryContext(ctx, query, int64(transactionDuration.Seconds()))
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer trxRows.Close()
+	for trxRows.Next() {
+		var transaction db.Transaction
+		if err := trxRows.Scan(
+			&transaction.ProcessID,
+			&transaction.Database,
+			&transaction.User,
+			&transaction.DurationSeconds,
+			&transaction.Query,
+		); err != nil {
+			return nil, err
+		}
+		health.TransactionList = append(health.TransactionList, transaction)
+	}
+	if err := trxRows.Err(); err != nil {
+		return nil, err
+	}
+
+	excludedDatabaseList := getExcludedDatabaseList()
+	query = `
+		SELECT 'DATABASE', datname, pg_database_size(oid) FROM pg_catalog.pg_database WHERE datallowconn AND NOT datistemplate
+		UNION ALL
+		SELECT 'TABLESPACE', spcname, pg_tablespace_size(oid) FROM pg_catalog.pg_tablespace`
+	sizeRows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer sizeRows.Close()
+	for sizeRows.Next() {
+		var storage db.Storage
+		if err := sizeRows.Scan(&storage.Type, &storage.Name, &storage.SizeBytes); err != nil {
+			return nil, err
+		}
+		if _, ok := excludedDatabaseList[storage.Name]; ok && storage.Type == db.StorageDatabase {
+			continue
+		}
+		health.StorageList = append(health.StorageList, storage)
+	}
+	if err := sizeRows.Err(); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// GetQueryDigestList returns the cumulative statement statistics from pg_stat_statements,
+// or nil if the pg_stat_statements extension isn't installed.
+func (driver *Driver) GetQueryDigestList(ctx context.Context) ([]*db.QueryDigest, error) {
+	var installed bool
+	query := "SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_extension WHERE extname = 'pg_stat_statements')"
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&installed); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	if !installed {
+		return nil, nil
+	}
+
+	var versionNum int
+	query = "SELECT current_setting('server_version_num')::INT"
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&versionNum); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	// The total_time is renamed to total_exec_time since Postgres 13, both in milliseconds.
+	totalTimeColumn := "total_time"
+	if versionNum >= 130000 {
+		totalTimeColumn = "total_exec_time"
+	}
+	// The same normalized statement is tracked per user, so the statistics are summed up by the query ID.
+	query = fmt.Sprintf(`
+		SELECT
+			d.datname,
+			s.queryid::TEXT,
+			MIN(s.query),
+			SUM(s.calls)::BIGINT,
+			(SUM(s.%s) * 1000)::BIGINT,
+			SUM(s.rows)::BIGINT
+		FROM pg_stat_statements s
+		JOIN pg_catalog.pg_database d ON d.oid = s.dbid
+		WHERE s.queryid IS NOT NULL
+		GROUP BY d.datname, s.queryid`, totalTimeColumn)
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	excludedDatabaseList := getExcludedDatabaseList()
+	var digestList []*db.QueryDigest
+	for rows.Next() {
+		var digest db.QueryDigest
+		if err := rows.Scan(
+			&digest.Database,
+			&digest.Digest,
+			&digest.Statement,
+			&digest.Calls,
+			&digest.TotalLatencyUs,
+			&digest.RowsExamined,
+		); err != nil {
+			return nil, err
+		}
+		if _, ok := excludedDatabaseList[digest.Database]; ok {
+			continue
+		}
+		digestList = append(digestList, &digest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return digestList, nil
 }
 
 func (driver *Driver) getUserList(ctx context.Context) ([]*db.User, error) {
@@ -401,8 +698,90 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, limit int, args ...interface{}) ([]interface{}, error) {
+	return util.Query(ctx, driver.l, driver.db, statement, limit, args...)
+}
+
+// QueryStream queries a SQL statement and streams the rows to the writer.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, limit int, writer db.QueryRowWriter, args ...interface{}) (int, error) {
+	return util.QueryStream(ctx, driver.l, driver.db, statement, limit, writer, args...)
+}
+
+// QueryCursor queries a SQL statement and returns the cursor to fetch the rows page by page.
+// The statement timeout is enforced by statement_timeout local to the transaction, and lib/pq sends the cancel request
+// to kill the statement once the context is done.
+func (driver *Driver) QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (db.QueryCursor, error) {
+	return util.QueryCursor(ctx, driver.l, driver.db, statement, timeout, util.QueryCursorOption{
+		TimeoutStatementFormat: "SET LOCAL statement_timeout = %d",
+	}, args...)
+}
+
+// planNode is a node of the Postgres JSON plan.
+type planNode struct {
+	NodeType     string      `json:"Node Type"`
+	RelationName string      `json:"Relation Name"`
+	Schema       string      `json:"Schema"`
+	IndexName    string      `json:"Index Name"`
+	PlanRows     float64     `json:"Plan Rows"`
+	TotalCost    float64     `json:"Total Cost"`
+	Plans        []*planNode `json:"Plans"`
+}
+
+// Explain returns the estimated execution plan from EXPLAIN (FORMAT JSON).
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	// EXPLAIN without ANALYZE doesn't run the statement, and the readonly transaction guards it anyway.
+	tx, err := driver.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "EXPLAIN (FORMAT JSON) " + statement
+	var raw string
+	if err := tx.QueryRowContext(ctx, query).Scan(&raw); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	root, err := parseQueryPlan(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the query plan: %w", err)
+	}
+	return &db.QueryPlan{
+		Raw:  raw,
+		Root: root,
+	}, nil
+}
+
+// parseQueryPlan parses the Postgres JSON plan into the normalized plan nodes.
+func parseQueryPlan(raw string) (*db.QueryPlanNode, error) {
+	var planList []struct {
+		Plan *planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &planList); err != nil {
+		return nil, err
+	}
+	if len(planList) != 1 || planList[0].Plan == nil {
+		return nil, fmt.Errorf("expect 1 plan, got %d", len(planList))
+	}
+	return convertPlanNode(planList[0].Plan), nil
+}
+
+func convertPlanNode(node *planNode) *db.QueryPlanNode {
+	table := node.RelationName
+	if table != "" && node.Schema != "" {
+		table = fmt.Sprintf("%s.%s", node.Schema, table)
+	}
+	queryPlanNode := &db.QueryPlanNode{
+		NodeType:      node.NodeType,
+		Table:         table,
+		Index:         node.IndexName,
+		EstimatedRows: node.PlanRows,
+		Cost:          node.TotalCost,
+		FullTableScan: node.NodeType == "Seq Scan",
+	}
+	for _, child := range node.Plans {
+		queryPlanNode.ChildList = append(queryPlanNode.ChildList, convertPlanNode(child))
+	}
+	return queryPlanNode
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -792,6 +1171,83 @@ func (driver *Driver) dumpOneDatabase(ctx context.Context, database string, out
 	}
 	defer txn.Rollback()
 
+	if err := dumpDatabaseTxn(txn, database, out, schemaOnly, includeUseDatabase); err != nil {
+		return err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DumpWithTableRowCount dumps the database in a repeatable read transaction, and returns the number of rows of each
+// base table counted in the same snapshot, keyed by the schema qualified table name.
+func (driver *Driver) DumpWithTableRowCount(ctx context.Context, database string, out io.Writer) (map[string]int64, error) {
+	if database == "" {
+		return nil, fmt.Errorf("database must be specified to dump with the table row count")
+	}
+	if err := driver.switchDatabase(database); err != nil {
+		return nil, err
+	}
+
+	txn, err := driver.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	tableRowCount, err := countTableRows(txn)
+	if err != nil {
+		return nil, err
+	}
+	if err := dumpDatabaseTxn(txn, database, out, false /* schemaOnly */, false /* includeUseDatabase */); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return tableRowCount, nil
+}
+
+// countTableRows returns the number of rows of each base table in the snapshot of the transaction.
+func countTableRows(txn *sql.Tx) (map[string]int64, error) {
+	type table struct {
+		schema string
+		name   string
+	}
+	var tableList []table
+	rows, err := txn.Query("SELECT schemaname, tablename FROM pg_catalog.pg_tables WHERE schemaname NOT IN ('pg_catalog', 'information_schema');")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t table
+		if err := rows.Scan(&t.schema, &t.name); err != nil {
+			return nil, err
+		}
+		tableList = append(tableList, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tableRowCount := make(map[string]int64)
+	for _, t := range tableList {
+		key := fmt.Sprintf("%s.%s", t.schema, t.name)
+		var count int64
+		if err := txn.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s";`, strings.ReplaceAll(t.schema, `"`, `""`), strings.ReplaceAll(t.name, `"`, `""`))).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count rows of table %q: %w", key, err)
+		}
+		tableRowCount[key] = count
+	}
+	return tableRowCount, nil
+}
+
+func dumpDatabaseTxn(txn *sql.Tx, database string, out io.Writer, schemaOnly bool, includeUseDatabase bool) error {
 	// Database statement.
 	if includeUseDatabase {
 		// Database header.
@@ -909,10 +1365,6 @@ func (driver *Driver) dumpOneDatabase(ctx context.Context, database string, out
 		}
 	}
 
-	if err := txn.Commit(); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -1013,6 +1465,7 @@ type indexSchema struct {
 	tableName  string
 	statement  string
 	unique     bool
+	primary    bool
 	// methodType such as btree.
 	methodType        string
 	columnExpressions []string
@@ -1429,6 +1882,178 @@ func getTableConstraints(txn *sql.Tx) (map[string][]*tableConstraint, error) {
 	return ret, nil
 }
 
+// getForeignKeys gets all foreign keys of a database, keyed by "schema.table".
+func getForeignKeys(txn *sql.Tx) (map[string][]db.ForeignKey, error) {
+	query := "" +
+		"SELECT n.nspname, cl.relname, c.conname, a.attname, k.ord, fn.nspname, fcl.relname, fa.attname, c.confupdtype, c.confdeltype " +
+		"FROM pg_constraint c " +
+		"JOIN pg_namespace n ON n.oid = c.connamespace " +
+		"JOIN pg_class cl ON cl.oid = c.conrelid " +
+		"JOIN pg_class fcl ON fcl.oid = c.confrelid " +
+		"JOIN pg_namespace fn ON fn.oid = fcl.relnamespace " +
+		"CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, fattnum, ord) " +
+		"JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum " +
+		"JOIN pg_attribute fa ON fa.attrelid = c.confrelid AND fa.attnum = k.fattnum " +
+		"WHERE c.contype = 'f' AND n.nspname NOT IN ('pg_catalog', 'information_schema') " +
+		"ORDER BY n.nspname, cl.relname, c.conname, k.ord;"
+	ret := make(map[string][]db.ForeignKey)
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, tableName, referencedSchemaName, referencedTableName, onUpdate, onDelete string
+		var fk db.ForeignKey
+		if err := rows.Scan(&schemaName, &tableName, &fk.Name, &fk.Column, &fk.Position, &referencedSchemaName, &referencedTableName, &fk.ReferencedColumn, &onUpdate, &onDelete); err != nil {
+			return nil, err
+		}
+		fk.Name, fk.Column, fk.ReferencedColumn = quoteIdentifier(fk.Name), quoteIdentifier(fk.Column), quoteIdentifier(fk.ReferencedColumn)
+		fk.ReferencedTable = fmt.Sprintf("%s.%s", quoteIdentifier(referencedSchemaName), quoteIdentifier(referencedTableName))
+		fk.OnUpdate = getForeignKeyAction(onUpdate)
+		fk.OnDelete = getForeignKeyAction(onDelete)
+		key := fmt.Sprintf("%s.%s", quoteIdentifier(schemaName), quoteIdentifier(tableName))
+		ret[key] = append(ret[key], fk)
+	}
+	return ret, nil
+}
+
+// getForeignKeyAction converts the pg_constraint action code to the referential action.
+func getForeignKeyAction(code string) string {
+	switch code {
+	case "r":
+		return "RESTRICT"
+	case "c":
+		return "CASCADE"
+	case "n":
+		return "SET NULL"
+	case "d":
+		return "SET DEFAULT"
+	default:
+		return "NO ACTION"
+	}
+}
+
+// getCheckConstraints gets all check constraints of a database, keyed by "schema.table".
+func getCheckConstraints(txn *sql.Tx) (map[string][]db.CheckConstraint, error) {
+	query := "" +
+		"SELECT n.nspname, cl.relname, c.conname, pg_get_constraintdef(c.oid) " +
+		"FROM pg_constraint c " +
+		"JOIN pg_namespace n ON n.oid = c.connamespace " +
+		"JOIN pg_class cl ON cl.oid = c.conrelid " +
+		"WHERE c.contype = 'c' AND n.nspname NOT IN ('pg_catalog', 'information_schema') " +
+		"ORDER BY n.nspname, cl.relname, c.conname;"
+	ret := make(map[string][]db.CheckConstraint)
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, tableName string
+		var check db.CheckConstraint
+		if err := rows.Scan(&schemaName, &tableName, &check.Name, &check.Expression); err != nil {
+			return nil, err
+		}
+		check.Name = quoteIdentifier(check.Name)
+		key := fmt.Sprintf("%s.%s", quoteIdentifier(schemaName), quoteIdentifier(tableName))
+		ret[key] = append(ret[key], check)
+	}
+	return ret, nil
+}
+
+// getTableTriggers gets all table triggers of a database, keyed by "schema.table".
+// Unlike getTriggers for the dump, the internal triggers for the foreign keys are skipped.
+func getTableTriggers(txn *sql.Tx) (map[string][]db.Trigger, error) {
+	query := "" +
+		"SELECT n.nspname, c.relname, t.tgname, t.tgtype, pg_get_triggerdef(t.oid) " +
+		"FROM pg_trigger t " +
+		"JOIN pg_class c ON c.oid = t.tgrelid " +
+		"JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"WHERE NOT t.tgisinternal AND n.nspname NOT IN ('pg_catalog', 'information_schema') " +
+		"ORDER BY n.nspname, c.relname, t.tgname;"
+	ret := make(map[string][]db.Trigger)
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, tableName string
+		var tgType int
+		var trigger db.Trigger
+		if err := rows.Scan(&schemaName, &tableName, &trigger.Name, &tgType, &trigger.Statement); err != nil {
+			return nil, err
+		}
+		trigger.Name = quoteIdentifier(trigger.Name)
+		trigger.Timing, trigger.Event = getTriggerTimingAndEvent(tgType)
+		key := fmt.Sprintf("%s.%s", quoteIdentifier(schemaName), quoteIdentifier(tableName))
+		ret[key] = append(ret[key], trigger)
+	}
+	return ret, nil
+}
+
+// getTriggerTimingAndEvent decodes the pg_trigger tgtype bits.
+func getTriggerTimingAndEvent(tgType int) (string, string) {
+	timing := "AFTER"
+	if tgType&(1<<1) != 0 {
+		timing = "BEFORE"
+	} else if tgType&(1<<6) != 0 {
+		timing = "INSTEAD OF"
+	}
+	var events []string
+	for _, e := range []struct {
+		bit   int
+		event string
+	}{
+		{bit: 1 << 2, event: "INSERT"},
+		{bit: 1 << 4, event: "UPDATE"},
+		{bit: 1 << 3, event: "DELETE"},
+		{bit: 1 << 5, event: "TRUNCATE"},
+	} {
+		if tgType&e.bit != 0 {
+			events = append(events, e.event)
+		}
+	}
+	return timing, strings.Join(events, " OR ")
+}
+
+// getRoutines gets all functions and procedures of a database.
+// Aggregate functions are skipped because pg_get_functiondef doesn't support them.
+func getRoutines(txn *sql.Tx) ([]db.Routine, error) {
+	query := "" +
+		"SELECT n.nspname, p.proname, r.routine_type, " +
+		"  CASE WHEN l.lanname = 'internal' THEN p.prosrc ELSE pg_get_functiondef(p.oid) END, " +
+		"  COALESCE(obj_description(p.oid, 'pg_proc'), '') " +
+		"FROM information_schema.routines r " +
+		"JOIN pg_proc p ON r.specific_name = p.proname || '_' || p.oid " +
+		"JOIN pg_namespace n ON n.oid = p.pronamespace " +
+		"LEFT JOIN pg_language l ON p.prolang = l.oid " +
+		"WHERE n.nspname NOT IN ('pg_catalog', 'information_schema') " +
+		"  AND NOT EXISTS (SELECT 1 FROM pg_aggregate a WHERE a.aggfnoid = p.oid) " +
+		"ORDER BY n.nspname, p.proname;"
+	var routines []db.Routine
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var schemaName, name string
+		var routine db.Routine
+		if err := rows.Scan(&schemaName, &name, &routine.Type, &routine.Definition, &routine.Comment); err != nil {
+			return nil, err
+		}
+		routine.Name = fmt.Sprintf("%s.%s", quoteIdentifier(schemaName), quoteIdentifier(name))
+		routines = append(routines, routine)
+	}
+	return routines, nil
+}
+
 // getViews gets all views of a database.
 func getViews(txn *sql.Tx) ([]*viewSchema, error) {
 	query := "" +
@@ -1521,7 +2146,8 @@ func getIndices(txn *sql.Tx) ([]*indexSchema, error) {
 }
 
 func getIndex(txn *sql.Tx, idx *indexSchema) error {
-	commentQuery := fmt.Sprintf("SELECT obj_description('%s.%s'::regclass);", idx.schemaName, idx.name)
+	regclass := fmt.Sprintf("'%s.%s'::regclass", idx.schemaName, idx.name)
+	commentQuery := fmt.Sprintf("SELECT obj_description(%s), (SELECT indisprimary FROM pg_index WHERE indexrelid = %s);", regclass, regclass)
 	crows, err := txn.Query(commentQuery)
 	if err != nil {
 		return err
@@ -1530,10 +2156,12 @@ func getIndex(txn *sql.Tx, idx *indexSchema) error {
 
 	for crows.Next() {
 		var comment sql.NullString
-		if err := crows.Scan(&comment); err != nil {
+		var primary sql.NullBool
+		if err := crows.Scan(&comment, &primary); err != nil {
 			return err
 		}
 		idx.comment = comment.String
+		idx.primary = primary.Bool
 	}
 	return nil
 }
@@ -0,0 +1,59 @@
+package pg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestParseQueryPlan(t *testing.T) {
+	raw := `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Join Type": "Inner",
+      "Startup Cost": 1.09,
+      "Total Cost": 40.2,
+      "Plan Rows": 12,
+      "Plan Width": 8,
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Parent Relationship": "Outer",
+          "Relation Name": "t1",
+          "Schema": "public",
+          "Alias": "t1",
+          "Total Cost": 32.6,
+          "Plan Rows": 2260
+        },
+        {
+          "Node Type": "Index Scan",
+          "Parent Relationship": "Inner",
+          "Relation Name": "t2",
+          "Index Name": "t2_pkey",
+          "Total Cost": 8.17,
+          "Plan Rows": 1
+        }
+      ]
+    }
+  }
+]`
+	want := &db.QueryPlanNode{
+		NodeType:      "Hash Join",
+		EstimatedRows: 12,
+		Cost:          40.2,
+		ChildList: []*db.QueryPlanNode{
+			{NodeType: "Seq Scan", Table: "public.t1", EstimatedRows: 2260, Cost: 32.6, FullTableScan: true},
+			{NodeType: "Index Scan", Table: "t2", Index: "t2_pkey", EstimatedRows: 1, Cost: 8.17},
+		},
+	}
+
+	got, err := parseQueryPlan(raw)
+	if err != nil {
+		t.Fatalf("parseQueryPlan() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseQueryPlan() = %+v, want %+v", got, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	// embed will embeds the migration schema.
 	_ "embed"
@@ -143,22 +144,29 @@ func (driver *Driver) useRole(ctx context.Context, role string) error {
 	return nil
 }
 
-// SyncSchema synces the schema.
-func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema, error) {
-	// Query user info
+// SyncUser syncs the users of the instance.
+func (driver *Driver) SyncUser(ctx context.Context) ([]*db.User, error) {
 	if err := driver.useRole(ctx, accountAdminRole); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return driver.getUserList(ctx)
+}
 
-	userList, err := driver.getUserList(ctx)
-	if err != nil {
-		return nil, nil, err
+// SyncSchema synces the schema.
+func (driver *Driver) SyncSchema(ctx context.Context, databaseList ...string) ([]*db.Schema, error) {
+	if err := driver.useRole(ctx, accountAdminRole); err != nil {
+		return nil, err
 	}
 
 	// Query db info
 	databases, err := driver.getDatabases(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	includedDatabaseList := make(map[string]bool)
+	for _, database := range databaseList {
+		includedDatabaseList[database] = true
 	}
 
 	var schemaList []*db.Schema
@@ -166,19 +174,56 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		if database == bytebaseDatabase {
 			continue
 		}
+		if len(includedDatabaseList) > 0 && !includedDatabaseList[database] {
+			continue
+		}
 
 		var schema db.Schema
 		schema.Name = database
 		tableList, viewList, err := driver.syncTableSchema(ctx, database)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		schema.TableList, schema.ViewList = tableList, viewList
 
 		schemaList = append(schemaList, &schema)
 	}
 
-	return userList, schemaList, nil
+	return schemaList, nil
+}
+
+// GetInstanceHealth returns nil as Snowflake doesn't support it.
+func (driver *Driver) GetInstanceHealth(ctx context.Context, transactionDuration time.Duration) (*db.InstanceHealth, error) {
+	return nil, nil
+}
+
+// GetQueryDigestList returns nil as Snowflake doesn't support it.
+func (driver *Driver) GetQueryDigestList(ctx context.Context) ([]*db.QueryDigest, error) {
+	return nil, nil
+}
+
+// Explain returns nil as Snowflake doesn't support it.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	return nil, nil
+}
+
+// GetSchemaFingerprint returns an empty fingerprint for each database as Snowflake doesn't support it, so all databases are always synced.
+func (driver *Driver) GetSchemaFingerprint(ctx context.Context) (map[string]string, error) {
+	if err := driver.useRole(ctx, accountAdminRole); err != nil {
+		return nil, err
+	}
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fingerprintMap := make(map[string]string)
+	for _, database := range databases {
+		if database == bytebaseDatabase {
+			continue
+		}
+		fingerprintMap[database] = ""
+	}
+	return fingerprintMap, nil
 }
 
 func (driver *Driver) syncTableSchema(ctx context.Context, database string) ([]db.Table, []db.View, error) {
@@ -515,8 +560,19 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, limit int, args ...interface{}) ([]interface{}, error) {
+	return util.Query(ctx, driver.l, driver.db, statement, limit, args...)
+}
+
+// QueryStream queries a SQL statement and streams the rows to the writer.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, limit int, writer db.QueryRowWriter, args ...interface{}) (int, error) {
+	return util.QueryStream(ctx, driver.l, driver.db, statement, limit, writer, args...)
+}
+
+// QueryCursor queries a SQL statement and returns the cursor to fetch the rows page by page.
+// The statement timeout is enforced by the context deadline, and the driver cancels the statement once the context is done.
+func (driver *Driver) QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (db.QueryCursor, error) {
+	return util.QueryCursor(ctx, driver.l, driver.db, statement, timeout, util.QueryCursorOption{}, args...)
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -826,6 +882,11 @@ const (
 		"--\n"
 )
 
+// DumpWithTableRowCount dumps the database without counting the table rows, which isn't supported.
+func (driver *Driver) DumpWithTableRowCount(ctx context.Context, database string, out io.Writer) (map[string]int64, error) {
+	return nil, driver.Dump(ctx, database, out, false /* schemaOnly */)
+}
+
 // Dump dumps the database.
 func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) error {
 	txn, err := driver.db.BeginTx(ctx, &sql.TxOptions{})
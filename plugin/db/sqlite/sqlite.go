@@ -8,7 +8,10 @@ import (
 	"io"
 	"io/ioutil"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	// embed will embeds the migration schema.
 	_ "embed"
@@ -108,11 +111,20 @@ func (driver *Driver) GetVersion(ctx context.Context) (string, error) {
 	return version, nil
 }
 
+// SyncUser returns no user since SQLite doesn't have users.
+func (driver *Driver) SyncUser(ctx context.Context) ([]*db.User, error) {
+	return nil, nil
+}
+
 // SyncSchema synces the schema.
-func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema, error) {
+func (driver *Driver) SyncSchema(ctx context.Context, databaseList ...string) ([]*db.Schema, error) {
 	databases, err := driver.getDatabases()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	includedDatabaseList := make(map[string]bool)
+	for _, database := range databaseList {
+		includedDatabaseList[database] = true
 	}
 
 	var schemaList []*db.Schema
@@ -120,24 +132,27 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 		if _, ok := excludedDatabaseList[dbName]; ok {
 			continue
 		}
+		if len(includedDatabaseList) > 0 && !includedDatabaseList[dbName] {
+			continue
+		}
 
 		var schema db.Schema
 		schema.Name = dbName
 
 		sqldb, err := driver.GetDbConnection(ctx, dbName)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get database connection for %q: %s", dbName, err)
+			return nil, fmt.Errorf("failed to get database connection for %q: %s", dbName, err)
 		}
 		txn, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		defer txn.Rollback()
 		// Index statements.
 		indicesMap := make(map[string][]indexSchema)
 		indices, err := getIndices(txn)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get indices from database %q: %s", dbName, err)
+			return nil, fmt.Errorf("failed to get indices from database %q: %s", dbName, err)
 		}
 		for _, idx := range indices {
 			indicesMap[idx.tableName] = append(indicesMap[idx.tableName], idx)
@@ -145,29 +160,77 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 
 		tbls, err := getTables(txn, indicesMap)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
+		}
+		triggersMap, err := getTriggers(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get triggers from database %q: %s", dbName, err)
+		}
+		for i := range tbls {
+			tbls[i].TriggerList = triggersMap[tbls[i].Name]
 		}
 		schema.TableList = tbls
 
 		views, err := getViews(txn)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		schema.ViewList = views
 
 		if err := txn.Commit(); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		schemaList = append(schemaList, &schema)
 	}
-	return nil, schemaList, nil
+	return schemaList, nil
+}
+
+// GetInstanceHealth returns nil as SQLite doesn't support it.
+func (driver *Driver) GetInstanceHealth(ctx context.Context, transactionDuration time.Duration) (*db.InstanceHealth, error) {
+	return nil, nil
+}
+
+// GetQueryDigestList returns nil as SQLite doesn't support it.
+func (driver *Driver) GetQueryDigestList(ctx context.Context) ([]*db.QueryDigest, error) {
+	return nil, nil
+}
+
+// Explain returns nil as SQLite doesn't support it.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	return nil, nil
+}
+
+// GetSchemaFingerprint returns the DDL fingerprint of each database, which is the schema version
+// incremented by SQLite whenever the schema changes.
+func (driver *Driver) GetSchemaFingerprint(ctx context.Context) (map[string]string, error) {
+	databases, err := driver.getDatabases()
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprintMap := make(map[string]string)
+	for _, dbName := range databases {
+		if _, ok := excludedDatabaseList[dbName]; ok {
+			continue
+		}
+		sqldb, err := driver.GetDbConnection(ctx, dbName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database connection for %q: %s", dbName, err)
+		}
+		var schemaVersion int64
+		if err := sqldb.QueryRowContext(ctx, "PRAGMA schema_version;").Scan(&schemaVersion); err != nil {
+			return nil, fmt.Errorf("failed to get schema version from database %q: %w", dbName, err)
+		}
+		fingerprintMap[dbName] = strconv.FormatInt(schemaVersion, 10)
+	}
+	return fingerprintMap, nil
 }
 
 // getTables gets all tables of a database.
 func getTables(txn *sql.Tx, indicesMap map[string][]indexSchema) ([]db.Table, error) {
 	var tables []db.Table
-	query := "SELECT name FROM sqlite_schema WHERE type ='table' AND name NOT LIKE 'sqlite_%';"
+	query := "SELECT name, sql FROM sqlite_schema WHERE type ='table' AND name NOT LIKE 'sqlite_%';"
 	rows, err := txn.Query(query)
 	if err != nil {
 		return nil, err
@@ -175,17 +238,20 @@ func getTables(txn *sql.Tx, indicesMap map[string][]indexSchema) ([]db.Table, er
 	defer rows.Close()
 
 	var tableNames []string
+	tableStatements := make(map[string]string)
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var name, statement string
+		if err := rows.Scan(&name, &statement); err != nil {
 			return nil, err
 		}
 		tableNames = append(tableNames, name)
+		tableStatements[name] = statement
 	}
 	for _, name := range tableNames {
 		var tbl db.Table
 		tbl.Name = name
 		tbl.Type = "BASE TABLE"
+		tbl.CheckConstraintList = getCheckConstraints(name, tableStatements[name])
 
 		// Get columns: cid, name, type, notnull, dflt_value, pk.
 		query := fmt.Sprintf("pragma table_info(%s);", name)
@@ -234,6 +300,30 @@ func getTables(txn *sql.Tx, indicesMap map[string][]indexSchema) ([]db.Table, er
 			}
 		}
 
+		// Get foreign keys: id, seq, table, from, to, on_update, on_delete, match.
+		// SQLite foreign keys are unnamed, so we name them after the table and the foreign key id.
+		query = fmt.Sprintf("pragma foreign_key_list(%s);", name)
+		fkRows, err := txn.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		defer fkRows.Close()
+		for fkRows.Next() {
+			var fk db.ForeignKey
+			var id int
+			var to sql.NullString
+			var match string
+			if err := fkRows.Scan(&id, &fk.Position, &fk.ReferencedTable, &fk.Column, &to, &fk.OnUpdate, &fk.OnDelete, &match); err != nil {
+				return nil, err
+			}
+			fk.Name = fmt.Sprintf("fk_%s_%d", name, id)
+			// The position of pragma foreign_key_list starts from 0.
+			fk.Position++
+			// The referenced column is NULL if it refers to the primary key of the referenced table.
+			fk.ReferencedColumn = to.String
+			tbl.ForeignKeyList = append(tbl.ForeignKeyList, fk)
+		}
+
 		tables = append(tables, tbl)
 	}
 	return tables, nil
@@ -260,6 +350,98 @@ func getIndices(txn *sql.Tx) ([]indexSchema, error) {
 	return indices, nil
 }
 
+var (
+	checkConstraintRegexp = regexp.MustCompile(`(?i)(?:\bCONSTRAINT\s+("(?:[^"]|"")+"|` + "`[^`]+`" + `|\[[^\]]+\]|\w+)\s+)?\bCHECK\s*\(`)
+	triggerRegexp         = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP(?:ORARY)?\s+)?TRIGGER\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:"(?:[^"]|"")+"|\S+)\s+(BEFORE\s+|AFTER\s+|INSTEAD\s+OF\s+)?(INSERT|UPDATE|DELETE)\b`)
+)
+
+// getCheckConstraints parses the check constraints from the CREATE TABLE statement, because SQLite doesn't provide them by pragma.
+// Unnamed check constraints are named "<table>_chk_<n>" the same as MySQL.
+func getCheckConstraints(tableName, statement string) []db.CheckConstraint {
+	var checks []db.CheckConstraint
+	for _, loc := range checkConstraintRegexp.FindAllStringSubmatchIndex(statement, -1) {
+		// The match ends right after the opening parenthesis of the check expression.
+		end := findClosingParenthesis(statement, loc[1])
+		if end < 0 {
+			continue
+		}
+		name := fmt.Sprintf("%s_chk_%d", tableName, len(checks)+1)
+		if loc[2] >= 0 {
+			name = strings.Trim(statement[loc[2]:loc[3]], "\"`[]")
+		}
+		checks = append(checks, db.CheckConstraint{
+			Name:       name,
+			Expression: strings.TrimSpace(statement[loc[1]:end]),
+		})
+	}
+	return checks
+}
+
+// findClosingParenthesis returns the index of the parenthesis closing the one right before start, or -1 if not found.
+// Parentheses in string literals and quoted identifiers are skipped.
+func findClosingParenthesis(s string, start int) int {
+	depth := 1
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			// Escaped quotes are doubled, which is the same as closing and reopening the quote.
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// getTriggers gets all triggers of a database, keyed by the table name.
+func getTriggers(txn *sql.Tx) (map[string][]db.Trigger, error) {
+	triggers := make(map[string][]db.Trigger)
+	query := "SELECT name, tbl_name, sql FROM sqlite_schema WHERE type ='trigger';"
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName string
+		var trigger db.Trigger
+		if err := rows.Scan(&trigger.Name, &tableName, &trigger.Statement); err != nil {
+			return nil, err
+		}
+		trigger.Timing, trigger.Event = getTriggerTimingAndEvent(trigger.Statement)
+		triggers[tableName] = append(triggers[tableName], trigger)
+	}
+	return triggers, nil
+}
+
+// getTriggerTimingAndEvent parses the timing and event from the CREATE TRIGGER statement.
+// The timing defaults to BEFORE if omitted.
+func getTriggerTimingAndEvent(statement string) (string, string) {
+	matches := triggerRegexp.FindStringSubmatch(statement)
+	if len(matches) == 0 {
+		return "", ""
+	}
+	timing := strings.Join(strings.Fields(strings.ToUpper(matches[1])), " ")
+	if timing == "" {
+		timing = "BEFORE"
+	}
+	return timing, strings.ToUpper(matches[2])
+}
+
 // indexSchema describes the schema of an index.
 type indexSchema struct {
 	name      string
@@ -352,8 +534,19 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 }
 
 // Query queries a SQL statement.
-func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+func (driver *Driver) Query(ctx context.Context, statement string, limit int, args ...interface{}) ([]interface{}, error) {
+	return util.Query(ctx, driver.l, driver.db, statement, limit, args...)
+}
+
+// QueryStream queries a SQL statement and streams the rows to the writer.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, limit int, writer db.QueryRowWriter, args ...interface{}) (int, error) {
+	return util.QueryStream(ctx, driver.l, driver.db, statement, limit, writer, args...)
+}
+
+// QueryCursor queries a SQL statement and returns the cursor to fetch the rows page by page.
+// The statement timeout is enforced by the context deadline, and the driver cancels the statement once the context is done.
+func (driver *Driver) QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (db.QueryCursor, error) {
+	return util.QueryCursor(ctx, driver.l, driver.db, statement, timeout, util.QueryCursorOption{}, args...)
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -630,6 +823,11 @@ func (driver *Driver) FindMigrationHistoryList(ctx context.Context, find *db.Mig
 	return util.FindMigrationHistoryList(ctx, query, params, driver, find, baseQuery)
 }
 
+// DumpWithTableRowCount dumps the database without counting the table rows, which isn't supported.
+func (driver *Driver) DumpWithTableRowCount(ctx context.Context, database string, out io.Writer) (map[string]int64, error) {
+	return nil, driver.Dump(ctx, database, out, false /* schemaOnly */)
+}
+
 // Dump dumps the database.
 func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) error {
 	if database == "" {
@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetCheckConstraints(t *testing.T) {
+	statement := `CREATE TABLE book (
+		id INTEGER PRIMARY KEY,
+		price REAL CHECK (price > 0),
+		title TEXT NOT NULL,
+		CONSTRAINT "chk_title" CHECK (length(title) > 0 AND title != ')'),
+		CHECK ((id > 0))
+	)`
+	want := []db.CheckConstraint{
+		{Name: "book_chk_1", Expression: "price > 0"},
+		{Name: "chk_title", Expression: "length(title) > 0 AND title != ')'"},
+		{Name: "book_chk_3", Expression: "(id > 0)"},
+	}
+	if got := getCheckConstraints("book", statement); !reflect.DeepEqual(got, want) {
+		t.Errorf("getCheckConstraints() got %+v, want %+v", got, want)
+	}
+	if got := getCheckConstraints("author", "CREATE TABLE author (id INTEGER, checked INTEGER)"); len(got) != 0 {
+		t.Errorf("getCheckConstraints() got %+v, want none", got)
+	}
+}
+
+func TestGetTriggerTimingAndEvent(t *testing.T) {
+	tests := []struct {
+		statement  string
+		wantTiming string
+		wantEvent  string
+	}{
+		{
+			statement:  "CREATE TRIGGER log_insert AFTER INSERT ON book BEGIN SELECT 1; END",
+			wantTiming: "AFTER",
+			wantEvent:  "INSERT",
+		},
+		{
+			statement:  "CREATE TEMP TRIGGER IF NOT EXISTS \"check update\" UPDATE OF price ON book BEGIN SELECT 1; END",
+			wantTiming: "BEFORE",
+			wantEvent:  "UPDATE",
+		},
+		{
+			statement:  "create trigger v_delete instead  of delete on v begin select 1; end",
+			wantTiming: "INSTEAD OF",
+			wantEvent:  "DELETE",
+		},
+	}
+	for _, test := range tests {
+		timing, event := getTriggerTimingAndEvent(test.statement)
+		if timing != test.wantTiming || event != test.wantEvent {
+			t.Errorf("getTriggerTimingAndEvent(%q) got (%q, %q), want (%q, %q)", test.statement, timing, event, test.wantTiming, test.wantEvent)
+		}
+	}
+}
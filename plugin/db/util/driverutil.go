@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -292,98 +295,150 @@ func endMigration(ctx context.Context, l *zap.Logger, executor MigrationExecutor
 	return nil
 }
 
-// Query will execute a readonly / SELECT query.
-func Query(ctx context.Context, l *zap.Logger, sqldb *sql.DB, statement string, limit int) ([]interface{}, error) {
+// Query will execute a readonly / SELECT query with the positional parameters bound to args.
+func Query(ctx context.Context, l *zap.Logger, sqldb *sql.DB, statement string, limit int, args ...interface{}) ([]interface{}, error) {
+	writer := &queryResultWriter{data: []interface{}{}}
+	if _, err := QueryStream(ctx, l, sqldb, statement, limit, writer, args...); err != nil {
+		return nil, err
+	}
+
+	return []interface{}{writer.columnNames, writer.columnTypeNames, writer.data}, nil
+}
+
+// queryResultWriter collects the streamed query result in memory.
+type queryResultWriter struct {
+	columnNames     []string
+	columnTypeNames []string
+	data            []interface{}
+}
+
+func (w *queryResultWriter) WriteHeader(columnNameList []string, columnTypeNameList []string) error {
+	w.columnNames = columnNameList
+	w.columnTypeNames = columnTypeNameList
+	return nil
+}
+
+func (w *queryResultWriter) WriteRow(row []interface{}) error {
+	w.data = append(w.data, row)
+	return nil
+}
+
+// QueryStream will execute a readonly / SELECT query with the positional parameters bound to args and stream the rows to the writer.
+// It returns the number of rows written.
+func QueryStream(ctx context.Context, l *zap.Logger, sqldb *sql.DB, statement string, limit int, writer db.QueryRowWriter, args ...interface{}) (int, error) {
 	// Not all sql engines support ReadOnly flag, so we will use tx rollback semantics to enforce readonly.
 	tx, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 	defer tx.Rollback()
 
-	rows, err := tx.QueryContext(ctx, statement)
+	rows, err := tx.QueryContext(ctx, statement, args...)
 	if err != nil {
-		return nil, FormatErrorWithQuery(err, statement)
+		return 0, FormatErrorWithQuery(err, statement)
 	}
 	defer rows.Close()
 
+	columnNames, columnTypeNames, err := getColumnNameList(rows)
+	if err != nil {
+		return 0, err
+	}
+	if err := writer.WriteHeader(columnNames, columnTypeNames); err != nil {
+		return 0, err
+	}
+
+	rowCount := 0
+	for rows.Next() {
+		rowData, err := scanRow(rows, columnTypeNames)
+		if err != nil {
+			return rowCount, err
+		}
+
+		if err := writer.WriteRow(rowData); err != nil {
+			return rowCount, err
+		}
+		rowCount++
+		if rowCount == limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return rowCount, formatError(err)
+	}
+
+	return rowCount, nil
+}
+
+// getColumnNameList returns the column names and the upper-case column type names of the rows.
+func getColumnNameList(rows *sql.Rows) ([]string, []string, error) {
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return nil, formatError(err)
+		return nil, nil, formatError(err)
 	}
 
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		return nil, formatError(err)
+		return nil, nil, formatError(err)
 	}
 
-	colCount := len(columnTypes)
-
 	var columnTypeNames []string
 	for _, v := range columnTypes {
 		// DatabaseTypeName returns the database system name of the column type.
 		// refer: https://pkg.go.dev/database/sql#ColumnType.DatabaseTypeName
 		columnTypeNames = append(columnTypeNames, strings.ToUpper(v.DatabaseTypeName()))
 	}
+	return columnNames, columnTypeNames, nil
+}
 
-	rowCount := 0
-	data := []interface{}{}
-	for rows.Next() {
-		scanArgs := make([]interface{}, colCount)
-		for i, v := range columnTypeNames {
-			// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
-			switch v {
-			case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
-				scanArgs[i] = new(sql.NullString)
-			case "BOOL":
-				scanArgs[i] = new(sql.NullBool)
-			case "INT", "INTEGER":
-				scanArgs[i] = new(sql.NullInt64)
-			case "FLOAT":
-				scanArgs[i] = new(sql.NullFloat64)
-			default:
-				scanArgs[i] = new(sql.NullString)
-			}
+// scanRow scans the current row with the values converted by the column type names.
+func scanRow(rows *sql.Rows, columnTypeNames []string) ([]interface{}, error) {
+	scanArgs := make([]interface{}, len(columnTypeNames))
+	for i, v := range columnTypeNames {
+		// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
+		switch v {
+		case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
+			scanArgs[i] = new(sql.NullString)
+		case "BOOL":
+			scanArgs[i] = new(sql.NullBool)
+		case "INT", "INTEGER":
+			scanArgs[i] = new(sql.NullInt64)
+		case "FLOAT":
+			scanArgs[i] = new(sql.NullFloat64)
+		default:
+			scanArgs[i] = new(sql.NullString)
 		}
+	}
 
-		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, formatError(err)
-		}
+	if err := rows.Scan(scanArgs...); err != nil {
+		return nil, formatError(err)
+	}
 
-		rowData := []interface{}{}
-		for i := range columnTypes {
-			if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
-				rowData = append(rowData, v.Bool)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
-				rowData = append(rowData, v.String)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
-				rowData = append(rowData, v.Int64)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
-				rowData = append(rowData, v.Int32)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
-				rowData = append(rowData, v.Float64)
-				continue
-			}
-			// If none of them match, set nil to its value.
-			rowData = append(rowData, nil)
+	rowData := []interface{}{}
+	for i := range columnTypeNames {
+		if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
+			rowData = append(rowData, v.Bool)
+			continue
 		}
-
-		data = append(data, rowData)
-		rowCount++
-		if rowCount == limit {
-			break
+		if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
+			rowData = append(rowData, v.String)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
+			rowData = append(rowData, v.Int64)
+			continue
 		}
+		if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
+			rowData = append(rowData, v.Int32)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
+			rowData = append(rowData, v.Float64)
+			continue
+		}
+		// If none of them match, set nil to its value.
+		rowData = append(rowData, nil)
 	}
-
-	return []interface{}{columnNames, columnTypeNames, data}, nil
+	return rowData, nil
 }
 
 // FindMigrationHistoryList will find the list of migration history.
@@ -445,6 +500,18 @@ func FindMigrationHistoryList(ctx context.Context, findMigrationHistoryListQuery
 	return migrationHistoryList, nil
 }
 
+// GetSchemaFingerprint returns the hex-encoded SHA-256 of the schema definition lines, regardless of the line order.
+func GetSchemaFingerprint(lineList []string) string {
+	sortedList := append([]string{}, lineList...)
+	sort.Strings(sortedList)
+	h := sha256.New()
+	for _, line := range sortedList {
+		h.Write([]byte(line))
+		h.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func formatError(err error) error {
 	if err == nil {
 		return nil
@@ -0,0 +1,233 @@
+package util
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+const (
+	// queryCursorKillTimeout is the timeout of killing the statement of the query cursor.
+	queryCursorKillTimeout = 10 * time.Second
+)
+
+// QueryCursorOption is the engine specific statements for QueryCursor to enforce the statement timeout and kill the statement
+// by the database. The empty ones are skipped, in which case it relies on the driver to cancel the statement once the context
+// is done, and the timeout is enforced by the context deadline instead.
+type QueryCursorOption struct {
+	// TimeoutStatementFormat sets the statement timeout of the connection in milliseconds formatted by %d,
+	// which is run in the readonly transaction before the statement.
+	TimeoutStatementFormat string
+	// ResetTimeoutStatement resets the statement timeout of the connection before it's returned to the pool.
+	// It's not needed if the timeout is local to the transaction.
+	ResetTimeoutStatement string
+	// ConnectionIDQuery returns the ID of the connection running the statement.
+	ConnectionIDQuery string
+	// KillStatementFormat kills the statement of the connection ID formatted by %s, which is run by another connection.
+	KillStatementFormat string
+}
+
+// QueryCursor will execute a readonly / SELECT query with the positional parameters bound to args on a dedicated connection,
+// and return the cursor to fetch the rows page by page. See db.Driver.QueryCursor.
+func QueryCursor(ctx context.Context, l *zap.Logger, sqldb *sql.DB, statement string, timeout time.Duration, option QueryCursorOption, args ...interface{}) (db.QueryCursor, error) {
+	var cancel context.CancelFunc
+	if timeout > 0 && option.TimeoutStatementFormat == "" {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	c := &queryCursor{
+		l:       l,
+		sqldb:   sqldb,
+		option:  option,
+		cancel:  cancel,
+		watched: make(chan struct{}),
+	}
+	go c.watch(ctx)
+
+	if err := c.open(ctx, statement, timeout, args...); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// queryCursor is the db.QueryCursor on a dedicated connection.
+type queryCursor struct {
+	l      *zap.Logger
+	sqldb  *sql.DB
+	option QueryCursorOption
+	// cancel cancels the context of the statement, which kills the statement if the rows aren't exhausted.
+	cancel context.CancelFunc
+	// watched is closed once the statement is killed if needed after the context is done.
+	watched chan struct{}
+
+	conn            *sql.Conn
+	tx              *sql.Tx
+	rows            *sql.Rows
+	resetTimeout    bool
+	columnNames     []string
+	columnTypeNames []string
+	// peeked is the row fetched ahead to tell whether there are more rows.
+	peeked []interface{}
+
+	mu           sync.Mutex
+	connectionID string
+	exhausted    bool
+	closed       bool
+}
+
+func (c *queryCursor) open(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) error {
+	conn, err := c.sqldb.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+
+	// Not all sql engines support ReadOnly flag, so we will use tx rollback semantics to enforce readonly.
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	c.tx = tx
+
+	if timeout > 0 && c.option.TimeoutStatementFormat != "" {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(c.option.TimeoutStatementFormat, timeout.Milliseconds())); err != nil {
+			return fmt.Errorf("failed to set the statement timeout, error: %w", err)
+		}
+		c.resetTimeout = c.option.ResetTimeoutStatement != ""
+	}
+
+	if c.option.ConnectionIDQuery != "" {
+		var connectionID string
+		if err := tx.QueryRowContext(ctx, c.option.ConnectionIDQuery).Scan(&connectionID); err != nil {
+			return fmt.Errorf("failed to get the connection ID, error: %w", err)
+		}
+		c.mu.Lock()
+		c.connectionID = connectionID
+		c.mu.Unlock()
+	}
+
+	rows, err := tx.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return FormatErrorWithQuery(err, statement)
+	}
+	c.rows = rows
+
+	c.columnNames, c.columnTypeNames, err = getColumnNameList(rows)
+	return err
+}
+
+// watch kills the statement by the database once the context is done, unless the rows are exhausted.
+func (c *queryCursor) watch(ctx context.Context) {
+	defer close(c.watched)
+	<-ctx.Done()
+
+	c.mu.Lock()
+	connectionID, exhausted := c.connectionID, c.exhausted
+	c.mu.Unlock()
+	if c.option.KillStatementFormat == "" || connectionID == "" || exhausted {
+		return
+	}
+
+	killCtx, cancel := context.WithTimeout(context.Background(), queryCursorKillTimeout)
+	defer cancel()
+	if _, err := c.sqldb.ExecContext(killCtx, fmt.Sprintf(c.option.KillStatementFormat, connectionID)); err != nil {
+		c.l.Warn("Failed to kill the statement of the query cursor",
+			zap.String("connection_id", connectionID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (c *queryCursor) Header() ([]string, []string) {
+	return c.columnNames, c.columnTypeNames
+}
+
+func (c *queryCursor) Next(n int) ([]interface{}, bool, error) {
+	data := []interface{}{}
+	for n <= 0 || len(data) < n {
+		row, err := c.fetch()
+		if err != nil {
+			return nil, false, err
+		}
+		if row == nil {
+			return data, false, nil
+		}
+		data = append(data, row)
+	}
+
+	row, err := c.fetch()
+	if err != nil {
+		return nil, false, err
+	}
+	c.peeked = row
+	return data, row != nil, nil
+}
+
+// fetch returns the next row, or nil if the rows are exhausted.
+func (c *queryCursor) fetch() ([]interface{}, error) {
+	if c.peeked != nil {
+		row := c.peeked
+		c.peeked = nil
+		return row, nil
+	}
+	if !c.rows.Next() {
+		if err := c.rows.Err(); err != nil {
+			return nil, formatError(err)
+		}
+		c.mu.Lock()
+		c.exhausted = true
+		c.mu.Unlock()
+		return nil, nil
+	}
+	return scanRow(c.rows, c.columnTypeNames)
+}
+
+func (c *queryCursor) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	exhausted := c.exhausted
+	c.mu.Unlock()
+
+	// If the rows aren't exhausted, kills the statement first, so that closing the rows doesn't wait for the remaining rows.
+	// The connection isn't returned to the pool until then, so that the kill can't hit a later statement of the connection.
+	// Otherwise, the transaction is rolled back before the context is canceled, which would discard the connection.
+	if !exhausted {
+		c.cancel()
+		<-c.watched
+	}
+	if c.rows != nil {
+		c.rows.Close()
+	}
+	if c.tx != nil {
+		c.tx.Rollback()
+	}
+	c.cancel()
+	<-c.watched
+
+	if c.conn == nil {
+		return nil
+	}
+	if c.resetTimeout {
+		resetCtx, cancel := context.WithTimeout(context.Background(), queryCursorKillTimeout)
+		defer cancel()
+		if _, err := c.conn.ExecContext(resetCtx, c.option.ResetTimeoutStatement); err != nil {
+			c.l.Debug("Failed to reset the statement timeout of the query cursor connection", zap.Error(err))
+		}
+	}
+	// The connection is already closed if it's discarded.
+	if err := c.conn.Close(); err != nil && err != sql.ErrConnDone {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+func TestQueryCursor(t *testing.T) {
+	ctx := context.Background()
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqldb.Close()
+	if _, err := sqldb.ExecContext(ctx, "CREATE TABLE t (id INTEGER, name TEXT); INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, NULL), (5, 'e');"); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err := QueryCursor(ctx, zap.NewNop(), sqldb, "SELECT id, name FROM t WHERE id > ? ORDER BY id", time.Minute, QueryCursorOption{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+
+	columnNames, columnTypeNames := cursor.Header()
+	if want := []string{"id", "name"}; !reflect.DeepEqual(columnNames, want) {
+		t.Errorf("column names = %v, want %v", columnNames, want)
+	}
+	if want := []string{"INTEGER", "TEXT"}; !reflect.DeepEqual(columnTypeNames, want) {
+		t.Errorf("column type names = %v, want %v", columnTypeNames, want)
+	}
+
+	tests := []struct {
+		n           int
+		wantData    []interface{}
+		wantHasMore bool
+	}{
+		{
+			n:           2,
+			wantData:    []interface{}{[]interface{}{int64(1), "a"}, []interface{}{int64(2), "b"}},
+			wantHasMore: true,
+		},
+		{
+			n:           2,
+			wantData:    []interface{}{[]interface{}{int64(3), "c"}, []interface{}{int64(4), nil}},
+			wantHasMore: true,
+		},
+		{
+			n:           2,
+			wantData:    []interface{}{[]interface{}{int64(5), "e"}},
+			wantHasMore: false,
+		},
+		{
+			n:           2,
+			wantData:    []interface{}{},
+			wantHasMore: false,
+		},
+	}
+	for i, test := range tests {
+		data, hasMore, err := cursor.Next(test.n)
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(data, test.wantData) {
+			t.Errorf("page %d: data = %v, want %v", i, data, test.wantData)
+		}
+		if hasMore != test.wantHasMore {
+			t.Errorf("page %d: hasMore = %v, want %v", i, hasMore, test.wantHasMore)
+		}
+	}
+	if err := cursor.Close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+	// Close is idempotent.
+	if err := cursor.Close(); err != nil {
+		t.Errorf("close again: %v", err)
+	}
+}
+
+func TestQueryCursorCancel(t *testing.T) {
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqldb.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cursor, err := QueryCursor(ctx, zap.NewNop(), sqldb, "WITH RECURSIVE r(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r) SELECT n FROM r", 0, QueryCursorOption{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+
+	if _, hasMore, err := cursor.Next(10); err != nil || !hasMore {
+		t.Fatalf("first page: hasMore = %v, err = %v", hasMore, err)
+	}
+	cancel()
+	// The rows are closed once the context is canceled.
+	if _, _, err := cursor.Next(0); err == nil {
+		t.Errorf("expect error after the context is canceled")
+	}
+}
@@ -0,0 +1,121 @@
+// Package s3 is the plugin for S3-compatible object storage, e.g. AWS S3 and MinIO.
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// defaultRegion is used if the region is not specified, which is accepted by most S3-compatible services.
+	defaultRegion = "us-east-1"
+	// uploadPartSize is the part size of the multipart upload.
+	// An object can have at most 10,000 parts, so the upload is able to handle objects up to ~160GB.
+	uploadPartSize = 16 * 1024 * 1024
+)
+
+// Config is the configuration of the S3-compatible object storage.
+type Config struct {
+	// Endpoint is the URL of the S3-compatible service, e.g. http://localhost:9000 for a local MinIO.
+	// Leave it empty to use AWS S3.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix is prepended to all object keys.
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// UsePathStyle addresses the bucket with path style (http://host/bucket/key) instead of the virtual
+	// hosted style (http://bucket.host/key). Most self hosted services such as MinIO require this.
+	UsePathStyle bool
+}
+
+// Client is the client of the S3-compatible object storage.
+type Client struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewClient creates a new S3-compatible object storage client.
+func NewClient(config Config) (*Client, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket must be specified")
+	}
+	region := config.Region
+	if region == "" {
+		region = defaultRegion
+	}
+
+	options := s3.Options{
+		Region:       region,
+		Credentials:  credentials.NewStaticCredentialsProvider(config.AccessKeyID, config.SecretAccessKey, ""),
+		UsePathStyle: config.UsePathStyle,
+	}
+	if config.Endpoint != "" {
+		options.EndpointResolver = s3.EndpointResolverFromURL(config.Endpoint, func(e *aws.Endpoint) {
+			e.HostnameImmutable = config.UsePathStyle
+		})
+	}
+
+	return &Client{
+		client: s3.New(options),
+		bucket: config.Bucket,
+		prefix: strings.Trim(config.Prefix, "/"),
+	}, nil
+}
+
+// Key returns the object key with the configured prefix for the name.
+func (c *Client) Key(name string) string {
+	if c.prefix == "" {
+		return strings.TrimPrefix(name, "/")
+	}
+	return path.Join(c.prefix, name)
+}
+
+// UploadObject streams the body to the object with the key. The body is uploaded with multipart upload
+// so that we don't need to know the size or buffer the whole content in advance.
+func (c *Client) UploadObject(ctx context.Context, key string, body io.Reader) error {
+	uploader := manager.NewUploader(c.client, func(u *manager.Uploader) {
+		u.PartSize = uploadPartSize
+	})
+	if _, err := uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}); err != nil {
+		return fmt.Errorf("failed to upload object %q to bucket %q: %w", key, c.bucket, err)
+	}
+	return nil
+}
+
+// ReadObject returns the content stream of the object with the key. The caller should close the stream.
+func (c *Client) ReadObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %q from bucket %q: %w", key, c.bucket, err)
+	}
+	return output.Body, nil
+}
+
+// DeleteObject deletes the object with the key.
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	if _, err := c.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return fmt.Errorf("failed to delete object %q from bucket %q: %w", key, c.bucket, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		prefix string
+		name   string
+		want   string
+	}{
+		{
+			prefix: "",
+			name:   "backup/db/101/foo.sql",
+			want:   "backup/db/101/foo.sql",
+		},
+		{
+			prefix: "bytebase",
+			name:   "backup/db/101/foo.sql",
+			want:   "bytebase/backup/db/101/foo.sql",
+		},
+		{
+			prefix: "/bytebase/prod/",
+			name:   "backup/db/101/foo.sql",
+			want:   "bytebase/prod/backup/db/101/foo.sql",
+		},
+	}
+
+	for _, test := range tests {
+		client, err := NewClient(Config{Bucket: "bucket", Prefix: test.prefix})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := client.Key(test.name); got != test.want {
+			t.Errorf("Key(%q) with prefix %q = %q, want %q", test.name, test.prefix, got, test.want)
+		}
+	}
+}
+
+// TestUploadReadDelete runs against a local MinIO started by "docker run -p 9000:9000 minio/minio server /data",
+// and is skipped unless BB_TEST_S3_ENDPOINT is set.
+func TestUploadReadDelete(t *testing.T) {
+	endpoint := os.Getenv("BB_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("BB_TEST_S3_ENDPOINT is not set")
+	}
+	client, err := NewClient(Config{
+		Endpoint:        endpoint,
+		Bucket:          os.Getenv("BB_TEST_S3_BUCKET"),
+		Prefix:          "bytebase-test",
+		AccessKeyID:     os.Getenv("BB_TEST_S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("BB_TEST_S3_SECRET_ACCESS_KEY"),
+		UsePathStyle:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	key := client.Key("backup/test.sql")
+	// Larger than a single part to exercise the multipart upload.
+	content := strings.Repeat("INSERT INTO t VALUES (1);\n", uploadPartSize/10)
+	if err := client.UploadObject(ctx, key, strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := client.ReadObject(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, body); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != content {
+		t.Errorf("read %d bytes, want %d bytes", buf.Len(), len(content))
+	}
+
+	if err := client.DeleteObject(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,37 @@
+package mysqlutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Instance is the MySQL client utilities used to archive and replay the binlog for the point-in-time recovery.
+type Instance struct {
+	// binDir is the directory of the MySQL client binaries.
+	binDir string
+}
+
+// BinDir returns the directory of the MySQL client binaries, e.g. mysql and mysqlbinlog.
+func (i Instance) BinDir() string { return i.binDir }
+
+// Find finds the MySQL client utilities in binDir, or on PATH if binDir is empty.
+// The MySQL client utilities aren't embedded, so they must be installed separately.
+func Find(binDir string) (*Instance, error) {
+	if binDir == "" {
+		mysqlbinlog, err := exec.LookPath("mysqlbinlog")
+		if err != nil {
+			return nil, fmt.Errorf("failed to find mysqlbinlog on PATH, error: %w", err)
+		}
+		binDir = filepath.Dir(mysqlbinlog)
+	}
+	for _, name := range []string{"mysqlbinlog", "mysql"} {
+		if _, err := os.Stat(filepath.Join(binDir, name)); err != nil {
+			return nil, fmt.Errorf("failed to find %s in the directory %q, error: %w", name, binDir, err)
+		}
+	}
+	return &Instance{
+		binDir: binDir,
+	}, nil
+}
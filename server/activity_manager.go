@@ -23,6 +23,8 @@ type ActivityManager struct {
 // ActivityMeta is the activity metadata.
 type ActivityMeta struct {
 	issue *api.Issue
+	// anomaly is set for the anomaly activities, whose container is the project of the anomaly.
+	anomaly *anomalyActivityMeta
 }
 
 // NewActivityManager creates an activity manager.
@@ -45,6 +47,12 @@ func (m *ActivityManager) CreateActivity(ctx context.Context, create *api.Activi
 		return nil, err
 	}
 
+	if meta.anomaly != nil {
+		if err := m.postAnomalyActivity(ctx, activity, meta.anomaly); err != nil {
+			return nil, err
+		}
+		return activity, nil
+	}
 	if meta.issue == nil {
 		return activity, nil
 	}
@@ -301,6 +309,107 @@ func (m *ActivityManager) getWebhookContext(ctx context.Context, activity *api.A
 	return webhookCtx, nil
 }
 
+// postAnomalyActivity posts the anomaly activity to the inbox of the receivers, and to the project webhooks
+// subscribing to the activity type and the anomaly severity.
+func (m *ActivityManager) postAnomalyActivity(ctx context.Context, activity *api.Activity, meta *anomalyActivityMeta) error {
+	for _, receiverID := range meta.inboxReceiverList {
+		inboxCreate := &api.InboxCreate{
+			ReceiverID: receiverID,
+			ActivityID: activity.ID,
+		}
+		if _, err := m.s.InboxService.CreateInbox(ctx, inboxCreate); err != nil {
+			return fmt.Errorf("failed to post anomaly activity to inbox: %d, error: %w", receiverID, err)
+		}
+	}
+
+	hookFind := &api.ProjectWebhookFind{
+		ProjectID:       &meta.project.ID,
+		ActivityType:    &activity.Type,
+		AnomalySeverity: &meta.anomaly.Severity,
+	}
+	webhookList, err := m.s.ProjectWebhookService.FindProjectWebhookList(ctx, hookFind)
+	if err != nil {
+		return fmt.Errorf("failed to find project webhook for the anomaly activity, project ID: %v, error: %w", meta.project.ID, err)
+	}
+	if len(webhookList) == 0 {
+		return nil
+	}
+
+	principalFind := &api.PrincipalFind{
+		ID: &activity.CreatorID,
+	}
+	creator, err := m.s.PrincipalService.FindPrincipal(ctx, principalFind)
+	if err != nil {
+		return fmt.Errorf("failed to find creator for posting webhook event of the anomaly activity, error: %w", err)
+	}
+	if creator == nil {
+		return fmt.Errorf("Creator principal not found for ID %v", activity.CreatorID)
+	}
+
+	webhookCtx := m.getAnomalyWebhookContext(activity, meta, creator)
+	// Call external webhook endpoint in Go routine to avoid blocking the anomaly scan.
+	go func() {
+		for _, hook := range webhookList {
+			webhookCtx.URL = hook.URL
+			webhookCtx.CreatedTs = time.Now().Unix()
+			if err := webhook.Post(hook.Type, webhookCtx); err != nil {
+				// The external webhook endpoint might be invalid which is out of our code control, so we just emit a warning
+				m.s.l.Warn("Failed to post webhook event of the anomaly activity",
+					zap.String("webhook_type", hook.Type),
+					zap.String("webhook_name", hook.Name),
+					zap.String("anomaly_type", string(meta.anomaly.Type)),
+					zap.Int("instance_id", meta.anomaly.InstanceID),
+					zap.Error(err))
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (m *ActivityManager) getAnomalyWebhookContext(activity *api.Activity, meta *anomalyActivityMeta, creator *api.Principal) webhook.Context {
+	level := webhook.WebhookSuccess
+	title := "Anomaly resolved - " + getAnomalyTypeName(meta.anomaly.Type)
+	if activity.Type == api.ActivityAnomalyOpen {
+		level = webhook.WebhookWarn
+		if meta.anomaly.Severity != api.AnomalySeverityMedium {
+			level = webhook.WebhookError
+		}
+		title = "Anomaly opened - " + getAnomalyTypeName(meta.anomaly.Type)
+	}
+
+	metaList := []webhook.Meta{
+		{
+			Name:  "Project",
+			Value: meta.project.Name,
+		},
+		{
+			Name:  "Instance",
+			Value: meta.instance.Name,
+		},
+	}
+	if meta.database != nil {
+		metaList = append(metaList, webhook.Meta{
+			Name:  "Database",
+			Value: meta.database.Name,
+		})
+	}
+	metaList = append(metaList, webhook.Meta{
+		Name:  "Severity",
+		Value: string(meta.anomaly.Severity),
+	})
+
+	return webhook.Context{
+		Level:        level,
+		Title:        title,
+		Description:  activity.Comment,
+		Link:         fmt.Sprintf("%s:%d/anomaly-center", m.s.frontendHost, m.s.frontendPort),
+		CreatorName:  creator.Name,
+		CreatorEmail: creator.Email,
+		MetaList:     metaList,
+	}
+}
+
 func shouldPostInbox(activity *api.Activity, createType api.ActivityType) (bool, error) {
 	switch createType {
 	case api.ActivityIssueCreate:
@@ -0,0 +1,387 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerAnomalyRoutes(g *echo.Group) {
+	// Lists the anomaly history including the resolved anomalies, the most recent first.
+	g.GET("/anomaly", func(c echo.Context) error {
+		ctx := context.Background()
+		anomalyFind, err := getAnomalyHistoryFind(c)
+		if err != nil {
+			return err
+		}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a number: %s", limitStr)).SetInternal(err)
+			}
+			anomalyFind.Limit = &limit
+		}
+		anomalyRawList, err := s.AnomalyService.FindAnomalyList(ctx, anomalyFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch anomaly list").SetInternal(err)
+		}
+
+		var anomalyList []*api.Anomaly
+		for _, anomalyRaw := range anomalyRawList {
+			anomaly, err := s.composeAnomalyRelationship(ctx, anomalyRaw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch anomaly relationship: %v", anomalyRaw.ID)).SetInternal(err)
+			}
+			anomalyList = append(anomalyList, anomaly)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, anomalyList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal anomaly list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Summarizes the anomaly history, including the mean time to resolution.
+	g.GET("/anomaly/summary", func(c echo.Context) error {
+		ctx := context.Background()
+		anomalyFind, err := getAnomalyHistoryFind(c)
+		if err != nil {
+			return err
+		}
+		anomalyRawList, err := s.AnomalyService.FindAnomalyList(ctx, anomalyFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch anomaly list").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, summarizeAnomalyList(anomalyRawList)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal anomaly summary response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Acknowledges, snoozes, reopens or resolves an unresolved anomaly.
+	g.PATCH("/anomaly/:anomalyID/status", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("anomalyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("anomalyID"))).SetInternal(err)
+		}
+
+		anomalyStatusPatch := &api.AnomalyStatusPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, anomalyStatusPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted update anomaly status request").SetInternal(err)
+		}
+		switch anomalyStatusPatch.Status {
+		case api.AnomalyOpen, api.AnomalyAcknowledged, api.AnomalyResolved:
+		case api.AnomalySnoozed:
+			if anomalyStatusPatch.SnoozeUntilTs <= time.Now().Unix() {
+				return echo.NewHTTPError(http.StatusBadRequest, "Snooze time must be in the future")
+			}
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid anomaly status %q", anomalyStatusPatch.Status))
+		}
+
+		rowStatus := api.Normal
+		anomalyRawList, err := s.AnomalyService.FindAnomalyList(ctx, &api.AnomalyFind{
+			ID:        &id,
+			RowStatus: &rowStatus,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch anomaly ID: %v", id)).SetInternal(err)
+		}
+		if len(anomalyRawList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unresolved anomaly ID not found: %d", id))
+		}
+
+		anomalyRaw, err := s.AnomalyService.PatchAnomalyStatus(ctx, anomalyStatusPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unresolved anomaly ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update anomaly status with ID: %d", id)).SetInternal(err)
+		}
+		if anomalyStatusPatch.Status == api.AnomalyResolved {
+			s.notifyAnomalyResolved(ctx, anomalyRawList[0])
+		}
+
+		anomaly, err := s.composeAnomalyRelationship(ctx, anomalyRaw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch anomaly relationship: %v", anomalyRaw.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, anomaly); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal anomaly ID response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// Returns the schema drift payload with the expected and the actual schemas, which are fetched on demand
+	// from the migration history and the database, so that they're not stored in the anomaly.
+	g.GET("/anomaly/:anomalyID/schema-drift", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("anomalyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("anomalyID"))).SetInternal(err)
+		}
+
+		anomalyRawList, err := s.AnomalyService.FindAnomalyList(ctx, &api.AnomalyFind{
+			ID: &id,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch anomaly ID: %v", id)).SetInternal(err)
+		}
+		if len(anomalyRawList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Anomaly ID not found: %d", id))
+		}
+		anomaly := anomalyRawList[0]
+		if anomaly.Type != api.AnomalyDatabaseSchemaDrift || anomaly.DatabaseID == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Anomaly ID %d is not a schema drift anomaly", id))
+		}
+
+		database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: anomaly.DatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", *anomaly.DatabaseID)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", *anomaly.DatabaseID))
+		}
+
+		payload := &api.AnomalyDatabaseSchemaDriftPayload{}
+		if err := json.Unmarshal([]byte(anomaly.Payload), payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal anomaly payload for anomaly ID: %v", id)).SetInternal(err)
+		}
+		payload.Expect, payload.Actual, err = s.getSchemaDriftSchema(ctx, database, payload.Version)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift for database ID: %v", database.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, payload)
+	})
+
+	// Creates an issue to resolve the anomaly.
+	// For the schema drift anomaly, the issue either establishes a new baseline to accept the drift, or applies a schema update to revert it.
+	// For the index and table anomalies, the issue applies the suggested schema update.
+	g.POST("/anomaly/:anomalyID/issue", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("anomalyID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("anomalyID"))).SetInternal(err)
+		}
+
+		resolve := &api.AnomalySchemaDriftResolve{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, resolve); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted resolve anomaly request").SetInternal(err)
+		}
+
+		rowStatus := api.Normal
+		anomalyRawList, err := s.AnomalyService.FindAnomalyList(ctx, &api.AnomalyFind{
+			ID:        &id,
+			RowStatus: &rowStatus,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch anomaly ID: %v", id)).SetInternal(err)
+		}
+		if len(anomalyRawList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Anomaly ID not found: %d", id))
+		}
+		anomaly := anomalyRawList[0]
+		if anomaly.DatabaseID == nil || !isAnomalyResolvableByIssue(anomaly.Type) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Anomaly ID %d of type %q cannot be resolved by an issue", id, anomaly.Type))
+		}
+
+		database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: anomaly.DatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", *anomaly.DatabaseID)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", *anomaly.DatabaseID))
+		}
+		if database.Project.TenantMode == api.TenantModeTenant {
+			return echo.NewHTTPError(http.StatusBadRequest, "Resolving anomaly is not supported for tenant mode project")
+		}
+
+		m := &api.UpdateSchemaContext{}
+		issueCreate := &api.IssueCreate{
+			ProjectID:  database.ProjectID,
+			Type:       api.IssueDatabaseSchemaUpdate,
+			AssigneeID: resolve.AssigneeID,
+		}
+		if anomaly.Type == api.AnomalyDatabaseSchemaDrift {
+			payload := &api.AnomalyDatabaseSchemaDriftPayload{}
+			if err := json.Unmarshal([]byte(anomaly.Payload), payload); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal anomaly payload for anomaly ID: %v", id)).SetInternal(err)
+			}
+			switch resolve.Action {
+			case api.SchemaDriftResolveBaseline:
+				m.MigrationType = db.Baseline
+				m.UpdateSchemaDetailList = []*api.UpdateSchemaDetail{{DatabaseID: database.ID}}
+				issueCreate.Name = fmt.Sprintf("Establish %q baseline to accept schema drift", database.Name)
+				issueCreate.Description = fmt.Sprintf("Accept the schema drift of database %q from version %s by establishing a new baseline.", database.Name, payload.Version)
+			case api.SchemaDriftResolveRevert:
+				// The ignored differences are not reverted.
+				filter, err := s.getSchemaDriftFilter(ctx, database)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift rules for database ID: %v", database.ID)).SetInternal(err)
+				}
+				expect, actual, err := s.getSchemaDriftSchema(ctx, database, payload.Version)
+				if err != nil {
+					if common.ErrorCode(err) == common.NotFound {
+						return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+					}
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift for database ID: %v", database.ID)).SetInternal(err)
+				}
+				statement := getSchemaDriftRevertStatement(database.Instance.Engine, filter.apply(expect), filter.apply(actual))
+				if statement == "" {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema drift of database %q has no table, column, index or view change to revert", database.Name))
+				}
+				m.MigrationType = db.Migrate
+				m.UpdateSchemaDetailList = []*api.UpdateSchemaDetail{{DatabaseID: database.ID, Statement: statement}}
+				issueCreate.Name = fmt.Sprintf("Revert %q schema drift", database.Name)
+				issueCreate.Description = fmt.Sprintf("Revert the schema drift of database %q back to version %s.", database.Name, payload.Version)
+			default:
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid schema drift resolve action %q", resolve.Action))
+			}
+		} else {
+			// All the index and table anomaly payloads carry the suggested statement.
+			payload := &struct {
+				Statement string `json:"statement"`
+			}{}
+			if err := json.Unmarshal([]byte(anomaly.Payload), payload); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal anomaly payload for anomaly ID: %v", id)).SetInternal(err)
+			}
+			if payload.Statement == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Anomaly ID %d has no suggested statement", id))
+			}
+			m.MigrationType = db.Migrate
+			m.UpdateSchemaDetailList = []*api.UpdateSchemaDetail{{DatabaseID: database.ID, Statement: payload.Statement}}
+			switch anomaly.Type {
+			case api.AnomalyDatabaseIndexRedundant:
+				issueCreate.Name = fmt.Sprintf("Drop %q redundant indexes", database.Name)
+				issueCreate.Description = fmt.Sprintf("Drop the indexes of database %q whose columns are covered by another index.", database.Name)
+			case api.AnomalyDatabaseIndexUnused:
+				issueCreate.Name = fmt.Sprintf("Drop %q unused indexes", database.Name)
+				issueCreate.Description = fmt.Sprintf("Drop the indexes of database %q never used since the engine statistics were reset.", database.Name)
+			case api.AnomalyDatabaseTableNoPrimaryKey:
+				issueCreate.Name = fmt.Sprintf("Add %q missing primary keys", database.Name)
+				issueCreate.Description = fmt.Sprintf("Add the primary keys to the tables of database %q without one.", database.Name)
+			}
+		}
+		createContext, err := json.Marshal(m)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct issue create context payload").SetInternal(err)
+		}
+		issueCreate.CreateContext = string(createContext)
+
+		issue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			// The HTTP error of createIssue, e.g. the missing assignee, is returned as is.
+			if httpErr, ok := err.(*echo.HTTPError); ok {
+				return httpErr
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, issue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create issue response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// isAnomalyResolvableByIssue returns true if the anomaly can be resolved by creating a schema update issue.
+func isAnomalyResolvableByIssue(anomalyType api.AnomalyType) bool {
+	switch anomalyType {
+	case api.AnomalyDatabaseSchemaDrift, api.AnomalyDatabaseIndexRedundant, api.AnomalyDatabaseIndexUnused, api.AnomalyDatabaseTableNoPrimaryKey:
+		return true
+	}
+	return false
+}
+
+func (s *Server) composeAnomalyRelationship(ctx context.Context, raw *api.AnomalyRaw) (*api.Anomaly, error) {
+	anomaly := raw.ToAnomaly()
+
+	creator, err := s.composePrincipalByID(ctx, anomaly.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	anomaly.Creator = creator
+
+	updater, err := s.composePrincipalByID(ctx, anomaly.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	anomaly.Updater = updater
+
+	if anomaly.AcknowledgerID != nil {
+		acknowledger, err := s.composePrincipalByID(ctx, *anomaly.AcknowledgerID)
+		if err != nil {
+			return nil, err
+		}
+		anomaly.Acknowledger = acknowledger
+	}
+
+	return anomaly, nil
+}
+
+// getAnomalyHistoryFind returns the find of the anomaly history of the instance or the database in the query parameters.
+// The history of an instance includes the anomalies of its databases.
+func getAnomalyHistoryFind(c echo.Context) (*api.AnomalyFind, error) {
+	anomalyFind := &api.AnomalyFind{}
+	if instanceIDStr := c.QueryParam("instance"); instanceIDStr != "" {
+		instanceID, err := strconv.Atoi(instanceIDStr)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter instance is not a number: %s", instanceIDStr)).SetInternal(err)
+		}
+		anomalyFind.InstanceID = &instanceID
+	}
+	if databaseIDStr := c.QueryParam("database"); databaseIDStr != "" {
+		databaseID, err := strconv.Atoi(databaseIDStr)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter database is not a number: %s", databaseIDStr)).SetInternal(err)
+		}
+		anomalyFind.DatabaseID = &databaseID
+	}
+	if typeStr := c.QueryParam("type"); typeStr != "" {
+		anomalyType := api.AnomalyType(typeStr)
+		anomalyFind.Type = &anomalyType
+	}
+	return anomalyFind, nil
+}
+
+// summarizeAnomalyList counts the unresolved and resolved anomalies, and calculates the mean time to resolution.
+func summarizeAnomalyList(anomalyList []*api.AnomalyRaw) *api.AnomalySummary {
+	summary := &api.AnomalySummary{}
+	var totalResolveSeconds int64
+	for _, anomaly := range anomalyList {
+		if anomaly.Status != api.AnomalyResolved {
+			summary.OpenCount++
+			continue
+		}
+		summary.ResolvedCount++
+		if anomaly.ResolvedTs > anomaly.CreatedTs {
+			totalResolveSeconds += anomaly.ResolvedTs - anomaly.CreatedTs
+		}
+	}
+	if summary.ResolvedCount > 0 {
+		summary.MeanTimeToResolveSeconds = totalResolveSeconds / int64(summary.ResolvedCount)
+	}
+	return summary
+}
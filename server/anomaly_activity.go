@@ -0,0 +1,346 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+const (
+	// An anomaly reopened within the cooldown since the last notification is not notified again until the cooldown passes,
+	// so that a flapping anomaly, e.g. an unstable connection, doesn't spam the inbox and the webhooks.
+	anomalyNotificationCooldown = time.Duration(1) * time.Hour
+)
+
+// anomalyActivityMeta is the activity metadata of the anomaly activities.
+type anomalyActivityMeta struct {
+	anomaly  *api.AnomalyRaw
+	project  *api.ProjectRaw
+	instance *api.InstanceRaw
+	// database is nil for the instance anomalies.
+	database *api.DatabaseRaw
+	// inboxReceiverList is the principals receiving the activity in their inbox.
+	inboxReceiverList []int
+}
+
+// anomalyNotification is the last notification of an anomaly.
+type anomalyNotification struct {
+	// open is true if the last notification tells the anomaly opens, false if it tells the anomaly is resolved.
+	open bool
+	ts   int64
+}
+
+// anomalyNotifier de-duplicates the notifications of the anomalies.
+type anomalyNotifier struct {
+	mu sync.Mutex
+	// notificationMap is the last notification of each anomaly, keyed by getAnomalyNotificationKey.
+	notificationMap map[string]*anomalyNotification
+}
+
+func newAnomalyNotifier() *anomalyNotifier {
+	return &anomalyNotifier{
+		notificationMap: make(map[string]*anomalyNotification),
+	}
+}
+
+// shouldNotifyOpen returns true if the active anomaly should be notified as opened.
+// created is true if the anomaly is just created rather than updated.
+// An anomaly suppressed due to the cooldown is notified by a later check once the cooldown passes if it's still active.
+func (n *anomalyNotifier) shouldNotifyOpen(key string, created bool, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	last, ok := n.notificationMap[key]
+	if !ok {
+		// The anomaly opened before the server started has been notified already.
+		n.notificationMap[key] = &anomalyNotification{open: true, ts: now.Unix()}
+		return created
+	}
+	if last.open || now.Sub(time.Unix(last.ts, 0)) < anomalyNotificationCooldown {
+		return false
+	}
+	n.notificationMap[key] = &anomalyNotification{open: true, ts: now.Unix()}
+	return true
+}
+
+// shouldNotifyResolve returns true if the just archived anomaly should be notified as resolved.
+// Only the anomalies notified as opened are notified as resolved.
+func (n *anomalyNotifier) shouldNotifyResolve(key string, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if last, ok := n.notificationMap[key]; ok && !last.open {
+		return false
+	}
+	n.notificationMap[key] = &anomalyNotification{open: false, ts: now.Unix()}
+	return true
+}
+
+// getAnomalyNotificationKey returns the key of the anomaly notification.
+// The database anomalies are keyed by the database only since archiving doesn't specify the instance.
+func getAnomalyNotificationKey(instanceID int, databaseID *int, anomalyType api.AnomalyType) string {
+	if databaseID != nil {
+		return fmt.Sprintf("database/%d/%s", *databaseID, anomalyType)
+	}
+	return fmt.Sprintf("instance/%d/%s", instanceID, anomalyType)
+}
+
+// upsertActiveAnomaly upserts the active anomaly, and posts the anomaly activity if the anomaly opens.
+func (s *Server) upsertActiveAnomaly(ctx context.Context, upsert *api.AnomalyUpsert) (*api.AnomalyRaw, error) {
+	status := api.Normal
+	anomalyFind := &api.AnomalyFind{
+		RowStatus:  &status,
+		InstanceID: &upsert.InstanceID,
+		DatabaseID: upsert.DatabaseID,
+		Type:       &upsert.Type,
+	}
+	activeList, err := s.AnomalyService.FindAnomalyList(ctx, anomalyFind)
+	if err != nil {
+		return nil, err
+	}
+
+	anomaly, err := s.AnomalyService.UpsertActiveAnomaly(ctx, upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	// The snoozed anomaly is suppressed from the notifications until the snooze expires.
+	if anomaly.Status == api.AnomalySnoozed {
+		return anomaly, nil
+	}
+	key := getAnomalyNotificationKey(upsert.InstanceID, upsert.DatabaseID, upsert.Type)
+	if s.anomalyNotifier.shouldNotifyOpen(key, len(activeList) == 0, time.Now()) {
+		if err := s.postAnomalyActivity(ctx, anomaly, api.ActivityAnomalyOpen); err != nil {
+			s.l.Warn("Failed to post anomaly activity after opening the anomaly",
+				zap.Int("anomaly_id", anomaly.ID),
+				zap.String("type", string(anomaly.Type)),
+				zap.Error(err))
+		}
+	}
+	return anomaly, nil
+}
+
+// archiveAnomaly archives the active anomaly, and posts the anomaly activity as the anomaly is resolved.
+// Returns ENOTFOUND if anomaly does not exist.
+func (s *Server) archiveAnomaly(ctx context.Context, archive *api.AnomalyArchive) error {
+	status := api.Normal
+	anomalyFind := &api.AnomalyFind{
+		RowStatus:    &status,
+		InstanceID:   archive.InstanceID,
+		DatabaseID:   archive.DatabaseID,
+		Type:         &archive.Type,
+		InstanceOnly: archive.InstanceID != nil,
+	}
+	activeList, err := s.AnomalyService.FindAnomalyList(ctx, anomalyFind)
+	if err != nil {
+		return err
+	}
+
+	if err := s.AnomalyService.ArchiveAnomaly(ctx, archive); err != nil {
+		return err
+	}
+
+	for _, anomaly := range activeList {
+		s.notifyAnomalyResolved(ctx, anomaly)
+	}
+	return nil
+}
+
+// notifyAnomalyResolved posts the anomaly activity for the just resolved anomaly.
+// The anomaly snoozed before resolving is suppressed from the notifications.
+func (s *Server) notifyAnomalyResolved(ctx context.Context, anomaly *api.AnomalyRaw) {
+	key := getAnomalyNotificationKey(anomaly.InstanceID, anomaly.DatabaseID, anomaly.Type)
+	if !s.anomalyNotifier.shouldNotifyResolve(key, time.Now()) || anomaly.Status == api.AnomalySnoozed {
+		return
+	}
+	if err := s.postAnomalyActivity(ctx, anomaly, api.ActivityAnomalyResolve); err != nil {
+		s.l.Warn("Failed to post anomaly activity after resolving the anomaly",
+			zap.Int("anomaly_id", anomaly.ID),
+			zap.String("type", string(anomaly.Type)),
+			zap.Error(err))
+	}
+}
+
+// postAnomalyActivity creates the anomaly activity in each project owning the database of the anomaly,
+// or owning any database of the instance for the instance anomalies.
+// The activity is posted to the inbox of the project owners and the DBAs, each receiver only once.
+func (s *Server) postAnomalyActivity(ctx context.Context, anomaly *api.AnomalyRaw, activityType api.ActivityType) error {
+	instance, err := s.InstanceService.FindInstance(ctx, &api.InstanceFind{ID: &anomaly.InstanceID})
+	if err != nil {
+		return fmt.Errorf("failed to find instance %d, error: %w", anomaly.InstanceID, err)
+	}
+	if instance == nil {
+		return fmt.Errorf("instance %d not found", anomaly.InstanceID)
+	}
+
+	var database *api.DatabaseRaw
+	var projectIDList []int
+	if anomaly.DatabaseID != nil {
+		database, err = s.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{ID: anomaly.DatabaseID})
+		if err != nil {
+			return fmt.Errorf("failed to find database %d, error: %w", *anomaly.DatabaseID, err)
+		}
+		if database == nil {
+			return fmt.Errorf("database %d not found", *anomaly.DatabaseID)
+		}
+		projectIDList = append(projectIDList, database.ProjectID)
+	} else {
+		databaseList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{InstanceID: &instance.ID})
+		if err != nil {
+			return fmt.Errorf("failed to find databases of instance %d, error: %w", instance.ID, err)
+		}
+		projectIDSet := make(map[int]bool)
+		for _, database := range databaseList {
+			if !projectIDSet[database.ProjectID] {
+				projectIDSet[database.ProjectID] = true
+				projectIDList = append(projectIDList, database.ProjectID)
+			}
+		}
+	}
+
+	dbaRole := api.DBA
+	dbaList, err := s.MemberService.FindMemberList(ctx, &api.MemberFind{Role: &dbaRole})
+	if err != nil {
+		return fmt.Errorf("failed to find DBAs, error: %w", err)
+	}
+
+	payload := &api.ActivityAnomalyPayload{
+		AnomalyID:    anomaly.ID,
+		AnomalyType:  anomaly.Type,
+		Severity:     anomaly.Severity,
+		InstanceID:   instance.ID,
+		InstanceName: instance.Name,
+	}
+	if database != nil {
+		payload.DatabaseID = database.ID
+		payload.DatabaseName = database.Name
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal anomaly activity payload, error: %w", err)
+	}
+
+	notifiedSet := make(map[int]bool)
+	for _, projectID := range projectIDList {
+		project, err := s.ProjectService.FindProject(ctx, &api.ProjectFind{ID: &projectID})
+		if err != nil {
+			return fmt.Errorf("failed to find project %d, error: %w", projectID, err)
+		}
+		if project == nil {
+			return fmt.Errorf("project %d not found", projectID)
+		}
+		projectMemberList, err := s.ProjectMemberService.FindProjectMemberList(ctx, &api.ProjectMemberFind{ProjectID: &projectID})
+		if err != nil {
+			return fmt.Errorf("failed to find members of project %d, error: %w", projectID, err)
+		}
+
+		activityCreate := &api.ActivityCreate{
+			CreatorID:   api.SystemBotID,
+			ContainerID: projectID,
+			Type:        activityType,
+			Level:       getAnomalyActivityLevel(activityType, anomaly.Severity),
+			Comment:     getAnomalyActivityComment(activityType, anomaly.Type, instance, database),
+			Payload:     string(bytes),
+		}
+		meta := &anomalyActivityMeta{
+			anomaly:           anomaly,
+			project:           project,
+			instance:          instance,
+			database:          database,
+			inboxReceiverList: getAnomalyInboxReceiverList(projectMemberList, dbaList, notifiedSet),
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{anomaly: meta}); err != nil {
+			return fmt.Errorf("failed to create anomaly activity in project %d, error: %w", projectID, err)
+		}
+	}
+	return nil
+}
+
+// getAnomalyInboxReceiverList returns the project owners and the DBAs not in notifiedSet, and adds them to notifiedSet.
+func getAnomalyInboxReceiverList(projectMemberList []*api.ProjectMemberRaw, dbaList []*api.MemberRaw, notifiedSet map[int]bool) []int {
+	var receiverList []int
+	addReceiver := func(principalID int) {
+		if principalID == api.SystemBotID || notifiedSet[principalID] {
+			return
+		}
+		notifiedSet[principalID] = true
+		receiverList = append(receiverList, principalID)
+	}
+	for _, projectMember := range projectMemberList {
+		if projectMember.Role == string(common.ProjectOwner) {
+			addReceiver(projectMember.PrincipalID)
+		}
+	}
+	for _, dba := range dbaList {
+		if dba.RowStatus == api.Normal {
+			addReceiver(dba.PrincipalID)
+		}
+	}
+	return receiverList
+}
+
+// getAnomalyActivityLevel returns ERROR for opening CRITICAL and HIGH anomalies, WARN for opening MEDIUM anomalies,
+// and INFO for resolving anomalies.
+func getAnomalyActivityLevel(activityType api.ActivityType, severity api.AnomalySeverity) api.ActivityLevel {
+	if activityType != api.ActivityAnomalyOpen {
+		return api.ActivityInfo
+	}
+	if severity == api.AnomalySeverityMedium {
+		return api.ActivityWarn
+	}
+	return api.ActivityError
+}
+
+func getAnomalyActivityComment(activityType api.ActivityType, anomalyType api.AnomalyType, instance *api.InstanceRaw, database *api.DatabaseRaw) string {
+	action := "opened"
+	if activityType == api.ActivityAnomalyResolve {
+		action = "resolved"
+	}
+	if database != nil {
+		return fmt.Sprintf("%s anomaly %s on database %q of instance %q.", getAnomalyTypeName(anomalyType), action, database.Name, instance.Name)
+	}
+	return fmt.Sprintf("%s anomaly %s on instance %q.", getAnomalyTypeName(anomalyType), action, instance.Name)
+}
+
+// getAnomalyTypeName returns the human readable name of the anomaly type.
+func getAnomalyTypeName(anomalyType api.AnomalyType) string {
+	switch anomalyType {
+	case api.AnomalyInstanceConnection, api.AnomalyDatabaseConnection:
+		return "Connection failure"
+	case api.AnomalyInstanceMigrationSchema:
+		return "Missing migration schema"
+	case api.AnomalyInstanceReplicationLag:
+		return "Replication lag"
+	case api.AnomalyInstanceConnectionUsage:
+		return "Connection usage"
+	case api.AnomalyInstanceLongRunningTransaction:
+		return "Long-running transaction"
+	case api.AnomalyInstanceStorageGrowth:
+		return "Storage growth"
+	case api.AnomalyDatabaseBackupPolicyViolation:
+		return "Backup enforcement violation"
+	case api.AnomalyDatabaseBackupMissing:
+		return "Missing backup"
+	case api.AnomalyDatabaseBackupCleanupFailure:
+		return "Backup cleanup failure"
+	case api.AnomalyDatabaseBackupVerificationFailure:
+		return "Backup verification failure"
+	case api.AnomalyDatabaseSchemaDrift:
+		return "Schema drift"
+	case api.AnomalyDatabaseIndexRedundant:
+		return "Redundant index"
+	case api.AnomalyDatabaseIndexUnused:
+		return "Unused index"
+	case api.AnomalyDatabaseTableNoPrimaryKey:
+		return "Missing primary key"
+	case api.AnomalyDatabaseSlowQueryRegression:
+		return "Slow query regression"
+	}
+	return string(anomalyType)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestAnomalyNotifier(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	type step struct {
+		after time.Duration
+		// open is true for an active anomaly check, false for an archived anomaly check.
+		open    bool
+		created bool
+		want    bool
+	}
+	tests := []struct {
+		name     string
+		stepList []step
+	}{
+		{
+			name: "open and resolve",
+			stepList: []step{
+				{after: 0, open: true, created: true, want: true},
+				{after: 10 * time.Minute, open: true, want: false},
+				{after: 20 * time.Minute, open: false, want: true},
+			},
+		},
+		{
+			name: "flapping",
+			stepList: []step{
+				{after: 0, open: true, created: true, want: true},
+				{after: 10 * time.Minute, open: false, want: true},
+				// Reopened within the cooldown.
+				{after: 20 * time.Minute, open: true, created: true, want: false},
+				// Not notified as resolved since the reopening isn't notified.
+				{after: 30 * time.Minute, open: false, want: false},
+				{after: 40 * time.Minute, open: true, created: true, want: false},
+				// Still active once the cooldown passes.
+				{after: 70 * time.Minute, open: true, want: true},
+				{after: 80 * time.Minute, open: false, want: true},
+			},
+		},
+		{
+			name: "opened before the server starts",
+			stepList: []step{
+				{after: 0, open: true, want: false},
+				{after: 10 * time.Minute, open: false, want: true},
+			},
+		},
+		{
+			name: "resolved after the server starts",
+			stepList: []step{
+				{after: 0, open: false, want: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		notifier := newAnomalyNotifier()
+		for i, step := range test.stepList {
+			now := start.Add(step.after)
+			var got bool
+			if step.open {
+				got = notifier.shouldNotifyOpen("instance/1/bb.anomaly.instance.connection", step.created, now)
+			} else {
+				got = notifier.shouldNotifyResolve("instance/1/bb.anomaly.instance.connection", now)
+			}
+			if got != step.want {
+				t.Errorf("%s: step %d got %v, want %v", test.name, i, got, step.want)
+			}
+		}
+	}
+}
+
+func TestGetAnomalyInboxReceiverList(t *testing.T) {
+	projectMemberList := []*api.ProjectMemberRaw{
+		{PrincipalID: 101, Role: "OWNER"},
+		{PrincipalID: 102, Role: "DEVELOPER"},
+		{PrincipalID: 103, Role: "OWNER"},
+	}
+	dbaList := []*api.MemberRaw{
+		{PrincipalID: 103, Role: api.DBA, RowStatus: api.Normal},
+		{PrincipalID: 104, Role: api.DBA, RowStatus: api.Normal},
+		{PrincipalID: 105, Role: api.DBA, RowStatus: api.Archived},
+	}
+	notifiedSet := map[int]bool{101: true}
+
+	want := []int{103, 104}
+	if got := getAnomalyInboxReceiverList(projectMemberList, dbaList, notifiedSet); !reflect.DeepEqual(got, want) {
+		t.Errorf("getAnomalyInboxReceiverList() = %v, want %v", got, want)
+	}
+	wantNotifiedSet := map[int]bool{101: true, 103: true, 104: true}
+	if !reflect.DeepEqual(notifiedSet, wantNotifiedSet) {
+		t.Errorf("got notified set %v, want %v", notifiedSet, wantNotifiedSet)
+	}
+}
+
+func TestGetAnomalyActivityLevel(t *testing.T) {
+	tests := []struct {
+		activityType api.ActivityType
+		severity     api.AnomalySeverity
+		want         api.ActivityLevel
+	}{
+		{activityType: api.ActivityAnomalyOpen, severity: api.AnomalySeverityCritical, want: api.ActivityError},
+		{activityType: api.ActivityAnomalyOpen, severity: api.AnomalySeverityHigh, want: api.ActivityError},
+		{activityType: api.ActivityAnomalyOpen, severity: api.AnomalySeverityMedium, want: api.ActivityWarn},
+		{activityType: api.ActivityAnomalyResolve, severity: api.AnomalySeverityCritical, want: api.ActivityInfo},
+	}
+
+	for _, test := range tests {
+		if got := getAnomalyActivityLevel(test.activityType, test.severity); got != test.want {
+			t.Errorf("getAnomalyActivityLevel(%s, %s) = %s, want %s", test.activityType, test.severity, got, test.want)
+		}
+	}
+}
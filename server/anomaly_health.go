@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+const (
+	// The storage growth is measured against the oldest size sample within the window.
+	storageGrowthWindow = time.Duration(24) * time.Hour
+	// The storage growth is only checked once the samples cover long enough of the window,
+	// otherwise a small absolute growth right after the start would be flagged.
+	storageGrowthMinWindow = time.Duration(6) * time.Hour
+	// The statement of a long-running transaction in the anomaly payload is truncated to this many characters.
+	maxTransactionQueryLength = 1024
+)
+
+// storageSample is the size of a database or tablespace at a point in time.
+type storageSample struct {
+	ts        int64
+	sizeBytes int64
+}
+
+// checkInstanceHealthAnomaly checks the replication lag, the connection usage, the long-running transactions
+// and the storage growth of the instance against the thresholds of the instance health policy.
+// The anomaly of a disabled check is resolved as well.
+func (s *AnomalyScanner) checkInstanceHealthAnomaly(ctx context.Context, instance *api.Instance, driver db.Driver, policy *api.InstanceHealthPolicy) {
+	transactionDuration := time.Duration(policy.LongRunningTransactionSeconds) * time.Second
+	health, err := driver.GetInstanceHealth(ctx, transactionDuration)
+	if err != nil {
+		s.l.Error("Failed to check instance health",
+			zap.String("instance", instance.Name),
+			zap.Error(err))
+		return
+	}
+	if health == nil {
+		return
+	}
+
+	// Check replication lag
+	var replicationLagPayload interface{}
+	if policy.ReplicationLagSeconds > 0 {
+		if itemList := getReplicationLagItemList(health.ReplicaList, policy.ReplicationLagSeconds); len(itemList) > 0 {
+			replicationLagPayload = &api.AnomalyInstanceReplicationLagPayload{
+				ThresholdSeconds: policy.ReplicationLagSeconds,
+				ItemList:         itemList,
+			}
+		}
+	}
+	s.upsertOrArchiveInstanceAnomaly(ctx, instance, api.AnomalyInstanceReplicationLag, replicationLagPayload)
+
+	// Check connection usage
+	var connectionUsagePayload interface{}
+	if policy.ConnectionUsagePercent > 0 && isConnectionUsageExceeded(health.Connections, health.MaxConnections, policy.ConnectionUsagePercent) {
+		connectionUsagePayload = &api.AnomalyInstanceConnectionUsagePayload{
+			ThresholdPercent: policy.ConnectionUsagePercent,
+			Connections:      health.Connections,
+			MaxConnections:   health.MaxConnections,
+		}
+	}
+	s.upsertOrArchiveInstanceAnomaly(ctx, instance, api.AnomalyInstanceConnectionUsage, connectionUsagePayload)
+
+	// Check long-running transactions
+	var longRunningTransactionPayload interface{}
+	if policy.LongRunningTransactionSeconds > 0 && len(health.TransactionList) > 0 {
+		var itemList []*api.AnomalyTransactionItem
+		for _, transaction := range health.TransactionList {
+			query := []rune(transaction.Query)
+			if len(query) > maxTransactionQueryLength {
+				query = append(query[:maxTransactionQueryLength], []rune("...")...)
+			}
+			itemList = append(itemList, &api.AnomalyTransactionItem{
+				ProcessID:       transaction.ProcessID,
+				Database:        transaction.Database,
+				User:            transaction.User,
+				DurationSeconds: transaction.DurationSeconds,
+				Query:           string(query),
+			})
+		}
+		longRunningTransactionPayload = &api.AnomalyInstanceLongRunningTransactionPayload{
+			ThresholdSeconds: policy.LongRunningTransactionSeconds,
+			ItemList:         itemList,
+		}
+	}
+	s.upsertOrArchiveInstanceAnomaly(ctx, instance, api.AnomalyInstanceLongRunningTransaction, longRunningTransactionPayload)
+
+	// Check storage growth
+	s.storageSampleMu.Lock()
+	itemList, sampleMap := getStorageGrowthItemList(health.StorageList, s.storageSampleMap[instance.ID], time.Now(), policy.StorageGrowthPercent)
+	s.storageSampleMap[instance.ID] = sampleMap
+	s.storageSampleMu.Unlock()
+	var storageGrowthPayload interface{}
+	if policy.StorageGrowthPercent > 0 && len(itemList) > 0 {
+		storageGrowthPayload = &api.AnomalyInstanceStorageGrowthPayload{
+			ThresholdPercent: policy.StorageGrowthPercent,
+			ItemList:         itemList,
+		}
+	}
+	s.upsertOrArchiveInstanceAnomaly(ctx, instance, api.AnomalyInstanceStorageGrowth, storageGrowthPayload)
+}
+
+// upsertOrArchiveInstanceAnomaly upserts the active anomaly of the instance with the payload, or archives it if the payload is nil.
+func (s *AnomalyScanner) upsertOrArchiveInstanceAnomaly(ctx context.Context, instance *api.Instance, anomalyType api.AnomalyType, anomalyPayload interface{}) {
+	if anomalyPayload == nil {
+		err := s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
+			InstanceID: &instance.ID,
+			Type:       anomalyType,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			s.l.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("type", string(anomalyType)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	payload, err := json.Marshal(anomalyPayload)
+	if err != nil {
+		s.l.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(anomalyType)),
+			zap.Error(err))
+		return
+	}
+	if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Type:       anomalyType,
+		Payload:    string(payload),
+	}); err != nil {
+		s.l.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("type", string(anomalyType)),
+			zap.Error(err))
+	}
+}
+
+// getReplicationLagItemList returns the replicas not replicating or lagging behind for at least thresholdSeconds.
+func getReplicationLagItemList(replicaList []db.Replica, thresholdSeconds int) []*api.AnomalyReplicaItem {
+	var itemList []*api.AnomalyReplicaItem
+	for _, replica := range replicaList {
+		if replica.Running && replica.LagSeconds < int64(thresholdSeconds) {
+			continue
+		}
+		itemList = append(itemList, &api.AnomalyReplicaItem{
+			Replica:    replica.Name,
+			Running:    replica.Running,
+			LagSeconds: replica.LagSeconds,
+			Detail:     replica.Detail,
+		})
+	}
+	return itemList
+}
+
+// isConnectionUsageExceeded returns true if the connections reach thresholdPercent of maxConnections.
+// The usage is never exceeded if the connections are unlimited.
+func isConnectionUsageExceeded(connections, maxConnections, thresholdPercent int) bool {
+	if maxConnections <= 0 {
+		return false
+	}
+	return connections*100 >= maxConnections*thresholdPercent
+}
+
+// getStorageGrowthItemList returns the databases and tablespaces whose size grows by more than thresholdPercent
+// against the oldest sample within the growth window, and the samples updated with the current sizes.
+// The samples of the databases and tablespaces no longer found are dropped.
+func getStorageGrowthItemList(storageList []db.Storage, sampleMap map[string][]storageSample, now time.Time, thresholdPercent int) ([]*api.AnomalyStorageItem, map[string][]storageSample) {
+	var itemList []*api.AnomalyStorageItem
+	updatedSampleMap := make(map[string][]storageSample)
+	for _, storage := range storageList {
+		key := fmt.Sprintf("%s/%s", storage.Type, storage.Name)
+		var sampleList []storageSample
+		for _, sample := range sampleMap[key] {
+			if now.Sub(time.Unix(sample.ts, 0)) <= storageGrowthWindow {
+				sampleList = append(sampleList, sample)
+			}
+		}
+		if thresholdPercent > 0 && len(sampleList) > 0 {
+			oldest := sampleList[0]
+			if now.Sub(time.Unix(oldest.ts, 0)) >= storageGrowthMinWindow && oldest.sizeBytes > 0 &&
+				(storage.SizeBytes-oldest.sizeBytes)*100 > oldest.sizeBytes*int64(thresholdPercent) {
+				itemList = append(itemList, &api.AnomalyStorageItem{
+					Type:              string(storage.Type),
+					Name:              storage.Name,
+					SizeBytes:         storage.SizeBytes,
+					PreviousSizeBytes: oldest.sizeBytes,
+					PreviousTs:        oldest.ts,
+				})
+			}
+		}
+		updatedSampleMap[key] = append(sampleList, storageSample{
+			ts:        now.Unix(),
+			sizeBytes: storage.SizeBytes,
+		})
+	}
+	return itemList, updatedSampleMap
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetReplicationLagItemList(t *testing.T) {
+	replicaList := []db.Replica{
+		{Name: "replica1", Running: true, LagSeconds: 10},
+		{Name: "replica2", Running: true, LagSeconds: 300},
+		{Name: "replica3", Running: false, Detail: "error connecting to source"},
+	}
+	want := []*api.AnomalyReplicaItem{
+		{Replica: "replica2", Running: true, LagSeconds: 300},
+		{Replica: "replica3", Running: false, Detail: "error connecting to source"},
+	}
+	if got := getReplicationLagItemList(replicaList, 300); !reflect.DeepEqual(got, want) {
+		t.Errorf("getReplicationLagItemList() = %+v, want %+v", got, want)
+	}
+}
+
+func TestIsConnectionUsageExceeded(t *testing.T) {
+	tests := []struct {
+		connections      int
+		maxConnections   int
+		thresholdPercent int
+		want             bool
+	}{
+		{connections: 79, maxConnections: 100, thresholdPercent: 80, want: false},
+		{connections: 80, maxConnections: 100, thresholdPercent: 80, want: true},
+		{connections: 130, maxConnections: 151, thresholdPercent: 80, want: true},
+		// Unlimited connections
+		{connections: 1000, maxConnections: 0, thresholdPercent: 80, want: false},
+	}
+
+	for _, test := range tests {
+		if got := isConnectionUsageExceeded(test.connections, test.maxConnections, test.thresholdPercent); got != test.want {
+			t.Errorf("isConnectionUsageExceeded(%d, %d, %d) = %v, want %v", test.connections, test.maxConnections, test.thresholdPercent, got, test.want)
+		}
+	}
+}
+
+func TestGetStorageGrowthItemList(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	ts := func(ago time.Duration) int64 {
+		return now.Add(-ago).Unix()
+	}
+	sampleMap := map[string][]storageSample{
+		// The sample out of the growth window is dropped, and the growth is measured against the 12 hours ago one.
+		"DATABASE/db1": {{ts: ts(30 * time.Hour), sizeBytes: 100}, {ts: ts(12 * time.Hour), sizeBytes: 1000}},
+		// Not grown enough.
+		"DATABASE/db2": {{ts: ts(12 * time.Hour), sizeBytes: 1000}},
+		// Not covering long enough of the window.
+		"DATABASE/db3": {{ts: ts(time.Hour), sizeBytes: 1000}},
+		// No longer found.
+		"DATABASE/db4":         {{ts: ts(12 * time.Hour), sizeBytes: 1000}},
+		"TABLESPACE/pg_global": {{ts: ts(20 * time.Hour), sizeBytes: 1000}},
+	}
+	storageList := []db.Storage{
+		{Type: db.StorageDatabase, Name: "db1", SizeBytes: 1300},
+		{Type: db.StorageDatabase, Name: "db2", SizeBytes: 1100},
+		{Type: db.StorageDatabase, Name: "db3", SizeBytes: 5000},
+		{Type: db.StorageDatabase, Name: "db5", SizeBytes: 5000},
+		{Type: db.StorageTablespace, Name: "pg_global", SizeBytes: 1201},
+	}
+
+	itemList, updatedSampleMap := getStorageGrowthItemList(storageList, sampleMap, now, 20)
+	wantItemList := []*api.AnomalyStorageItem{
+		{Type: "DATABASE", Name: "db1", SizeBytes: 1300, PreviousSizeBytes: 1000, PreviousTs: ts(12 * time.Hour)},
+		{Type: "TABLESPACE", Name: "pg_global", SizeBytes: 1201, PreviousSizeBytes: 1000, PreviousTs: ts(20 * time.Hour)},
+	}
+	if !reflect.DeepEqual(itemList, wantItemList) {
+		t.Errorf("got item list %+v, want %+v", itemList, wantItemList)
+	}
+	wantSampleMap := map[string][]storageSample{
+		"DATABASE/db1":         {{ts: ts(12 * time.Hour), sizeBytes: 1000}, {ts: now.Unix(), sizeBytes: 1300}},
+		"DATABASE/db2":         {{ts: ts(12 * time.Hour), sizeBytes: 1000}, {ts: now.Unix(), sizeBytes: 1100}},
+		"DATABASE/db3":         {{ts: ts(time.Hour), sizeBytes: 1000}, {ts: now.Unix(), sizeBytes: 5000}},
+		"DATABASE/db5":         {{ts: now.Unix(), sizeBytes: 5000}},
+		"TABLESPACE/pg_global": {{ts: ts(20 * time.Hour), sizeBytes: 1000}, {ts: now.Unix(), sizeBytes: 1201}},
+	}
+	if !reflect.DeepEqual(updatedSampleMap, wantSampleMap) {
+		t.Errorf("got sample map %+v, want %+v", updatedSampleMap, wantSampleMap)
+	}
+}
@@ -0,0 +1,474 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+const (
+	// The engine must have collected the index usage for long enough before an index is considered unused,
+	// otherwise indexes only used by the daily or weekly jobs would be flagged right after a restart.
+	indexUsageMinCollectDuration = time.Duration(7*24) * time.Hour
+)
+
+func isIndexAnomalySupported(engine db.Type) bool {
+	return engine == db.MySQL || engine == db.TiDB || engine == db.Postgres
+}
+
+// tableIndex is an index of a table composed from the synced index metadata, which is stored one row per column.
+type tableIndex struct {
+	name       string
+	columnList []string
+	// indexType such as BTREE.
+	indexType string
+	unique    bool
+	primary   bool
+}
+
+// redundantIndex is an index whose columns are a left prefix of the covering index.
+type redundantIndex struct {
+	index         *tableIndex
+	coveringIndex *tableIndex
+}
+
+// checkIndexAnomaly checks the redundant indexes, the unused indexes and the tables without a primary key
+// from the synced metadata and the engine statistics of the database.
+func (s *AnomalyScanner) checkIndexAnomaly(ctx context.Context, instance *api.Instance, database *api.Database, driver db.Driver) {
+	if !isIndexAnomalySupported(instance.Engine) {
+		return
+	}
+
+	tableList, err := s.server.TableService.FindTableList(ctx, &api.TableFind{DatabaseID: &database.ID})
+	if err != nil {
+		s.l.Error("Failed to retrieve table list",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+	indexList, err := s.server.IndexService.FindIndexList(ctx, &api.IndexFind{DatabaseID: &database.ID})
+	if err != nil {
+		s.l.Error("Failed to retrieve index list",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+	columnList, err := s.server.ColumnService.FindColumnList(ctx, &api.ColumnFind{DatabaseID: &database.ID})
+	if err != nil {
+		s.l.Error("Failed to retrieve column list",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+	tableIndexMap := composeTableIndexMap(indexList)
+	tableColumnMap := make(map[int][]*api.Column)
+	for _, column := range columnList {
+		tableColumnMap[column.TableID] = append(tableColumnMap[column.TableID], column)
+	}
+
+	// Check redundant indexes and tables without a primary key
+	var redundantItemList []*api.AnomalyIndexItem
+	var noPrimaryKeyItemList []*api.AnomalyTableItem
+	for _, table := range tableList {
+		if table.Type != "BASE TABLE" {
+			continue
+		}
+		tableIndexList := tableIndexMap[table.ID]
+		for _, redundant := range findRedundantIndexList(tableIndexList) {
+			redundantItemList = append(redundantItemList, &api.AnomalyIndexItem{
+				Table:         table.Name,
+				Index:         redundant.index.name,
+				ColumnList:    redundant.index.columnList,
+				CoveringIndex: redundant.coveringIndex.name,
+				Statement:     getIndexAnomalyDropStatement(instance.Engine, table.Name, redundant.index.name),
+			})
+		}
+		if !hasPrimaryKey(tableIndexList) {
+			noPrimaryKeyItemList = append(noPrimaryKeyItemList, &api.AnomalyTableItem{
+				Table:     table.Name,
+				Statement: getAddPrimaryKeyStatement(instance.Engine, table.Name, tableIndexList, tableColumnMap[table.ID]),
+			})
+		}
+	}
+	var redundantPayload interface{}
+	if len(redundantItemList) > 0 {
+		redundantPayload = &api.AnomalyDatabaseIndexRedundantPayload{
+			ItemList:  redundantItemList,
+			Statement: joinIndexItemStatement(redundantItemList),
+		}
+	}
+	s.upsertOrArchiveDatabaseAnomaly(ctx, instance, database, api.AnomalyDatabaseIndexRedundant, redundantPayload)
+
+	var noPrimaryKeyPayload interface{}
+	if len(noPrimaryKeyItemList) > 0 {
+		var statementList []string
+		for _, item := range noPrimaryKeyItemList {
+			statementList = append(statementList, item.Statement)
+		}
+		noPrimaryKeyPayload = &api.AnomalyDatabaseTableNoPrimaryKeyPayload{
+			ItemList:  noPrimaryKeyItemList,
+			Statement: strings.Join(statementList, "\n"),
+		}
+	}
+	s.upsertOrArchiveDatabaseAnomaly(ctx, instance, database, api.AnomalyDatabaseTableNoPrimaryKey, noPrimaryKeyPayload)
+
+	// Check unused indexes
+	unusedIndexSet, statsSinceTs, err := getUnusedIndexSet(ctx, driver, instance.Engine, database.Name)
+	if err != nil {
+		s.l.Debug("Failed to check anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseIndexUnused)),
+			zap.Error(err))
+		return
+	}
+	// Leave the anomaly as is until the engine has collected the index usage for long enough.
+	if unusedIndexSet == nil || time.Since(time.Unix(statsSinceTs, 0)) < indexUsageMinCollectDuration {
+		return
+	}
+	var unusedItemList []*api.AnomalyIndexItem
+	for _, table := range tableList {
+		if table.Type != "BASE TABLE" {
+			continue
+		}
+		for _, index := range tableIndexMap[table.ID] {
+			// Unique indexes and primary keys enforce the constraints even if they are never read.
+			if index.unique || index.primary {
+				continue
+			}
+			if !unusedIndexSet[getIndexUsageKey(instance.Engine, table.Name, index.name)] {
+				continue
+			}
+			unusedItemList = append(unusedItemList, &api.AnomalyIndexItem{
+				Table:      table.Name,
+				Index:      index.name,
+				ColumnList: index.columnList,
+				Statement:  getIndexAnomalyDropStatement(instance.Engine, table.Name, index.name),
+			})
+		}
+	}
+	var unusedPayload interface{}
+	if len(unusedItemList) > 0 {
+		unusedPayload = &api.AnomalyDatabaseIndexUnusedPayload{
+			ItemList:     unusedItemList,
+			StatsSinceTs: statsSinceTs,
+			Statement:    joinIndexItemStatement(unusedItemList),
+		}
+	}
+	s.upsertOrArchiveDatabaseAnomaly(ctx, instance, database, api.AnomalyDatabaseIndexUnused, unusedPayload)
+}
+
+// upsertOrArchiveDatabaseAnomaly upserts the active anomaly of the database with the payload, or archives it if the payload is nil.
+func (s *AnomalyScanner) upsertOrArchiveDatabaseAnomaly(ctx context.Context, instance *api.Instance, database *api.Database, anomalyType api.AnomalyType, anomalyPayload interface{}) {
+	if anomalyPayload == nil {
+		err := s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
+			DatabaseID: &database.ID,
+			Type:       anomalyType,
+		})
+		if err != nil && common.ErrorCode(err) != common.NotFound {
+			s.l.Error("Failed to close anomaly",
+				zap.String("instance", instance.Name),
+				zap.String("database", database.Name),
+				zap.String("type", string(anomalyType)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	payload, err := json.Marshal(anomalyPayload)
+	if err != nil {
+		s.l.Error("Failed to marshal anomaly payload",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(anomalyType)),
+			zap.Error(err))
+		return
+	}
+	if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		DatabaseID: &database.ID,
+		Type:       anomalyType,
+		Payload:    string(payload),
+	}); err != nil {
+		s.l.Error("Failed to create anomaly",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(anomalyType)),
+			zap.Error(err))
+	}
+}
+
+// composeTableIndexMap composes the index rows into indexes ordered by name, keyed by the table ID.
+func composeTableIndexMap(indexList []*api.Index) map[int][]*tableIndex {
+	sorted := make([]*api.Index, len(indexList))
+	copy(sorted, indexList)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].TableID != sorted[j].TableID {
+			return sorted[i].TableID < sorted[j].TableID
+		}
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Position < sorted[j].Position
+	})
+
+	tableIndexMap := make(map[int][]*tableIndex)
+	for _, index := range sorted {
+		list := tableIndexMap[index.TableID]
+		if len(list) == 0 || list[len(list)-1].name != index.Name {
+			list = append(list, &tableIndex{
+				name:      index.Name,
+				indexType: index.Type,
+				unique:    index.Unique,
+				primary:   index.Primary,
+			})
+			tableIndexMap[index.TableID] = list
+		}
+		last := list[len(list)-1]
+		last.columnList = append(last.columnList, index.Expression)
+	}
+	return tableIndexMap
+}
+
+// findRedundantIndexList finds the non-unique indexes whose columns are a left prefix of another index of the same type.
+// For indexes with exactly the same columns, the unique one or the one with the smaller name is kept.
+func findRedundantIndexList(indexList []*tableIndex) []*redundantIndex {
+	var redundantList []*redundantIndex
+	for _, index := range indexList {
+		if index.unique || index.primary {
+			continue
+		}
+		for _, other := range indexList {
+			if other == index || !strings.EqualFold(other.indexType, index.indexType) {
+				continue
+			}
+			if !isLeftPrefix(index.columnList, other.columnList) {
+				continue
+			}
+			sameColumns := len(index.columnList) == len(other.columnList)
+			if sameColumns && !other.unique && !other.primary && other.name > index.name {
+				continue
+			}
+			redundantList = append(redundantList, &redundantIndex{
+				index:         index,
+				coveringIndex: other,
+			})
+			break
+		}
+	}
+	return redundantList
+}
+
+func isLeftPrefix(prefix, columnList []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(columnList) {
+		return false
+	}
+	for i, column := range prefix {
+		if column != columnList[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasPrimaryKey(indexList []*tableIndex) bool {
+	for _, index := range indexList {
+		if index.primary {
+			return true
+		}
+	}
+	return false
+}
+
+// getIndexAnomalyDropStatement returns the statement dropping the index flagged by the index anomalies.
+// The Postgres table and index names are already quoted in the synced metadata.
+func getIndexAnomalyDropStatement(engine db.Type, table, index string) string {
+	if engine == db.Postgres {
+		schema, _ := splitPgTableName(table)
+		return fmt.Sprintf("DROP INDEX %s.%s;", schema, index)
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` DROP INDEX `%s`;", table, index)
+}
+
+// getAddPrimaryKeyStatement returns the statement adding the primary key to the table.
+// It prefers promoting a unique index on NOT NULL columns, then an existing id column, and otherwise adds an auto-increment id column.
+func getAddPrimaryKeyStatement(engine db.Type, table string, indexList []*tableIndex, columnList []*api.Column) string {
+	columnMap := make(map[string]*api.Column)
+	for _, column := range columnList {
+		columnMap[column.Name] = column
+	}
+
+	var keyColumnList []string
+	for _, index := range indexList {
+		if !index.unique {
+			continue
+		}
+		notNull := true
+		for _, name := range index.columnList {
+			if column, ok := columnMap[name]; !ok || column.Nullable {
+				notNull = false
+				break
+			}
+		}
+		if notNull {
+			keyColumnList = index.columnList
+			break
+		}
+	}
+	if keyColumnList == nil {
+		if _, ok := columnMap["id"]; ok {
+			keyColumnList = []string{"id"}
+		}
+	}
+
+	if engine == db.Postgres {
+		if keyColumnList == nil {
+			return fmt.Sprintf("ALTER TABLE %s ADD COLUMN id BIGSERIAL PRIMARY KEY;", table)
+		}
+		var quotedList []string
+		for _, column := range keyColumnList {
+			quotedList = append(quotedList, fmt.Sprintf(`"%s"`, strings.ReplaceAll(column, `"`, `""`)))
+		}
+		return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", table, strings.Join(quotedList, ", "))
+	}
+	if keyColumnList == nil {
+		return fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY FIRST;", table)
+	}
+	var quotedList []string
+	for _, column := range keyColumnList {
+		quotedList = append(quotedList, fmt.Sprintf("`%s`", column))
+	}
+	return fmt.Sprintf("ALTER TABLE `%s` ADD PRIMARY KEY (%s);", table, strings.Join(quotedList, ", "))
+}
+
+func joinIndexItemStatement(itemList []*api.AnomalyIndexItem) string {
+	var statementList []string
+	for _, item := range itemList {
+		statementList = append(statementList, item.Statement)
+	}
+	return strings.Join(statementList, "\n")
+}
+
+// splitPgTableName splits the quoted and schema qualified Postgres table name in the synced metadata into the schema and the table.
+func splitPgTableName(name string) (string, string) {
+	quoted := false
+	for i, c := range name {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == '.' && !quoted:
+			return name[:i], name[i+1:]
+		}
+	}
+	return "", name
+}
+
+// getIndexUsageKey returns the unquoted "table/index" key of the index usage.
+// The Postgres table is schema qualified.
+func getIndexUsageKey(engine db.Type, table, index string) string {
+	if engine == db.Postgres {
+		return fmt.Sprintf("%s/%s", parseDumpTableName(table), parseDumpTableName(index))
+	}
+	return fmt.Sprintf("%s/%s", table, index)
+}
+
+// parseDumpTableName parses the possibly quoted and schema qualified name at the beginning of s,
+// e.g. "`t` (" for MySQL and "public.t (" for Postgres. The quotes are removed.
+func parseDumpTableName(s string) string {
+	var parts []string
+	for {
+		var part string
+		if s != "" && (s[0] == '`' || s[0] == '"') {
+			end := strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				return ""
+			}
+			part, s = s[1:end+1], s[end+2:]
+		} else {
+			end := strings.IndexAny(s, " .(")
+			if end < 0 {
+				end = len(s)
+			}
+			part, s = s[:end], s[end:]
+		}
+		if part == "" {
+			return ""
+		}
+		parts = append(parts, part)
+		if !strings.HasPrefix(s, ".") {
+			break
+		}
+		s = s[1:]
+	}
+	return strings.Join(parts, ".")
+}
+
+// getUnusedIndexSet returns the set of the indexes never used since the engine statistics were reset, keyed by getIndexUsageKey,
+// and the time since when the statistics are collected. It returns a nil set if the engine doesn't provide the index usage.
+func getUnusedIndexSet(ctx context.Context, driver db.Driver, engine db.Type, database string) (map[string]bool, int64, error) {
+	sqlDB, err := driver.GetDbConnection(ctx, database)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sinceQuery, query string
+	var args []interface{}
+	switch engine {
+	case db.MySQL:
+		var enabled bool
+		if err := sqlDB.QueryRowContext(ctx, "SELECT @@performance_schema").Scan(&enabled); err != nil {
+			return nil, 0, err
+		}
+		if !enabled {
+			return nil, 0, nil
+		}
+		sinceQuery = "SELECT CAST(UNIX_TIMESTAMP() - CAST(VARIABLE_VALUE AS UNSIGNED) AS SIGNED) FROM performance_schema.global_status WHERE VARIABLE_NAME = 'Uptime'"
+		query = "" +
+			"SELECT OBJECT_NAME, INDEX_NAME FROM performance_schema.table_io_waits_summary_by_index_usage " +
+			"WHERE OBJECT_SCHEMA = ? AND INDEX_NAME IS NOT NULL AND COUNT_STAR = 0"
+		args = append(args, database)
+	case db.Postgres:
+		sinceQuery = "" +
+			"SELECT EXTRACT(EPOCH FROM COALESCE(" +
+			"(SELECT stats_reset FROM pg_catalog.pg_stat_database WHERE datname = current_database()), " +
+			"pg_postmaster_start_time()))::BIGINT"
+		query = "SELECT schemaname || '.' || relname, indexrelname FROM pg_catalog.pg_stat_user_indexes WHERE idx_scan = 0"
+	default:
+		return nil, 0, nil
+	}
+
+	var statsSinceTs int64
+	if err := sqlDB.QueryRowContext(ctx, sinceQuery).Scan(&statsSinceTs); err != nil {
+		return nil, 0, err
+	}
+	rows, err := sqlDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	unusedIndexSet := make(map[string]bool)
+	for rows.Next() {
+		var table, index string
+		if err := rows.Scan(&table, &index); err != nil {
+			return nil, 0, err
+		}
+		unusedIndexSet[fmt.Sprintf("%s/%s", table, index)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return unusedIndexSet, statsSinceTs, nil
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestFindRedundantIndexList(t *testing.T) {
+	tests := []struct {
+		name      string
+		indexList []*api.Index
+		// index name -> covering index name
+		want map[string]string
+	}{
+		{
+			name: "left prefix of primary key and composite index",
+			indexList: []*api.Index{
+				{TableID: 1, Name: "PRIMARY", Expression: "a", Position: 1, Type: "BTREE", Unique: true, Primary: true},
+				{TableID: 1, Name: "PRIMARY", Expression: "b", Position: 2, Type: "BTREE", Unique: true, Primary: true},
+				{TableID: 1, Name: "idx_a", Expression: "a", Position: 1, Type: "BTREE"},
+				{TableID: 1, Name: "idx_c_d", Expression: "d", Position: 2, Type: "BTREE"},
+				{TableID: 1, Name: "idx_c_d", Expression: "c", Position: 1, Type: "BTREE"},
+				{TableID: 1, Name: "idx_c", Expression: "c", Position: 1, Type: "BTREE"},
+				{TableID: 1, Name: "idx_d", Expression: "d", Position: 1, Type: "BTREE"},
+			},
+			want: map[string]string{"idx_a": "PRIMARY", "idx_c": "idx_c_d"},
+		},
+		{
+			name: "duplicate indexes",
+			indexList: []*api.Index{
+				{TableID: 1, Name: "idx_b", Expression: "a", Position: 1, Type: "btree"},
+				{TableID: 1, Name: "idx_a", Expression: "a", Position: 1, Type: "btree"},
+				{TableID: 1, Name: "uk_a", Expression: "a", Position: 1, Type: "btree", Unique: true},
+			},
+			want: map[string]string{"idx_a": "uk_a", "idx_b": "idx_a"},
+		},
+		{
+			name: "different type and table",
+			indexList: []*api.Index{
+				{TableID: 1, Name: "ft_a", Expression: "a", Position: 1, Type: "FULLTEXT"},
+				{TableID: 1, Name: "idx_a_b", Expression: "a", Position: 1, Type: "BTREE"},
+				{TableID: 1, Name: "idx_a_b", Expression: "b", Position: 2, Type: "BTREE"},
+				{TableID: 2, Name: "idx_a", Expression: "a", Position: 1, Type: "BTREE"},
+				{TableID: 1, Name: "uk_a", Expression: "a", Position: 1, Type: "BTREE", Unique: true},
+			},
+			want: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		got := make(map[string]string)
+		for _, indexList := range composeTableIndexMap(test.indexList) {
+			for _, redundant := range findRedundantIndexList(indexList) {
+				got[redundant.index.name] = redundant.coveringIndex.name
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetAddPrimaryKeyStatement(t *testing.T) {
+	tests := []struct {
+		name       string
+		engine     db.Type
+		table      string
+		indexList  []*api.Index
+		columnList []*api.Column
+		want       string
+	}{
+		{
+			name:   "MySQL unique index on NOT NULL columns",
+			engine: db.MySQL,
+			table:  "t",
+			indexList: []*api.Index{
+				{TableID: 1, Name: "uk_nullable", Expression: "c", Position: 1, Unique: true},
+				{TableID: 1, Name: "uk_a_b", Expression: "b", Position: 2, Unique: true},
+				{TableID: 1, Name: "uk_a_b", Expression: "a", Position: 1, Unique: true},
+			},
+			columnList: []*api.Column{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c", Nullable: true},
+				{Name: "id"},
+			},
+			want: "ALTER TABLE `t` ADD PRIMARY KEY (`a`, `b`);",
+		},
+		{
+			name:       "MySQL id column",
+			engine:     db.MySQL,
+			table:      "t",
+			columnList: []*api.Column{{Name: "id"}},
+			want:       "ALTER TABLE `t` ADD PRIMARY KEY (`id`);",
+		},
+		{
+			name:       "MySQL new id column",
+			engine:     db.MySQL,
+			table:      "t",
+			columnList: []*api.Column{{Name: "a"}},
+			want:       "ALTER TABLE `t` ADD COLUMN `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY FIRST;",
+		},
+		{
+			name:   "Postgres unique index",
+			engine: db.Postgres,
+			table:  `public."T"`,
+			indexList: []*api.Index{
+				{TableID: 1, Name: "uk_a", Expression: "a", Position: 1, Unique: true},
+			},
+			columnList: []*api.Column{{Name: "a"}},
+			want:       `ALTER TABLE public."T" ADD PRIMARY KEY ("a");`,
+		},
+		{
+			name:       "Postgres new id column",
+			engine:     db.Postgres,
+			table:      "public.t",
+			columnList: []*api.Column{{Name: "a"}},
+			want:       "ALTER TABLE public.t ADD COLUMN id BIGSERIAL PRIMARY KEY;",
+		},
+	}
+
+	for _, test := range tests {
+		got := getAddPrimaryKeyStatement(test.engine, test.table, composeTableIndexMap(test.indexList)[1], test.columnList)
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetIndexAnomalyDropStatement(t *testing.T) {
+	tests := []struct {
+		engine   db.Type
+		table    string
+		index    string
+		want     string
+		usageKey string
+	}{
+		{
+			engine:   db.MySQL,
+			table:    "t",
+			index:    "idx_a",
+			want:     "ALTER TABLE `t` DROP INDEX `idx_a`;",
+			usageKey: "t/idx_a",
+		},
+		{
+			engine:   db.Postgres,
+			table:    "public.t",
+			index:    "idx_a",
+			want:     "DROP INDEX public.idx_a;",
+			usageKey: "public.t/idx_a",
+		},
+		{
+			engine:   db.Postgres,
+			table:    `"my.schema"."T"`,
+			index:    `"Idx"`,
+			want:     `DROP INDEX "my.schema"."Idx";`,
+			usageKey: "my.schema.T/Idx",
+		},
+	}
+
+	for _, test := range tests {
+		if got := getIndexAnomalyDropStatement(test.engine, test.table, test.index); got != test.want {
+			t.Errorf("getIndexAnomalyDropStatement(%s, %q, %q) = %q, want %q", test.engine, test.table, test.index, got, test.want)
+		}
+		if got := getIndexUsageKey(test.engine, test.table, test.index); got != test.usageKey {
+			t.Errorf("getIndexUsageKey(%s, %q, %q) = %q, want %q", test.engine, test.table, test.index, got, test.usageKey)
+		}
+	}
+}
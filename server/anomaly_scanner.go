@@ -22,8 +22,9 @@ const (
 // NewAnomalyScanner creates a anomaly scanner
 func NewAnomalyScanner(logger *zap.Logger, server *Server) *AnomalyScanner {
 	return &AnomalyScanner{
-		l:      logger,
-		server: server,
+		l:                logger,
+		server:           server,
+		storageSampleMap: make(map[int]map[string][]storageSample),
 	}
 }
 
@@ -31,6 +32,11 @@ func NewAnomalyScanner(logger *zap.Logger, server *Server) *AnomalyScanner {
 type AnomalyScanner struct {
 	l      *zap.Logger
 	server *Server
+
+	storageSampleMu sync.Mutex
+	// storageSampleMap is the size samples of the databases and tablespaces keyed by the instance ID,
+	// which are used to measure the storage growth.
+	storageSampleMap map[int]map[string][]storageSample
 }
 
 // Run will run the anomaly scanner once.
@@ -90,6 +96,18 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 					backupPlanPolicyMap[env.ID] = policy
 				}
 
+				instanceHealthPolicyMap := make(map[int]*api.InstanceHealthPolicy)
+				for _, env := range envList {
+					policy, err := s.server.PolicyService.GetInstanceHealthPolicy(ctx, env.ID)
+					if err != nil {
+						s.l.Error("Failed to retrieve instance health policy",
+							zap.String("environment", env.Name),
+							zap.Error(err))
+						return
+					}
+					instanceHealthPolicyMap[env.ID] = policy
+				}
+
 				rowStatus := api.Normal
 				instanceFind := &api.InstanceFind{
 					RowStatus: &rowStatus,
@@ -139,7 +157,7 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 							mu.Unlock()
 						}()
 
-						s.checkInstanceAnomaly(ctx, instance)
+						s.checkInstanceAnomaly(ctx, instance, instanceHealthPolicyMap[instance.EnvironmentID])
 
 						databaseFind := &api.DatabaseFind{
 							InstanceID: &instance.ID,
@@ -169,7 +187,7 @@ func (s *AnomalyScanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
-func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instance) {
+func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instance, instanceHealthPolicy *api.InstanceHealthPolicy) {
 	driver, err := getAdminDatabaseDriver(ctx, instance, "", s.l)
 
 	// Check connection
@@ -184,7 +202,7 @@ func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api
 				zap.String("type", string(api.AnomalyInstanceConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.server.AnomalyService.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.ID,
 				Type:       api.AnomalyInstanceConnection,
@@ -200,7 +218,7 @@ func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api
 	}
 
 	defer driver.Close(ctx)
-	err = s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+	err = s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
 		InstanceID: &instance.ID,
 		Type:       api.AnomalyInstanceConnection,
 	})
@@ -221,7 +239,7 @@ func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api
 				zap.Error(err))
 		} else {
 			if setup {
-				if _, err = s.server.AnomalyService.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					Type:       api.AnomalyInstanceMigrationSchema,
@@ -232,7 +250,7 @@ func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api
 						zap.Error(err))
 				}
 			} else {
-				err := s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+				err := s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
 					InstanceID: &instance.ID,
 					Type:       api.AnomalyInstanceMigrationSchema,
 				})
@@ -245,6 +263,9 @@ func (s *AnomalyScanner) checkInstanceAnomaly(ctx context.Context, instance *api
 			}
 		}
 	}
+
+	// Check replication lag, connection usage, long-running transactions and storage growth
+	s.checkInstanceHealthAnomaly(ctx, instance, driver, instanceHealthPolicy)
 }
 
 func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api.Instance, database *api.Database) {
@@ -263,7 +284,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 				zap.String("type", string(api.AnomalyDatabaseConnection)),
 				zap.Error(err))
 		} else {
-			if _, err = s.server.AnomalyService.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 				CreatorID:  api.SystemBotID,
 				InstanceID: instance.ID,
 				DatabaseID: &database.ID,
@@ -280,7 +301,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 		return
 	}
 	defer driver.Close(ctx)
-	err = s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+	err = s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
 		DatabaseID: &database.ID,
 		Type:       api.AnomalyDatabaseConnection,
 	})
@@ -292,6 +313,9 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 			zap.Error(err))
 	}
 
+	// Check redundant indexes, unused indexes and tables without a primary key
+	s.checkIndexAnomaly(ctx, instance, database, driver)
+
 	// Check schema drift
 	if s.server.feature(api.FeatureSchemaDrift) {
 		setup, err := driver.NeedsSetupMigration(ctx)
@@ -338,11 +362,30 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 			goto SchemaDriftEnd
 		}
 		if len(list) > 0 {
-			if list[0].Schema != schemaBuf.String() {
+			filter, err := s.server.getSchemaDriftFilter(ctx, database)
+			if err != nil {
+				s.l.Error("Failed to check anomaly",
+					zap.String("instance", instance.Name),
+					zap.String("database", database.Name),
+					zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
+					zap.Error(err))
+				goto SchemaDriftEnd
+			}
+			expect, actual := filter.apply(list[0].Schema), filter.apply(schemaBuf.String())
+			if expect != actual {
+				diff, err := getSchemaDriftUnifiedDiff(expect, actual)
+				if err != nil {
+					s.l.Error("Failed to diff schema",
+						zap.String("instance", instance.Name),
+						zap.String("database", database.Name),
+						zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
+						zap.Error(err))
+				}
+				// The schemas are not stored, since they're fetched on demand from the migration history and the database.
 				anomalyPayload := api.AnomalyDatabaseSchemaDriftPayload{
-					Version: list[0].Version,
-					Expect:  list[0].Schema,
-					Actual:  schemaBuf.String(),
+					Version:  list[0].Version,
+					ItemList: diffSchema(expect, actual),
+					Diff:     diff,
 				}
 				payload, err := json.Marshal(anomalyPayload)
 				if err != nil {
@@ -352,7 +395,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 						zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 						zap.Error(err))
 				} else {
-					if _, err = s.server.AnomalyService.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+					if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 						CreatorID:  api.SystemBotID,
 						InstanceID: instance.ID,
 						DatabaseID: &database.ID,
@@ -367,9 +410,9 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 					}
 				}
 			} else {
-				err := s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+				err := s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
 					DatabaseID: &database.ID,
-					Type:       api.AnomalyDatabaseConnection,
+					Type:       api.AnomalyDatabaseSchemaDrift,
 				})
 				if err != nil && common.ErrorCode(err) != common.NotFound {
 					s.l.Error("Failed to close anomaly",
@@ -436,7 +479,7 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 					zap.String("type", string(api.AnomalyDatabaseBackupPolicyViolation)),
 					zap.Error(err))
 			} else {
-				if _, err = s.server.AnomalyService.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					DatabaseID: &database.ID,
@@ -451,7 +494,7 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 				}
 			}
 		} else {
-			err := s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			err := s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
 				DatabaseID: &database.ID,
 				Type:       api.AnomalyDatabaseBackupPolicyViolation,
 			})
@@ -519,7 +562,7 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 					zap.String("type", string(api.AnomalyDatabaseBackupMissing)),
 					zap.Error(err))
 			} else {
-				if _, err = s.server.AnomalyService.UpsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+				if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
 					CreatorID:  api.SystemBotID,
 					InstanceID: instance.ID,
 					DatabaseID: &database.ID,
@@ -534,7 +577,7 @@ func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.I
 				}
 			}
 		} else {
-			err := s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
+			err := s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
 				DatabaseID: &database.ID,
 				Type:       api.AnomalyDatabaseBackupMissing,
 			})
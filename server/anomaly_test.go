@@ -0,0 +1,28 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestSummarizeAnomalyList(t *testing.T) {
+	anomalyList := []*api.AnomalyRaw{
+		{CreatedTs: 1000, Status: api.AnomalyOpen},
+		{CreatedTs: 1000, Status: api.AnomalySnoozed, SnoozeUntilTs: 5000},
+		{CreatedTs: 1000, Status: api.AnomalyResolved, ResolvedTs: 1600},
+		{CreatedTs: 2000, Status: api.AnomalyResolved, ResolvedTs: 3000},
+	}
+	want := &api.AnomalySummary{
+		OpenCount:                2,
+		ResolvedCount:            2,
+		MeanTimeToResolveSeconds: 800,
+	}
+	if got := summarizeAnomalyList(anomalyList); !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizeAnomalyList() = %+v, want %+v", got, want)
+	}
+	if got := summarizeAnomalyList(nil); !reflect.DeepEqual(got, &api.AnomalySummary{}) {
+		t.Errorf("summarizeAnomalyList(nil) = %+v, want empty summary", got)
+	}
+}
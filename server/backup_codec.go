@@ -0,0 +1,286 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The encrypted backup is a sequence of AES-GCM sealed chunks, so that it can be streamed without
+// holding the whole backup in memory:
+//
+//	magic (4 bytes) | nonce prefix (8 bytes) | chunk...
+//	chunk: sealed length (4 bytes, big endian) | sealed chunk
+//
+// The nonce of each chunk is the nonce prefix followed by the 4-byte big endian chunk sequence number,
+// and the additional data marks whether the chunk is the final one, so that reordered, duplicated or
+// truncated chunks fail the authentication.
+const (
+	backupEncryptionMagic       = "BBE1"
+	backupEncryptionNoncePrefix = 8
+	backupEncryptionChunkSize   = 64 * 1024
+)
+
+var (
+	backupEncryptionChunkAD = []byte{0}
+	backupEncryptionFinalAD = []byte{1}
+)
+
+// newBackupEncoder returns a writer compressing and then encrypting the content written to w.
+// The caller must close the returned writer to flush the content, which doesn't close w.
+func newBackupEncoder(w io.Writer, compression api.BackupCompression, encryption api.BackupEncryption, key []byte) (io.WriteCloser, error) {
+	var closers []io.Closer
+
+	switch encryption {
+	case api.BackupEncryptionNone:
+	case api.BackupEncryptionAES256GCM:
+		ew, err := newBackupEncryptWriter(w, key)
+		if err != nil {
+			return nil, err
+		}
+		w = ew
+		closers = append(closers, ew)
+	default:
+		return nil, fmt.Errorf("unsupported backup encryption %q", encryption)
+	}
+
+	switch compression {
+	case api.BackupCompressionNone:
+	case api.BackupCompressionGzip:
+		gw := gzip.NewWriter(w)
+		w = gw
+		closers = append(closers, gw)
+	case api.BackupCompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		w = zw
+		closers = append(closers, zw)
+	default:
+		return nil, fmt.Errorf("unsupported backup compression %q", compression)
+	}
+
+	// Close from the outermost writer so that each layer flushes into the next one.
+	for i, j := 0, len(closers)-1; i < j; i, j = i+1, j-1 {
+		closers[i], closers[j] = closers[j], closers[i]
+	}
+	return &backupEncoder{Writer: w, closers: closers}, nil
+}
+
+// newBackupDecoder returns a reader decrypting and then decompressing the content read from r.
+// Closing the returned reader doesn't close r.
+func newBackupDecoder(r io.Reader, compression api.BackupCompression, encryption api.BackupEncryption, key []byte) (io.ReadCloser, error) {
+	switch encryption {
+	case api.BackupEncryptionNone:
+	case api.BackupEncryptionAES256GCM:
+		dr, err := newBackupDecryptReader(r, key)
+		if err != nil {
+			return nil, err
+		}
+		r = dr
+	default:
+		return nil, fmt.Errorf("unsupported backup encryption %q", encryption)
+	}
+
+	switch compression {
+	case api.BackupCompressionNone:
+		return io.NopCloser(r), nil
+	case api.BackupCompressionGzip:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return gr, nil
+	case api.BackupCompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return nil, fmt.Errorf("unsupported backup compression %q", compression)
+}
+
+type backupEncoder struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (e *backupEncoder) Close() error {
+	for _, c := range e.closers {
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newBackupAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("backup encryption key must be 32 bytes, got %d bytes", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func backupChunkNonce(prefix []byte, seq uint32) []byte {
+	nonce := make([]byte, backupEncryptionNoncePrefix+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[backupEncryptionNoncePrefix:], seq)
+	return nonce
+}
+
+type backupEncryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	seq    uint32
+	buf    []byte
+}
+
+func newBackupEncryptWriter(w io.Writer, key []byte) (*backupEncryptWriter, error) {
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, backupEncryptionNoncePrefix)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	if _, err := w.Write(append([]byte(backupEncryptionMagic), prefix...)); err != nil {
+		return nil, err
+	}
+	return &backupEncryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: prefix,
+		buf:    make([]byte, 0, backupEncryptionChunkSize),
+	}, nil
+}
+
+func (e *backupEncryptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// Only seal a full chunk when there is more to write, the last chunk is sealed on Close as the final one.
+		if len(e.buf) == backupEncryptionChunkSize {
+			if err := e.seal(backupEncryptionChunkAD); err != nil {
+				return 0, err
+			}
+		}
+		m := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+m]
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func (e *backupEncryptWriter) Close() error {
+	return e.seal(backupEncryptionFinalAD)
+}
+
+func (e *backupEncryptWriter) seal(ad []byte) error {
+	if e.seq == ^uint32(0) {
+		return fmt.Errorf("backup is too large to encrypt")
+	}
+	sealed := e.aead.Seal(nil, backupChunkNonce(e.prefix, e.seq), e.buf, ad)
+	e.seq++
+	e.buf = e.buf[:0]
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+type backupDecryptReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	seq    uint32
+	buf    []byte
+	final  bool
+}
+
+func newBackupDecryptReader(r io.Reader, key []byte) (*backupDecryptReader, error) {
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(backupEncryptionMagic)+backupEncryptionNoncePrefix)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read backup encryption header: %w", err)
+	}
+	if string(header[:len(backupEncryptionMagic)]) != backupEncryptionMagic {
+		return nil, fmt.Errorf("invalid backup encryption header")
+	}
+	return &backupDecryptReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		prefix: header[len(backupEncryptionMagic):],
+	}, nil
+}
+
+func (d *backupDecryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.final {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *backupDecryptReader) open() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("backup is truncated")
+		}
+		return err
+	}
+	sealedLength := int(binary.BigEndian.Uint32(length[:]))
+	if sealedLength > backupEncryptionChunkSize+d.aead.Overhead() {
+		return fmt.Errorf("invalid backup chunk length %d", sealedLength)
+	}
+	sealed := make([]byte, sealedLength)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return fmt.Errorf("failed to read backup chunk: %w", err)
+	}
+
+	nonce := backupChunkNonce(d.prefix, d.seq)
+	d.seq++
+	// Try the regular chunk first, and the final chunk otherwise.
+	if plain, err := d.aead.Open(nil, nonce, sealed, backupEncryptionChunkAD); err == nil {
+		d.buf = plain
+		return nil
+	}
+	plain, err := d.aead.Open(nil, nonce, sealed, backupEncryptionFinalAD)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt backup, the key may be wrong or the backup may be corrupted: %w", err)
+	}
+	if _, err := d.r.Peek(1); err != io.EOF {
+		return fmt.Errorf("unexpected data after the final backup chunk")
+	}
+	d.buf = plain
+	d.final = true
+	return nil
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func encodeBackup(t *testing.T, content string, compression api.BackupCompression, encryption api.BackupEncryption, key []byte) []byte {
+	var buf bytes.Buffer
+	encoder, err := newBackupEncoder(&buf, compression, encryption, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(encoder, content); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeBackup(stored []byte, compression api.BackupCompression, encryption api.BackupEncryption, key []byte) (string, error) {
+	decoder, err := newBackupDecoder(bytes.NewReader(stored), compression, encryption, key)
+	if err != nil {
+		return "", err
+	}
+	defer decoder.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, decoder); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func TestBackupCodecRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	contents := []string{
+		"",
+		"INSERT INTO t VALUES (1);\n",
+		// Exactly one chunk and more than one chunk.
+		strings.Repeat("a", backupEncryptionChunkSize),
+		strings.Repeat("INSERT INTO t VALUES (1);\n", backupEncryptionChunkSize/10),
+	}
+
+	for _, compression := range []api.BackupCompression{api.BackupCompressionNone, api.BackupCompressionGzip, api.BackupCompressionZstd} {
+		for _, encryption := range []api.BackupEncryption{api.BackupEncryptionNone, api.BackupEncryptionAES256GCM} {
+			for _, content := range contents {
+				stored := encodeBackup(t, content, compression, encryption, key)
+				got, err := decodeBackup(stored, compression, encryption, key)
+				if err != nil {
+					t.Fatalf("%s/%s: failed to decode %d bytes: %v", compression, encryption, len(content), err)
+				}
+				if got != content {
+					t.Errorf("%s/%s: decoded %d bytes, want %d bytes", compression, encryption, len(got), len(content))
+				}
+			}
+		}
+	}
+}
+
+func TestBackupDecryptFailure(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	wrongKey := make([]byte, 32)
+	content := strings.Repeat("INSERT INTO t VALUES (1);\n", backupEncryptionChunkSize/10)
+	stored := encodeBackup(t, content, api.BackupCompressionNone, api.BackupEncryptionAES256GCM, key)
+
+	tests := []struct {
+		name   string
+		stored []byte
+		key    []byte
+	}{
+		{
+			name:   "wrong key",
+			stored: stored,
+			key:    wrongKey,
+		},
+		{
+			name:   "truncated at chunk boundary",
+			stored: stored[:len(backupEncryptionMagic)+backupEncryptionNoncePrefix+4+backupEncryptionChunkSize+16],
+			key:    key,
+		},
+		{
+			name:   "truncated in chunk",
+			stored: stored[:len(stored)-1],
+			key:    key,
+		},
+		{
+			name:   "trailing data",
+			stored: append(append([]byte{}, stored...), 0),
+			key:    key,
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := decodeBackup(test.stored, api.BackupCompressionNone, api.BackupEncryptionAES256GCM, test.key); err == nil {
+			t.Errorf("%s: expect error, got nil", test.name)
+		}
+	}
+}
+
+func TestLoadBackupEncryptionKey(t *testing.T) {
+	current := bytes.Repeat([]byte{1}, 32)
+	previous := bytes.Repeat([]byte{2}, 32)
+	setting := &api.BackupStorageSetting{
+		Encryption:                api.BackupEncryptionAES256GCM,
+		EncryptionKey:             base64.StdEncoding.EncodeToString(current),
+		PreviousEncryptionKeyList: []string{base64.StdEncoding.EncodeToString(previous)},
+	}
+	key, previousList, err := loadBackupEncryptionKey(setting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, current) || len(previousList) != 1 || !bytes.Equal(previousList[0], previous) {
+		t.Errorf("loadBackupEncryptionKey() = %v, %v, want %v, [%v]", key, previousList, current, previous)
+	}
+
+	fingerprint, err := getBackupEncryptionKeyFingerprint(setting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := api.GetBackupEncryptionKeyFingerprint(current); fingerprint != want {
+		t.Errorf("fingerprint = %s, want %s", fingerprint, want)
+	}
+	if fingerprint == api.GetBackupEncryptionKeyFingerprint(previous) {
+		t.Errorf("expect different fingerprints of different keys")
+	}
+
+	setting.PreviousEncryptionKeyList = []string{"invalid base64!"}
+	if _, _, err := loadBackupEncryptionKey(setting); err == nil {
+		t.Errorf("expect error for invalid previous key")
+	}
+	if fingerprint, err := getBackupEncryptionKeyFingerprint(&api.BackupStorageSetting{Encryption: api.BackupEncryptionNone}); err != nil || fingerprint != "" {
+		t.Errorf("expect empty fingerprint without encryption, got %q, %v", fingerprint, err)
+	}
+}
+
+func TestBackupReaderChecksum(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Repeat("INSERT INTO t VALUES (1);\n", 1000)
+	stored := encodeBackup(t, content, api.BackupCompressionGzip, api.BackupEncryptionAES256GCM, key)
+	hash := sha256.Sum256(stored)
+
+	read := func(backup *api.Backup) (string, error) {
+		checksum := &checksumReader{r: bytes.NewReader(stored), hash: sha256.New(), backup: backup}
+		decoder, err := newBackupDecoder(checksum, api.BackupCompressionGzip, api.BackupEncryptionAES256GCM, key)
+		if err != nil {
+			return "", err
+		}
+		r := &backupReader{Reader: decoder, checksum: checksum, close: decoder.Close}
+		defer r.Close()
+		buf, err := io.ReadAll(r)
+		return string(buf), err
+	}
+
+	got, err := read(&api.Backup{Name: "b", Size: int64(len(stored)), Checksum: hex.EncodeToString(hash[:])})
+	if err != nil {
+		t.Fatalf("read with the matching checksum got error: %v", err)
+	}
+	if got != content {
+		t.Errorf("read got %d bytes, want %d bytes", len(got), len(content))
+	}
+	// The mismatch is only detected at the end of the stream, and returned instead of io.EOF.
+	if _, err := read(&api.Backup{Name: "b", Size: int64(len(stored)), Checksum: strings.Repeat("0", 64)}); err == nil {
+		t.Errorf("read with the mismatched checksum got nil error, want error")
+	}
+	if _, err := read(&api.Backup{Name: "b", Size: int64(len(stored)) + 1, Checksum: hex.EncodeToString(hash[:])}); err == nil {
+		t.Errorf("read with the mismatched size got nil error, want error")
+	}
+}
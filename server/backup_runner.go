@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,6 +14,11 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// backupCleanupInterval is the interval to purge the expired automatic backups.
+	backupCleanupInterval = time.Duration(1) * time.Hour
+)
+
 // NewBackupRunner creates a new backup runner.
 func NewBackupRunner(logger *zap.Logger, server *Server, backupRunnerInterval time.Duration) *BackupRunner {
 	return &BackupRunner{
@@ -37,6 +43,9 @@ func (s *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	s.l.Debug("Auto backup runner started", zap.Duration("interval", s.backupRunnerInterval))
 	runningTasks := make(map[int]bool)
 	var mu sync.RWMutex
+	var lastCleanupTime time.Time
+	var lastVerificationTime time.Time
+	verifying := false
 	for {
 		select {
 		case <-ticker.C:
@@ -132,6 +141,35 @@ func (s *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 						}
 					}(db, backupSetting.ID, backupName, backupSetting.HookURL)
 				}
+
+				if time.Since(lastCleanupTime) >= backupCleanupInterval {
+					lastCleanupTime = time.Now()
+					s.purgeExpiredBackups(ctx)
+				}
+
+				// The verification restores backups and may take a long time, so it runs in the background
+				// and a new round is not started until the previous one finishes.
+				mu.Lock()
+				if !verifying && time.Since(lastVerificationTime) >= backupVerificationInterval {
+					verifying = true
+					lastVerificationTime = time.Now()
+					go func() {
+						defer func() {
+							if r := recover(); r != nil {
+								err, ok := r.(error)
+								if !ok {
+									err = fmt.Errorf("%v", r)
+								}
+								s.l.Error("Backup verification PANIC RECOVER", zap.Error(err), zap.Stack("stack"))
+							}
+							mu.Lock()
+							verifying = false
+							mu.Unlock()
+						}()
+						s.verifyBackups(ctx)
+					}()
+				}
+				mu.Unlock()
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			return
@@ -140,11 +178,6 @@ func (s *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 }
 
 func (s *BackupRunner) scheduleBackupTask(ctx context.Context, database *api.Database, backupName string) error {
-	path, err := getAndCreateBackupPath(s.server.dataDir, database, backupName)
-	if err != nil {
-		return err
-	}
-
 	// Store the migration history version if exists.
 	driver, err := getAdminDatabaseDriver(ctx, database.Instance, database.Name, s.l)
 	if err != nil {
@@ -171,8 +204,9 @@ func (s *BackupRunner) scheduleBackupTask(ctx context.Context, database *api.Dat
 		Name:                    backupName,
 		Type:                    api.BackupTypeAutomatic,
 		MigrationHistoryVersion: migrationHistoryVersion,
-		StorageBackend:          api.BackupStorageBackendLocal,
-		Path:                    path,
+	}
+	if err := s.server.prepareBackupCreate(ctx, database, backupCreate); err != nil {
+		return err
 	}
 	backupRawNew, err := s.server.BackupService.CreateBackup(ctx, backupCreate)
 	if err != nil {
@@ -225,3 +259,182 @@ func (s *BackupRunner) scheduleBackupTask(ctx context.Context, database *api.Dat
 	}
 	return nil
 }
+
+// purgeExpiredBackups deletes the automatic backup files which are expired according to the backup plan policy
+// of the environment, and marks the corresponding backups as EXPIRED.
+func (s *BackupRunner) purgeExpiredBackups(ctx context.Context) {
+	rowStatus := api.Normal
+	instanceRawList, err := s.server.InstanceService.FindInstanceList(ctx, &api.InstanceFind{RowStatus: &rowStatus})
+	if err != nil {
+		s.l.Error("Failed to retrieve instance list", zap.Error(err))
+		return
+	}
+
+	policyMap := make(map[int]*api.BackupPlanPolicy)
+	for _, instanceRaw := range instanceRawList {
+		policy, ok := policyMap[instanceRaw.EnvironmentID]
+		if !ok {
+			policy, err = s.server.PolicyService.GetBackupPlanPolicy(ctx, instanceRaw.EnvironmentID)
+			if err != nil {
+				s.l.Error("Failed to retrieve backup policy",
+					zap.Int("environmentID", instanceRaw.EnvironmentID),
+					zap.Error(err))
+				continue
+			}
+			policyMap[instanceRaw.EnvironmentID] = policy
+		}
+		if !policy.HasRetention() {
+			continue
+		}
+
+		dbRawList, err := s.server.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{InstanceID: &instanceRaw.ID})
+		if err != nil {
+			s.l.Error("Failed to retrieve database list",
+				zap.String("instance", instanceRaw.Name),
+				zap.Error(err))
+			continue
+		}
+		for _, dbRaw := range dbRawList {
+			s.purgeExpiredBackupsForDatabase(ctx, instanceRaw.ID, dbRaw.ToDatabase(), policy)
+		}
+	}
+}
+
+func (s *BackupRunner) purgeExpiredBackupsForDatabase(ctx context.Context, instanceID int, database *api.Database, policy *api.BackupPlanPolicy) {
+	status := api.BackupStatusDone
+	backupType := api.BackupTypeAutomatic
+	backupRawList, err := s.server.BackupService.FindBackupList(ctx, &api.BackupFind{
+		DatabaseID: &database.ID,
+		Status:     &status,
+		Type:       &backupType,
+	})
+	if err != nil {
+		s.l.Error("Failed to retrieve backup list",
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+
+	var cleanupErr error
+	var failedBackup *api.BackupRaw
+	for _, backupRaw := range getExpiredBackupList(backupRawList, policy, time.Now()) {
+		if err := s.purgeBackup(ctx, backupRaw); err != nil {
+			s.l.Error("Failed to purge expired backup",
+				zap.String("database", database.Name),
+				zap.String("backup", backupRaw.Name),
+				zap.Error(err))
+			cleanupErr = err
+			failedBackup = backupRaw
+			continue
+		}
+		s.l.Debug("Purged expired backup",
+			zap.String("database", database.Name),
+			zap.String("backup", backupRaw.Name),
+		)
+	}
+
+	if cleanupErr != nil {
+		anomalyPayload := api.AnomalyDatabaseBackupCleanupFailurePayload{
+			BackupID:   failedBackup.ID,
+			BackupName: failedBackup.Name,
+			Detail:     cleanupErr.Error(),
+		}
+		payload, err := json.Marshal(anomalyPayload)
+		if err != nil {
+			s.l.Error("Failed to marshal anomaly payload",
+				zap.String("database", database.Name),
+				zap.String("type", string(api.AnomalyDatabaseBackupCleanupFailure)),
+				zap.Error(err))
+			return
+		}
+		if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			CreatorID:  api.SystemBotID,
+			InstanceID: instanceID,
+			DatabaseID: &database.ID,
+			Type:       api.AnomalyDatabaseBackupCleanupFailure,
+			Payload:    string(payload),
+		}); err != nil {
+			s.l.Error("Failed to create anomaly",
+				zap.String("database", database.Name),
+				zap.String("type", string(api.AnomalyDatabaseBackupCleanupFailure)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	err = s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
+		DatabaseID: &database.ID,
+		Type:       api.AnomalyDatabaseBackupCleanupFailure,
+	})
+	if err != nil && common.ErrorCode(err) != common.NotFound {
+		s.l.Error("Failed to close anomaly",
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseBackupCleanupFailure)),
+			zap.Error(err))
+	}
+}
+
+// purgeBackup removes the backup data from its storage and marks the backup as EXPIRED.
+func (s *BackupRunner) purgeBackup(ctx context.Context, backupRaw *api.BackupRaw) error {
+	if err := s.server.removeBackup(ctx, backupRaw.ToBackup()); err != nil {
+		return err
+	}
+
+	if _, err := s.server.BackupService.PatchBackup(ctx, &api.BackupPatch{
+		ID:        backupRaw.ID,
+		Status:    string(api.BackupStatusExpired),
+		UpdaterID: api.SystemBotID,
+		Comment:   fmt.Sprintf("Expired by the backup retention policy at %s.", time.Now().UTC().Format(time.RFC3339)),
+	}); err != nil {
+		return fmt.Errorf("failed to patch backup %q: %w", backupRaw.Name, err)
+	}
+	return nil
+}
+
+// getExpiredBackupList returns the backups expired according to the retention of the backup plan policy.
+// A backup is retained if any of the enabled retention rules keeps it, and the latest backup is always retained
+// so that the database never loses its last good backup.
+func getExpiredBackupList(backupList []*api.BackupRaw, policy *api.BackupPlanPolicy, now time.Time) []*api.BackupRaw {
+	if !policy.HasRetention() || len(backupList) == 0 {
+		return nil
+	}
+
+	sorted := make([]*api.BackupRaw, len(backupList))
+	copy(sorted, backupList)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedTs > sorted[j].CreatedTs
+	})
+
+	keepWeek := make(map[string]bool)
+	keepMonth := make(map[string]bool)
+	var expiredList []*api.BackupRaw
+	for i, backup := range sorted {
+		keep := i == 0
+		if policy.RetentionCount > 0 && i < policy.RetentionCount {
+			keep = true
+		}
+		if policy.RetentionDays > 0 && backup.CreatedTs >= now.AddDate(0, 0, -policy.RetentionDays).Unix() {
+			keep = true
+		}
+		createdTime := time.Unix(backup.CreatedTs, 0).UTC()
+		if policy.KeepWeekly {
+			year, week := createdTime.ISOWeek()
+			key := fmt.Sprintf("%d-%d", year, week)
+			if !keepWeek[key] {
+				keepWeek[key] = true
+				keep = true
+			}
+		}
+		if policy.KeepMonthly {
+			key := createdTime.Format("2006-01")
+			if !keepMonth[key] {
+				keepMonth[key] = true
+				keep = true
+			}
+		}
+		if !keep {
+			expiredList = append(expiredList, backup)
+		}
+	}
+	return expiredList
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetExpiredBackupList(t *testing.T) {
+	now := time.Date(2022, 3, 31, 12, 0, 0, 0, time.UTC)
+	day := int64(24 * 60 * 60)
+	// Daily backups from 2022-03-31 back to 2022-01-31, ID 0 is the latest.
+	var backupList []*api.BackupRaw
+	for i := 0; i < 60; i++ {
+		backupList = append(backupList, &api.BackupRaw{
+			ID:        i,
+			CreatedTs: now.Unix() - int64(i)*day,
+		})
+	}
+
+	tests := []struct {
+		name        string
+		backupList  []*api.BackupRaw
+		policy      *api.BackupPlanPolicy
+		wantExpired int
+	}{
+		{
+			name:        "no retention",
+			backupList:  backupList,
+			policy:      &api.BackupPlanPolicy{Schedule: api.BackupPlanPolicyScheduleDaily},
+			wantExpired: 0,
+		},
+		{
+			name:        "keep last 7",
+			backupList:  backupList,
+			policy:      &api.BackupPlanPolicy{RetentionCount: 7},
+			wantExpired: 53,
+		},
+		{
+			name:       "keep 10 days",
+			backupList: backupList,
+			policy:     &api.BackupPlanPolicy{RetentionDays: 10},
+			// Backups created within [now - 10 days, now] are kept.
+			wantExpired: 49,
+		},
+		{
+			name:       "keep last 3 and one monthly",
+			backupList: backupList,
+			policy:     &api.BackupPlanPolicy{RetentionCount: 3, KeepMonthly: true},
+			// Latest backup of March is already kept by the count, February and January are kept by the monthly rule.
+			wantExpired: 55,
+		},
+		{
+			name: "always keep the last good backup",
+			backupList: []*api.BackupRaw{
+				{ID: 1, CreatedTs: now.Unix() - 100*day},
+				{ID: 2, CreatedTs: now.Unix() - 200*day},
+			},
+			policy:      &api.BackupPlanPolicy{RetentionDays: 7},
+			wantExpired: 1,
+		},
+	}
+
+	for _, test := range tests {
+		expiredList := getExpiredBackupList(test.backupList, test.policy, now)
+		if len(expiredList) != test.wantExpired {
+			t.Errorf("%s: got %d expired backups, want %d", test.name, len(expiredList), test.wantExpired)
+		}
+		for _, backup := range expiredList {
+			if backup.CreatedTs == test.backupList[0].CreatedTs {
+				t.Errorf("%s: the latest backup %d should never expire", test.name, backup.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/storage/s3"
+	"github.com/labstack/echo/v4"
+)
+
+// getBackupStorageSetting returns the workspace setting of where to store new backups.
+func (s *Server) getBackupStorageSetting(ctx context.Context) (*api.BackupStorageSetting, error) {
+	settingName := api.SettingBackupStorage
+	setting, err := s.SettingService.FindSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find backup storage setting: %w", err)
+	}
+	// Default to the local storage if the setting has not been initialized.
+	if setting == nil {
+		return &api.BackupStorageSetting{Backend: api.BackupStorageBackendLocal}, nil
+	}
+	return api.UnmarshalBackupStorageSetting(setting.Value)
+}
+
+// getBackupS3Client returns the client of the S3-compatible backup storage.
+// The S3 configuration is kept in the backup storage setting even after switching back to the local storage,
+// so that the existing S3 backups can still be restored.
+func (s *Server) getBackupS3Client(ctx context.Context) (*s3.Client, error) {
+	setting, err := s.getBackupStorageSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if setting.S3 == nil {
+		return nil, fmt.Errorf("S3 backup storage is not configured")
+	}
+	return s3.NewClient(s3.Config{
+		Endpoint:        setting.S3.Endpoint,
+		Region:          setting.S3.Region,
+		Bucket:          setting.S3.Bucket,
+		Prefix:          setting.S3.Prefix,
+		AccessKeyID:     setting.S3.AccessKeyID,
+		SecretAccessKey: setting.S3.SecretAccessKey,
+		UsePathStyle:    setting.S3.UsePathStyle,
+	})
+}
+
+// prepareBackupCreate sets the storage backend, path, compression and encryption of a new backup for the database
+// according to the backup storage setting.
+// For the local storage, the path is relative to the data directory and the directory will be created.
+// For the S3 storage, the path is the object key.
+func (s *Server) prepareBackupCreate(ctx context.Context, database *api.Database, create *api.BackupCreate) error {
+	setting, err := s.getBackupStorageSetting(ctx)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := getBackupEncryptionKeyFingerprint(setting)
+	if err != nil {
+		return err
+	}
+	create.Compression = setting.Compression
+	create.Encryption = setting.Encryption
+	create.EncryptionKeyFingerprint = fingerprint
+	fileName := fmt.Sprintf("%s.sql%s", create.Name, getBackupFileExtension(setting.Compression, setting.Encryption))
+
+	switch setting.Backend {
+	case api.BackupStorageBackendS3:
+		client, err := s.getBackupS3Client(ctx)
+		if err != nil {
+			return err
+		}
+		create.StorageBackend = api.BackupStorageBackendS3
+		create.Path = client.Key(filepath.ToSlash(filepath.Join("backup", "db", fmt.Sprintf("%d", database.ID), fileName)))
+	default:
+		dir, err := getAndCreateBackupDirectory(s.dataDir, database)
+		if err != nil {
+			return err
+		}
+		create.StorageBackend = api.BackupStorageBackendLocal
+		create.Path = filepath.Join(dir, fileName)
+	}
+	return nil
+}
+
+// loadBackupEncryptionKey returns the current backup encryption key, nil if not configured, and the previous keys in the setting.
+func loadBackupEncryptionKey(setting *api.BackupStorageSetting) ([]byte, [][]byte, error) {
+	var current []byte
+	// The key file is preferred, so that the key isn't stored in the metadata database.
+	encodedKey := setting.EncryptionKey
+	if setting.EncryptionKeyFile != "" {
+		buf, err := os.ReadFile(setting.EncryptionKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read backup encryption key file %q: %w", setting.EncryptionKeyFile, err)
+		}
+		encodedKey = string(buf)
+	}
+	if encodedKey != "" {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode backup encryption key: %w", err)
+		}
+		current = key
+	}
+
+	var previousList [][]byte
+	for i, encodedKey := range setting.PreviousEncryptionKeyList {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode previous backup encryption key #%d: %w", i+1, err)
+		}
+		previousList = append(previousList, key)
+	}
+	return current, previousList, nil
+}
+
+// getBackupEncryptionKeyFingerprint returns the fingerprint of the current backup encryption key to record with the new
+// backups and binlog files, empty if they aren't encrypted.
+func getBackupEncryptionKeyFingerprint(setting *api.BackupStorageSetting) (string, error) {
+	if setting.Encryption == api.BackupEncryptionNone {
+		return "", nil
+	}
+	key, _, err := loadBackupEncryptionKey(setting)
+	if err != nil {
+		return "", err
+	}
+	if key == nil {
+		return "", fmt.Errorf("backup encryption key is not configured")
+	}
+	return api.GetBackupEncryptionKeyFingerprint(key), nil
+}
+
+// getBackupEncryptionKey returns the backup encryption key of the fingerprint recorded with the backup or the binlog file,
+// which is either the current or a previous key in the backup storage setting. The empty fingerprint recorded before the
+// fingerprint is introduced selects the current key.
+func (s *Server) getBackupEncryptionKey(ctx context.Context, encryption api.BackupEncryption, fingerprint string) ([]byte, error) {
+	if encryption == api.BackupEncryptionNone {
+		return nil, nil
+	}
+	setting, err := s.getBackupStorageSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, previousList, err := loadBackupEncryptionKey(setting)
+	if err != nil {
+		return nil, err
+	}
+	if fingerprint == "" {
+		if current == nil {
+			return nil, fmt.Errorf("backup encryption key is not configured")
+		}
+		return current, nil
+	}
+	for _, key := range append([][]byte{current}, previousList...) {
+		if key != nil && api.GetBackupEncryptionKeyFingerprint(key) == fingerprint {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("backup encryption key with fingerprint %s is not found, configure it as the current or a previous backup encryption key", fingerprint)
+}
+
+// validateBackupEncryptionKeyRotation returns an error if the new backup storage setting drops any key still needed by
+// the encrypted backups or binlog files. Otherwise, the artifacts encrypted before the fingerprint is recorded get the
+// fingerprint of the current key, so that they're still restored with it after the rotation.
+// The returned error is an HTTP error.
+func (s *Server) validateBackupEncryptionKeyRotation(ctx context.Context, setting *api.BackupStorageSetting) error {
+	current, previousList, err := loadBackupEncryptionKey(setting)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid backup storage setting: %v", err)).SetInternal(err)
+	}
+	fingerprintMap := make(map[string]bool)
+	for _, key := range append([][]byte{current}, previousList...) {
+		if key != nil {
+			fingerprintMap[api.GetBackupEncryptionKeyFingerprint(key)] = true
+		}
+	}
+
+	oldSetting, err := s.getBackupStorageSetting(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch backup storage setting").SetInternal(err)
+	}
+	// The old key file might be unreadable, in which case nothing is encrypted by it anyway.
+	oldCurrent, _, _ := loadBackupEncryptionKey(oldSetting)
+
+	backupFingerprintList, err := s.BackupService.FindEncryptionKeyFingerprintList(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch backup encryption key fingerprints").SetInternal(err)
+	}
+	binlogFingerprintList, err := s.BinlogFileService.FindEncryptionKeyFingerprintList(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch binlog file encryption key fingerprints").SetInternal(err)
+	}
+	hasUnrecorded := false
+	for _, fingerprint := range append(backupFingerprintList, binlogFingerprintList...) {
+		if fingerprint == "" {
+			if oldCurrent == nil {
+				continue
+			}
+			hasUnrecorded = true
+			fingerprint = api.GetBackupEncryptionKeyFingerprint(oldCurrent)
+		}
+		if !fingerprintMap[fingerprint] {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Backup encryption key with fingerprint %s is still needed to restore the encrypted backups or binlog files, keep it in the previous encryption key list", fingerprint))
+		}
+	}
+
+	if hasUnrecorded {
+		fingerprint := api.GetBackupEncryptionKeyFingerprint(oldCurrent)
+		if err := s.BackupService.PatchEncryptionKeyFingerprint(ctx, fingerprint); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record backup encryption key fingerprint").SetInternal(err)
+		}
+		if err := s.BinlogFileService.PatchEncryptionKeyFingerprint(ctx, fingerprint); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record binlog file encryption key fingerprint").SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// writeBackup compresses and encrypts the content written by dump, and streams it to the storage of the backup.
+// Returns the size and the hex encoded SHA-256 checksum of the stored backup.
+func (s *Server) writeBackup(ctx context.Context, backup *api.Backup, dump func(w io.Writer) error) (int64, string, error) {
+	key, err := s.getBackupEncryptionKey(ctx, backup.Encryption, backup.EncryptionKeyFingerprint)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var size int64
+	hash := sha256.New()
+	if err := s.writeBackupStorage(ctx, backup, func(w io.Writer) error {
+		cw := &countingWriter{w: io.MultiWriter(w, hash)}
+		encoder, err := newBackupEncoder(cw, backup.Compression, backup.Encryption, key)
+		if err != nil {
+			return err
+		}
+		if err := dump(encoder); err != nil {
+			return err
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to flush backup: %w", err)
+		}
+		size = cw.n
+		return nil
+	}); err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeBackupStorage streams the content written by write to the storage of the backup.
+func (s *Server) writeBackupStorage(ctx context.Context, backup *api.Backup, write func(w io.Writer) error) error {
+	switch backup.StorageBackend {
+	case api.BackupStorageBackendLocal:
+		f, err := os.Create(getBackupAbsFilePath(s.dataDir, backup.Path))
+		if err != nil {
+			return fmt.Errorf("failed to open backup path %s: %w", backup.Path, err)
+		}
+		defer f.Close()
+		return write(f)
+	case api.BackupStorageBackendS3:
+		client, err := s.getBackupS3Client(ctx)
+		if err != nil {
+			return err
+		}
+		pr, pw := io.Pipe()
+		writeErr := make(chan error, 1)
+		go func() {
+			err := write(pw)
+			pw.CloseWithError(err)
+			writeErr <- err
+		}()
+		uploadErr := client.UploadObject(ctx, backup.Path, pr)
+		// Unblock the write if the upload fails halfway.
+		pr.CloseWithError(uploadErr)
+		if err := <-writeErr; err != nil {
+			return err
+		}
+		return uploadErr
+	}
+	return fmt.Errorf("unsupported backup storage backend %q", backup.StorageBackend)
+}
+
+// openBackup returns the decrypted and decompressed content stream of the backup, and the caller should close the stream.
+// The checksum of the local backup is verified before it's returned. The S3 backup is streamed without downloading it first,
+// so its checksum is verified while reading, and the stream returns the mismatch error instead of io.EOF at the end. The caller
+// restoring the backup should apply the content in a transaction, so that it's rolled back on the error.
+func (s *Server) openBackup(ctx context.Context, backup *api.Backup) (io.ReadCloser, error) {
+	key, err := s.getBackupEncryptionKey(ctx, backup.Encryption, backup.EncryptionKeyFingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored io.ReadCloser
+	var checksum *checksumReader
+	switch backup.StorageBackend {
+	case api.BackupStorageBackendLocal:
+		backupPath := getBackupAbsFilePath(s.dataDir, backup.Path)
+		f, err := os.Open(backupPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open backup file at %s: %w", backupPath, err)
+		}
+		// Backups taken before the checksum is recorded can't be verified.
+		if backup.Checksum != "" {
+			if err := verifyBackupChecksum(f, backup); err != nil {
+				f.Close()
+				return nil, err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				f.Close()
+				return nil, err
+			}
+		}
+		stored = f
+	case api.BackupStorageBackendS3:
+		client, err := s.getBackupS3Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body, err := client.ReadObject(ctx, backup.Path)
+		if err != nil {
+			return nil, err
+		}
+		stored = body
+		if backup.Checksum != "" {
+			checksum = &checksumReader{r: body, hash: sha256.New(), backup: backup}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported backup storage backend %q", backup.StorageBackend)
+	}
+
+	var r io.Reader = stored
+	if checksum != nil {
+		r = checksum
+	}
+	decoder, err := newBackupDecoder(r, backup.Compression, backup.Encryption, key)
+	if err != nil {
+		stored.Close()
+		return nil, err
+	}
+	return &backupReader{Reader: decoder, checksum: checksum, close: func() error {
+		defer stored.Close()
+		return decoder.Close()
+	}}, nil
+}
+
+// verifyBackupChecksum checks the size and the SHA-256 checksum of the stored backup read from r.
+func verifyBackupChecksum(r io.Reader, backup *api.Backup) error {
+	hash := sha256.New()
+	size, err := io.Copy(hash, r)
+	if err != nil {
+		return fmt.Errorf("failed to read backup %q: %w", backup.Name, err)
+	}
+	return checkBackupChecksum(backup, size, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// checkBackupChecksum checks the size and the SHA-256 checksum of the stored backup.
+func checkBackupChecksum(backup *api.Backup, size int64, checksum string) error {
+	if size != backup.Size {
+		return fmt.Errorf("backup %q is corrupted, expect size %d, got %d", backup.Name, backup.Size, size)
+	}
+	if checksum != backup.Checksum {
+		return fmt.Errorf("backup %q is corrupted, expect checksum %s, got %s", backup.Name, backup.Checksum, checksum)
+	}
+	return nil
+}
+
+// checksumReader computes the size and the SHA-256 checksum of the stored backup while it's read.
+type checksumReader struct {
+	r      io.Reader
+	hash   hash.Hash
+	size   int64
+	backup *api.Backup
+	// verified is true once verify is called, and err is its result.
+	verified bool
+	err      error
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+// verify reads the rest of the stored backup not consumed by the decoder, and checks the checksum.
+func (c *checksumReader) verify() error {
+	if c.verified {
+		return c.err
+	}
+	c.verified = true
+	if _, err := io.Copy(io.Discard, c); err != nil {
+		c.err = fmt.Errorf("failed to read backup %q: %w", c.backup.Name, err)
+	} else {
+		c.err = checkBackupChecksum(c.backup, c.size, hex.EncodeToString(c.hash.Sum(nil)))
+	}
+	return c.err
+}
+
+// getBackupFileExtension returns the file extension appended to ".sql" for the compression and encryption.
+func getBackupFileExtension(compression api.BackupCompression, encryption api.BackupEncryption) string {
+	ext := ""
+	switch compression {
+	case api.BackupCompressionGzip:
+		ext += ".gz"
+	case api.BackupCompressionZstd:
+		ext += ".zst"
+	}
+	if encryption != api.BackupEncryptionNone {
+		ext += ".enc"
+	}
+	return ext
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type backupReader struct {
+	io.Reader
+	// checksum is verified once the content is read to the end if not nil.
+	checksum *checksumReader
+	close    func() error
+}
+
+func (r *backupReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF && r.checksum != nil {
+		if verifyErr := r.checksum.verify(); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+	return n, err
+}
+
+func (r *backupReader) Close() error {
+	return r.close()
+}
+
+// removeBackup removes the backup content from its storage. It's not an error if the content doesn't exist.
+func (s *Server) removeBackup(ctx context.Context, backup *api.Backup) error {
+	switch backup.StorageBackend {
+	case api.BackupStorageBackendLocal:
+		backupPath := getBackupAbsFilePath(s.dataDir, backup.Path)
+		if err := os.Remove(backupPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove backup file %q: %w", backupPath, err)
+		}
+		return nil
+	case api.BackupStorageBackendS3:
+		client, err := s.getBackupS3Client(ctx)
+		if err != nil {
+			return err
+		}
+		return client.DeleteObject(ctx, backup.Path)
+	}
+	return fmt.Errorf("unsupported backup storage backend %q", backup.StorageBackend)
+}
+
+// getBackupAbsFilePath returns the absolute path of a local backup file.
+func getBackupAbsFilePath(dataDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dataDir, path)
+}
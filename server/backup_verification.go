@@ -0,0 +1,350 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+const (
+	// backupVerificationInterval is the interval to check whether any backup is due for verification.
+	backupVerificationInterval = time.Duration(1) * time.Hour
+	// backupVerificationDatabasePrefix is the name prefix of the scratch databases restored for the backup verification.
+	// The schema syncer skips these databases.
+	backupVerificationDatabasePrefix = "bbverify_"
+)
+
+// isBackupVerificationSupported returns whether the backups of the engine can be verified.
+func isBackupVerificationSupported(engine db.Type) bool {
+	return engine == db.MySQL || engine == db.TiDB || engine == db.Postgres
+}
+
+// isBackupVerificationDatabase returns whether the database is a scratch database of the backup verification.
+func isBackupVerificationDatabase(name string) bool {
+	return strings.HasPrefix(name, backupVerificationDatabasePrefix)
+}
+
+// getTableRowCount returns the exact number of rows of each base table in the database, keyed by the same table name
+// as the ones recorded by db.Driver.DumpWithTableRowCount.
+func getTableRowCount(ctx context.Context, driver db.Driver, engine db.Type, database string) (map[string]int64, error) {
+	sqlDB, err := driver.GetDbConnection(ctx, database)
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	var args []interface{}
+	switch engine {
+	case db.MySQL, db.TiDB:
+		query = "SELECT '', TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'"
+		args = append(args, database)
+	case db.Postgres:
+		query = "SELECT schemaname, tablename FROM pg_catalog.pg_tables WHERE schemaname NOT IN ('pg_catalog', 'information_schema')"
+	default:
+		return nil, fmt.Errorf("counting table rows is not supported for engine %s", engine)
+	}
+
+	type table struct {
+		schema string
+		name   string
+	}
+	var tableList []table
+	rows, err := sqlDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t table
+		if err := rows.Scan(&t.schema, &t.name); err != nil {
+			return nil, err
+		}
+		tableList = append(tableList, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tableRowCount := make(map[string]int64)
+	for _, t := range tableList {
+		key := t.name
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`", database, t.name)
+		if engine == db.Postgres {
+			key = fmt.Sprintf("%s.%s", t.schema, t.name)
+			countQuery = fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s"`, t.schema, t.name)
+		}
+		var count int64
+		if err := sqlDB.QueryRowContext(ctx, countQuery).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count rows of table %q: %w", key, err)
+		}
+		tableRowCount[key] = count
+	}
+	return tableRowCount, nil
+}
+
+// compareTableRowCount compares the table row counts recorded in the backup with the restored ones.
+func compareTableRowCount(expected, actual map[string]int64) error {
+	var diffList []string
+	for table, count := range expected {
+		actualCount, ok := actual[table]
+		if !ok {
+			diffList = append(diffList, fmt.Sprintf("table %q is missing", table))
+		} else if actualCount != count {
+			diffList = append(diffList, fmt.Sprintf("table %q has %d rows, expect %d rows", table, actualCount, count))
+		}
+	}
+	for table := range actual {
+		if _, ok := expected[table]; !ok {
+			diffList = append(diffList, fmt.Sprintf("table %q is unexpected", table))
+		}
+	}
+	if len(diffList) == 0 {
+		return nil
+	}
+	sort.Strings(diffList)
+	return fmt.Errorf("restored tables don't match the backup: %s", strings.Join(diffList, "; "))
+}
+
+// verifyBackup restores the backup into a scratch database on the instance, compares the restored tables and rows
+// with the ones recorded when taking the backup, and drops the scratch database afterwards.
+// Returns the verification detail on success.
+func (s *Server) verifyBackup(ctx context.Context, instance *api.Instance, backup *api.Backup) (string, error) {
+	payload := &api.BackupPayload{}
+	if err := json.Unmarshal([]byte(backup.Payload), payload); err != nil {
+		return "", fmt.Errorf("invalid backup payload: %w", err)
+	}
+
+	createStmt, dropStmt := "CREATE DATABASE `%s`", "DROP DATABASE IF EXISTS `%s`"
+	if instance.Engine == db.Postgres {
+		createStmt, dropStmt = `CREATE DATABASE "%s"`, `DROP DATABASE IF EXISTS "%s"`
+	}
+	scratchDatabase := fmt.Sprintf("%s%d_%d", backupVerificationDatabasePrefix, backup.ID, time.Now().Unix())
+
+	adminDriver, err := getAdminDatabaseDriver(ctx, instance, "", s.l)
+	if err != nil {
+		return "", err
+	}
+	defer adminDriver.Close(ctx)
+	if err := adminDriver.Execute(ctx, fmt.Sprintf(createStmt, scratchDatabase), false /* useTransaction */); err != nil {
+		return "", fmt.Errorf("failed to create scratch database %q: %w", scratchDatabase, err)
+	}
+	defer func() {
+		if err := adminDriver.Execute(ctx, fmt.Sprintf(dropStmt, scratchDatabase), false /* useTransaction */); err != nil {
+			s.l.Error("Failed to drop backup verification scratch database",
+				zap.String("instance", instance.Name),
+				zap.String("database", scratchDatabase),
+				zap.Error(err))
+		}
+	}()
+
+	// The connection to the scratch database must be closed before dropping it.
+	tableRowCount, err := func() (map[string]int64, error) {
+		driver, err := getAdminDatabaseDriver(ctx, instance, scratchDatabase, s.l)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close(ctx)
+
+		r, err := s.openBackup(ctx, backup)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if err := driver.Restore(ctx, bufio.NewScanner(r)); err != nil {
+			return nil, fmt.Errorf("failed to restore backup: %w", err)
+		}
+		return getTableRowCount(ctx, driver, instance.Engine, scratchDatabase)
+	}()
+	if err != nil {
+		return "", err
+	}
+
+	// Backups taken before the table row counts are recorded can only be verified to restore successfully.
+	if payload.TableRowCount == nil {
+		return fmt.Sprintf("Restored %d tables, the table row counts are not recorded in the backup.", len(tableRowCount)), nil
+	}
+	if err := compareTableRowCount(payload.TableRowCount, tableRowCount); err != nil {
+		return "", err
+	}
+	var rowCount int64
+	for _, count := range tableRowCount {
+		rowCount += count
+	}
+	return fmt.Sprintf("Restored %d tables with %d rows matching the backup.", len(tableRowCount), rowCount), nil
+}
+
+// isBackupVerificationDue returns whether the database is due for the backup verification according to the schedule.
+func isBackupVerificationDue(lastVerifiedTs int64, schedule api.BackupPlanPolicySchedule, now time.Time) bool {
+	var period time.Duration
+	switch schedule {
+	case api.BackupPlanPolicyScheduleDaily:
+		period = 24 * time.Hour
+	case api.BackupPlanPolicyScheduleWeekly:
+		period = 7 * 24 * time.Hour
+	default:
+		return false
+	}
+	return now.Sub(time.Unix(lastVerifiedTs, 0)) >= period
+}
+
+// verifyBackups verifies the latest backup of the databases in the environments whose backup plan policy
+// schedules the backup verification.
+func (s *BackupRunner) verifyBackups(ctx context.Context) {
+	rowStatus := api.Normal
+	instanceRawList, err := s.server.InstanceService.FindInstanceList(ctx, &api.InstanceFind{RowStatus: &rowStatus})
+	if err != nil {
+		s.l.Error("Failed to retrieve instance list", zap.Error(err))
+		return
+	}
+
+	policyMap := make(map[int]*api.BackupPlanPolicy)
+	for _, instanceRaw := range instanceRawList {
+		if !isBackupVerificationSupported(instanceRaw.Engine) {
+			continue
+		}
+		policy, ok := policyMap[instanceRaw.EnvironmentID]
+		if !ok {
+			policy, err = s.server.PolicyService.GetBackupPlanPolicy(ctx, instanceRaw.EnvironmentID)
+			if err != nil {
+				s.l.Error("Failed to retrieve backup policy",
+					zap.Int("environmentID", instanceRaw.EnvironmentID),
+					zap.Error(err))
+				continue
+			}
+			policyMap[instanceRaw.EnvironmentID] = policy
+		}
+		if policy.VerificationSchedule == "" || policy.VerificationSchedule == api.BackupPlanPolicyScheduleUnset {
+			continue
+		}
+
+		instance, err := s.server.composeInstanceRelationship(ctx, instanceRaw)
+		if err != nil {
+			s.l.Error("Failed to compose instance relationship",
+				zap.String("instance", instanceRaw.Name),
+				zap.Error(err))
+			continue
+		}
+		dbRawList, err := s.server.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{InstanceID: &instance.ID})
+		if err != nil {
+			s.l.Error("Failed to retrieve database list",
+				zap.String("instance", instance.Name),
+				zap.Error(err))
+			continue
+		}
+		for _, dbRaw := range dbRawList {
+			s.verifyBackupForDatabase(ctx, instance, dbRaw.ToDatabase(), policy)
+		}
+	}
+}
+
+func (s *BackupRunner) verifyBackupForDatabase(ctx context.Context, instance *api.Instance, database *api.Database, policy *api.BackupPlanPolicy) {
+	status := api.BackupStatusDone
+	backupRawList, err := s.server.BackupService.FindBackupList(ctx, &api.BackupFind{
+		DatabaseID: &database.ID,
+		Status:     &status,
+	})
+	if err != nil {
+		s.l.Error("Failed to retrieve backup list",
+			zap.String("database", database.Name),
+			zap.Error(err))
+		return
+	}
+	if len(backupRawList) == 0 {
+		return
+	}
+
+	var latestBackup *api.BackupRaw
+	var lastVerifiedTs int64
+	for _, backupRaw := range backupRawList {
+		if latestBackup == nil || backupRaw.CreatedTs > latestBackup.CreatedTs {
+			latestBackup = backupRaw
+		}
+		if backupRaw.VerifiedTs > lastVerifiedTs {
+			lastVerifiedTs = backupRaw.VerifiedTs
+		}
+	}
+	if !isBackupVerificationDue(lastVerifiedTs, policy.VerificationSchedule, time.Now()) {
+		return
+	}
+
+	s.l.Debug("Start backup verification...",
+		zap.String("instance", instance.Name),
+		zap.String("database", database.Name),
+		zap.String("backup", latestBackup.Name),
+	)
+	detail, verifyErr := s.server.verifyBackup(ctx, instance, latestBackup.ToBackup())
+	verificationStatus := api.BackupVerificationStatusPassed
+	if verifyErr != nil {
+		verificationStatus = api.BackupVerificationStatusFailed
+		detail = verifyErr.Error()
+	}
+	verifiedTs := time.Now().Unix()
+	if _, err := s.server.BackupService.PatchBackup(ctx, &api.BackupPatch{
+		ID:                 latestBackup.ID,
+		Status:             string(latestBackup.Status),
+		UpdaterID:          api.SystemBotID,
+		Comment:            latestBackup.Comment,
+		VerificationStatus: &verificationStatus,
+		VerifiedTs:         &verifiedTs,
+		VerificationDetail: &detail,
+	}); err != nil {
+		s.l.Error("Failed to patch backup verification status",
+			zap.String("database", database.Name),
+			zap.String("backup", latestBackup.Name),
+			zap.Error(err))
+		return
+	}
+
+	if verifyErr != nil {
+		s.l.Warn("Backup verification failed",
+			zap.String("database", database.Name),
+			zap.String("backup", latestBackup.Name),
+			zap.Error(verifyErr))
+		anomalyPayload := api.AnomalyDatabaseBackupVerificationFailurePayload{
+			BackupID:   latestBackup.ID,
+			BackupName: latestBackup.Name,
+			Detail:     detail,
+		}
+		payload, err := json.Marshal(anomalyPayload)
+		if err != nil {
+			s.l.Error("Failed to marshal anomaly payload",
+				zap.String("database", database.Name),
+				zap.String("type", string(api.AnomalyDatabaseBackupVerificationFailure)),
+				zap.Error(err))
+			return
+		}
+		if _, err = s.server.upsertActiveAnomaly(ctx, &api.AnomalyUpsert{
+			CreatorID:  api.SystemBotID,
+			InstanceID: instance.ID,
+			DatabaseID: &database.ID,
+			Type:       api.AnomalyDatabaseBackupVerificationFailure,
+			Payload:    string(payload),
+		}); err != nil {
+			s.l.Error("Failed to create anomaly",
+				zap.String("database", database.Name),
+				zap.String("type", string(api.AnomalyDatabaseBackupVerificationFailure)),
+				zap.Error(err))
+		}
+		return
+	}
+
+	err = s.server.archiveAnomaly(ctx, &api.AnomalyArchive{
+		DatabaseID: &database.ID,
+		Type:       api.AnomalyDatabaseBackupVerificationFailure,
+	})
+	if err != nil && common.ErrorCode(err) != common.NotFound {
+		s.l.Error("Failed to close anomaly",
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseBackupVerificationFailure)),
+			zap.Error(err))
+	}
+}
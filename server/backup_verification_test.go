@@ -0,0 +1,69 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestCompareTableRowCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected map[string]int64
+		actual   map[string]int64
+		wantErr  string
+	}{
+		{
+			name:     "match",
+			expected: map[string]int64{"t1": 2, "t2": 0},
+			actual:   map[string]int64{"t1": 2, "t2": 0},
+		},
+		{
+			name:     "empty",
+			expected: map[string]int64{},
+			actual:   map[string]int64{},
+		},
+		{
+			name:     "mismatch",
+			expected: map[string]int64{"t1": 2, "t2": 0},
+			actual:   map[string]int64{"t1": 1, "t3": 0},
+			wantErr:  `restored tables don't match the backup: table "t1" has 1 rows, expect 2 rows; table "t2" is missing; table "t3" is unexpected`,
+		},
+	}
+
+	for _, test := range tests {
+		err := compareTableRowCount(test.expected, test.actual)
+		if test.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: got error %v, want nil", test.name, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.wantErr {
+			t.Errorf("%s: got error %v, want %q", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestIsBackupVerificationDue(t *testing.T) {
+	now := time.Date(2022, 3, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		lastVerifiedTs int64
+		schedule       api.BackupPlanPolicySchedule
+		want           bool
+	}{
+		{0, api.BackupPlanPolicyScheduleUnset, false},
+		{0, api.BackupPlanPolicyScheduleDaily, true},
+		{now.Add(-23 * time.Hour).Unix(), api.BackupPlanPolicyScheduleDaily, false},
+		{now.Add(-24 * time.Hour).Unix(), api.BackupPlanPolicyScheduleDaily, true},
+		{now.Add(-6 * 24 * time.Hour).Unix(), api.BackupPlanPolicyScheduleWeekly, false},
+		{now.Add(-7 * 24 * time.Hour).Unix(), api.BackupPlanPolicyScheduleWeekly, true},
+	}
+
+	for _, test := range tests {
+		if got := isBackupVerificationDue(test.lastVerifiedTs, test.schedule, now); got != test.want {
+			t.Errorf("isBackupVerificationDue(%d, %s) = %v, want %v", test.lastVerifiedTs, test.schedule, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/mysql"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mysqlBinary       = "mysql"
+	mysqlbinlogBinary = "mysqlbinlog"
+)
+
+// getMySQLBinaryPath returns the path of the MySQL client binary.
+func (s *Server) getMySQLBinaryPath(binary string) (string, error) {
+	if s.mysqlBinDir == "" {
+		return "", fmt.Errorf("MySQL utilities are not found, set --mysql-bin-dir or install mysqlbinlog and mysql on PATH")
+	}
+	return filepath.Join(s.mysqlBinDir, binary), nil
+}
+
+// getMySQLCommandArgs returns the connection arguments and environment variables of the MySQL client
+// binaries for connecting to the instance with the admin data source.
+// The password is passed by the environment variable so that it doesn't show up in the process list.
+func getMySQLCommandArgs(instance *api.Instance) ([]string, []string, error) {
+	adminDataSource := api.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil {
+		return nil, nil, fmt.Errorf("admin data source not found for instance %q", instance.Name)
+	}
+	var args []string
+	if strings.HasPrefix(instance.Host, "/") {
+		args = append(args, fmt.Sprintf("--socket=%s", instance.Host))
+	} else {
+		port := instance.Port
+		if port == "" {
+			port = "3306"
+		}
+		args = append(args, fmt.Sprintf("--host=%s", instance.Host), fmt.Sprintf("--port=%s", port))
+	}
+	args = append(args, fmt.Sprintf("--user=%s", adminDataSource.Username))
+	env := append(os.Environ(), fmt.Sprintf("MYSQL_PWD=%s", adminDataSource.Password))
+	return args, env, nil
+}
+
+// prepareBinlogFileCreate sets the storage backend, path, compression and encryption of a binlog file archived
+// from the instance according to the backup storage setting.
+func (s *Server) prepareBinlogFileCreate(ctx context.Context, instance *api.Instance, create *api.BinlogFileCreate) error {
+	setting, err := s.getBackupStorageSetting(ctx)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := getBackupEncryptionKeyFingerprint(setting)
+	if err != nil {
+		return err
+	}
+	create.Compression = setting.Compression
+	create.Encryption = setting.Encryption
+	create.EncryptionKeyFingerprint = fingerprint
+	// The binlog file name is reused after RESET MASTER or the MySQL server is rebuilt, so the archiving time is
+	// appended to keep the archived one with the same name.
+	fileName := fmt.Sprintf("%s.%d%s", create.Name, time.Now().Unix(), getBackupFileExtension(setting.Compression, setting.Encryption))
+	dir := filepath.Join("backup", "instance", fmt.Sprintf("%d", instance.ID), "binlog", create.ServerUUID)
+
+	switch setting.Backend {
+	case api.BackupStorageBackendS3:
+		client, err := s.getBackupS3Client(ctx)
+		if err != nil {
+			return err
+		}
+		create.StorageBackend = api.BackupStorageBackendS3
+		create.Path = client.Key(filepath.ToSlash(filepath.Join(dir, fileName)))
+	default:
+		if err := os.MkdirAll(filepath.Join(s.dataDir, dir), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create binlog directory %q: %w", dir, err)
+		}
+		create.StorageBackend = api.BackupStorageBackendLocal
+		create.Path = filepath.Join(dir, fileName)
+	}
+	return nil
+}
+
+// getBinlogFileStorage returns the archived binlog file as a backup, since the archived binlog files are stored
+// in the same way as backups.
+func getBinlogFileStorage(binlogFile *api.BinlogFile) *api.Backup {
+	return &api.Backup{
+		Name:                     binlogFile.Name,
+		StorageBackend:           binlogFile.StorageBackend,
+		Path:                     binlogFile.Path,
+		Compression:              binlogFile.Compression,
+		Encryption:               binlogFile.Encryption,
+		EncryptionKeyFingerprint: binlogFile.EncryptionKeyFingerprint,
+		Size:                     binlogFile.Size,
+		Checksum:                 binlogFile.Checksum,
+	}
+}
+
+// getBackupBinlogInfo returns the binlog coordinate recorded in the backup payload, nil if not recorded.
+func getBackupBinlogInfo(backupPayload string) (*api.BackupBinlogInfo, error) {
+	payload := &api.BackupPayload{}
+	if err := json.Unmarshal([]byte(backupPayload), payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal backup payload: %w", err)
+	}
+	return payload.BinlogInfo, nil
+}
+
+// downloadBinlogFile downloads the binlog file from the MySQL server to dir, and returns the local path.
+func (s *Server) downloadBinlogFile(ctx context.Context, instance *api.Instance, name string, dir string) (string, error) {
+	mysqlbinlog, err := s.getMySQLBinaryPath(mysqlbinlogBinary)
+	if err != nil {
+		return "", err
+	}
+	connArgs, env, err := getMySQLCommandArgs(instance)
+	if err != nil {
+		return "", err
+	}
+	// With --raw, the binlog file is written as is to the --result-file prefix followed by the file name.
+	args := append([]string{"--read-from-remote-server", "--raw", fmt.Sprintf("--result-file=%s%c", dir, filepath.Separator)}, connArgs...)
+	args = append(args, name)
+	cmd := exec.CommandContext(ctx, mysqlbinlog, args...)
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to download binlog file %q from instance %q: %w, %s", name, instance.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// fetchArchivedBinlogFile decrypts and decompresses the archived binlog file to dir, and returns the local path.
+func (s *Server) fetchArchivedBinlogFile(ctx context.Context, binlogFile *api.BinlogFile, dir string) (string, error) {
+	r, err := s.openBackup(ctx, getBinlogFileStorage(binlogFile))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	path := filepath.Join(dir, binlogFile.Name)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create binlog file %q: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return "", fmt.Errorf("failed to fetch archived binlog file %q: %w", binlogFile.Name, err)
+	}
+	return path, nil
+}
+
+// replayBinlog replays the binlog events of the source database from the binlog coordinate of the backup up to
+// pointInTimeTs (inclusive) into the target database, which has been restored from the backup.
+// The binlog files archived to the backup storage are used first, and the remaining ones are read from the source
+// instance.
+func (s *Server) replayBinlog(ctx context.Context, sourceDatabase, targetDatabase *api.Database, backup *api.Backup, binlogInfo *api.BackupBinlogInfo, pointInTimeTs int64) error {
+	mysqlbinlog, err := s.getMySQLBinaryPath(mysqlbinlogBinary)
+	if err != nil {
+		return err
+	}
+	mysqlClient, err := s.getMySQLBinaryPath(mysqlBinary)
+	if err != nil {
+		return err
+	}
+
+	startSeq, err := mysql.GetBinlogFileSeq(binlogInfo.FileName)
+	if err != nil {
+		return err
+	}
+	archivedList, err := s.BinlogFileService.FindBinlogFileList(ctx, &api.BinlogFileFind{InstanceID: &sourceDatabase.InstanceID})
+	if err != nil {
+		return fmt.Errorf("failed to find archived binlog files: %w", err)
+	}
+	driver, err := getAdminDatabaseDriver(ctx, sourceDatabase.Instance, "", s.l)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	mysqlDriver, ok := driver.(*mysql.Driver)
+	if !ok {
+		return fmt.Errorf("point-in-time recovery is not supported for %s", sourceDatabase.Instance.Engine)
+	}
+	serverUUID, err := mysqlDriver.GetServerUUID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get server UUID of instance %q: %w", sourceDatabase.Instance.Name, err)
+	}
+	if binlogInfo.ServerUUID != "" && binlogInfo.ServerUUID != serverUUID {
+		return fmt.Errorf("the MySQL server of instance %q has been rebuilt since backup %q is taken", sourceDatabase.Instance.Name, backup.Name)
+	}
+	serverList, err := mysqlDriver.GetBinlogFileList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list binlog files of instance %q: %w", sourceDatabase.Instance.Name, err)
+	}
+	replayList, err := getBinlogReplayList(startSeq, getBinlogReplayArchivedList(archivedList, backup, binlogInfo), serverList)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp(s.dataDir, "binlog-*")
+	if err != nil {
+		return fmt.Errorf("failed to create binlog temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var pathList []string
+	for _, f := range replayList {
+		var path string
+		if f.archived != nil {
+			path, err = s.fetchArchivedBinlogFile(ctx, f.archived, dir)
+		} else {
+			path, err = s.downloadBinlogFile(ctx, sourceDatabase.Instance, f.name, dir)
+		}
+		if err != nil {
+			return err
+		}
+		pathList = append(pathList, path)
+	}
+
+	// The binlog events are parsed by mysqlbinlog and piped to the mysql client connected to the target instance.
+	// The target database name is rewritten, and GTIDs are skipped since they have been executed on the source.
+	binlogArgs := []string{
+		fmt.Sprintf("--rewrite-db=%s->%s", sourceDatabase.Name, targetDatabase.Name),
+		fmt.Sprintf("--database=%s", targetDatabase.Name),
+		"--skip-gtids",
+		fmt.Sprintf("--start-position=%d", binlogInfo.Position),
+		// --stop-datetime is exclusive and in the local time zone of mysqlbinlog.
+		fmt.Sprintf("--stop-datetime=%s", time.Unix(pointInTimeTs+1, 0).UTC().Format("2006-01-02 15:04:05")),
+	}
+	binlogArgs = append(binlogArgs, pathList...)
+	binlogCmd := exec.CommandContext(ctx, mysqlbinlog, binlogArgs...)
+	binlogCmd.Env = append(os.Environ(), "TZ=UTC")
+	var binlogStderr bytes.Buffer
+	binlogCmd.Stderr = &binlogStderr
+
+	connArgs, env, err := getMySQLCommandArgs(targetDatabase.Instance)
+	if err != nil {
+		return err
+	}
+	mysqlCmd := exec.CommandContext(ctx, mysqlClient, connArgs...)
+	mysqlCmd.Env = env
+	var mysqlStderr bytes.Buffer
+	mysqlCmd.Stderr = &mysqlStderr
+	pipe, err := binlogCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	mysqlCmd.Stdin = pipe
+
+	if err := mysqlCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start mysql: %w", err)
+	}
+	binlogErr := binlogCmd.Run()
+	// The mysql client exits after mysqlbinlog exits and closes the pipe.
+	// Check the mysql client first, because mysqlbinlog fails with a broken pipe if the mysql client fails.
+	if err := mysqlCmd.Wait(); err != nil {
+		return fmt.Errorf("failed to replay binlog: %w, %s", err, strings.TrimSpace(mysqlStderr.String()))
+	}
+	if binlogErr != nil {
+		return fmt.Errorf("failed to parse binlog: %w, %s", binlogErr, strings.TrimSpace(binlogStderr.String()))
+	}
+	return nil
+}
+
+// binlogReplayFile is a binlog file to replay, either archived or read from the MySQL server.
+type binlogReplayFile struct {
+	name     string
+	archived *api.BinlogFile
+}
+
+// getBinlogReplayArchivedList returns the archived binlog files that may follow the binlog coordinate of the backup.
+// The binlog file of the coordinate is being written when the backup is taken, so it and the following ones are
+// archived after that, and the ones with the same name archived before are from an earlier RESET MASTER.
+func getBinlogReplayArchivedList(archivedList []*api.BinlogFile, backup *api.Backup, binlogInfo *api.BackupBinlogInfo) []*api.BinlogFile {
+	var list []*api.BinlogFile
+	for _, f := range archivedList {
+		if f.CreatedTs < backup.CreatedTs {
+			continue
+		}
+		if binlogInfo.ServerUUID != "" && f.ServerUUID != "" && f.ServerUUID != binlogInfo.ServerUUID {
+			continue
+		}
+		list = append(list, f)
+	}
+	return list
+}
+
+// getBinlogReplayList returns the binlog files to replay starting from the file with startSeq.
+// The archived files are preferred since the MySQL server may have purged them, and the files must be consecutive.
+func getBinlogReplayList(startSeq int64, archivedList []*api.BinlogFile, serverList []*mysql.BinlogFile) ([]*binlogReplayFile, error) {
+	var replayList []*binlogReplayFile
+	nextSeq := startSeq
+	for _, f := range archivedList {
+		if f.Seq < nextSeq {
+			continue
+		}
+		if f.Seq > nextSeq {
+			break
+		}
+		replayList = append(replayList, &binlogReplayFile{name: f.Name, archived: f})
+		nextSeq++
+	}
+	for _, f := range serverList {
+		seq, err := mysql.GetBinlogFileSeq(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if seq < nextSeq {
+			continue
+		}
+		if seq > nextSeq {
+			break
+		}
+		replayList = append(replayList, &binlogReplayFile{name: f.Name})
+		nextSeq++
+	}
+
+	// The last binlog file on the server is the one being written, and must be included.
+	if len(serverList) > 0 {
+		lastSeq, err := mysql.GetBinlogFileSeq(serverList[len(serverList)-1].Name)
+		if err != nil {
+			return nil, err
+		}
+		if nextSeq <= lastSeq {
+			return nil, fmt.Errorf("binlog file with sequence %d is neither archived nor on the MySQL server", nextSeq)
+		}
+	}
+	if len(replayList) == 0 {
+		return nil, fmt.Errorf("binlog file with sequence %d is neither archived nor on the MySQL server", startSeq)
+	}
+	return replayList, nil
+}
+
+// getPointInTimeRecoveryBackupID validates the point-in-time recovery in the database creation context,
+// and returns the ID of the backup to restore.
+func (s *Server) getPointInTimeRecoveryBackupID(ctx context.Context, instance *api.Instance, m *api.CreateDatabaseContext) (int, error) {
+	if m.BackupID != 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, backupId and pointInTimeTs are mutually exclusive")
+	}
+	// The point in time must be in the past, so that the binlog events of restoring the target database itself,
+	// which happen later, are never replayed.
+	if m.PointInTimeTs >= time.Now().Unix() {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, point in time %s is not in the past", formatPointInTime(m.PointInTimeTs)))
+	}
+	if !isPointInTimeRecoverySupported(instance.Engine) {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, point-in-time recovery is not supported for %s", instance.Engine))
+	}
+	if s.mysqlBinDir == "" {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, MySQL utilities for the point-in-time recovery are not found, set --mysql-bin-dir or install mysqlbinlog and mysql on PATH")
+	}
+
+	sourceDatabase, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &m.SourceDatabaseID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find source database %v: %w", m.SourceDatabaseID, err)
+	}
+	if sourceDatabase == nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, source database ID not found %v", m.SourceDatabaseID))
+	}
+	if !isPointInTimeRecoverySupported(sourceDatabase.Instance.Engine) {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, point-in-time recovery is not supported for %s", sourceDatabase.Instance.Engine))
+	}
+
+	status := api.BackupStatusDone
+	backupRawList, err := s.BackupService.FindBackupList(ctx, &api.BackupFind{
+		DatabaseID: &sourceDatabase.ID,
+		Status:     &status,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find backups of database %q: %w", sourceDatabase.Name, err)
+	}
+	backupRaw := getPointInTimeRecoveryBackup(backupRawList, m.PointInTimeTs)
+	if backupRaw == nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, no backup of database %q for the point-in-time recovery is taken before %s", sourceDatabase.Name, formatPointInTime(m.PointInTimeTs)))
+	}
+	return backupRaw.ID, nil
+}
+
+// getPointInTimeRecoveryBackup returns the latest backup with the binlog coordinate taken no later than pointInTimeTs,
+// nil if there is none.
+func getPointInTimeRecoveryBackup(backupRawList []*api.BackupRaw, pointInTimeTs int64) *api.BackupRaw {
+	var result *api.BackupRaw
+	var resultTs int64
+	for _, backupRaw := range backupRawList {
+		binlogInfo, err := getBackupBinlogInfo(backupRaw.Payload)
+		if err != nil || binlogInfo == nil {
+			continue
+		}
+		if binlogInfo.Ts > pointInTimeTs {
+			continue
+		}
+		if result == nil || binlogInfo.Ts > resultTs {
+			result, resultTs = backupRaw, binlogInfo.Ts
+		}
+	}
+	return result
+}
+
+// formatPointInTime formats the point in time of the point-in-time recovery.
+func formatPointInTime(ts int64) string {
+	return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+}
+
+// isPointInTimeRecoverySupported returns whether the engine supports the point-in-time recovery.
+func isPointInTimeRecoverySupported(engine db.Type) bool {
+	return engine == db.MySQL
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/mysql"
+	"go.uber.org/zap"
+)
+
+// NewBinlogArchiver creates a new binlog archiver.
+func NewBinlogArchiver(logger *zap.Logger, server *Server, binlogArchiverInterval time.Duration) *BinlogArchiver {
+	return &BinlogArchiver{
+		l:                      logger,
+		server:                 server,
+		binlogArchiverInterval: binlogArchiverInterval,
+	}
+}
+
+// BinlogArchiver is the runner archiving the binlog files of MySQL instances to the backup storage
+// for the point-in-time recovery.
+type BinlogArchiver struct {
+	l                      *zap.Logger
+	server                 *Server
+	binlogArchiverInterval time.Duration
+}
+
+// Run is the runner for binlog archiver.
+func (s *BinlogArchiver) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(s.binlogArchiverInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	s.l.Debug("Binlog archiver started", zap.Duration("interval", s.binlogArchiverInterval))
+	for {
+		select {
+		case <-ticker.C:
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						s.l.Error("Binlog archiver PANIC RECOVER", zap.Error(err), zap.Stack("stack"))
+					}
+				}()
+				s.archiveBinlog(ctx)
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// archiveBinlog archives the binlog files of the MySQL instances in the environments whose backup plan policy
+// enables the binlog archiving.
+func (s *BinlogArchiver) archiveBinlog(ctx context.Context) {
+	if s.server.mysqlBinDir == "" {
+		return
+	}
+	rowStatus := api.Normal
+	instanceRawList, err := s.server.InstanceService.FindInstanceList(ctx, &api.InstanceFind{RowStatus: &rowStatus})
+	if err != nil {
+		s.l.Error("Failed to retrieve instance list", zap.Error(err))
+		return
+	}
+
+	policyMap := make(map[int]*api.BackupPlanPolicy)
+	for _, instanceRaw := range instanceRawList {
+		if !isPointInTimeRecoverySupported(instanceRaw.Engine) {
+			continue
+		}
+		policy, ok := policyMap[instanceRaw.EnvironmentID]
+		if !ok {
+			policy, err = s.server.PolicyService.GetBackupPlanPolicy(ctx, instanceRaw.EnvironmentID)
+			if err != nil {
+				s.l.Error("Failed to retrieve backup policy",
+					zap.Int("environmentID", instanceRaw.EnvironmentID),
+					zap.Error(err))
+				continue
+			}
+			policyMap[instanceRaw.EnvironmentID] = policy
+		}
+		if !policy.ArchiveBinlog {
+			continue
+		}
+
+		instance, err := s.server.composeInstanceRelationship(ctx, instanceRaw)
+		if err != nil {
+			s.l.Error("Failed to compose instance relationship",
+				zap.String("instance", instanceRaw.Name),
+				zap.Error(err))
+			continue
+		}
+		if err := s.archiveInstanceBinlog(ctx, instance); err != nil {
+			s.l.Error("Failed to archive binlog",
+				zap.String("instance", instance.Name),
+				zap.Error(err))
+			continue
+		}
+		if err := s.purgeInstanceBinlog(ctx, instance); err != nil {
+			s.l.Error("Failed to purge archived binlog",
+				zap.String("instance", instance.Name),
+				zap.Error(err))
+		}
+	}
+}
+
+// archiveInstanceBinlog archives the binlog files of the instance that have not been archived yet.
+// The binlog file being written is archived after the MySQL server rotates to a new one.
+func (s *BinlogArchiver) archiveInstanceBinlog(ctx context.Context, instance *api.Instance) error {
+	driver, err := getAdminDatabaseDriver(ctx, instance, "", s.l)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	mysqlDriver, ok := driver.(*mysql.Driver)
+	if !ok {
+		return fmt.Errorf("binlog archiving is not supported for %s", instance.Engine)
+	}
+	serverUUID, err := mysqlDriver.GetServerUUID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get server UUID: %w", err)
+	}
+	serverList, err := mysqlDriver.GetBinlogFileList(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list binlog files: %w", err)
+	}
+	if len(serverList) == 0 {
+		return nil
+	}
+
+	archivedList, err := s.server.BinlogFileService.FindBinlogFileList(ctx, &api.BinlogFileFind{InstanceID: &instance.ID})
+	if err != nil {
+		return fmt.Errorf("failed to find archived binlog files: %w", err)
+	}
+	toArchive := getBinlogArchiveList(serverUUID, archivedList, serverList[:len(serverList)-1])
+	if len(toArchive) == 0 {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp(s.server.dataDir, "binlog-*")
+	if err != nil {
+		return fmt.Errorf("failed to create binlog temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range toArchive {
+		if err := s.archiveBinlogFile(ctx, instance, serverUUID, f, dir); err != nil {
+			return err
+		}
+		s.l.Debug("Archived binlog file",
+			zap.String("instance", instance.Name),
+			zap.String("binlog", f.Name),
+		)
+	}
+	return nil
+}
+
+// getBinlogArchiveList returns the binlog files on the MySQL server that have not been archived yet.
+// The binlog files are told apart by the server UUID and the file size besides the name, since the name is reused
+// after RESET MASTER or the MySQL server is rebuilt. The ones archived before the server UUID is recorded are
+// only matched by the name.
+func getBinlogArchiveList(serverUUID string, archivedList []*api.BinlogFile, serverList []*mysql.BinlogFile) []*mysql.BinlogFile {
+	type binlogFileKey struct {
+		serverUUID string
+		name       string
+		fileSize   int64
+	}
+	archived := make(map[binlogFileKey]bool)
+	legacyArchived := make(map[string]bool)
+	for _, f := range archivedList {
+		if f.ServerUUID == "" {
+			legacyArchived[f.Name] = true
+			continue
+		}
+		archived[binlogFileKey{serverUUID: f.ServerUUID, name: f.Name, fileSize: f.FileSize}] = true
+	}
+
+	var toArchive []*mysql.BinlogFile
+	for _, f := range serverList {
+		if legacyArchived[f.Name] || archived[binlogFileKey{serverUUID: serverUUID, name: f.Name, fileSize: f.Size}] {
+			continue
+		}
+		toArchive = append(toArchive, f)
+	}
+	return toArchive
+}
+
+func (s *BinlogArchiver) archiveBinlogFile(ctx context.Context, instance *api.Instance, serverUUID string, binlogFile *mysql.BinlogFile, dir string) error {
+	name := binlogFile.Name
+	seq, err := mysql.GetBinlogFileSeq(name)
+	if err != nil {
+		return err
+	}
+	path, err := s.server.downloadBinlogFile(ctx, instance, name, dir)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	create := &api.BinlogFileCreate{
+		CreatorID:  api.SystemBotID,
+		InstanceID: instance.ID,
+		Name:       name,
+		Seq:        seq,
+		ServerUUID: serverUUID,
+		FileSize:   binlogFile.Size,
+	}
+	if err := s.server.prepareBinlogFileCreate(ctx, instance, create); err != nil {
+		return err
+	}
+	storage := &api.Backup{
+		Name:                     create.Name,
+		StorageBackend:           create.StorageBackend,
+		Path:                     create.Path,
+		Compression:              create.Compression,
+		Encryption:               create.Encryption,
+		EncryptionKeyFingerprint: create.EncryptionKeyFingerprint,
+	}
+	create.Size, create.Checksum, err = s.server.writeBackup(ctx, storage, func(w io.Writer) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive binlog file %q: %w", name, err)
+	}
+
+	if _, err := s.server.BinlogFileService.CreateBinlogFile(ctx, create); err != nil {
+		return fmt.Errorf("failed to create binlog file %q: %w", name, err)
+	}
+	return nil
+}
+
+// purgeInstanceBinlog removes the archived binlog files older than the oldest backup with the binlog coordinate
+// of the instance, since they are no longer needed by the point-in-time recovery.
+// The binlog files are archived after they're rotated, so the ones archived before the backup is taken end before
+// its binlog coordinate. Unlike the sequence numbers, this holds across RESET MASTER and the rebuilt MySQL server.
+func (s *BinlogArchiver) purgeInstanceBinlog(ctx context.Context, instance *api.Instance) error {
+	dbRawList, err := s.server.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{InstanceID: &instance.ID})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve database list: %w", err)
+	}
+	var minTs int64 = -1
+	status := api.BackupStatusDone
+	for _, dbRaw := range dbRawList {
+		backupRawList, err := s.server.BackupService.FindBackupList(ctx, &api.BackupFind{
+			DatabaseID: &dbRaw.ID,
+			Status:     &status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve backup list of database %q: %w", dbRaw.Name, err)
+		}
+		for _, backupRaw := range backupRawList {
+			binlogInfo, err := getBackupBinlogInfo(backupRaw.Payload)
+			if err != nil || binlogInfo == nil {
+				continue
+			}
+			if minTs < 0 || backupRaw.CreatedTs < minTs {
+				minTs = backupRaw.CreatedTs
+			}
+		}
+	}
+	// Keep all archived binlog files until there is a backup to replay them on.
+	if minTs < 0 {
+		return nil
+	}
+
+	archivedList, err := s.server.BinlogFileService.FindBinlogFileList(ctx, &api.BinlogFileFind{InstanceID: &instance.ID})
+	if err != nil {
+		return fmt.Errorf("failed to find archived binlog files: %w", err)
+	}
+	for _, f := range archivedList {
+		if f.CreatedTs >= minTs {
+			continue
+		}
+		if err := s.server.removeBackup(ctx, getBinlogFileStorage(f)); err != nil {
+			return err
+		}
+		if err := s.server.BinlogFileService.DeleteBinlogFile(ctx, &api.BinlogFileDelete{
+			ID:        f.ID,
+			DeleterID: api.SystemBotID,
+		}); err != nil && common.ErrorCode(err) != common.NotFound {
+			return fmt.Errorf("failed to delete binlog file %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
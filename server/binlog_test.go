@@ -0,0 +1,185 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db/mysql"
+)
+
+func TestGetBinlogReplayList(t *testing.T) {
+	archived := func(seqList ...int64) []*api.BinlogFile {
+		var list []*api.BinlogFile
+		for _, seq := range seqList {
+			list = append(list, &api.BinlogFile{Name: fmt.Sprintf("binlog.%06d", seq), Seq: seq})
+		}
+		return list
+	}
+	server := func(seqList ...int64) []*mysql.BinlogFile {
+		var list []*mysql.BinlogFile
+		for _, seq := range seqList {
+			list = append(list, &mysql.BinlogFile{Name: fmt.Sprintf("binlog.%06d", seq)})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name         string
+		startSeq     int64
+		archivedList []*api.BinlogFile
+		serverList   []*mysql.BinlogFile
+		// want is the names of the replayed files, prefixed with "a:" if archived.
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:       "server only",
+			startSeq:   2,
+			serverList: server(1, 2, 3),
+			want:       []string{"binlog.000002", "binlog.000003"},
+		},
+		{
+			name:         "archived and server",
+			startSeq:     2,
+			archivedList: archived(1, 2, 3),
+			serverList:   server(3, 4),
+			want:         []string{"a:binlog.000002", "a:binlog.000003", "binlog.000004"},
+		},
+		{
+			name:         "purged from server after archived",
+			startSeq:     1,
+			archivedList: archived(1, 2),
+			serverList:   server(3),
+			want:         []string{"a:binlog.000001", "a:binlog.000002", "binlog.000003"},
+		},
+		{
+			name:         "gap",
+			startSeq:     1,
+			archivedList: archived(1),
+			serverList:   server(3, 4),
+			wantErr:      true,
+		},
+		{
+			name:       "start purged",
+			startSeq:   1,
+			serverList: server(2, 3),
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		replayList, err := getBinlogReplayList(test.startSeq, test.archivedList, test.serverList)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expect error, got nil", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: got error %v", test.name, err)
+			continue
+		}
+		var got []string
+		for _, f := range replayList {
+			if f.archived != nil {
+				got = append(got, "a:"+f.name)
+			} else {
+				got = append(got, f.name)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetPointInTimeRecoveryBackup(t *testing.T) {
+	backupList := []*api.BackupRaw{
+		{ID: 1, Payload: `{"binlogInfo":{"fileName":"binlog.000001","position":4,"ts":100}}`},
+		{ID: 2, Payload: `{"tableRowCount":{}}`},
+		{ID: 3, Payload: `{"binlogInfo":{"fileName":"binlog.000002","position":4,"ts":300}}`},
+		{ID: 4, Payload: `{"binlogInfo":{"fileName":"binlog.000002","position":8,"ts":200}}`},
+	}
+	tests := []struct {
+		pointInTimeTs int64
+		wantID        int
+	}{
+		{99, 0},
+		{100, 1},
+		{250, 4},
+		{300, 3},
+		{1000, 3},
+	}
+
+	for _, test := range tests {
+		gotID := 0
+		if backup := getPointInTimeRecoveryBackup(backupList, test.pointInTimeTs); backup != nil {
+			gotID = backup.ID
+		}
+		if gotID != test.wantID {
+			t.Errorf("getPointInTimeRecoveryBackup(%d) = backup %d, want backup %d", test.pointInTimeTs, gotID, test.wantID)
+		}
+	}
+}
+
+func TestGetBinlogArchiveList(t *testing.T) {
+	archivedList := []*api.BinlogFile{
+		// Archived before the server UUID is recorded.
+		{Name: "binlog.000001"},
+		{Name: "binlog.000002", ServerUUID: "a", FileSize: 100},
+		// Archived before RESET MASTER.
+		{Name: "binlog.000003", ServerUUID: "a", FileSize: 100},
+	}
+	serverList := []*mysql.BinlogFile{
+		{Name: "binlog.000001", Size: 200},
+		{Name: "binlog.000002", Size: 100},
+		{Name: "binlog.000003", Size: 200},
+	}
+	tests := []struct {
+		serverUUID string
+		want       []string
+	}{
+		{"a", []string{"binlog.000003"}},
+		// The MySQL server is rebuilt.
+		{"b", []string{"binlog.000002", "binlog.000003"}},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, f := range getBinlogArchiveList(test.serverUUID, archivedList, serverList) {
+			got = append(got, f.Name)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("getBinlogArchiveList(%q) = %v, want %v", test.serverUUID, got, test.want)
+		}
+	}
+}
+
+func TestGetBinlogReplayArchivedList(t *testing.T) {
+	archivedList := []*api.BinlogFile{
+		{ID: 1, CreatedTs: 100, Name: "binlog.000001", ServerUUID: "a"},
+		{ID: 2, CreatedTs: 300, Name: "binlog.000001", ServerUUID: "a"},
+		{ID: 3, CreatedTs: 300, Name: "binlog.000002", ServerUUID: "b"},
+		{ID: 4, CreatedTs: 400, Name: "binlog.000002"},
+	}
+	backup := &api.Backup{CreatedTs: 200}
+	tests := []struct {
+		serverUUID string
+		wantIDList []int
+	}{
+		{"a", []int{2, 4}},
+		{"", []int{2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		var got []int
+		for _, f := range getBinlogReplayArchivedList(archivedList, backup, &api.BackupBinlogInfo{ServerUUID: test.serverUUID}) {
+			got = append(got, f.ID)
+		}
+		if !reflect.DeepEqual(got, test.wantIDList) {
+			t.Errorf("getBinlogReplayArchivedList(%q) = %v, want %v", test.serverUUID, got, test.wantIDList)
+		}
+	}
+}
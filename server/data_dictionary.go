@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/dictionary"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerDataDictionaryRoutes(g *echo.Group) {
+	// Exports the data dictionary of a database from the synced schema.
+	// The format query parameter is one of markdown (default), html and json.
+	g.GET("/database/:id/dictionary", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		format, err := getDataDictionaryFormat(c)
+		if err != nil {
+			return err
+		}
+
+		database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		return s.renderDataDictionary(ctx, c, format, fmt.Sprintf("Data dictionary of %s", database.Name), database.Name, []*api.Database{database})
+	})
+
+	// Exports the data dictionary of all databases in a project from the synced schema.
+	g.GET("/project/:projectID/dictionary", func(c echo.Context) error {
+		ctx := context.Background()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		format, err := getDataDictionaryFormat(c)
+		if err != nil {
+			return err
+		}
+
+		project, err := s.composeProjectByID(ctx, projectID)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+
+		dbRawList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{
+			ProjectID: &projectID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases in project ID: %v", projectID)).SetInternal(err)
+		}
+		var dbList []*api.Database
+		for _, dbRaw := range dbRawList {
+			database, err := s.composeDatabaseRelationship(ctx, dbRaw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compose databases relation for ID %v", dbRaw.ID)).SetInternal(err)
+			}
+			dbList = append(dbList, database)
+		}
+
+		return s.renderDataDictionary(ctx, c, format, fmt.Sprintf("Data dictionary of project %s", project.Name), project.Key, dbList)
+	})
+}
+
+func getDataDictionaryFormat(c echo.Context) (dictionary.Format, error) {
+	name := c.QueryParam("format")
+	if name == "" {
+		return dictionary.Markdown, nil
+	}
+	format, err := dictionary.ParseFormat(name)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return format, nil
+}
+
+// renderDataDictionary writes the data dictionary of the databases as an attachment named after filename.
+func (s *Server) renderDataDictionary(ctx context.Context, c echo.Context, format dictionary.Format, title, filename string, dbList []*api.Database) error {
+	dict := &dictionary.Dictionary{Title: title}
+	for _, database := range dbList {
+		schema, err := s.getSyncedSchema(ctx, database)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the synced schema of database %q", database.Name)).SetInternal(err)
+		}
+		dictDatabase := dictionary.NewDatabase(schema)
+		dictDatabase.Environment = database.Instance.Environment.Name
+		dict.DatabaseList = append(dict.DatabaseList, dictDatabase)
+	}
+
+	var buf bytes.Buffer
+	if err := dictionary.Render(&buf, format, dict); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render data dictionary").SetInternal(err)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename+"__DICTIONARY"+format.Extension()))
+	return c.Blob(http.StatusOK, format.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db/masking"
+	"go.uber.org/zap"
+)
+
+// getDataMasker returns the masker of the SQL editor statement, or nil if nothing is masked for the role,
+// either because the role is exempted by the data masking policy of the instance environment or because
+// there is no sensitive column.
+func (s *Server) getDataMasker(ctx context.Context, role api.Role, instance *api.Instance, databaseName, statement string) (*masking.Masker, error) {
+	policy, err := s.PolicyService.GetDataMaskingPolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data masking policy for environment ID %v, error: %w", instance.EnvironmentID, err)
+	}
+	if policy.IsExempted(role) {
+		return nil, nil
+	}
+	patternList, err := policy.CompilePatternList()
+	if err != nil {
+		return nil, err
+	}
+	sensitiveColumnRawList, err := s.SensitiveColumnService.FindSensitiveColumnList(ctx, &api.SensitiveColumnFind{InstanceID: &instance.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sensitive column list for instance ID %v, error: %w", instance.ID, err)
+	}
+	if len(patternList) == 0 && len(sensitiveColumnRawList) == 0 {
+		return nil, nil
+	}
+
+	databaseRawList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{InstanceID: &instance.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database list for instance ID %v, error: %w", instance.ID, err)
+	}
+	databaseNameMap := make(map[int]string)
+	for _, databaseRaw := range databaseRawList {
+		databaseNameMap[databaseRaw.ID] = databaseRaw.Name
+	}
+	var sensitiveColumnList []*masking.SensitiveColumn
+	for _, raw := range sensitiveColumnRawList {
+		sensitiveColumnList = append(sensitiveColumnList, &masking.SensitiveColumn{
+			Database: databaseNameMap[raw.DatabaseID],
+			Table:    raw.TableName,
+			Column:   raw.ColumnName,
+			Type:     raw.MaskingType,
+		})
+	}
+
+	masker := masking.NewMasker(&masking.Config{
+		Engine:              instance.Engine,
+		Database:            databaseName,
+		SensitiveColumnList: sensitiveColumnList,
+		PatternList:         patternList,
+		Catalog:             s.newDataMaskingCatalog(ctx, databaseRawList),
+	}, statement)
+	if err := masker.Err(); err != nil {
+		s.l.Debug("Failed to resolve the result columns, mask all of them",
+			zap.String("instance_name", instance.Name),
+			zap.String("database_name", databaseName),
+			zap.String("statement", statement),
+			zap.Error(err))
+	}
+	return masker, nil
+}
+
+// newDataMaskingCatalog returns the catalog to expand SELECT * from the synced schema of the databases.
+func (s *Server) newDataMaskingCatalog(ctx context.Context, databaseRawList []*api.DatabaseRaw) masking.Catalog {
+	// The tables of each database, keyed by the lower case name.
+	cache := make(map[int]map[string][]string)
+	return func(databaseName, tableName string) ([]string, bool) {
+		for _, databaseRaw := range databaseRawList {
+			if !strings.EqualFold(databaseRaw.Name, databaseName) {
+				continue
+			}
+			tableMap, ok := cache[databaseRaw.ID]
+			if !ok {
+				var err error
+				tableMap, err = s.getDataMaskingTableMap(ctx, databaseRaw.ID)
+				if err != nil {
+					s.l.Warn("Failed to load the synced schema for data masking",
+						zap.String("database_name", databaseRaw.Name),
+						zap.Error(err))
+				}
+				cache[databaseRaw.ID] = tableMap
+			}
+			columnNameList, ok := tableMap[strings.ToLower(tableName)]
+			return columnNameList, ok
+		}
+		return nil, false
+	}
+}
+
+// getDataMaskingTableMap returns the column names ordered by position of each table in the database.
+func (s *Server) getDataMaskingTableMap(ctx context.Context, databaseID int) (map[string][]string, error) {
+	tableRawList, err := s.TableService.FindTableList(ctx, &api.TableFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch table list for database ID %v, error: %w", databaseID, err)
+	}
+	columnList, err := s.ColumnService.FindColumnList(ctx, &api.ColumnFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch column list for database ID %v, error: %w", databaseID, err)
+	}
+	sort.Slice(columnList, func(i, j int) bool {
+		return columnList[i].Position < columnList[j].Position
+	})
+
+	tableNameMap := make(map[int]string)
+	tableMap := make(map[string][]string)
+	for _, tableRaw := range tableRawList {
+		tableNameMap[tableRaw.ID] = strings.ToLower(tableRaw.Name)
+		tableMap[strings.ToLower(tableRaw.Name)] = []string{}
+	}
+	for _, column := range columnList {
+		if name, ok := tableNameMap[column.TableID]; ok {
+			tableMap[name] = append(tableMap[name], column.Name)
+		}
+	}
+	return tableMap, nil
+}
+
+// maskQueryResult masks the rows in place of the result returned by db.Driver.Query,
+// which is the column names, the column type names and the rows.
+func maskQueryResult(masker *masking.Masker, rowSet []interface{}) {
+	if len(rowSet) != 3 {
+		return
+	}
+	columnNameList, _ := rowSet[0].([]string)
+	typeList := masker.TypeList(columnNameList)
+	dataList, _ := rowSet[2].([]interface{})
+	for _, data := range dataList {
+		if row, ok := data.([]interface{}); ok {
+			masking.MaskRow(row, typeList)
+		}
+	}
+}
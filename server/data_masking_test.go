@@ -0,0 +1,38 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/masking"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestMaskQueryResult(t *testing.T) {
+	masker := masking.NewMasker(&masking.Config{
+		Engine:   db.MySQL,
+		Database: "hr",
+		SensitiveColumnList: []*masking.SensitiveColumn{
+			{Database: "hr", Table: "employee", Column: "email", Type: masking.Full},
+		},
+	}, "SELECT e.id, e.email AS contact FROM employee AS e")
+	rowSet := []interface{}{
+		[]string{"id", "contact"},
+		[]string{"INT", "VARCHAR"},
+		[]interface{}{
+			[]interface{}{int64(1), "alice@example.com"},
+			[]interface{}{int64(2), nil},
+		},
+	}
+
+	maskQueryResult(masker, rowSet)
+	want := []interface{}{
+		[]interface{}{int64(1), "******"},
+		[]interface{}{int64(2), nil},
+	}
+	if !reflect.DeepEqual(rowSet[2], want) {
+		t.Errorf("got rows %v, want %v", rowSet[2], want)
+	}
+}
@@ -413,6 +413,36 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch index list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
 			}
 			table.IndexList = indexList
+
+			foreignKeyFind := &api.ForeignKeyFind{
+				DatabaseID: &id,
+				TableID:    &table.ID,
+			}
+			foreignKeyList, err := s.ForeignKeyService.FindForeignKeyList(ctx, foreignKeyFind)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch foreign key list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+			}
+			table.ForeignKeyList = foreignKeyList
+
+			checkConstraintFind := &api.CheckConstraintFind{
+				DatabaseID: &id,
+				TableID:    &table.ID,
+			}
+			checkConstraintList, err := s.CheckConstraintService.FindCheckConstraintList(ctx, checkConstraintFind)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch check constraint list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+			}
+			table.CheckConstraintList = checkConstraintList
+
+			triggerFind := &api.TriggerFind{
+				DatabaseID: &id,
+				TableID:    &table.ID,
+			}
+			triggerList, err := s.TriggerService.FindTriggerList(ctx, triggerFind)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch trigger list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+			}
+			table.TriggerList = triggerList
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
@@ -479,6 +509,36 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		}
 		table.IndexList = indexList
 
+		foreignKeyFind := &api.ForeignKeyFind{
+			DatabaseID: &id,
+			TableID:    &table.ID,
+		}
+		foreignKeyList, err := s.ForeignKeyService.FindForeignKeyList(ctx, foreignKeyFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch foreign key list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+		}
+		table.ForeignKeyList = foreignKeyList
+
+		checkConstraintFind := &api.CheckConstraintFind{
+			DatabaseID: &id,
+			TableID:    &table.ID,
+		}
+		checkConstraintList, err := s.CheckConstraintService.FindCheckConstraintList(ctx, checkConstraintFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch check constraint list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+		}
+		table.CheckConstraintList = checkConstraintList
+
+		triggerFind := &api.TriggerFind{
+			DatabaseID: &id,
+			TableID:    &table.ID,
+		}
+		triggerList, err := s.TriggerService.FindTriggerList(ctx, triggerFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch trigger list for database id: %d, table name: %s", id, table.Name)).SetInternal(err)
+		}
+		table.TriggerList = triggerList
+
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, table); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch table response: %v", id)).SetInternal(err)
@@ -527,6 +587,88 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.GET("/database/:id/routine", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		databaseFind := &api.DatabaseFind{
+			ID: &id,
+		}
+		database, err := s.composeDatabaseByFind(ctx, databaseFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		routineFind := &api.RoutineFind{
+			DatabaseID: &id,
+		}
+		routineList, err := s.RoutineService.FindRoutineList(ctx, routineFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch routine list for database id: %d", id)).SetInternal(err)
+		}
+
+		for _, routine := range routineList {
+			routine.Database = database
+
+			if err := s.composeRoutineRelationship(ctx, routine); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose routine relationship").SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, routineList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch routine list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/sequence", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		databaseFind := &api.DatabaseFind{
+			ID: &id,
+		}
+		database, err := s.composeDatabaseByFind(ctx, databaseFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		sequenceFind := &api.SequenceFind{
+			DatabaseID: &id,
+		}
+		sequenceList, err := s.SequenceService.FindSequenceList(ctx, sequenceFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sequence list for database id: %d", id)).SetInternal(err)
+		}
+
+		for _, sequence := range sequenceList {
+			sequence.Database = database
+
+			if err := s.composeSequenceRelationship(ctx, sequence); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose sequence relationship").SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, sequenceList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch sequence list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.POST("/database/:id/backup", func(c echo.Context) error {
 		ctx := context.Background()
 		id, err := strconv.Atoi(c.Param("id"))
@@ -551,9 +693,8 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
 		}
 
-		backupCreate.Path, err = getAndCreateBackupPath(s.dataDir, database, backupCreate.Name)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create backup directory for database ID: %v", id)).SetInternal(err)
+		if err := s.prepareBackupCreate(ctx, database, backupCreate); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create backup path for database ID: %v", id)).SetInternal(err)
 		}
 
 		driver, err := getAdminDatabaseDriver(ctx, database.Instance, database.Name, s.l)
@@ -1026,28 +1167,22 @@ func (s *Server) composeDatabaseRelationship(ctx context.Context, raw *api.Datab
 
 	rowStatus := api.Normal
 	anomalyListRaw, err := s.AnomalyService.FindAnomalyList(ctx, &api.AnomalyFind{
-		RowStatus:  &rowStatus,
-		DatabaseID: &db.ID,
+		RowStatus:      &rowStatus,
+		DatabaseID:     &db.ID,
+		ExcludeSnoozed: true,
 	})
 	if err != nil {
 		return nil, err
 	}
 	var anomalyList []*api.Anomaly
 	for _, anomalyRaw := range anomalyListRaw {
-		anomalyList = append(anomalyList, anomalyRaw.ToAnomaly())
-	}
-	// TODO(dragonly): implement composeAnomalyRelationship
-	db.AnomalyList = anomalyList
-	for _, anomaly := range db.AnomalyList {
-		anomaly.Creator, err = s.composePrincipalByID(ctx, anomaly.CreatorID)
-		if err != nil {
-			return nil, err
-		}
-		anomaly.Updater, err = s.composePrincipalByID(ctx, anomaly.UpdaterID)
+		anomaly, err := s.composeAnomalyRelationship(ctx, anomalyRaw)
 		if err != nil {
 			return nil, err
 		}
+		anomalyList = append(anomalyList, anomaly)
 	}
+	db.AnomalyList = anomalyList
 
 	rowStatus = api.Normal
 	labelRawList, err := s.LabelService.FindDatabaseLabelList(ctx, &api.DatabaseLabelFind{
@@ -1118,6 +1253,36 @@ func (s *Server) composeViewRelationship(ctx context.Context, view *api.View) er
 	return nil
 }
 
+func (s *Server) composeRoutineRelationship(ctx context.Context, routine *api.Routine) error {
+	var err error
+
+	routine.Creator, err = s.composePrincipalByID(ctx, routine.CreatorID)
+	if err != nil {
+		return err
+	}
+
+	routine.Updater, err = s.composePrincipalByID(ctx, routine.UpdaterID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) composeSequenceRelationship(ctx context.Context, sequence *api.Sequence) error {
+	var err error
+
+	sequence.Creator, err = s.composePrincipalByID(ctx, sequence.CreatorID)
+	if err != nil {
+		return err
+	}
+
+	sequence.Updater, err = s.composePrincipalByID(ctx, sequence.UpdaterID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // composeBackupByID will compose the backup by backup ID.
 func (s *Server) composeBackupByID(ctx context.Context, id int) (*api.Backup, error) {
 	backupFind := &api.BackupFind{
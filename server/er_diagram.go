@@ -0,0 +1,66 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db/erd"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerERDiagramRoutes(g *echo.Group) {
+	// Generates the ER diagram of a database from the synced schema.
+	// The format query parameter is one of mermaid (default) and dot.
+	// The table query parameter is a comma separated list of tables to include, and can be repeated. All tables are included if empty.
+	g.GET("/database/:id/erd", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		format := erd.Mermaid
+		if name := c.QueryParam("format"); name != "" {
+			format, err = erd.ParseFormat(name)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			}
+		}
+		var tableList []string
+		for _, param := range c.QueryParams()["table"] {
+			for _, table := range strings.Split(param, ",") {
+				if table = strings.TrimSpace(table); table != "" {
+					tableList = append(tableList, table)
+				}
+			}
+		}
+
+		database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		schema, err := s.getSyncedSchema(ctx, database)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the synced schema of database %q", database.Name)).SetInternal(err)
+		}
+		diagram, err := erd.NewDiagram(schema, tableList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := erd.Render(&buf, format, diagram); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render ER diagram").SetInternal(err)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", database.Name+"__ERD"+format.Extension()))
+		return c.Blob(http.StatusOK, format.ContentType(), buf.Bytes())
+	})
+}
@@ -57,7 +57,8 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 					zap.Error(err))
 			}
 			// Try immediately sync the engine version and schema after instance creation.
-			s.syncEngineVersionAndSchema(ctx, instance)
+			incremental := false
+			s.syncEngineVersionAndSchema(ctx, instance, incremental)
 		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
@@ -183,7 +184,8 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 						zap.String("engine", string(instancePatchedRaw.Engine)),
 						zap.Error(err))
 				}
-				s.syncEngineVersionAndSchema(ctx, instancePatched)
+				incremental := false
+				s.syncEngineVersionAndSchema(ctx, instancePatched, incremental)
 			}
 		}
 
@@ -467,27 +469,23 @@ func (s *Server) composeInstanceRelationship(ctx context.Context, raw *api.Insta
 
 	rowStatus := api.Normal
 	anomalyListRaw, err := s.AnomalyService.FindAnomalyList(ctx, &api.AnomalyFind{
-		RowStatus:    &rowStatus,
-		InstanceID:   &instance.ID,
-		InstanceOnly: true,
+		RowStatus:      &rowStatus,
+		InstanceID:     &instance.ID,
+		InstanceOnly:   true,
+		ExcludeSnoozed: true,
 	})
 	if err != nil {
 		return nil, err
 	}
 	var anomalyList []*api.Anomaly
 	for _, anomalyRaw := range anomalyListRaw {
-		anomalyList = append(anomalyList, anomalyRaw.ToAnomaly())
-	}
-	// TODO(dragonly): implement composeAnomalyRelationship
-	instance.AnomalyList = anomalyList
-	for _, anomaly := range instance.AnomalyList {
-		if anomaly.Creator, err = s.composePrincipalByID(ctx, anomaly.CreatorID); err != nil {
-			return nil, err
-		}
-		if anomaly.Updater, err = s.composePrincipalByID(ctx, anomaly.UpdaterID); err != nil {
+		anomaly, err := s.composeAnomalyRelationship(ctx, anomalyRaw)
+		if err != nil {
 			return nil, err
 		}
+		anomalyList = append(anomalyList, anomaly)
 	}
+	instance.AnomalyList = anomalyList
 
 	dataSourceRawList, err := s.DataSourceService.FindDataSourceList(ctx, &api.DataSourceFind{
 		InstanceID: &instance.ID,
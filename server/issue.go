@@ -142,6 +142,10 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if issue == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Unable to find issue ID to update: %d", id))
 		}
+		// The requested database and expiration can't be changed once the request is approved.
+		if issue.Type == api.IssueDataSourceRequest && issuePatch.Payload != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Cannot update the payload of the data source request issue")
+		}
 
 		updatedIssue, err := s.IssueService.PatchIssue(ctx, issuePatch)
 		if err != nil {
@@ -235,6 +239,11 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		if issue == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
 		}
+		// Resolving the data source request issue approves it, which is only up to the assignee other than the requester.
+		if issue.Type == api.IssueDataSourceRequest && issueStatusPatch.Status == api.IssueDone &&
+			(issueStatusPatch.UpdaterID != issue.AssigneeID || issueStatusPatch.UpdaterID == issue.CreatorID) {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the assignee other than the requester can approve the data source request")
+		}
 
 		updatedIssue, err := s.changeIssueStatus(ctx, issue, issueStatusPatch.Status, issueStatusPatch.UpdaterID, issueStatusPatch.Comment)
 		if err != nil {
@@ -665,19 +674,37 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			taskStatus = api.TaskPending
 		}
 
+		// For the point-in-time recovery, restore the closest backup of the source database taken before the point
+		// in time, and replay the binlog up to the point in time.
+		if m.PointInTimeTs != 0 {
+			backupID, err := s.getPointInTimeRecoveryBackupID(ctx, instance, &m)
+			if err != nil {
+				return nil, err
+			}
+			m.BackupID = backupID
+		}
+
 		if m.BackupID != 0 {
 			backupRaw, err := s.BackupService.FindBackup(ctx, &api.BackupFind{ID: &m.BackupID})
 			if err != nil {
 				return nil, fmt.Errorf("failed to find backup %v", m.BackupID)
 			}
+			if backupRaw != nil && backupRaw.Status == api.BackupStatusExpired {
+				return nil, fmt.Errorf("backup %q has expired and its data has been removed", backupRaw.Name)
+			}
 			restorePayload := api.TaskDatabaseRestorePayload{}
 			restorePayload.DatabaseName = m.DatabaseName
 			restorePayload.BackupID = m.BackupID
+			restorePayload.PointInTimeTs = m.PointInTimeTs
 			restoreBytes, err := json.Marshal(restorePayload)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create restore database task, unable to marshal payload %w", err)
 			}
 
+			restoreTaskName := fmt.Sprintf("Restore backup %v", backupRaw.Name)
+			if m.PointInTimeTs != 0 {
+				restoreTaskName = fmt.Sprintf("Restore backup %v and replay binlog to %s", backupRaw.Name, formatPointInTime(m.PointInTimeTs))
+			}
 			pipelineCreate = &api.PipelineCreate{
 				Name: fmt.Sprintf("Pipeline - Create database %v from backup %v", payload.DatabaseName, backupRaw.Name),
 				StageList: []api.StageCreate{
@@ -701,7 +728,7 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 						TaskList: []api.TaskCreate{
 							{
 								InstanceID:   m.InstanceID,
-								Name:         fmt.Sprintf("Restore backup %v", backupRaw.Name),
+								Name:         restoreTaskName,
 								Status:       api.TaskPending,
 								Type:         api.TaskDatabaseRestore,
 								DatabaseName: payload.DatabaseName,
@@ -879,6 +906,42 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			}
 			pipelineCreate = pc
 		}
+	case issueCreate.Type == api.IssueDataSourceRequest:
+		m := api.DataSourceRequestContext{}
+		if err := json.Unmarshal([]byte(issueCreate.CreateContext), &m); err != nil {
+			return nil, err
+		}
+		now := time.Now().Unix()
+		if m.ExpireTs <= now {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, the data source request should expire in the future")
+		}
+		database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &m.DatabaseID})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", m.DatabaseID)).SetInternal(err)
+		}
+		if database == nil {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", m.DatabaseID))
+		}
+		if database.ProjectID != issueCreate.ProjectID {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, database %q doesn't belong to project ID %d", database.Name, issueCreate.ProjectID))
+		}
+		policy, err := s.PolicyService.GetSQLQueryAccessPolicy(ctx, database.Instance.EnvironmentID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch SQL query access policy of environment ID: %v", database.Instance.EnvironmentID)).SetInternal(err)
+		}
+		if maxDuration := policy.MaxAccessRequestDurationSeconds; maxDuration > 0 && m.ExpireTs > now+maxDuration {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to create issue, the data source request should expire within %d seconds by the SQL query access policy of environment %q", maxDuration, database.Instance.Environment.Name))
+		}
+		// The request is checked against the payload by the SQL query access policy.
+		payload, err := json.Marshal(m)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal data source request payload").SetInternal(err)
+		}
+		issueCreate.Payload = string(payload)
+		// The request has nothing to execute, so the pipeline has no stage.
+		pipelineCreate = &api.PipelineCreate{
+			Name: fmt.Sprintf("Request querying database %q", database.Name),
+		}
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue type %q", issueCreate.Type))
 	}
@@ -891,7 +954,7 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			break
 		}
 	}
-	if !hasTask {
+	if !hasTask && issueCreate.Type != api.IssueDataSourceRequest {
 		err := fmt.Errorf("issue has no task to be executed")
 		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 	}
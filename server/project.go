@@ -210,6 +210,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create linked repository request: %s", err.Error()))
 		}
 
+		if err := api.ValidateRepositoryDictionaryPathTemplate(repositoryCreate.DictionaryPathTemplate, project.TenantMode); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create linked repository request: %s", err.Error()))
+		}
+
 		vcsFind := &api.VCSFind{
 			ID: &repositoryCreate.VCSID,
 		}
@@ -353,6 +357,12 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			}
 		}
 
+		if repoPatch.DictionaryPathTemplate != nil {
+			if err := api.ValidateRepositoryDictionaryPathTemplate(*repoPatch.DictionaryPathTemplate, project.TenantMode); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted patch linked repository request: %s", err.Error()))
+			}
+		}
+
 		// Remove enclosing /
 		if repoPatch.BaseDirectory != nil {
 			baseDir := strings.Trim(*repoPatch.BaseDirectory, "/")
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultQueryHistoryLimit is the default number of query histories returned.
+	defaultQueryHistoryLimit = 100
+	// defaultQueryHistoryRerunLimit is the default maximum row count of the re-run query, which is the same as the SQL editor.
+	defaultQueryHistoryRerunLimit = 50
+)
+
+func (s *Server) registerQueryHistoryRoutes(g *echo.Group) {
+	// Lists the query histories of the current principal, the latest first.
+	g.GET("/sql/history", func(c echo.Context) error {
+		ctx := context.Background()
+		creatorID := c.Get(getPrincipalIDContextKey()).(int)
+		limit := defaultQueryHistoryLimit
+		queryHistoryFind := &api.QueryHistoryFind{
+			CreatorID: &creatorID,
+			Limit:     &limit,
+		}
+
+		if instanceIDStr := c.QueryParam("instanceId"); instanceIDStr != "" {
+			instanceID, err := strconv.Atoi(instanceIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter instanceId is not a number: %s", instanceIDStr)).SetInternal(err)
+			}
+			queryHistoryFind.InstanceID = &instanceID
+		}
+		if databaseName := c.QueryParam("databaseName"); databaseName != "" {
+			queryHistoryFind.DatabaseName = &databaseName
+		}
+		if search := c.QueryParam("search"); search != "" {
+			queryHistoryFind.Search = &search
+		}
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err := strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a positive number: %s", limitStr)).SetInternal(err)
+			}
+			queryHistoryFind.Limit = &limit
+		}
+
+		queryHistoryRawList, err := s.QueryHistoryService.FindQueryHistoryList(ctx, queryHistoryFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch query history list").SetInternal(err)
+		}
+		var queryHistoryList []*api.QueryHistory
+		for _, raw := range queryHistoryRawList {
+			queryHistory, err := s.composeQueryHistoryRelationship(ctx, raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query history relationship: %v", raw.ID)).SetInternal(err)
+			}
+			queryHistoryList = append(queryHistoryList, queryHistory)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, queryHistoryList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal query history list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Re-runs the query history of the current principal with the same parameters, which is recorded as a new query history.
+	g.POST("/sql/history/:id/rerun", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		limit := defaultQueryHistoryRerunLimit
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a number: %s", limitStr)).SetInternal(err)
+			}
+		}
+
+		// The query history of others is not found, so that its existence isn't disclosed.
+		creatorID := c.Get(getPrincipalIDContextKey()).(int)
+		queryHistoryRaw, err := s.QueryHistoryService.FindQueryHistory(ctx, &api.QueryHistoryFind{ID: &id, CreatorID: &creatorID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query history ID: %v", id)).SetInternal(err)
+		}
+		if queryHistoryRaw == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query history ID not found: %d", id))
+		}
+
+		resultSet, err := s.executeSQL(ctx, c, &api.SQLExecute{
+			InstanceID:    queryHistoryRaw.InstanceID,
+			DatabaseName:  queryHistoryRaw.DatabaseName,
+			Statement:     queryHistoryRaw.Statement,
+			Readonly:      true,
+			Limit:         limit,
+			ParameterList: queryHistoryRaw.ParameterList,
+		})
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func (s *Server) composeQueryHistoryRelationship(ctx context.Context, raw *api.QueryHistoryRaw) (*api.QueryHistory, error) {
+	queryHistory := raw.ToQueryHistory()
+
+	creator, err := s.composePrincipalByID(ctx, queryHistory.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	queryHistory.Creator = creator
+
+	updater, err := s.composePrincipalByID(ctx, queryHistory.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	queryHistory.Updater = updater
+
+	return queryHistory, nil
+}
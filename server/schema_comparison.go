@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerSchemaComparisonRoutes(g *echo.Group) {
+	// Compares the synced schemas of the same database across environments, and across tenants in tenant mode.
+	g.GET("/project/:projectID/schemacompare", func(c echo.Context) error {
+		ctx := context.Background()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		project, err := s.composeProjectByID(ctx, projectID)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project ID not found: %d", projectID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
+		}
+
+		dbRawList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{
+			ProjectID: &projectID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases in project ID: %v", projectID)).SetInternal(err)
+		}
+		var dbList []*api.Database
+		for _, dbRaw := range dbRawList {
+			database, err := s.composeDatabaseRelationship(ctx, dbRaw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compose databases relation for ID %v", dbRaw.ID)).SetInternal(err)
+			}
+			dbList = append(dbList, database)
+		}
+
+		groupList, err := s.getSchemaComparisonGroupList(ctx, project, dbList)
+		if err != nil {
+			return err
+		}
+		report := &api.SchemaComparisonReport{
+			ProjectID: projectID,
+			GroupList: groupList,
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema comparison response for project ID: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// schemaComparisonCandidate is a database to compare in a group.
+type schemaComparisonCandidate struct {
+	entry      *api.SchemaComparisonEntry
+	definition *schemaSnapshotDefinition
+}
+
+// getSchemaComparisonGroupList groups the project databases and compares the schemas in each group.
+// In tenant mode, the databases are grouped by the base database name and ordered by the deployment schedule.
+// Otherwise, the databases are grouped by name and ordered by the environment order.
+func (s *Server) getSchemaComparisonGroupList(ctx context.Context, project *api.Project, dbList []*api.Database) ([]*api.SchemaComparisonGroup, error) {
+	var nameList []string
+	databaseGroups := make(map[string][][]*api.Database)
+	deploymentGroups := make(map[string][]*api.Deployment)
+	if project.TenantMode == api.TenantModeTenant {
+		baseNames := make(map[string]bool)
+		for _, database := range dbList {
+			baseName, err := api.GetBaseDatabaseName(database.Name, project.DBNameTemplate, database.Labels)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the base database name of database %q", database.Name)).SetInternal(err)
+			}
+			baseNames[baseName] = true
+		}
+		nameList = sortedKeys(baseNames)
+		for _, baseName := range nameList {
+			deployments, matrix, err := s.getTenantDatabaseMatrix(ctx, project.ID, project.DBNameTemplate, dbList, baseName)
+			if err != nil {
+				return nil, err
+			}
+			databaseGroups[baseName], deploymentGroups[baseName] = matrix, deployments
+		}
+	} else {
+		names := make(map[string]bool)
+		for _, database := range dbList {
+			names[database.Name] = true
+			databaseGroups[database.Name] = append(databaseGroups[database.Name], []*api.Database{database})
+		}
+		nameList = sortedKeys(names)
+		for _, name := range nameList {
+			group := databaseGroups[name]
+			sort.SliceStable(group, func(i, j int) bool {
+				return group[i][0].Instance.Environment.Order < group[j][0].Instance.Environment.Order
+			})
+		}
+	}
+
+	var groupList []*api.SchemaComparisonGroup
+	for _, name := range nameList {
+		var candidateList []*schemaComparisonCandidate
+		for i, databaseList := range databaseGroups[name] {
+			for _, database := range databaseList {
+				schema, err := s.getSyncedSchema(ctx, database)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the synced schema of database %q", database.Name)).SetInternal(err)
+				}
+				entry := &api.SchemaComparisonEntry{
+					DatabaseID:      database.ID,
+					DatabaseName:    database.Name,
+					InstanceID:      database.InstanceID,
+					EnvironmentID:   database.Instance.EnvironmentID,
+					EnvironmentName: database.Instance.Environment.Name,
+					SchemaVersion:   database.SchemaVersion,
+					SyncStatus:      database.SyncStatus,
+				}
+				if deployments := deploymentGroups[name]; deployments != nil {
+					entry.Deployment = deployments[i].Name
+					var labelList []*api.DatabaseLabel
+					if err := json.Unmarshal([]byte(database.Labels), &labelList); err != nil {
+						return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal labels for database %q", database.Name)).SetInternal(err)
+					}
+					for _, label := range labelList {
+						if label.Key == api.TenantLabelKey {
+							entry.Tenant = label.Value
+						}
+					}
+				}
+				candidateList = append(candidateList, &schemaComparisonCandidate{
+					entry:      entry,
+					definition: newSchemaSnapshotDefinition(newSchemaSnapshotMetadata(schema)),
+				})
+			}
+		}
+		if len(candidateList) > 0 {
+			groupList = append(groupList, compareSchemaComparisonGroup(name, candidateList))
+		}
+	}
+	return groupList, nil
+}
+
+// compareSchemaComparisonGroup compares the databases in a group against the one with the latest schema version.
+// The candidates are in the deployment order, so that the earliest one wins among the databases with the latest schema version.
+func compareSchemaComparisonGroup(name string, candidateList []*schemaComparisonCandidate) *api.SchemaComparisonGroup {
+	reference := candidateList[0]
+	for _, candidate := range candidateList[1:] {
+		if candidate.entry.SchemaVersion > reference.entry.SchemaVersion {
+			reference = candidate
+		}
+	}
+
+	group := &api.SchemaComparisonGroup{
+		Name:                name,
+		ReferenceDatabaseID: reference.entry.DatabaseID,
+	}
+	for _, candidate := range candidateList {
+		entry := candidate.entry
+		entry.ItemList = diffSchemaSnapshotDefinition(reference.definition, candidate.definition)
+		if entry.ItemList == nil {
+			entry.ItemList = []*api.SchemaDriftItem{}
+		}
+		switch {
+		case entry.SchemaVersion < reference.entry.SchemaVersion:
+			entry.Status = api.SchemaComparisonBehind
+		case len(entry.ItemList) > 0:
+			entry.Status = api.SchemaComparisonDifferent
+		default:
+			entry.Status = api.SchemaComparisonInSync
+		}
+		group.EntryList = append(group.EntryList, entry)
+	}
+	return group
+}
+
+// getSyncedSchema returns the database schema from the metadata stored by the schema sync.
+func (s *Server) getSyncedSchema(ctx context.Context, database *api.Database) (*db.Schema, error) {
+	schema := &db.Schema{
+		Name:         database.Name,
+		CharacterSet: database.CharacterSet,
+		Collation:    database.Collation,
+	}
+
+	tableRawList, err := s.TableService.FindTableList(ctx, &api.TableFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch table list for database ID %v, error: %w", database.ID, err)
+	}
+	columnList, err := s.ColumnService.FindColumnList(ctx, &api.ColumnFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch column list for database ID %v, error: %w", database.ID, err)
+	}
+	indexList, err := s.IndexService.FindIndexList(ctx, &api.IndexFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch index list for database ID %v, error: %w", database.ID, err)
+	}
+	foreignKeyList, err := s.ForeignKeyService.FindForeignKeyList(ctx, &api.ForeignKeyFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch foreign key list for database ID %v, error: %w", database.ID, err)
+	}
+	checkConstraintList, err := s.CheckConstraintService.FindCheckConstraintList(ctx, &api.CheckConstraintFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch check constraint list for database ID %v, error: %w", database.ID, err)
+	}
+	triggerList, err := s.TriggerService.FindTriggerList(ctx, &api.TriggerFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trigger list for database ID %v, error: %w", database.ID, err)
+	}
+	viewList, err := s.ViewService.FindViewList(ctx, &api.ViewFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch view list for database ID %v, error: %w", database.ID, err)
+	}
+	routineList, err := s.RoutineService.FindRoutineList(ctx, &api.RoutineFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch routine list for database ID %v, error: %w", database.ID, err)
+	}
+	sequenceList, err := s.SequenceService.FindSequenceList(ctx, &api.SequenceFind{DatabaseID: &database.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sequence list for database ID %v, error: %w", database.ID, err)
+	}
+
+	tableColumns := make(map[int][]db.Column)
+	for _, column := range columnList {
+		tableColumns[column.TableID] = append(tableColumns[column.TableID], db.Column{
+			Name:         column.Name,
+			Position:     column.Position,
+			Default:      column.Default,
+			Nullable:     column.Nullable,
+			Type:         column.Type,
+			CharacterSet: column.CharacterSet,
+			Collation:    column.Collation,
+			Comment:      column.Comment,
+		})
+	}
+	tableIndexes := make(map[int][]db.Index)
+	for _, index := range indexList {
+		tableIndexes[index.TableID] = append(tableIndexes[index.TableID], db.Index{
+			Name:       index.Name,
+			Expression: index.Expression,
+			Position:   index.Position,
+			Type:       index.Type,
+			Unique:     index.Unique,
+			Primary:    index.Primary,
+			Visible:    index.Visible,
+			Comment:    index.Comment,
+		})
+	}
+	tableForeignKeys := make(map[int][]db.ForeignKey)
+	for _, foreignKey := range foreignKeyList {
+		tableForeignKeys[foreignKey.TableID] = append(tableForeignKeys[foreignKey.TableID], db.ForeignKey{
+			Name:             foreignKey.Name,
+			Column:           foreignKey.Column,
+			Position:         foreignKey.Position,
+			ReferencedTable:  foreignKey.ReferencedTable,
+			ReferencedColumn: foreignKey.ReferencedColumn,
+			OnUpdate:         foreignKey.OnUpdate,
+			OnDelete:         foreignKey.OnDelete,
+		})
+	}
+	tableCheckConstraints := make(map[int][]db.CheckConstraint)
+	for _, checkConstraint := range checkConstraintList {
+		tableCheckConstraints[checkConstraint.TableID] = append(tableCheckConstraints[checkConstraint.TableID], db.CheckConstraint{
+			Name:       checkConstraint.Name,
+			Expression: checkConstraint.Expression,
+		})
+	}
+	tableTriggers := make(map[int][]db.Trigger)
+	for _, trigger := range triggerList {
+		tableTriggers[trigger.TableID] = append(tableTriggers[trigger.TableID], db.Trigger{
+			Name:      trigger.Name,
+			Timing:    trigger.Timing,
+			Event:     trigger.Event,
+			Statement: trigger.Statement,
+		})
+	}
+	for _, table := range tableRawList {
+		schema.TableList = append(schema.TableList, db.Table{
+			Name:                table.Name,
+			Type:                table.Type,
+			Engine:              table.Engine,
+			Collation:           table.Collation,
+			CreateOptions:       table.CreateOptions,
+			Comment:             table.Comment,
+			ColumnList:          tableColumns[table.ID],
+			IndexList:           tableIndexes[table.ID],
+			ForeignKeyList:      tableForeignKeys[table.ID],
+			CheckConstraintList: tableCheckConstraints[table.ID],
+			TriggerList:         tableTriggers[table.ID],
+		})
+	}
+	for _, view := range viewList {
+		schema.ViewList = append(schema.ViewList, db.View{
+			Name:       view.Name,
+			Definition: view.Definition,
+			Comment:    view.Comment,
+		})
+	}
+	for _, routine := range routineList {
+		schema.RoutineList = append(schema.RoutineList, db.Routine{
+			Name:       routine.Name,
+			Type:       routine.Type,
+			Definition: routine.Definition,
+			Comment:    routine.Comment,
+		})
+	}
+	for _, sequence := range sequenceList {
+		schema.SequenceList = append(schema.SequenceList, db.Sequence{
+			Name:       sequence.Name,
+			DataType:   sequence.DataType,
+			StartValue: sequence.StartValue,
+			Increment:  sequence.Increment,
+			MinValue:   sequence.MinValue,
+			MaxValue:   sequence.MaxValue,
+			Cycle:      sequence.Cycle,
+			CacheSize:  sequence.CacheSize,
+		})
+	}
+	return schema, nil
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestCompareSchemaComparisonGroup(t *testing.T) {
+	newCandidate := func(id int, version string, columnType string) *schemaComparisonCandidate {
+		schema := &db.Schema{
+			Name: "db",
+			TableList: []db.Table{
+				{Name: "author", ColumnList: []db.Column{{Name: "id", Position: 1, Type: columnType}}},
+			},
+		}
+		return &schemaComparisonCandidate{
+			entry:      &api.SchemaComparisonEntry{DatabaseID: id, SchemaVersion: version},
+			definition: newSchemaSnapshotDefinition(newSchemaSnapshotMetadata(schema)),
+		}
+	}
+	group := compareSchemaComparisonGroup("db", []*schemaComparisonCandidate{
+		newCandidate(101, "0002", "bigint"),
+		newCandidate(102, "0002", "int"),
+		newCandidate(103, "0002", "bigint"),
+		newCandidate(104, "0001", "int"),
+	})
+
+	if group.ReferenceDatabaseID != 101 {
+		t.Errorf("compareSchemaComparisonGroup() reference = %d, want 101", group.ReferenceDatabaseID)
+	}
+	wantStatus := []api.SchemaComparisonStatus{
+		api.SchemaComparisonInSync,
+		api.SchemaComparisonDifferent,
+		api.SchemaComparisonInSync,
+		api.SchemaComparisonBehind,
+	}
+	wantItemCount := []int{0, 1, 0, 1}
+	for i, entry := range group.EntryList {
+		if entry.Status != wantStatus[i] {
+			t.Errorf("compareSchemaComparisonGroup() entry %d status = %s, want %s", entry.DatabaseID, entry.Status, wantStatus[i])
+		}
+		if len(entry.ItemList) != wantItemCount[i] {
+			t.Errorf("compareSchemaComparisonGroup() entry %d has %d changes, want %d", entry.DatabaseID, len(entry.ItemList), wantItemCount[i])
+		}
+	}
+	if item := group.EntryList[1].ItemList[0]; item.Expect != "bigint NOT NULL" || item.Actual != "int NOT NULL" {
+		t.Errorf("compareSchemaComparisonGroup() change = %q -> %q, want %q -> %q", item.Expect, item.Actual, "bigint NOT NULL", "int NOT NULL")
+	}
+}
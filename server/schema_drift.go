@@ -0,0 +1,705 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// The schema dumps are parsed on a best-effort basis, only the statements describing tables, columns,
+// indexes and views are recognized. Changes to other objects such as routines and triggers only show up
+// in the unified text diff.
+var (
+	createTableRegexp   = regexp.MustCompile(`(?is)^CREATE\s+(?:TEMPORARY\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\S+?)\s*\((.*)\)(.*)$`)
+	createIndexRegexp   = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(\S+)\s+ON\s+(?:ONLY\s+)?([^\s(]+)`)
+	createViewRegexp    = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:ALGORITHM\s*=\s*\S+\s+)?(?:DEFINER\s*=\s*\S+\s+)?(?:SQL\s+SECURITY\s+\S+\s+)?(?:(?:TEMP|TEMPORARY|MATERIALIZED|RECURSIVE)\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?(\S+)`)
+	addConstraintRegexp = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:ONLY\s+)?(\S+)\s+ADD\s+CONSTRAINT\s+(\S+)\s+(.*)$`)
+	delimiterRegexp     = regexp.MustCompile(`(?i)^DELIMITER\s+(\S+)$`)
+	// The table element keywords of the inline indexes and constraints, the rest elements are columns.
+	inlineIndexRegexp      = regexp.MustCompile("(?is)^(?:PRIMARY\\s+KEY|UNIQUE(?:\\s+(?:KEY|INDEX))?|KEY|INDEX|FULLTEXT(?:\\s+(?:KEY|INDEX))?|SPATIAL(?:\\s+(?:KEY|INDEX))?)\\b(?:\\s+([`\"]?[^\\s(]+))?\\s*\\(")
+	inlineConstraintRegexp = regexp.MustCompile(`(?is)^(?:CONSTRAINT|FOREIGN\s+KEY|CHECK)\b`)
+	keyConstraintRegexp    = regexp.MustCompile(`(?is)^(?:PRIMARY\s+KEY|UNIQUE)\b`)
+)
+
+// schemaDriftTable is a table parsed from a schema dump.
+type schemaDriftTable struct {
+	// name is the identifier as it appears in the dump, used to compose the statements.
+	name      string
+	statement string
+	// columnList keeps the column order of the table.
+	columnList []string
+	columns    map[string]*schemaDriftColumn
+	indexes    map[string]*schemaDriftIndex
+	// options contains the table options and the constraints other than the keys, which are compared as a whole.
+	options string
+}
+
+type schemaDriftColumn struct {
+	name       string
+	definition string
+}
+
+type schemaDriftIndex struct {
+	name       string
+	definition string
+	// inline is true if the index is defined in the create table statement, false if in a separate statement.
+	inline bool
+	// constraint is true if the index is added by the ADD CONSTRAINT statement.
+	constraint bool
+}
+
+type schemaDriftView struct {
+	name      string
+	statement string
+}
+
+// schemaDriftSchema is the tables and views parsed from a schema dump.
+type schemaDriftSchema struct {
+	tables map[string]*schemaDriftTable
+	views  map[string]*schemaDriftView
+}
+
+// normalizeSchemaDriftIdentifier removes the identifier quotes, so that the same object dumped with and without
+// quotes is treated as the same.
+func normalizeSchemaDriftIdentifier(identifier string) string {
+	return strings.NewReplacer("`", "", `"`, "", "[", "", "]", "").Replace(identifier)
+}
+
+// splitSchemaDump splits the schema dump into statements without the trailing delimiter.
+// Comment lines are skipped, and the MySQL DELIMITER command is honored.
+func splitSchemaDump(schema string) []string {
+	var stmtList []string
+	var buf []string
+	delimiter := ";"
+	for _, line := range strings.Split(schema, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if len(buf) == 0 {
+			if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+				continue
+			}
+			if matches := delimiterRegexp.FindStringSubmatch(trimmed); matches != nil {
+				delimiter = matches[1]
+				continue
+			}
+		}
+		buf = append(buf, line)
+		if strings.HasSuffix(trimmed, delimiter) {
+			stmt := strings.TrimSpace(strings.Join(buf, "\n"))
+			stmt = strings.TrimSpace(strings.TrimSuffix(stmt, delimiter))
+			stmtList = append(stmtList, stmt)
+			buf = nil
+		}
+	}
+	if stmt := strings.TrimSpace(strings.Join(buf, "\n")); stmt != "" {
+		stmtList = append(stmtList, stmt)
+	}
+	return stmtList
+}
+
+// splitTableElements splits the body of a create table statement by the top level commas.
+func splitTableElements(body string) []string {
+	var elementList []string
+	depth := 0
+	var quote rune
+	start := 0
+	for i, c := range body {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			elementList = append(elementList, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	if element := strings.TrimSpace(body[start:]); element != "" {
+		elementList = append(elementList, element)
+	}
+	return elementList
+}
+
+// parseSchemaDump parses the tables and views from a schema dump.
+func parseSchemaDump(schema string) *schemaDriftSchema {
+	s := &schemaDriftSchema{
+		tables: make(map[string]*schemaDriftTable),
+		views:  make(map[string]*schemaDriftView),
+	}
+	// The indexes and constraints defined in separate statements may precede the table in some dumps,
+	// so we attach them after all tables are parsed.
+	type pendingIndex struct {
+		table string
+		index *schemaDriftIndex
+	}
+	var pendingList []pendingIndex
+	for _, stmt := range splitSchemaDump(schema) {
+		if matches := createTableRegexp.FindStringSubmatch(stmt); matches != nil {
+			table := &schemaDriftTable{
+				name:      matches[1],
+				statement: stmt,
+				columns:   make(map[string]*schemaDriftColumn),
+				indexes:   make(map[string]*schemaDriftIndex),
+			}
+			var optionList []string
+			for _, element := range splitTableElements(matches[2]) {
+				if m := inlineIndexRegexp.FindStringSubmatch(element); m != nil {
+					name := "PRIMARY"
+					if !strings.HasPrefix(strings.ToUpper(element), "PRIMARY") {
+						name = normalizeSchemaDriftIdentifier(m[1])
+					}
+					if name == "" {
+						name = element
+					}
+					table.indexes[name] = &schemaDriftIndex{name: name, definition: element, inline: true}
+					continue
+				}
+				if inlineConstraintRegexp.MatchString(element) {
+					optionList = append(optionList, element)
+					continue
+				}
+				fields := strings.Fields(element)
+				column := &schemaDriftColumn{
+					name:       normalizeSchemaDriftIdentifier(fields[0]),
+					definition: element,
+				}
+				table.columnList = append(table.columnList, column.name)
+				table.columns[column.name] = column
+			}
+			if options := strings.TrimSpace(matches[3]); options != "" {
+				optionList = append(optionList, options)
+			}
+			table.options = strings.Join(optionList, "\n")
+			s.tables[normalizeSchemaDriftIdentifier(table.name)] = table
+			continue
+		}
+		if matches := createIndexRegexp.FindStringSubmatch(stmt); matches != nil {
+			pendingList = append(pendingList, pendingIndex{
+				table: normalizeSchemaDriftIdentifier(matches[2]),
+				index: &schemaDriftIndex{name: normalizeSchemaDriftIdentifier(matches[1]), definition: stmt},
+			})
+			continue
+		}
+		if matches := addConstraintRegexp.FindStringSubmatch(stmt); matches != nil {
+			pendingList = append(pendingList, pendingIndex{
+				table: normalizeSchemaDriftIdentifier(matches[1]),
+				index: &schemaDriftIndex{name: normalizeSchemaDriftIdentifier(matches[2]), definition: stmt, constraint: true},
+			})
+			continue
+		}
+		if matches := createViewRegexp.FindStringSubmatch(stmt); matches != nil {
+			view := &schemaDriftView{name: matches[1], statement: stmt}
+			s.views[normalizeSchemaDriftIdentifier(view.name)] = view
+		}
+	}
+	for _, pending := range pendingList {
+		table, ok := s.tables[pending.table]
+		if !ok {
+			continue
+		}
+		// Only the key constraints are treated as indexes, the rest are compared as the table options.
+		if pending.index.constraint && !keyConstraintRegexp.MatchString(addConstraintRegexp.FindStringSubmatch(pending.index.definition)[3]) {
+			table.options = strings.TrimSpace(table.options + "\n" + pending.index.definition)
+			continue
+		}
+		table.indexes[pending.index.name] = pending.index
+	}
+	return s
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keyList []string
+	for k := range m {
+		keyList = append(keyList, k)
+	}
+	sort.Strings(keyList)
+	return keyList
+}
+
+// diffSchema returns the structured changes from the expected schema dump to the actual schema dump.
+// The changes are ordered by tables, followed by views.
+func diffSchema(expect, actual string) []*api.SchemaDriftItem {
+	expectSchema, actualSchema := parseSchemaDump(expect), parseSchemaDump(actual)
+	var itemList []*api.SchemaDriftItem
+
+	tableNames := make(map[string]bool)
+	for name := range expectSchema.tables {
+		tableNames[name] = true
+	}
+	for name := range actualSchema.tables {
+		tableNames[name] = true
+	}
+	for _, name := range sortedKeys(tableNames) {
+		expectTable, actualTable := expectSchema.tables[name], actualSchema.tables[name]
+		switch {
+		case expectTable == nil:
+			itemList = append(itemList, &api.SchemaDriftItem{
+				Type:   api.SchemaDriftObjectTable,
+				Action: api.SchemaDriftActionAdded,
+				Name:   name,
+				Actual: actualTable.statement,
+			})
+			continue
+		case actualTable == nil:
+			itemList = append(itemList, &api.SchemaDriftItem{
+				Type:   api.SchemaDriftObjectTable,
+				Action: api.SchemaDriftActionRemoved,
+				Name:   name,
+				Expect: expectTable.statement,
+			})
+			continue
+		}
+		if expectTable.options != actualTable.options {
+			itemList = append(itemList, &api.SchemaDriftItem{
+				Type:   api.SchemaDriftObjectTable,
+				Action: api.SchemaDriftActionChanged,
+				Name:   name,
+				Expect: expectTable.options,
+				Actual: actualTable.options,
+			})
+		}
+		itemList = append(itemList, diffSchemaColumns(name, expectTable, actualTable)...)
+		itemList = append(itemList, diffSchemaIndexes(name, expectTable, actualTable)...)
+	}
+
+	viewNames := make(map[string]bool)
+	for name := range expectSchema.views {
+		viewNames[name] = true
+	}
+	for name := range actualSchema.views {
+		viewNames[name] = true
+	}
+	for _, name := range sortedKeys(viewNames) {
+		item := &api.SchemaDriftItem{
+			Type: api.SchemaDriftObjectView,
+			Name: name,
+		}
+		expectView, actualView := expectSchema.views[name], actualSchema.views[name]
+		switch {
+		case expectView == nil:
+			item.Action, item.Actual = api.SchemaDriftActionAdded, actualView.statement
+		case actualView == nil:
+			item.Action, item.Expect = api.SchemaDriftActionRemoved, expectView.statement
+		case expectView.statement != actualView.statement:
+			item.Action, item.Expect, item.Actual = api.SchemaDriftActionChanged, expectView.statement, actualView.statement
+		default:
+			continue
+		}
+		itemList = append(itemList, item)
+	}
+	return itemList
+}
+
+func diffSchemaColumns(tableName string, expectTable, actualTable *schemaDriftTable) []*api.SchemaDriftItem {
+	var itemList []*api.SchemaDriftItem
+	// Removed and changed columns follow the expected column order, and added ones follow the actual order.
+	for _, name := range expectTable.columnList {
+		expectColumn, actualColumn := expectTable.columns[name], actualTable.columns[name]
+		item := &api.SchemaDriftItem{
+			Type:   api.SchemaDriftObjectColumn,
+			Table:  tableName,
+			Name:   name,
+			Expect: expectColumn.definition,
+		}
+		switch {
+		case actualColumn == nil:
+			item.Action = api.SchemaDriftActionRemoved
+		case expectColumn.definition != actualColumn.definition:
+			item.Action, item.Actual = api.SchemaDriftActionChanged, actualColumn.definition
+		default:
+			continue
+		}
+		itemList = append(itemList, item)
+	}
+	for _, name := range actualTable.columnList {
+		if _, ok := expectTable.columns[name]; ok {
+			continue
+		}
+		itemList = append(itemList, &api.SchemaDriftItem{
+			Type:   api.SchemaDriftObjectColumn,
+			Action: api.SchemaDriftActionAdded,
+			Table:  tableName,
+			Name:   name,
+			Actual: actualTable.columns[name].definition,
+		})
+	}
+	return itemList
+}
+
+func diffSchemaIndexes(tableName string, expectTable, actualTable *schemaDriftTable) []*api.SchemaDriftItem {
+	var itemList []*api.SchemaDriftItem
+	indexNames := make(map[string]bool)
+	for name := range expectTable.indexes {
+		indexNames[name] = true
+	}
+	for name := range actualTable.indexes {
+		indexNames[name] = true
+	}
+	for _, name := range sortedKeys(indexNames) {
+		item := &api.SchemaDriftItem{
+			Type:  api.SchemaDriftObjectIndex,
+			Table: tableName,
+			Name:  name,
+		}
+		expectIndex, actualIndex := expectTable.indexes[name], actualTable.indexes[name]
+		switch {
+		case expectIndex == nil:
+			item.Action, item.Actual = api.SchemaDriftActionAdded, actualIndex.definition
+		case actualIndex == nil:
+			item.Action, item.Expect = api.SchemaDriftActionRemoved, expectIndex.definition
+		case expectIndex.definition != actualIndex.definition:
+			item.Action, item.Expect, item.Actual = api.SchemaDriftActionChanged, expectIndex.definition, actualIndex.definition
+		default:
+			continue
+		}
+		itemList = append(itemList, item)
+	}
+	return itemList
+}
+
+// getSchemaDriftUnifiedDiff returns the unified text diff from the expected schema to the actual schema.
+func getSchemaDriftUnifiedDiff(expect, actual string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expect),
+		B:        difflib.SplitLines(actual),
+		FromFile: "expect",
+		ToFile:   "actual",
+		Context:  3,
+	})
+}
+
+// getSchemaDriftRevertStatement returns the statement reverting the actual schema to the expected schema.
+// The changes that can't be reverted safely, e.g. changed table options, are left as comments for the reviewer.
+func getSchemaDriftRevertStatement(engine db.Type, expect, actual string) string {
+	expectSchema, actualSchema := parseSchemaDump(expect), parseSchemaDump(actual)
+	mysqlFamily := engine == db.MySQL || engine == db.TiDB
+	var stmtList []string
+
+	itemList := diffSchema(expect, actual)
+
+	// Drop the added and changed views first, since they may depend on the tables being reverted.
+	var recreateViewList []string
+	for _, item := range itemList {
+		if item.Type != api.SchemaDriftObjectView {
+			continue
+		}
+		switch item.Action {
+		case api.SchemaDriftActionAdded:
+			stmtList = append(stmtList, fmt.Sprintf("DROP VIEW %s;", actualSchema.views[item.Name].name))
+		case api.SchemaDriftActionChanged:
+			stmtList = append(stmtList, fmt.Sprintf("DROP VIEW %s;", actualSchema.views[item.Name].name))
+			recreateViewList = append(recreateViewList, item.Expect+";")
+		case api.SchemaDriftActionRemoved:
+			recreateViewList = append(recreateViewList, item.Expect+";")
+		}
+	}
+
+	for _, item := range itemList {
+		switch item.Type {
+		case api.SchemaDriftObjectTable:
+			switch item.Action {
+			case api.SchemaDriftActionAdded:
+				stmtList = append(stmtList, fmt.Sprintf("DROP TABLE %s;", actualSchema.tables[item.Name].name))
+			case api.SchemaDriftActionRemoved:
+				table := expectSchema.tables[item.Name]
+				stmtList = append(stmtList, table.statement+";")
+				for _, index := range sortedIndexes(table) {
+					if !index.inline {
+						stmtList = append(stmtList, index.definition+";")
+					}
+				}
+			case api.SchemaDriftActionChanged:
+				stmtList = append(stmtList, fmt.Sprintf("-- Table %s options or constraints differ, please revert manually from:\n%s\n-- to:\n%s",
+					item.Name, commentOut(item.Actual), commentOut(item.Expect)))
+			}
+		case api.SchemaDriftObjectColumn:
+			tableName := actualSchema.tables[item.Table].name
+			switch item.Action {
+			case api.SchemaDriftActionAdded:
+				stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", tableName, strings.Fields(item.Actual)[0]))
+			case api.SchemaDriftActionRemoved:
+				stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", tableName, item.Expect))
+			case api.SchemaDriftActionChanged:
+				if mysqlFamily {
+					stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", tableName, item.Expect))
+				} else {
+					stmtList = append(stmtList, fmt.Sprintf("-- Column %s.%s differs, please revert manually from:\n%s\n-- to:\n%s",
+						item.Table, item.Name, commentOut(item.Actual), commentOut(item.Expect)))
+				}
+			}
+		case api.SchemaDriftObjectIndex:
+			table := actualSchema.tables[item.Table]
+			if item.Action != api.SchemaDriftActionRemoved {
+				stmtList = append(stmtList, getDropIndexStatement(table, table.indexes[item.Name]))
+			}
+			if item.Action != api.SchemaDriftActionAdded {
+				index := expectSchema.tables[item.Table].indexes[item.Name]
+				if index.inline {
+					stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ADD %s;", table.name, index.definition))
+				} else {
+					stmtList = append(stmtList, index.definition+";")
+				}
+			}
+		}
+	}
+
+	stmtList = append(stmtList, recreateViewList...)
+	return strings.Join(stmtList, "\n")
+}
+
+func getDropIndexStatement(table *schemaDriftTable, index *schemaDriftIndex) string {
+	switch {
+	case index.constraint:
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", table.name, addConstraintRegexp.FindStringSubmatch(index.definition)[2])
+	case !index.inline:
+		return fmt.Sprintf("DROP INDEX %s;", createIndexRegexp.FindStringSubmatch(index.definition)[1])
+	case index.name == "PRIMARY":
+		return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY;", table.name)
+	case inlineIndexRegexp.FindStringSubmatch(index.definition)[1] == "":
+		return fmt.Sprintf("-- Index %s of table %s is unnamed, please drop it manually.", index.definition, table.name)
+	default:
+		return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", table.name, inlineIndexRegexp.FindStringSubmatch(index.definition)[1])
+	}
+}
+
+func sortedIndexes(table *schemaDriftTable) []*schemaDriftIndex {
+	names := make(map[string]bool)
+	for name := range table.indexes {
+		names[name] = true
+	}
+	var indexList []*schemaDriftIndex
+	for _, name := range sortedKeys(names) {
+		indexList = append(indexList, table.indexes[name])
+	}
+	return indexList
+}
+
+func commentOut(s string) string {
+	var lineList []string
+	for _, line := range strings.Split(s, "\n") {
+		lineList = append(lineList, "-- "+line)
+	}
+	return strings.Join(lineList, "\n")
+}
+
+var (
+	createObjectRegexp = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:ALGORITHM|DEFINER)\s*=\s*\S+\s+|SQL\s+SECURITY\s+\S+\s+|(?:TEMP|TEMPORARY|MATERIALIZED|RECURSIVE|UNIQUE|CONSTRAINT|AGGREGATE)\s+)*(TABLE|VIEW|INDEX|FUNCTION|PROCEDURE|TRIGGER|EVENT|SEQUENCE)\b`)
+	alterObjectRegexp  = regexp.MustCompile(`(?is)^ALTER\s+(TABLE|SEQUENCE)\s+(?:ONLY\s+)?(?:IF\s+EXISTS\s+)?(\S+)`)
+	triggerTableRegexp = regexp.MustCompile(`(?is)\bON\s+(\S+)`)
+)
+
+// schemaDriftNormalizer replaces the matches of the regular expression with the replacement.
+type schemaDriftNormalizer struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// schemaDriftFilter removes the ignored schema differences from the schema dumps according to the ignore rules.
+type schemaDriftFilter struct {
+	tableGlobList  []string
+	objectTypes    map[api.SchemaDriftObjectType]bool
+	normalizerList []*schemaDriftNormalizer
+}
+
+// validateSchemaDriftRule validates the pattern of a schema drift ignore rule.
+func validateSchemaDriftRule(ruleType api.SchemaDriftRuleType, pattern string) error {
+	switch ruleType {
+	case api.SchemaDriftRuleTableGlob:
+		if pattern == "" {
+			return fmt.Errorf("table glob is empty")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid table glob %q: %w", pattern, err)
+		}
+	case api.SchemaDriftRuleObjectType:
+		switch api.SchemaDriftObjectType(pattern) {
+		case api.SchemaDriftObjectTable, api.SchemaDriftObjectIndex, api.SchemaDriftObjectView, api.SchemaDriftObjectFunction,
+			api.SchemaDriftObjectProcedure, api.SchemaDriftObjectTrigger, api.SchemaDriftObjectEvent, api.SchemaDriftObjectSequence:
+		default:
+			return fmt.Errorf("invalid object type %q", pattern)
+		}
+	case api.SchemaDriftRuleNormalizer:
+		if pattern == "" {
+			return fmt.Errorf("normalizer regular expression is empty")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid normalizer regular expression %q: %w", pattern, err)
+		}
+	default:
+		return fmt.Errorf("invalid schema drift rule type %q", ruleType)
+	}
+	return nil
+}
+
+func newSchemaDriftFilter(ruleList []*api.SchemaDriftRuleRaw) (*schemaDriftFilter, error) {
+	filter := &schemaDriftFilter{
+		objectTypes: make(map[api.SchemaDriftObjectType]bool),
+	}
+	for _, rule := range ruleList {
+		if err := validateSchemaDriftRule(rule.Type, rule.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid schema drift rule ID %d: %w", rule.ID, err)
+		}
+		switch rule.Type {
+		case api.SchemaDriftRuleTableGlob:
+			filter.tableGlobList = append(filter.tableGlobList, rule.Pattern)
+		case api.SchemaDriftRuleObjectType:
+			filter.objectTypes[api.SchemaDriftObjectType(rule.Pattern)] = true
+		case api.SchemaDriftRuleNormalizer:
+			filter.normalizerList = append(filter.normalizerList, &schemaDriftNormalizer{
+				re:          regexp.MustCompile(rule.Pattern),
+				replacement: rule.Replacement,
+			})
+		}
+	}
+	return filter, nil
+}
+
+// getSchemaDriftFilter returns the schema drift filter composed of the project-wide rules and the rules of the database.
+func (s *Server) getSchemaDriftFilter(ctx context.Context, database *api.Database) (*schemaDriftFilter, error) {
+	ruleList, err := s.SchemaDriftRuleService.FindSchemaDriftRuleList(ctx, &api.SchemaDriftRuleFind{
+		ProjectID:  &database.ProjectID,
+		DatabaseID: &database.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schema drift rules for database %q: %w", database.Name, err)
+	}
+	return newSchemaDriftFilter(ruleList)
+}
+
+// apply returns the schema dump with the ignored differences removed. The schema is returned as is if there is no rule,
+// otherwise the statements are re-joined without the comments.
+func (f *schemaDriftFilter) apply(schema string) string {
+	if len(f.tableGlobList) == 0 && len(f.objectTypes) == 0 && len(f.normalizerList) == 0 {
+		return schema
+	}
+	for _, normalizer := range f.normalizerList {
+		schema = normalizer.re.ReplaceAllString(schema, normalizer.replacement)
+	}
+	var stmtList []string
+	for _, stmt := range splitSchemaDump(schema) {
+		objectType, table := classifySchemaStatement(stmt)
+		if f.objectTypes[objectType] || (table != "" && f.matchTable(table)) {
+			continue
+		}
+		if objectType == api.SchemaDriftObjectTable && f.objectTypes[api.SchemaDriftObjectIndex] {
+			stmt = removeInlineIndexes(stmt)
+		}
+		stmtList = append(stmtList, stmt+";\n")
+	}
+	return strings.Join(stmtList, "\n")
+}
+
+// matchTable returns true if the table matches any table glob, with or without the schema qualifier.
+func (f *schemaDriftFilter) matchTable(table string) bool {
+	table = normalizeSchemaDriftIdentifier(table)
+	nameList := []string{table}
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		nameList = append(nameList, table[i+1:])
+	}
+	for _, glob := range f.tableGlobList {
+		for _, name := range nameList {
+			if ok, _ := path.Match(glob, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// classifySchemaStatement returns the object type of the statement, and the table or view it belongs to if any.
+func classifySchemaStatement(stmt string) (api.SchemaDriftObjectType, string) {
+	if matches := addConstraintRegexp.FindStringSubmatch(stmt); matches != nil {
+		if keyConstraintRegexp.MatchString(matches[3]) {
+			return api.SchemaDriftObjectIndex, matches[1]
+		}
+		return api.SchemaDriftObjectTable, matches[1]
+	}
+	if matches := alterObjectRegexp.FindStringSubmatch(stmt); matches != nil {
+		if strings.EqualFold(matches[1], "TABLE") {
+			return api.SchemaDriftObjectTable, matches[2]
+		}
+		return api.SchemaDriftObjectSequence, ""
+	}
+	matches := createObjectRegexp.FindStringSubmatch(stmt)
+	if matches == nil {
+		return "", ""
+	}
+	objectType := api.SchemaDriftObjectType(strings.ToUpper(matches[1]))
+	switch objectType {
+	case api.SchemaDriftObjectTable:
+		if m := createTableRegexp.FindStringSubmatch(stmt); m != nil {
+			return objectType, m[1]
+		}
+	case api.SchemaDriftObjectIndex:
+		if m := createIndexRegexp.FindStringSubmatch(stmt); m != nil {
+			return objectType, m[2]
+		}
+	case api.SchemaDriftObjectView:
+		if m := createViewRegexp.FindStringSubmatch(stmt); m != nil {
+			return objectType, m[1]
+		}
+	case api.SchemaDriftObjectTrigger:
+		if m := triggerTableRegexp.FindStringSubmatch(stmt); m != nil {
+			return objectType, m[1]
+		}
+	}
+	return objectType, ""
+}
+
+// removeInlineIndexes removes the indexes defined in the create table statement.
+func removeInlineIndexes(stmt string) string {
+	matches := createTableRegexp.FindStringSubmatch(stmt)
+	if matches == nil {
+		return stmt
+	}
+	var elementList []string
+	for _, element := range splitTableElements(matches[2]) {
+		if !inlineIndexRegexp.MatchString(element) {
+			elementList = append(elementList, element)
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n)%s", matches[1], strings.Join(elementList, ",\n  "), matches[3])
+}
+
+// getSchemaDriftSchema returns the expected schema of the migration history version, and the actual schema dumped from the database.
+// The ignored differences are not removed.
+func (s *Server) getSchemaDriftSchema(ctx context.Context, database *api.Database, version string) (string, string, error) {
+	driver, err := getAdminDatabaseDriver(ctx, database.Instance, database.Name, s.l)
+	if err != nil {
+		return "", "", err
+	}
+	defer driver.Close(ctx)
+
+	limit := 1
+	list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
+		Database: &database.Name,
+		Version:  &version,
+		Limit:    &limit,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find migration history of version %s for database %q: %w", version, database.Name, err)
+	}
+	if len(list) == 0 {
+		return "", "", common.Errorf(common.NotFound, fmt.Errorf("migration history of version %s not found for database %q", version, database.Name))
+	}
+
+	var schemaBuf bytes.Buffer
+	if err := driver.Dump(ctx, database.Name, &schemaBuf, true /*schemaOnly*/); err != nil {
+		return "", "", fmt.Errorf("failed to dump schema of database %q: %w", database.Name, err)
+	}
+	return list[0].Schema, schemaBuf.String(), nil
+}
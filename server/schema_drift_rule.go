@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerSchemaDriftRuleRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/driftrule", func(c echo.Context) error {
+		ctx := context.Background()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		find := &api.SchemaDriftRuleFind{
+			ProjectID: &projectID,
+		}
+		if databaseIDStr := c.QueryParam("database"); databaseIDStr != "" {
+			databaseID, err := strconv.Atoi(databaseIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("database query parameter is not a number: %s", databaseIDStr)).SetInternal(err)
+			}
+			find.DatabaseID = &databaseID
+		}
+		ruleRawList, err := s.SchemaDriftRuleService.FindSchemaDriftRuleList(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift rule list for project ID: %d", projectID)).SetInternal(err)
+		}
+		var ruleList []*api.SchemaDriftRule
+		for _, raw := range ruleRawList {
+			rule, err := s.composeSchemaDriftRuleRelationship(ctx, raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift rule relationship: %v", raw.ID)).SetInternal(err)
+			}
+			ruleList = append(ruleList, rule)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, ruleList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema drift rule list response: %v", projectID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/project/:projectID/driftrule", func(c echo.Context) error {
+		ctx := context.Background()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		ruleCreate := &api.SchemaDriftRuleCreate{
+			CreatorID: c.Get(getPrincipalIDContextKey()).(int),
+			ProjectID: projectID,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, ruleCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted create schema drift rule request").SetInternal(err)
+		}
+		if err := validateSchemaDriftRule(ruleCreate.Type, ruleCreate.Pattern); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid schema drift rule: %v", err))
+		}
+		if ruleCreate.DatabaseID != nil {
+			databaseRaw, err := s.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{ID: ruleCreate.DatabaseID})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", *ruleCreate.DatabaseID)).SetInternal(err)
+			}
+			if databaseRaw == nil || databaseRaw.ProjectID != projectID {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID %d not found in project ID %d", *ruleCreate.DatabaseID, projectID))
+			}
+		}
+
+		ruleRaw, err := s.SchemaDriftRuleService.CreateSchemaDriftRule(ctx, ruleCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create schema drift rule").SetInternal(err)
+		}
+
+		rule, err := s.composeSchemaDriftRuleRelationship(ctx, ruleRaw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch schema drift rule relationship").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rule); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create schema drift rule response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/project/:projectID/driftrule/:ruleID", func(c echo.Context) error {
+		ctx := context.Background()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		id, err := strconv.Atoi(c.Param("ruleID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema drift rule ID is not a number: %s", c.Param("ruleID"))).SetInternal(err)
+		}
+
+		rulePatch := &api.SchemaDriftRulePatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, rulePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted change schema drift rule").SetInternal(err)
+		}
+
+		existing, err := s.SchemaDriftRuleService.FindSchemaDriftRule(ctx, &api.SchemaDriftRuleFind{ID: &id, ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift rule ID: %v", id)).SetInternal(err)
+		}
+		if existing == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema drift rule ID not found: %d", id))
+		}
+		if rulePatch.Pattern != nil {
+			if err := validateSchemaDriftRule(existing.Type, *rulePatch.Pattern); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid schema drift rule: %v", err))
+			}
+		}
+
+		ruleRaw, err := s.SchemaDriftRuleService.PatchSchemaDriftRule(ctx, rulePatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema drift rule ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to change schema drift rule ID: %v", id)).SetInternal(err)
+		}
+
+		rule, err := s.composeSchemaDriftRuleRelationship(ctx, ruleRaw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch updated schema drift rule relationship").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rule); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema drift rule change response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/project/:projectID/driftrule/:ruleID", func(c echo.Context) error {
+		ctx := context.Background()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+
+		id, err := strconv.Atoi(c.Param("ruleID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema drift rule ID is not a number: %s", c.Param("ruleID"))).SetInternal(err)
+		}
+
+		existing, err := s.SchemaDriftRuleService.FindSchemaDriftRule(ctx, &api.SchemaDriftRuleFind{ID: &id, ProjectID: &projectID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema drift rule ID: %v", id)).SetInternal(err)
+		}
+		if existing == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema drift rule ID not found: %d", id))
+		}
+
+		ruleDelete := &api.SchemaDriftRuleDelete{
+			ID:        id,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.SchemaDriftRuleService.DeleteSchemaDriftRule(ctx, ruleDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete schema drift rule ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+func (s *Server) composeSchemaDriftRuleRelationship(ctx context.Context, raw *api.SchemaDriftRuleRaw) (*api.SchemaDriftRule, error) {
+	rule := raw.ToSchemaDriftRule()
+
+	creator, err := s.composePrincipalByID(ctx, rule.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	rule.Creator = creator
+
+	updater, err := s.composePrincipalByID(ctx, rule.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	rule.Updater = updater
+
+	return rule, nil
+}
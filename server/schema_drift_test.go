@@ -0,0 +1,255 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	mysqlExpectSchema = "" +
+		"--\n" +
+		"-- Table structure for `author`\n" +
+		"--\n" +
+		"CREATE TABLE `author` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `name` varchar(64) NOT NULL,\n" +
+		"  `email` varchar(64) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_name` (`name`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"--\n" +
+		"-- Table structure for `book`\n" +
+		"--\n" +
+		"CREATE TABLE `book` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"--\n" +
+		"-- View structure for `author_view`\n" +
+		"--\n" +
+		"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `author_view` AS select `author`.`id` AS `id` from `author`;\n" +
+		"--\n" +
+		"-- Procedure structure for `p`\n" +
+		"--\n" +
+		"DELIMITER ;;\n" +
+		"CREATE DEFINER=`root`@`%` PROCEDURE `p`()\n" +
+		"BEGIN\n" +
+		"  SELECT 1 FROM author;\n" +
+		"END ;;\n" +
+		"DELIMITER ;\n"
+	mysqlActualSchema = "" +
+		"--\n" +
+		"-- Table structure for `author`\n" +
+		"--\n" +
+		"CREATE TABLE `author` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `name` varchar(128) NOT NULL,\n" +
+		"  `key_id` int DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `uk_key_id` (`key_id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"--\n" +
+		"-- Table structure for `publisher`\n" +
+		"--\n" +
+		"CREATE TABLE `publisher` (\n" +
+		"  `id` int NOT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"--\n" +
+		"-- View structure for `author_view`\n" +
+		"--\n" +
+		"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `author_view` AS select `author`.`name` AS `name` from `author`;\n" +
+		"--\n" +
+		"-- Procedure structure for `p`\n" +
+		"--\n" +
+		"DELIMITER ;;\n" +
+		"CREATE DEFINER=`root`@`%` PROCEDURE `p`()\n" +
+		"BEGIN\n" +
+		"  SELECT 2 FROM author;\n" +
+		"END ;;\n" +
+		"DELIMITER ;\n"
+)
+
+func TestDiffSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect string
+		actual string
+		want   []*api.SchemaDriftItem
+	}{
+		{
+			name:   "identical",
+			expect: mysqlExpectSchema,
+			actual: mysqlExpectSchema,
+			want:   nil,
+		},
+		{
+			name:   "mysql",
+			expect: mysqlExpectSchema,
+			actual: mysqlActualSchema,
+			want: []*api.SchemaDriftItem{
+				{Type: api.SchemaDriftObjectColumn, Action: api.SchemaDriftActionChanged, Table: "author", Name: "name", Expect: "`name` varchar(64) NOT NULL", Actual: "`name` varchar(128) NOT NULL"},
+				{Type: api.SchemaDriftObjectColumn, Action: api.SchemaDriftActionRemoved, Table: "author", Name: "email", Expect: "`email` varchar(64) DEFAULT NULL"},
+				{Type: api.SchemaDriftObjectColumn, Action: api.SchemaDriftActionAdded, Table: "author", Name: "key_id", Actual: "`key_id` int DEFAULT NULL"},
+				{Type: api.SchemaDriftObjectIndex, Action: api.SchemaDriftActionRemoved, Table: "author", Name: "idx_name", Expect: "KEY `idx_name` (`name`)"},
+				{Type: api.SchemaDriftObjectIndex, Action: api.SchemaDriftActionAdded, Table: "author", Name: "uk_key_id", Actual: "UNIQUE KEY `uk_key_id` (`key_id`)"},
+				{Type: api.SchemaDriftObjectTable, Action: api.SchemaDriftActionRemoved, Name: "book", Expect: "CREATE TABLE `book` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"},
+				{Type: api.SchemaDriftObjectTable, Action: api.SchemaDriftActionAdded, Name: "publisher", Actual: "CREATE TABLE `publisher` (\n  `id` int NOT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"},
+				{
+					Type:   api.SchemaDriftObjectView,
+					Action: api.SchemaDriftActionChanged,
+					Name:   "author_view",
+					Expect: "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `author_view` AS select `author`.`id` AS `id` from `author`",
+					Actual: "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `author_view` AS select `author`.`name` AS `name` from `author`",
+				},
+			},
+		},
+		{
+			name: "postgres",
+			expect: "" +
+				"CREATE TABLE public.t (\n" +
+				"  id integer NOT NULL,\n" +
+				"  name text\n" +
+				");\n\n" +
+				"ALTER TABLE ONLY public.t\n" +
+				"    ADD CONSTRAINT t_pkey PRIMARY KEY (id);\n\n" +
+				"CREATE INDEX idx_name ON public.t USING btree (name);\n\n",
+			actual: "" +
+				"CREATE TABLE public.t (\n" +
+				"  id integer NOT NULL,\n" +
+				"  name text\n" +
+				");\n\n" +
+				"ALTER TABLE ONLY public.t\n" +
+				"    ADD CONSTRAINT t_pkey PRIMARY KEY (id);\n" +
+				"ALTER TABLE ONLY public.t\n" +
+				"    ADD CONSTRAINT t_check CHECK (id > 0);\n\n",
+			want: []*api.SchemaDriftItem{
+				{Type: api.SchemaDriftObjectTable, Action: api.SchemaDriftActionChanged, Name: "public.t", Actual: "ALTER TABLE ONLY public.t\n    ADD CONSTRAINT t_check CHECK (id > 0)"},
+				{Type: api.SchemaDriftObjectIndex, Action: api.SchemaDriftActionRemoved, Table: "public.t", Name: "idx_name", Expect: "CREATE INDEX idx_name ON public.t USING btree (name)"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := diffSchema(test.expect, test.actual)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: diffSchema() mismatch (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestGetSchemaDriftRevertStatement(t *testing.T) {
+	want := "" +
+		"DROP VIEW `author_view`;\n" +
+		"ALTER TABLE `author` MODIFY COLUMN `name` varchar(64) NOT NULL;\n" +
+		"ALTER TABLE `author` ADD COLUMN `email` varchar(64) DEFAULT NULL;\n" +
+		"ALTER TABLE `author` DROP COLUMN `key_id`;\n" +
+		"ALTER TABLE `author` ADD KEY `idx_name` (`name`);\n" +
+		"ALTER TABLE `author` DROP INDEX `uk_key_id`;\n" +
+		"CREATE TABLE `book` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"DROP TABLE `publisher`;\n" +
+		"CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `author_view` AS select `author`.`id` AS `id` from `author`;"
+	got := getSchemaDriftRevertStatement(db.MySQL, mysqlExpectSchema, mysqlActualSchema)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("getSchemaDriftRevertStatement() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetSchemaDriftUnifiedDiff(t *testing.T) {
+	got, err := getSchemaDriftUnifiedDiff("a;\nb;", "a;\nc;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"--- expect\n" +
+		"+++ actual\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" a;\n" +
+		"-b;\n" +
+		"+c;\n"
+	if got != want {
+		t.Errorf("getSchemaDriftUnifiedDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestSchemaDriftFilter(t *testing.T) {
+	expect := "" +
+		"CREATE TABLE `t` (\n" +
+		"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4;\n"
+	noisy := "" +
+		"CREATE TABLE `t` (\n" +
+		"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_id` (`id`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4;\n" +
+		"CREATE TABLE `_t_gho` (\n" +
+		"  `id` int NOT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"DELIMITER ;;\n" +
+		"CREATE DEFINER=`root`@`%` TRIGGER `tr` BEFORE INSERT ON `t` FOR EACH ROW BEGIN\n" +
+		"  SET NEW.id = 1;\n" +
+		"END ;;\n" +
+		"DELIMITER ;\n"
+	drifted := "" +
+		"CREATE TABLE `t` (\n" +
+		"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` text,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4;\n"
+
+	filter, err := newSchemaDriftFilter([]*api.SchemaDriftRuleRaw{
+		{ID: 1, Type: api.SchemaDriftRuleTableGlob, Pattern: "_*_gho"},
+		{ID: 2, Type: api.SchemaDriftRuleObjectType, Pattern: string(api.SchemaDriftObjectTrigger)},
+		{ID: 3, Type: api.SchemaDriftRuleObjectType, Pattern: string(api.SchemaDriftObjectIndex)},
+		{ID: 4, Type: api.SchemaDriftRuleNormalizer, Pattern: ` AUTO_INCREMENT=\d+`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := filter.apply(noisy), filter.apply(expect); got != want {
+		t.Errorf("filter.apply() of the ignored differences = %q, want %q", got, want)
+	}
+	if filter.apply(drifted) == filter.apply(expect) {
+		t.Errorf("filter.apply() should not ignore the added column")
+	}
+	want := []*api.SchemaDriftItem{
+		{Type: api.SchemaDriftObjectColumn, Action: api.SchemaDriftActionAdded, Table: "t", Name: "name", Actual: "`name` text"},
+	}
+	if diff := cmp.Diff(want, diffSchema(filter.apply(expect), filter.apply(drifted))); diff != "" {
+		t.Errorf("diffSchema() of the filtered schema mismatch (-want +got):\n%s", diff)
+	}
+
+	emptyFilter, err := newSchemaDriftFilter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := emptyFilter.apply(noisy); got != noisy {
+		t.Errorf("filter.apply() without rule = %q, want %q", got, noisy)
+	}
+}
+
+func TestValidateSchemaDriftRule(t *testing.T) {
+	tests := []struct {
+		ruleType api.SchemaDriftRuleType
+		pattern  string
+		wantErr  bool
+	}{
+		{api.SchemaDriftRuleTableGlob, "_*_gho", false},
+		{api.SchemaDriftRuleTableGlob, "[", true},
+		{api.SchemaDriftRuleObjectType, "TRIGGER", false},
+		{api.SchemaDriftRuleObjectType, "COLUMN", true},
+		{api.SchemaDriftRuleNormalizer, `AUTO_INCREMENT=\d+`, false},
+		{api.SchemaDriftRuleNormalizer, `(`, true},
+		{"UNKNOWN", "", true},
+	}
+	for _, test := range tests {
+		err := validateSchemaDriftRule(test.ruleType, test.pattern)
+		if (err != nil) != test.wantErr {
+			t.Errorf("validateSchemaDriftRule(%q, %q) got error %v, wantErr %v", test.ruleType, test.pattern, err, test.wantErr)
+		}
+	}
+}
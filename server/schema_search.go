@@ -0,0 +1,362 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultSchemaSearchLimit = 100
+	maxSchemaSearchLimit     = 1000
+)
+
+func (s *Server) registerSchemaSearchRoutes(g *echo.Group) {
+	// Searches the synced table, column, index and view metadata across all databases in the workspace.
+	// Developers only get the databases in the projects they are members of, while Owners and DBAs get all databases.
+	g.GET("/schema/search", func(c echo.Context) error {
+		ctx := context.Background()
+		find, err := getSchemaSearchFind(c)
+		if err != nil {
+			return err
+		}
+		pattern := getSchemaSearchPattern(find.Query, find.Mode)
+		matcher, err := newSchemaSearchMatcher(find.Query, find.Mode)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid search query %q", find.Query)).SetInternal(err)
+		}
+
+		databaseList, err := s.composeDatabaseListByFind(ctx, &api.DatabaseFind{
+			InstanceID: find.InstanceID,
+			ProjectID:  find.ProjectID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch database list").SetInternal(err)
+		}
+		role := c.Get(getRoleContextKey()).(api.Role)
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		databaseMap := make(map[int]*api.Database)
+		databaseIDList := []int{}
+		for _, database := range databaseList {
+			if find.EnvironmentID != nil && database.Instance.EnvironmentID != *find.EnvironmentID {
+				continue
+			}
+			if find.Engine != nil && database.Instance.Engine != *find.Engine {
+				continue
+			}
+			if role == api.Developer && !isProjectMember(database.Project, principalID) {
+				continue
+			}
+			databaseMap[database.ID] = database
+			databaseIDList = append(databaseIDList, database.ID)
+		}
+
+		metadata := &schemaSearchMetadata{}
+		if len(databaseIDList) > 0 {
+			// Fetches one more object of each type than the limit to tell whether the matches are truncated.
+			metadata, err = s.findSchemaSearchMetadata(ctx, databaseIDList, find.ObjectTypeList, pattern, find.Limit+1)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch the synced schema metadata").SetInternal(err)
+			}
+		}
+		matchList := searchSchema(matcher, find.ObjectTypeList, databaseMap, metadata)
+		result := &api.SchemaSearchResult{
+			Query:     find.Query,
+			Mode:      find.Mode,
+			MatchList: matchList,
+			Truncated: metadata.truncated,
+		}
+		if len(matchList) > find.Limit {
+			result.MatchList = matchList[:find.Limit]
+			result.Truncated = true
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal schema search response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// getSchemaSearchFind parses the query parameters of the schema search.
+func getSchemaSearchFind(c echo.Context) (*api.SchemaSearchFind, error) {
+	find := &api.SchemaSearchFind{
+		Query: c.QueryParam("query"),
+		Mode:  api.SchemaSearchWildcard,
+		Limit: defaultSchemaSearchLimit,
+	}
+	if find.Query == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Missing query parameter query")
+	}
+	if mode := c.QueryParam("mode"); mode != "" {
+		switch api.SchemaSearchMode(strings.ToUpper(mode)) {
+		case api.SchemaSearchWildcard:
+			find.Mode = api.SchemaSearchWildcard
+		case api.SchemaSearchRegex:
+			find.Mode = api.SchemaSearchRegex
+		default:
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid search mode %q, should be WILDCARD or REGEX", mode))
+		}
+	}
+	if types := c.QueryParam("type"); types != "" {
+		for _, objectType := range strings.Split(types, ",") {
+			switch t := api.SchemaSearchObjectType(strings.ToUpper(strings.TrimSpace(objectType))); t {
+			case api.SchemaSearchTable, api.SchemaSearchColumn, api.SchemaSearchIndex, api.SchemaSearchView:
+				find.ObjectTypeList = append(find.ObjectTypeList, t)
+			default:
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid object type %q, should be TABLE, COLUMN, INDEX or VIEW", objectType))
+			}
+		}
+	}
+	for param, field := range map[string]**int{
+		"project":     &find.ProjectID,
+		"environment": &find.EnvironmentID,
+		"instance":    &find.InstanceID,
+	} {
+		if value := c.QueryParam(param); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter %s is not a number: %s", param, value)).SetInternal(err)
+			}
+			*field = &id
+		}
+	}
+	if engine := c.QueryParam("engine"); engine != "" {
+		engineType := db.Type(strings.ToUpper(engine))
+		find.Engine = &engineType
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > maxSchemaSearchLimit {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit should be a number between 1 and %d: %s", maxSchemaSearchLimit, limit))
+		}
+		find.Limit = n
+	}
+	return find, nil
+}
+
+// newSchemaSearchMatcher compiles the query to a case-insensitive regular expression.
+// A wildcard query must match the whole text, while a regex query matches any part of the text.
+func newSchemaSearchMatcher(query string, mode api.SchemaSearchMode) (*regexp.Regexp, error) {
+	if mode == api.SchemaSearchRegex {
+		return regexp.Compile("(?i)" + query)
+	}
+	return regexp.Compile("(?is)" + getSchemaSearchPattern(query, mode))
+}
+
+// getSchemaSearchPattern returns the regular expression of the query without the flags, which is also a POSIX regular expression
+// to filter the synced schema metadata in the store. The matches are still checked by newSchemaSearchMatcher.
+func getSchemaSearchPattern(query string, mode api.SchemaSearchMode) string {
+	if mode == api.SchemaSearchRegex {
+		return query
+	}
+	var buf strings.Builder
+	buf.WriteString("^")
+	for _, r := range query {
+		switch r {
+		case '*':
+			buf.WriteString(".*")
+		case '?':
+			buf.WriteString(".")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	buf.WriteString("$")
+	return buf.String()
+}
+
+func isProjectMember(project *api.Project, principalID int) bool {
+	for _, projectMember := range project.ProjectMemberList {
+		if projectMember.PrincipalID == principalID {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaSearchMetadata is the synced schema metadata of the databases to search.
+type schemaSearchMetadata struct {
+	tableList  []*api.TableRaw
+	columnList []*api.Column
+	indexList  []*api.Index
+	viewList   []*api.View
+	// truncated is true if any object type has more objects matching the pattern than the limit.
+	truncated bool
+}
+
+// findSchemaSearchMetadata fetches at most limit objects of each object type matching the pattern in the databases.
+// The tables of the columns and indexes are also fetched for their table names.
+func (s *Server) findSchemaSearchMetadata(ctx context.Context, databaseIDList []int, objectTypeList []api.SchemaSearchObjectType, pattern string, limit int) (*schemaSearchMetadata, error) {
+	typeSet := getSchemaSearchObjectTypeSet(objectTypeList)
+	metadata := &schemaSearchMetadata{}
+	var err error
+	if typeSet[api.SchemaSearchTable] {
+		if metadata.tableList, err = s.TableService.FindTableList(ctx, &api.TableFind{
+			DatabaseIDList: &databaseIDList,
+			NamePattern:    &pattern,
+			Limit:          &limit,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to fetch table list, error: %w", err)
+		}
+		metadata.truncated = metadata.truncated || len(metadata.tableList) >= limit
+	}
+	if typeSet[api.SchemaSearchColumn] {
+		if metadata.columnList, err = s.ColumnService.FindColumnList(ctx, &api.ColumnFind{
+			DatabaseIDList: &databaseIDList,
+			Pattern:        &pattern,
+			Limit:          &limit,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to fetch column list, error: %w", err)
+		}
+		metadata.truncated = metadata.truncated || len(metadata.columnList) >= limit
+	}
+	if typeSet[api.SchemaSearchIndex] {
+		if metadata.indexList, err = s.IndexService.FindIndexList(ctx, &api.IndexFind{
+			DatabaseIDList: &databaseIDList,
+			NamePattern:    &pattern,
+			Limit:          &limit,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to fetch index list, error: %w", err)
+		}
+		metadata.truncated = metadata.truncated || len(metadata.indexList) >= limit
+	}
+	if typeSet[api.SchemaSearchView] {
+		if metadata.viewList, err = s.ViewService.FindViewList(ctx, &api.ViewFind{
+			DatabaseIDList: &databaseIDList,
+			NamePattern:    &pattern,
+			Limit:          &limit,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to fetch view list, error: %w", err)
+		}
+		metadata.truncated = metadata.truncated || len(metadata.viewList) >= limit
+	}
+
+	// The tables already fetched are not fetched again for the table names.
+	tableIDSet := make(map[int]bool)
+	for _, table := range metadata.tableList {
+		tableIDSet[table.ID] = true
+	}
+	tableIDList := []int{}
+	addTableID := func(tableID int) {
+		if !tableIDSet[tableID] {
+			tableIDSet[tableID] = true
+			tableIDList = append(tableIDList, tableID)
+		}
+	}
+	for _, column := range metadata.columnList {
+		addTableID(column.TableID)
+	}
+	for _, index := range metadata.indexList {
+		addTableID(index.TableID)
+	}
+	if len(tableIDList) > 0 {
+		tableList, err := s.TableService.FindTableList(ctx, &api.TableFind{
+			IDList: &tableIDList,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch table list, error: %w", err)
+		}
+		// The tables fetched for the table names are matched against the query by searchSchema as well.
+		metadata.tableList = append(metadata.tableList, tableList...)
+	}
+	return metadata, nil
+}
+
+// getSchemaSearchObjectTypeSet returns the set of the object types, which contains all object types if the list is empty.
+func getSchemaSearchObjectTypeSet(objectTypeList []api.SchemaSearchObjectType) map[api.SchemaSearchObjectType]bool {
+	if len(objectTypeList) == 0 {
+		objectTypeList = []api.SchemaSearchObjectType{api.SchemaSearchTable, api.SchemaSearchColumn, api.SchemaSearchIndex, api.SchemaSearchView}
+	}
+	typeSet := make(map[api.SchemaSearchObjectType]bool)
+	for _, objectType := range objectTypeList {
+		typeSet[objectType] = true
+	}
+	return typeSet
+}
+
+// searchSchema returns the objects in the databases matching the query.
+// Matches are sorted by the database name and ID, then the tables, views, columns and indexes in the order of the metadata.
+func searchSchema(matcher *regexp.Regexp, objectTypeList []api.SchemaSearchObjectType, databaseMap map[int]*api.Database, metadata *schemaSearchMetadata) []*api.SchemaSearchMatch {
+	typeSet := getSchemaSearchObjectTypeSet(objectTypeList)
+	matchList := []*api.SchemaSearchMatch{}
+	add := func(databaseID int, objectType api.SchemaSearchObjectType, tableName, name string, field api.SchemaSearchField, value string) {
+		database, ok := databaseMap[databaseID]
+		if !ok || !matcher.MatchString(value) {
+			return
+		}
+		matchList = append(matchList, &api.SchemaSearchMatch{
+			DatabaseID:      database.ID,
+			DatabaseName:    database.Name,
+			ProjectID:       database.ProjectID,
+			ProjectName:     database.Project.Name,
+			EnvironmentID:   database.Instance.EnvironmentID,
+			EnvironmentName: database.Instance.Environment.Name,
+			InstanceID:      database.InstanceID,
+			InstanceName:    database.Instance.Name,
+			Engine:          database.Instance.Engine,
+			ObjectType:      objectType,
+			TableName:       tableName,
+			Name:            name,
+			Field:           field,
+			Value:           value,
+		})
+	}
+
+	tableNames := make(map[int]string)
+	for _, table := range metadata.tableList {
+		tableNames[table.ID] = table.Name
+		if typeSet[api.SchemaSearchTable] {
+			add(table.DatabaseID, api.SchemaSearchTable, "", table.Name, api.SchemaSearchName, table.Name)
+		}
+	}
+	if typeSet[api.SchemaSearchView] {
+		for _, view := range metadata.viewList {
+			add(view.DatabaseID, api.SchemaSearchView, "", view.Name, api.SchemaSearchName, view.Name)
+		}
+	}
+	if typeSet[api.SchemaSearchColumn] {
+		for _, column := range metadata.columnList {
+			tableName := tableNames[column.TableID]
+			add(column.DatabaseID, api.SchemaSearchColumn, tableName, column.Name, api.SchemaSearchName, column.Name)
+			add(column.DatabaseID, api.SchemaSearchColumn, tableName, column.Name, api.SchemaSearchType, column.Type)
+			if column.Comment != "" {
+				add(column.DatabaseID, api.SchemaSearchColumn, tableName, column.Name, api.SchemaSearchComment, column.Comment)
+			}
+		}
+	}
+	if typeSet[api.SchemaSearchIndex] {
+		// There is one index row for each expression, so we only match the index name once.
+		type indexKey struct {
+			tableID int
+			name    string
+		}
+		visited := make(map[indexKey]bool)
+		for _, index := range metadata.indexList {
+			key := indexKey{tableID: index.TableID, name: index.Name}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+			add(index.DatabaseID, api.SchemaSearchIndex, tableNames[index.TableID], index.Name, api.SchemaSearchName, index.Name)
+		}
+	}
+
+	sort.SliceStable(matchList, func(i, j int) bool {
+		if matchList[i].DatabaseName != matchList[j].DatabaseName {
+			return matchList[i].DatabaseName < matchList[j].DatabaseName
+		}
+		return matchList[i].DatabaseID < matchList[j].DatabaseID
+	})
+	return matchList
+}
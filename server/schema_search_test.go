@@ -0,0 +1,113 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestNewSchemaSearchMatcher(t *testing.T) {
+	tests := []struct {
+		query string
+		mode  api.SchemaSearchMode
+		text  string
+		want  bool
+	}{
+		{query: "ssn", mode: api.SchemaSearchWildcard, text: "SSN", want: true},
+		{query: "ssn", mode: api.SchemaSearchWildcard, text: "user_ssn", want: false},
+		{query: "*ssn*", mode: api.SchemaSearchWildcard, text: "user_ssn_hash", want: true},
+		{query: "order?", mode: api.SchemaSearchWildcard, text: "orders", want: true},
+		{query: "a.b", mode: api.SchemaSearchWildcard, text: "axb", want: false},
+		{query: "^(ssn|tax_id)$", mode: api.SchemaSearchRegex, text: "TAX_ID", want: true},
+		{query: "ssn", mode: api.SchemaSearchRegex, text: "user_ssn", want: true},
+	}
+	for _, test := range tests {
+		matcher, err := newSchemaSearchMatcher(test.query, test.mode)
+		if err != nil {
+			t.Fatalf("newSchemaSearchMatcher(%q, %s) got error: %v", test.query, test.mode, err)
+		}
+		if got := matcher.MatchString(test.text); got != test.want {
+			t.Errorf("newSchemaSearchMatcher(%q, %s).MatchString(%q) = %v, want %v", test.query, test.mode, test.text, got, test.want)
+		}
+	}
+
+	if _, err := newSchemaSearchMatcher("(", api.SchemaSearchRegex); err == nil {
+		t.Errorf("newSchemaSearchMatcher() with invalid regex got nil error, want error")
+	}
+}
+
+func TestGetSchemaSearchPattern(t *testing.T) {
+	tests := []struct {
+		query string
+		mode  api.SchemaSearchMode
+		want  string
+	}{
+		{query: "*ssn?", mode: api.SchemaSearchWildcard, want: "^.*ssn.$"},
+		{query: "a.b(c)", mode: api.SchemaSearchWildcard, want: `^a\.b\(c\)$`},
+		{query: "^(ssn|tax_id)$", mode: api.SchemaSearchRegex, want: "^(ssn|tax_id)$"},
+	}
+	for _, test := range tests {
+		if got := getSchemaSearchPattern(test.query, test.mode); got != test.want {
+			t.Errorf("getSchemaSearchPattern(%q, %s) = %q, want %q", test.query, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestSearchSchema(t *testing.T) {
+	newDatabase := func(id int, name string) *api.Database {
+		return &api.Database{
+			ID:      id,
+			Name:    name,
+			Project: &api.Project{Name: "Default"},
+			Instance: &api.Instance{
+				Name:        "mysql",
+				Environment: &api.Environment{Name: "Prod"},
+			},
+		}
+	}
+	databaseMap := map[int]*api.Database{
+		101: newDatabase(101, "hr"),
+		102: newDatabase(102, "crm"),
+	}
+	metadata := &schemaSearchMetadata{
+		tableList: []*api.TableRaw{
+			{ID: 1, DatabaseID: 101, Name: "employee"},
+			{ID: 2, DatabaseID: 102, Name: "customer"},
+			// The database 103 isn't accessible.
+			{ID: 3, DatabaseID: 103, Name: "secret"},
+		},
+		columnList: []*api.Column{
+			{DatabaseID: 101, TableID: 1, Name: "ssn", Type: "varchar(11)"},
+			{DatabaseID: 102, TableID: 2, Name: "tax_id", Type: "varchar(11)", Comment: "The ssn or ein"},
+			{DatabaseID: 103, TableID: 3, Name: "ssn", Type: "varchar(11)"},
+		},
+		indexList: []*api.Index{
+			{DatabaseID: 101, TableID: 1, Name: "idx_ssn", Expression: "ssn", Position: 1},
+			{DatabaseID: 101, TableID: 1, Name: "idx_ssn", Expression: "id", Position: 2},
+		},
+	}
+
+	matcher, err := newSchemaSearchMatcher("*ssn*", api.SchemaSearchWildcard)
+	if err != nil {
+		t.Fatalf("newSchemaSearchMatcher() got error: %v", err)
+	}
+	matchList := searchSchema(matcher, nil, databaseMap, metadata)
+	want := []api.SchemaSearchMatch{
+		{DatabaseID: 102, ObjectType: api.SchemaSearchColumn, TableName: "customer", Name: "tax_id", Field: api.SchemaSearchComment},
+		{DatabaseID: 101, ObjectType: api.SchemaSearchColumn, TableName: "employee", Name: "ssn", Field: api.SchemaSearchName},
+		{DatabaseID: 101, ObjectType: api.SchemaSearchIndex, TableName: "employee", Name: "idx_ssn", Field: api.SchemaSearchName},
+	}
+	if len(matchList) != len(want) {
+		t.Fatalf("searchSchema() got %d matches, want %d", len(matchList), len(want))
+	}
+	for i, match := range matchList {
+		if match.DatabaseID != want[i].DatabaseID || match.ObjectType != want[i].ObjectType || match.TableName != want[i].TableName || match.Name != want[i].Name || match.Field != want[i].Field {
+			t.Errorf("searchSchema() match %d got %+v, want %+v", i, match, want[i])
+		}
+	}
+
+	matchList = searchSchema(matcher, []api.SchemaSearchObjectType{api.SchemaSearchTable}, databaseMap, metadata)
+	if len(matchList) != 0 {
+		t.Errorf("searchSchema() for tables got %d matches, want 0", len(matchList))
+	}
+}
@@ -0,0 +1,535 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"github.com/pmezard/go-difflib/difflib"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSchemaSnapshotLimit is the default number of snapshots returned by the snapshot list.
+	defaultSchemaSnapshotLimit = 100
+)
+
+func (s *Server) registerSchemaSnapshotRoutes(g *echo.Group) {
+	// The metadata is omitted in the list, use the snapshot API to fetch it.
+	g.GET("/database/:id/snapshot", func(c echo.Context) error {
+		ctx := context.Background()
+		database, err := s.findSchemaSnapshotDatabase(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+
+		limit := defaultSchemaSnapshotLimit
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a positive number: %s", limitStr)).SetInternal(err)
+			}
+		}
+		snapshotRawList, err := s.SchemaSnapshotService.FindSchemaSnapshotList(ctx, &api.SchemaSnapshotFind{
+			DatabaseID: &database.ID,
+			Limit:      &limit,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot list for database ID: %v", database.ID)).SetInternal(err)
+		}
+		var snapshotList []*api.SchemaSnapshot
+		for _, raw := range snapshotRawList {
+			raw.Metadata = ""
+			snapshot, err := s.composeSchemaSnapshotRelationship(ctx, raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compose schema snapshot relationship: %v", raw.ID)).SetInternal(err)
+			}
+			snapshotList = append(snapshotList, snapshot)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, snapshotList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot list response for database ID: %v", database.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/snapshot/diff", func(c echo.Context) error {
+		ctx := context.Background()
+		database, err := s.findSchemaSnapshotDatabase(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+
+		from, err := s.findSchemaSnapshotByParam(ctx, database, "from", c.QueryParam("from"))
+		if err != nil {
+			return err
+		}
+		to, err := s.findSchemaSnapshotByParam(ctx, database, "to", c.QueryParam("to"))
+		if err != nil {
+			return err
+		}
+
+		diff, err := diffSchemaSnapshot(from, to)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to diff schema snapshot %d and %d", from.ID, to.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, diff); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal schema snapshot diff response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/snapshot/:snapshotID", func(c echo.Context) error {
+		ctx := context.Background()
+		database, err := s.findSchemaSnapshotDatabase(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+
+		raw, err := s.findSchemaSnapshotByParam(ctx, database, "snapshotID", c.Param("snapshotID"))
+		if err != nil {
+			return err
+		}
+		snapshot, err := s.composeSchemaSnapshotRelationship(ctx, raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compose schema snapshot relationship: %v", raw.ID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, snapshot); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal schema snapshot response: %v", raw.ID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func (s *Server) findSchemaSnapshotDatabase(ctx context.Context, idStr string) (*api.Database, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", idStr)).SetInternal(err)
+	}
+	database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &id})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+	}
+	return database, nil
+}
+
+func (s *Server) findSchemaSnapshotByParam(ctx context.Context, database *api.Database, name, idStr string) (*api.SchemaSnapshotRaw, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s is not a number: %s", name, idStr)).SetInternal(err)
+	}
+	snapshot, err := s.SchemaSnapshotService.FindSchemaSnapshot(ctx, &api.SchemaSnapshotFind{
+		ID:         &id,
+		DatabaseID: &database.ID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch schema snapshot ID: %v", id)).SetInternal(err)
+	}
+	if snapshot == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema snapshot ID %d not found in database %q", id, database.Name))
+	}
+	return snapshot, nil
+}
+
+func (s *Server) composeSchemaSnapshotRelationship(ctx context.Context, raw *api.SchemaSnapshotRaw) (*api.SchemaSnapshot, error) {
+	snapshot := raw.ToSchemaSnapshot()
+
+	creator, err := s.composePrincipalByID(ctx, snapshot.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Creator = creator
+
+	updater, err := s.composePrincipalByID(ctx, snapshot.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Updater = updater
+
+	return snapshot, nil
+}
+
+// createSchemaSnapshotIfChanged stores a snapshot of the synced schema if it differs from the latest snapshot of the database.
+// The snapshot records the schema version so that it can be correlated with the migration history.
+func (s *Server) createSchemaSnapshotIfChanged(ctx context.Context, database *api.Database, schema *db.Schema, schemaVersion string) error {
+	metadata, err := json.Marshal(newSchemaSnapshotMetadata(schema))
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema snapshot metadata for database %q, error: %w", database.Name, err)
+	}
+	sum := sha256.Sum256(metadata)
+	checksum := hex.EncodeToString(sum[:])
+
+	limit := 1
+	latestList, err := s.SchemaSnapshotService.FindSchemaSnapshotList(ctx, &api.SchemaSnapshotFind{
+		DatabaseID: &database.ID,
+		Limit:      &limit,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch the latest schema snapshot for database %q, error: %w", database.Name, err)
+	}
+	// Only the schema version changes, e.g. a data change, doesn't need a new snapshot.
+	if len(latestList) > 0 && latestList[0].Checksum == checksum {
+		return nil
+	}
+
+	if _, err := s.SchemaSnapshotService.CreateSchemaSnapshot(ctx, &api.SchemaSnapshotCreate{
+		CreatorID:     api.SystemBotID,
+		DatabaseID:    database.ID,
+		SchemaVersion: schemaVersion,
+		Checksum:      checksum,
+		Metadata:      string(metadata),
+	}); err != nil {
+		return fmt.Errorf("failed to create schema snapshot for database %q, error: %w", database.Name, err)
+	}
+	return nil
+}
+
+// createSchemaSnapshotOrWarn is the same as createSchemaSnapshotIfChanged, except that it only logs the error,
+// since failing to take a snapshot shouldn't fail the schema sync.
+func (s *Server) createSchemaSnapshotOrWarn(ctx context.Context, database *api.Database, schema *db.Schema, schemaVersion string) {
+	if err := s.createSchemaSnapshotIfChanged(ctx, database, schema, schemaVersion); err != nil {
+		s.l.Warn("Failed to create schema snapshot",
+			zap.Int("database_id", database.ID),
+			zap.String("database_name", database.Name),
+			zap.Error(err),
+		)
+	}
+}
+
+// newSchemaSnapshotMetadata converts the synced schema to the snapshot metadata.
+// Tables, views, columns and indexes are sorted so that the same schema always produces the same metadata.
+func newSchemaSnapshotMetadata(schema *db.Schema) *api.SchemaSnapshotMetadata {
+	metadata := &api.SchemaSnapshotMetadata{
+		CharacterSet: schema.CharacterSet,
+		Collation:    schema.Collation,
+		TableList:    []*api.SchemaSnapshotTable{},
+		ViewList:     []*api.SchemaSnapshotView{},
+	}
+	for _, table := range schema.TableList {
+		t := &api.SchemaSnapshotTable{
+			Name:          table.Name,
+			Type:          table.Type,
+			Engine:        table.Engine,
+			Collation:     table.Collation,
+			CreateOptions: table.CreateOptions,
+			Comment:       table.Comment,
+			ColumnList:    []*api.SchemaSnapshotColumn{},
+			IndexList:     []*api.SchemaSnapshotIndex{},
+		}
+		for _, column := range table.ColumnList {
+			t.ColumnList = append(t.ColumnList, &api.SchemaSnapshotColumn{
+				Name:         column.Name,
+				Position:     column.Position,
+				Default:      column.Default,
+				Nullable:     column.Nullable,
+				Type:         column.Type,
+				CharacterSet: column.CharacterSet,
+				Collation:    column.Collation,
+				Comment:      column.Comment,
+			})
+		}
+		sort.Slice(t.ColumnList, func(i, j int) bool {
+			if t.ColumnList[i].Position != t.ColumnList[j].Position {
+				return t.ColumnList[i].Position < t.ColumnList[j].Position
+			}
+			return t.ColumnList[i].Name < t.ColumnList[j].Name
+		})
+		for _, index := range table.IndexList {
+			t.IndexList = append(t.IndexList, &api.SchemaSnapshotIndex{
+				Name:       index.Name,
+				Expression: index.Expression,
+				Position:   index.Position,
+				Type:       index.Type,
+				Unique:     index.Unique,
+				Visible:    index.Visible,
+				Comment:    index.Comment,
+			})
+		}
+		sort.Slice(t.IndexList, func(i, j int) bool {
+			if t.IndexList[i].Name != t.IndexList[j].Name {
+				return t.IndexList[i].Name < t.IndexList[j].Name
+			}
+			return t.IndexList[i].Position < t.IndexList[j].Position
+		})
+		metadata.TableList = append(metadata.TableList, t)
+	}
+	sort.Slice(metadata.TableList, func(i, j int) bool {
+		return metadata.TableList[i].Name < metadata.TableList[j].Name
+	})
+	for _, view := range schema.ViewList {
+		metadata.ViewList = append(metadata.ViewList, &api.SchemaSnapshotView{
+			Name:       view.Name,
+			Definition: view.Definition,
+			Comment:    view.Comment,
+		})
+	}
+	sort.Slice(metadata.ViewList, func(i, j int) bool {
+		return metadata.ViewList[i].Name < metadata.ViewList[j].Name
+	})
+	return metadata
+}
+
+// schemaSnapshotDefinition is the canonical text definitions of the objects in a snapshot, keyed by the object name.
+type schemaSnapshotDefinition struct {
+	tables map[string]*schemaSnapshotTableDefinition
+	views  map[string]string
+}
+
+type schemaSnapshotTableDefinition struct {
+	options string
+	columns map[string]string
+	indexes map[string]string
+	// lines is the canonical text of the table, in the order of the options, columns and indexes.
+	lines []string
+}
+
+func newSchemaSnapshotDefinition(metadata *api.SchemaSnapshotMetadata) *schemaSnapshotDefinition {
+	def := &schemaSnapshotDefinition{
+		tables: make(map[string]*schemaSnapshotTableDefinition),
+		views:  make(map[string]string),
+	}
+	for _, table := range metadata.TableList {
+		var optionList []string
+		for _, option := range []struct{ key, value string }{
+			{"TYPE", table.Type},
+			{"ENGINE", table.Engine},
+			{"COLLATE", table.Collation},
+			{"OPTIONS", table.CreateOptions},
+			{"COMMENT", table.Comment},
+		} {
+			if option.value != "" {
+				optionList = append(optionList, fmt.Sprintf("%s=%q", option.key, option.value))
+			}
+		}
+		t := &schemaSnapshotTableDefinition{
+			options: strings.Join(optionList, " "),
+			columns: make(map[string]string),
+			indexes: make(map[string]string),
+		}
+		t.lines = append(t.lines, strings.TrimSpace(fmt.Sprintf("TABLE %s %s", table.Name, t.options)))
+
+		for _, column := range table.ColumnList {
+			t.columns[column.Name] = getSchemaSnapshotColumnDefinition(column)
+			t.lines = append(t.lines, fmt.Sprintf("  COLUMN %s %s", column.Name, t.columns[column.Name]))
+		}
+
+		var indexNameList []string
+		indexExpressions := make(map[string][]string)
+		indexFirst := make(map[string]*api.SchemaSnapshotIndex)
+		for _, index := range table.IndexList {
+			if _, ok := indexFirst[index.Name]; !ok {
+				indexNameList = append(indexNameList, index.Name)
+				indexFirst[index.Name] = index
+			}
+			indexExpressions[index.Name] = append(indexExpressions[index.Name], index.Expression)
+		}
+		for _, name := range indexNameList {
+			t.indexes[name] = getSchemaSnapshotIndexDefinition(indexFirst[name], indexExpressions[name])
+			t.lines = append(t.lines, fmt.Sprintf("  INDEX %s %s", name, t.indexes[name]))
+		}
+		def.tables[table.Name] = t
+	}
+	for _, view := range metadata.ViewList {
+		definition := view.Definition
+		if view.Comment != "" {
+			definition += fmt.Sprintf(" COMMENT=%q", view.Comment)
+		}
+		def.views[view.Name] = definition
+	}
+	return def
+}
+
+func getSchemaSnapshotColumnDefinition(column *api.SchemaSnapshotColumn) string {
+	var buf strings.Builder
+	buf.WriteString(column.Type)
+	if column.CharacterSet != "" {
+		fmt.Fprintf(&buf, " CHARACTER SET %s", column.CharacterSet)
+	}
+	if column.Collation != "" {
+		fmt.Fprintf(&buf, " COLLATE %s", column.Collation)
+	}
+	if !column.Nullable {
+		buf.WriteString(" NOT NULL")
+	}
+	if column.Default != nil {
+		fmt.Fprintf(&buf, " DEFAULT %q", *column.Default)
+	}
+	if column.Comment != "" {
+		fmt.Fprintf(&buf, " COMMENT %q", column.Comment)
+	}
+	return buf.String()
+}
+
+func getSchemaSnapshotIndexDefinition(index *api.SchemaSnapshotIndex, expressionList []string) string {
+	var buf strings.Builder
+	if index.Unique {
+		buf.WriteString("UNIQUE ")
+	}
+	if index.Type != "" {
+		fmt.Fprintf(&buf, "%s ", index.Type)
+	}
+	fmt.Fprintf(&buf, "(%s)", strings.Join(expressionList, ", "))
+	if !index.Visible {
+		buf.WriteString(" INVISIBLE")
+	}
+	if index.Comment != "" {
+		fmt.Fprintf(&buf, " COMMENT %q", index.Comment)
+	}
+	return buf.String()
+}
+
+// text returns the canonical text of the snapshot for the unified diff.
+func (def *schemaSnapshotDefinition) text() string {
+	var lines []string
+	for _, name := range sortedSchemaSnapshotKeys(def.tables) {
+		lines = append(lines, def.tables[name].lines...)
+	}
+	viewNames := make(map[string]bool)
+	for name := range def.views {
+		viewNames[name] = true
+	}
+	for _, name := range sortedKeys(viewNames) {
+		lines = append(lines, fmt.Sprintf("VIEW %s AS %s", name, def.views[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sortedSchemaSnapshotKeys(m map[string]*schemaSnapshotTableDefinition) []string {
+	keys := make(map[string]bool)
+	for k := range m {
+		keys[k] = true
+	}
+	return sortedKeys(keys)
+}
+
+// diffSchemaSnapshot returns the changes from the "from" snapshot to the "to" snapshot.
+func diffSchemaSnapshot(from, to *api.SchemaSnapshotRaw) (*api.SchemaSnapshotDiff, error) {
+	var fromMetadata, toMetadata api.SchemaSnapshotMetadata
+	if err := json.Unmarshal([]byte(from.Metadata), &fromMetadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata of schema snapshot %d, error: %w", from.ID, err)
+	}
+	if err := json.Unmarshal([]byte(to.Metadata), &toMetadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata of schema snapshot %d, error: %w", to.ID, err)
+	}
+	fromDef, toDef := newSchemaSnapshotDefinition(&fromMetadata), newSchemaSnapshotDefinition(&toMetadata)
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromDef.text()),
+		B:        difflib.SplitLines(toDef.text()),
+		FromFile: fmt.Sprintf("snapshot-%d (%s)", from.ID, from.SchemaVersion),
+		ToFile:   fmt.Sprintf("snapshot-%d (%s)", to.ID, to.SchemaVersion),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itemList := diffSchemaSnapshotDefinition(fromDef, toDef)
+	if itemList == nil {
+		itemList = []*api.SchemaDriftItem{}
+	}
+	return &api.SchemaSnapshotDiff{
+		FromSnapshotID: from.ID,
+		ToSnapshotID:   to.ID,
+		ItemList:       itemList,
+		Diff:           diff,
+	}, nil
+}
+
+func diffSchemaSnapshotDefinition(from, to *schemaSnapshotDefinition) []*api.SchemaDriftItem {
+	var itemList []*api.SchemaDriftItem
+
+	tableNames := make(map[string]bool)
+	for name := range from.tables {
+		tableNames[name] = true
+	}
+	for name := range to.tables {
+		tableNames[name] = true
+	}
+	for _, name := range sortedKeys(tableNames) {
+		fromTable, toTable := from.tables[name], to.tables[name]
+		switch {
+		case fromTable == nil:
+			itemList = append(itemList, &api.SchemaDriftItem{
+				Type:   api.SchemaDriftObjectTable,
+				Action: api.SchemaDriftActionAdded,
+				Name:   name,
+				Actual: strings.Join(toTable.lines, "\n"),
+			})
+			continue
+		case toTable == nil:
+			itemList = append(itemList, &api.SchemaDriftItem{
+				Type:   api.SchemaDriftObjectTable,
+				Action: api.SchemaDriftActionRemoved,
+				Name:   name,
+				Expect: strings.Join(fromTable.lines, "\n"),
+			})
+			continue
+		}
+		if fromTable.options != toTable.options {
+			itemList = append(itemList, &api.SchemaDriftItem{
+				Type:   api.SchemaDriftObjectTable,
+				Action: api.SchemaDriftActionChanged,
+				Name:   name,
+				Expect: fromTable.options,
+				Actual: toTable.options,
+			})
+		}
+		itemList = append(itemList, diffSchemaSnapshotObjects(api.SchemaDriftObjectColumn, name, fromTable.columns, toTable.columns)...)
+		itemList = append(itemList, diffSchemaSnapshotObjects(api.SchemaDriftObjectIndex, name, fromTable.indexes, toTable.indexes)...)
+	}
+	itemList = append(itemList, diffSchemaSnapshotObjects(api.SchemaDriftObjectView, "", from.views, to.views)...)
+	return itemList
+}
+
+func diffSchemaSnapshotObjects(objectType api.SchemaDriftObjectType, table string, from, to map[string]string) []*api.SchemaDriftItem {
+	var itemList []*api.SchemaDriftItem
+	names := make(map[string]bool)
+	for name := range from {
+		names[name] = true
+	}
+	for name := range to {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		fromDef, inFrom := from[name]
+		toDef, inTo := to[name]
+		item := &api.SchemaDriftItem{
+			Type:   objectType,
+			Table:  table,
+			Name:   name,
+			Expect: fromDef,
+			Actual: toDef,
+		}
+		switch {
+		case !inFrom:
+			item.Action = api.SchemaDriftActionAdded
+		case !inTo:
+			item.Action = api.SchemaDriftActionRemoved
+		case fromDef != toDef:
+			item.Action = api.SchemaDriftActionChanged
+		default:
+			continue
+		}
+		itemList = append(itemList, item)
+	}
+	return itemList
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestSchemaSnapshot(t *testing.T, id int, schema *db.Schema) *api.SchemaSnapshotRaw {
+	metadata, err := json.Marshal(newSchemaSnapshotMetadata(schema))
+	if err != nil {
+		t.Fatalf("failed to marshal schema snapshot metadata: %v", err)
+	}
+	return &api.SchemaSnapshotRaw{
+		ID:            id,
+		SchemaVersion: "0001",
+		Metadata:      string(metadata),
+	}
+}
+
+func TestNewSchemaSnapshotMetadata(t *testing.T) {
+	// The statistics and the order of the synced objects shouldn't affect the metadata.
+	a := &db.Schema{
+		Name: "db",
+		TableList: []db.Table{
+			{Name: "book", RowCount: 10, ColumnList: []db.Column{{Name: "id", Position: 1, Type: "int"}}},
+			{Name: "author", RowCount: 1, ColumnList: []db.Column{{Name: "name", Position: 2, Type: "text"}, {Name: "id", Position: 1, Type: "int"}}},
+		},
+	}
+	b := &db.Schema{
+		Name: "db",
+		TableList: []db.Table{
+			{Name: "author", RowCount: 5, DataSize: 4096, ColumnList: []db.Column{{Name: "id", Position: 1, Type: "int"}, {Name: "name", Position: 2, Type: "text"}}},
+			{Name: "book", RowCount: 20, ColumnList: []db.Column{{Name: "id", Position: 1, Type: "int"}}},
+		},
+	}
+	if diff := cmp.Diff(newSchemaSnapshotMetadata(a), newSchemaSnapshotMetadata(b)); diff != "" {
+		t.Errorf("newSchemaSnapshotMetadata() mismatch (-a +b):\n%s", diff)
+	}
+}
+
+func TestDiffSchemaSnapshot(t *testing.T) {
+	defaultValue := "0"
+	from := newTestSchemaSnapshot(t, 101, &db.Schema{
+		Name: "db",
+		TableList: []db.Table{
+			{
+				Name:   "author",
+				Engine: "InnoDB",
+				ColumnList: []db.Column{
+					{Name: "id", Position: 1, Type: "int"},
+					{Name: "name", Position: 2, Type: "varchar(64)"},
+				},
+				IndexList: []db.Index{{Name: "PRIMARY", Expression: "id", Position: 1, Unique: true, Visible: true}},
+			},
+			{Name: "legacy", Engine: "InnoDB", ColumnList: []db.Column{{Name: "id", Position: 1, Type: "int"}}},
+		},
+		ViewList: []db.View{{Name: "v", Definition: "select 1"}},
+	})
+	to := newTestSchemaSnapshot(t, 102, &db.Schema{
+		Name: "db",
+		TableList: []db.Table{
+			{
+				Name:   "author",
+				Engine: "InnoDB",
+				ColumnList: []db.Column{
+					{Name: "id", Position: 1, Type: "int"},
+					{Name: "name", Position: 2, Type: "varchar(128)"},
+					{Name: "age", Position: 3, Type: "int", Default: &defaultValue, Nullable: true},
+				},
+				IndexList: []db.Index{
+					{Name: "PRIMARY", Expression: "id", Position: 1, Unique: true, Visible: true},
+					{Name: "idx_name_age", Expression: "name", Position: 1, Visible: true},
+					{Name: "idx_name_age", Expression: "age", Position: 2, Visible: true},
+				},
+			},
+		},
+		ViewList: []db.View{{Name: "v", Definition: "select 2"}},
+	})
+
+	diff, err := diffSchemaSnapshot(from, to)
+	if err != nil {
+		t.Fatalf("diffSchemaSnapshot() error: %v", err)
+	}
+	want := []*api.SchemaDriftItem{
+		{Type: api.SchemaDriftObjectColumn, Action: api.SchemaDriftActionAdded, Table: "author", Name: "age", Actual: `int DEFAULT "0"`},
+		{Type: api.SchemaDriftObjectColumn, Action: api.SchemaDriftActionChanged, Table: "author", Name: "name", Expect: "varchar(64) NOT NULL", Actual: "varchar(128) NOT NULL"},
+		{Type: api.SchemaDriftObjectIndex, Action: api.SchemaDriftActionAdded, Table: "author", Name: "idx_name_age", Actual: "(name, age)"},
+		{Type: api.SchemaDriftObjectTable, Action: api.SchemaDriftActionRemoved, Name: "legacy", Expect: "TABLE legacy ENGINE=\"InnoDB\"\n  COLUMN id int NOT NULL"},
+		{Type: api.SchemaDriftObjectView, Action: api.SchemaDriftActionChanged, Name: "v", Expect: "select 1", Actual: "select 2"},
+	}
+	if d := cmp.Diff(want, diff.ItemList); d != "" {
+		t.Errorf("diffSchemaSnapshot() item list mismatch (-want +got):\n%s", d)
+	}
+	if diff.FromSnapshotID != 101 || diff.ToSnapshotID != 102 {
+		t.Errorf("diffSchemaSnapshot() snapshot IDs = %d, %d, want 101, 102", diff.FromSnapshotID, diff.ToSnapshotID)
+	}
+	if diff.Diff == "" {
+		t.Errorf("diffSchemaSnapshot() unified diff is empty")
+	}
+
+	same, err := diffSchemaSnapshot(from, from)
+	if err != nil {
+		t.Fatalf("diffSchemaSnapshot() error: %v", err)
+	}
+	if len(same.ItemList) != 0 || same.Diff != "" {
+		t.Errorf("diffSchemaSnapshot() of the same snapshot = %v, %q, want no change", same.ItemList, same.Diff)
+	}
+}
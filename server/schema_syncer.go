@@ -12,6 +12,11 @@ import (
 
 const (
 	schemaSyncInterval = time.Duration(30) * time.Minute
+	// schemaFullSyncInterval is the interval to sync all databases of an instance regardless of the DDL fingerprint,
+	// which refreshes the table statistics like the row count and the users.
+	schemaFullSyncInterval = time.Duration(24) * time.Hour
+	// maxConcurrentSchemaSyncPerInstance is the maximum number of the databases syncing concurrently in each instance.
+	maxConcurrentSchemaSyncPerInstance = 2
 )
 
 // NewSchemaSyncer creates a schema syncer.
@@ -35,6 +40,8 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	s.l.Debug(fmt.Sprintf("Schema syncer started and will run every %v", schemaSyncInterval))
 	runningTasks := make(map[int]bool)
+	// The last time of syncing all databases for each instance, it starts from now as the databases are synced incrementally anyway.
+	lastFullSyncTime := make(map[int]time.Time)
 	mu := sync.RWMutex{}
 	for {
 		select {
@@ -70,6 +77,13 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 						continue
 					}
 					runningTasks[instanceRaw.ID] = true
+					if _, ok := lastFullSyncTime[instanceRaw.ID]; !ok {
+						lastFullSyncTime[instanceRaw.ID] = time.Now()
+					}
+					incremental := time.Since(lastFullSyncTime[instanceRaw.ID]) < schemaFullSyncInterval
+					if !incremental {
+						lastFullSyncTime[instanceRaw.ID] = time.Now()
+					}
 					mu.Unlock()
 
 					instance, err := s.server.composeInstanceRelationship(ctx, instanceRaw)
@@ -80,21 +94,21 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 							zap.String("error", err.Error()))
 						continue
 					}
-					go func(instance *api.Instance) {
-						s.l.Debug("Sync instance schema", zap.String("instance", instance.Name))
+					go func(instance *api.Instance, incremental bool) {
+						s.l.Debug("Sync instance schema", zap.String("instance", instance.Name), zap.Bool("incremental", incremental))
 						defer func() {
 							mu.Lock()
 							delete(runningTasks, instance.ID)
 							mu.Unlock()
 						}()
-						resultSet := s.server.syncEngineVersionAndSchema(ctx, instance)
+						resultSet := s.server.syncEngineVersionAndSchema(ctx, instance, incremental)
 						if resultSet.Error != "" {
 							s.l.Debug("Failed to sync instance",
 								zap.Int("id", instance.ID),
 								zap.String("name", instance.Name),
 								zap.String("error", resultSet.Error))
 						}
-					}(instance)
+					}(instance, incremental)
 				}
 			}()
 		case <-ctx.Done(): // if cancel() execute
@@ -102,3 +116,35 @@ func (s *SchemaSyncer) Run(ctx context.Context, wg *sync.WaitGroup) {
 		}
 	}
 }
+
+// instanceSyncLimiter limits the concurrent database schema syncs of each instance,
+// so that syncing an instance with thousands of databases doesn't hammer it.
+type instanceSyncLimiter struct {
+	limit int
+
+	mu           sync.Mutex
+	semaphoreMap map[int]chan struct{}
+}
+
+func newInstanceSyncLimiter(limit int) *instanceSyncLimiter {
+	return &instanceSyncLimiter{
+		limit:        limit,
+		semaphoreMap: make(map[int]chan struct{}),
+	}
+}
+
+// acquire blocks until a sync slot of the instance is available, and returns the function to release the slot.
+func (l *instanceSyncLimiter) acquire(instanceID int) func() {
+	l.mu.Lock()
+	semaphore, ok := l.semaphoreMap[instanceID]
+	if !ok {
+		semaphore = make(chan struct{}, l.limit)
+		l.semaphoreMap[instanceID] = semaphore
+	}
+	l.mu.Unlock()
+
+	semaphore <- struct{}{}
+	return func() {
+		<-semaphore
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInstanceSyncLimiter(t *testing.T) {
+	limit := 2
+	limiter := newInstanceSyncLimiter(limit)
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := limiter.acquire(1)
+			defer release()
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	if maxRunning > int32(limit) {
+		t.Errorf("got %d concurrent syncs, want at most %d", maxRunning, limit)
+	}
+
+	// The limit is per instance, so another instance is not blocked.
+	release := limiter.acquire(1)
+	defer release()
+	release2 := limiter.acquire(1)
+	defer release2()
+	done := make(chan struct{})
+	go func() {
+		limiter.acquire(2)()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("acquire() blocked by the syncs of another instance")
+	}
+}
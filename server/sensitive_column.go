@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerSensitiveColumnRoutes(g *echo.Group) {
+	g.GET("/database/:id/sensitive-column", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		sensitiveColumnRawList, err := s.SensitiveColumnService.FindSensitiveColumnList(ctx, &api.SensitiveColumnFind{DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sensitive column list for database ID: %v", id)).SetInternal(err)
+		}
+		var sensitiveColumnList []*api.SensitiveColumn
+		for _, raw := range sensitiveColumnRawList {
+			sensitiveColumn, err := s.composeSensitiveColumnRelationship(ctx, raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sensitive column relationship: %v", raw.ID)).SetInternal(err)
+			}
+			sensitiveColumnList = append(sensitiveColumnList, sensitiveColumn)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, sensitiveColumnList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal sensitive column list response for database ID: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.POST("/database/:id/sensitive-column", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		sensitiveColumnUpsert := &api.SensitiveColumnUpsert{
+			CreatorID:  c.Get(getPrincipalIDContextKey()).(int),
+			DatabaseID: id,
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, sensitiveColumnUpsert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted mark sensitive column request").SetInternal(err)
+		}
+		if sensitiveColumnUpsert.TableName == "" || sensitiveColumnUpsert.ColumnName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted mark sensitive column request, missing table name or column name")
+		}
+		if !sensitiveColumnUpsert.MaskingType.Valid() {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid masking type %q, expect one of FULL, PARTIAL and HASH", sensitiveColumnUpsert.MaskingType))
+		}
+
+		databaseRaw, err := s.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if databaseRaw == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		sensitiveColumnRaw, err := s.SensitiveColumnService.UpsertSensitiveColumn(ctx, sensitiveColumnUpsert)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark sensitive column").SetInternal(err)
+		}
+
+		sensitiveColumn, err := s.composeSensitiveColumnRelationship(ctx, sensitiveColumnRaw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch sensitive column relationship").SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, sensitiveColumn); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal mark sensitive column response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database/:id/sensitive-column/:columnID", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		columnID, err := strconv.Atoi(c.Param("columnID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Sensitive column ID is not a number: %s", c.Param("columnID"))).SetInternal(err)
+		}
+
+		existing, err := s.SensitiveColumnService.FindSensitiveColumn(ctx, &api.SensitiveColumnFind{ID: &columnID, DatabaseID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sensitive column ID: %v", columnID)).SetInternal(err)
+		}
+		if existing == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Sensitive column ID not found: %d", columnID))
+		}
+
+		sensitiveColumnDelete := &api.SensitiveColumnDelete{
+			ID:        columnID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.SensitiveColumnService.DeleteSensitiveColumn(ctx, sensitiveColumnDelete); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmark sensitive column ID: %v", columnID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+func (s *Server) composeSensitiveColumnRelationship(ctx context.Context, raw *api.SensitiveColumnRaw) (*api.SensitiveColumn, error) {
+	sensitiveColumn := raw.ToSensitiveColumn()
+
+	creator, err := s.composePrincipalByID(ctx, sensitiveColumn.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	sensitiveColumn.Creator = creator
+
+	updater, err := s.composePrincipalByID(ctx, sensitiveColumn.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	sensitiveColumn.Updater = updater
+
+	return sensitiveColumn, nil
+}
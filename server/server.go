@@ -30,8 +30,16 @@ type Server struct {
 	TaskCheckScheduler *TaskCheckScheduler
 	SchemaSyncer       *SchemaSyncer
 	BackupRunner       *BackupRunner
+	BinlogArchiver     *BinlogArchiver
 	AnomalyScanner     *AnomalyScanner
+	SlowQueryCollector *SlowQueryCollector
 	runnerWG           sync.WaitGroup
+	// schemaSyncLimiter limits the concurrent database schema syncs of each instance.
+	schemaSyncLimiter *instanceSyncLimiter
+	// anomalyNotifier de-duplicates the notifications of the anomalies.
+	anomalyNotifier *anomalyNotifier
+	// sqlQueryManager tracks the running SQL editor queries and the open query cursors.
+	sqlQueryManager *sqlQueryManager
 
 	ActivityManager *ActivityManager
 
@@ -52,6 +60,11 @@ type Server struct {
 	ColumnService           api.ColumnService
 	ViewService             api.ViewService
 	IndexService            api.IndexService
+	ForeignKeyService       api.ForeignKeyService
+	CheckConstraintService  api.CheckConstraintService
+	TriggerService          api.TriggerService
+	RoutineService          api.RoutineService
+	SequenceService         api.SequenceService
 	DataSourceService       api.DataSourceService
 	BackupService           api.BackupService
 	IssueService            api.IssueService
@@ -70,6 +83,12 @@ type Server struct {
 	DeploymentConfigService api.DeploymentConfigService
 	LicenseService          enterprise.LicenseService
 	SheetService            api.SheetService
+	BinlogFileService       api.BinlogFileService
+	SchemaDriftRuleService  api.SchemaDriftRuleService
+	SchemaSnapshotService   api.SchemaSnapshotService
+	SlowQueryService        api.SlowQueryService
+	SensitiveColumnService  api.SensitiveColumnService
+	QueryHistoryService     api.QueryHistoryService
 
 	e *echo.Echo
 
@@ -87,6 +106,7 @@ type Server struct {
 	readonly      bool
 	demo          bool
 	dataDir       string
+	mysqlBinDir   string
 	subscription  *enterprise.Subscription
 }
 
@@ -103,7 +123,7 @@ var casbinDBAPolicy string
 var casbinDeveloperPolicy string
 
 // NewServer creates a server.
-func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string, host string, port int, frontendHost string, frontendPort, datastorePort int, mode string, dataDir string, backupRunnerInterval time.Duration, secret string, readonly bool, demo bool, debug bool) *Server {
+func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string, host string, port int, frontendHost string, frontendPort, datastorePort int, mode string, dataDir string, mysqlBinDir string, backupRunnerInterval time.Duration, secret string, readonly bool, demo bool, debug bool) *Server {
 	e := echo.New()
 	e.Debug = debug
 	e.HideBanner = true
@@ -133,6 +153,11 @@ func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string,
 		readonly:      readonly,
 		demo:          demo,
 		dataDir:       dataDir,
+		mysqlBinDir:   mysqlBinDir,
+
+		schemaSyncLimiter: newInstanceSyncLimiter(maxConcurrentSchemaSyncPerInstance),
+		anomalyNotifier:   newAnomalyNotifier(),
+		sqlQueryManager:   newSQLQueryManager(),
 	}
 
 	if !readonly {
@@ -184,8 +209,14 @@ func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string,
 		// Backup runner
 		s.BackupRunner = NewBackupRunner(logger, s, backupRunnerInterval)
 
+		// Binlog archiver
+		s.BinlogArchiver = NewBinlogArchiver(logger, s, backupRunnerInterval)
+
 		// Anomaly scanner
 		s.AnomalyScanner = NewAnomalyScanner(logger, s)
+
+		// Slow query collector
+		s.SlowQueryCollector = NewSlowQueryCollector(logger, s)
 	}
 
 	// Middleware
@@ -234,16 +265,28 @@ func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string,
 	s.registerProjectRoutes(apiGroup)
 	s.registerProjectWebhookRoutes(apiGroup)
 	s.registerProjectMemberRoutes(apiGroup)
+	s.registerSchemaDriftRuleRoutes(apiGroup)
+	s.registerSchemaComparisonRoutes(apiGroup)
 	s.registerEnvironmentRoutes(apiGroup)
 	s.registerInstanceRoutes(apiGroup)
 	s.registerDatabaseRoutes(apiGroup)
+	s.registerSchemaSnapshotRoutes(apiGroup)
+	s.registerSlowQueryRoutes(apiGroup)
+	s.registerSensitiveColumnRoutes(apiGroup)
+	s.registerDataDictionaryRoutes(apiGroup)
+	s.registerERDiagramRoutes(apiGroup)
+	s.registerSchemaSearchRoutes(apiGroup)
 	s.registerIssueRoutes(apiGroup)
 	s.registerIssueSubscriberRoutes(apiGroup)
 	s.registerTaskRoutes(apiGroup)
 	s.registerActivityRoutes(apiGroup)
+	s.registerAnomalyRoutes(apiGroup)
 	s.registerInboxRoutes(apiGroup)
 	s.registerBookmarkRoutes(apiGroup)
 	s.registerSQLRoutes(apiGroup)
+	s.registerSQLExportRoutes(apiGroup)
+	s.registerSQLQueryRoutes(apiGroup)
+	s.registerQueryHistoryRoutes(apiGroup)
 	s.registerVCSRoutes(apiGroup)
 	s.registerLabelRoutes(apiGroup)
 	s.registerSubscriptionRoutes(apiGroup)
@@ -276,8 +319,12 @@ func (server *Server) Run(ctx context.Context) error {
 		server.runnerWG.Add(1)
 		go server.BackupRunner.Run(ctx, &server.runnerWG)
 		server.runnerWG.Add(1)
+		go server.BinlogArchiver.Run(ctx, &server.runnerWG)
+		server.runnerWG.Add(1)
 		go server.AnomalyScanner.Run(ctx, &server.runnerWG)
 		server.runnerWG.Add(1)
+		go server.SlowQueryCollector.Run(ctx, &server.runnerWG)
+		server.runnerWG.Add(1)
 	}
 
 	// Sleep for 1 sec to make sure port is released between runs.
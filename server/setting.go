@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -37,6 +38,9 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 		for _, setting := range settingList {
 			for _, whitelist := range whitelistSettings {
 				if setting.Name == whitelist {
+					if err := redactSetting(setting); err != nil {
+						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to redact setting: %v", setting.Name)).SetInternal(err)
+					}
 					filteredList = append(filteredList, setting)
 					break
 				}
@@ -59,14 +63,41 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, settingPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted update setting request").SetInternal(err)
 		}
+		if settingPatch.Name == api.SettingBackupStorage {
+			backupStorageSetting, err := api.UnmarshalBackupStorageSetting(settingPatch.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid backup storage setting: %v", err)).SetInternal(err)
+			}
+			// The secrets redacted in the response are sent back as is if they're not changed.
+			oldBackupStorageSetting, err := s.getBackupStorageSetting(ctx)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch backup storage setting").SetInternal(err)
+			}
+			backupStorageSetting.RestoreRedacted(oldBackupStorageSetting)
+			value, err := json.Marshal(backupStorageSetting)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal backup storage setting").SetInternal(err)
+			}
+			settingPatch.Value = string(value)
+			if err := s.validateBackupEncryptionKeyRotation(ctx, backupStorageSetting); err != nil {
+				return err
+			}
+		}
 
-		setting, err := s.SettingService.PatchSetting(ctx, settingPatch)
+		settingRaw, err := s.SettingService.PatchSetting(ctx, settingPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
 				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Setting name not found: %s", settingPatch.Name))
 			}
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update setting: %v", settingPatch.Name)).SetInternal(err)
 		}
+		setting, err := s.composeSettingRelationship(ctx, settingRaw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compose setting relationship for ID %d", settingRaw.ID)).SetInternal(err)
+		}
+		if err := redactSetting(setting); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to redact setting: %v", settingPatch.Name)).SetInternal(err)
+		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
@@ -93,3 +124,20 @@ func (s *Server) composeSettingRelationship(ctx context.Context, raw *api.Settin
 
 	return setting, nil
 }
+
+// redactSetting replaces the secrets in the setting value before it's returned to the client.
+func redactSetting(setting *api.Setting) error {
+	if setting.Name != api.SettingBackupStorage {
+		return nil
+	}
+	backupStorageSetting, err := api.UnmarshalBackupStorageSetting(setting.Value)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(backupStorageSetting.Redact())
+	if err != nil {
+		return err
+	}
+	setting.Value = string(value)
+	return nil
+}
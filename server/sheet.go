@@ -8,6 +8,8 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/param"
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 )
@@ -237,5 +239,12 @@ func (s *Server) composeSheetRelationship(ctx context.Context, raw *api.SheetRaw
 		sheet.Database = database
 	}
 
+	// The engine is unknown for the sheet without the database, whose string literals are scanned the MySQL way.
+	var engine db.Type
+	if sheet.Database != nil && sheet.Database.Instance != nil {
+		engine = sheet.Database.Instance.Engine
+	}
+	sheet.ParameterList = param.GetNameList(engine, sheet.Statement)
+
 	return sheet, nil
 }
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultSlowQueryWindow is the default time window of the slow query report and trend.
+	defaultSlowQueryWindow = time.Duration(7*24) * time.Hour
+	// defaultSlowQueryReportLimit is the default number of statements returned by the slow query report.
+	defaultSlowQueryReportLimit = 20
+)
+
+func (s *Server) registerSlowQueryRoutes(g *echo.Group) {
+	g.GET("/database/:id/slow-query", func(c echo.Context) error {
+		ctx := context.Background()
+		database, err := s.findSlowQueryDatabase(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+		fromTs, toTs, err := getSlowQueryWindow(c)
+		if err != nil {
+			return err
+		}
+		limit := defaultSlowQueryReportLimit
+		if limitStr := c.QueryParam("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter limit is not a positive number: %s", limitStr)).SetInternal(err)
+			}
+		}
+
+		slowQueryList, err := s.SlowQueryService.FindSlowQueryList(ctx, &api.SlowQueryFind{
+			DatabaseID:      &database.ID,
+			CreatedTsAfter:  &fromTs,
+			CreatedTsBefore: &toTs,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch slow query list for database ID: %v", database.ID)).SetInternal(err)
+		}
+		report := &api.SlowQueryReport{
+			DatabaseID: database.ID,
+			FromTs:     fromTs,
+			ToTs:       toTs,
+			ItemList:   getSlowQueryReportItemList(slowQueryList, limit),
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, report); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal slow query report response for database ID: %v", database.ID)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/database/:id/slow-query/trend", func(c echo.Context) error {
+		ctx := context.Background()
+		database, err := s.findSlowQueryDatabase(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+		fromTs, toTs, err := getSlowQueryWindow(c)
+		if err != nil {
+			return err
+		}
+
+		slowQueryFind := &api.SlowQueryFind{
+			DatabaseID:      &database.ID,
+			CreatedTsAfter:  &fromTs,
+			CreatedTsBefore: &toTs,
+		}
+		digest := c.QueryParam("digest")
+		if digest != "" {
+			slowQueryFind.Digest = &digest
+		}
+		slowQueryList, err := s.SlowQueryService.FindSlowQueryList(ctx, slowQueryFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch slow query list for database ID: %v", database.ID)).SetInternal(err)
+		}
+		trend := &api.SlowQueryTrend{
+			DatabaseID: database.ID,
+			Digest:     digest,
+			PointList:  getSlowQueryTrendPointList(slowQueryList),
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, trend); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal slow query trend response for database ID: %v", database.ID)).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+func (s *Server) findSlowQueryDatabase(ctx context.Context, idStr string) (*api.Database, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", idStr)).SetInternal(err)
+	}
+	database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &id})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+	}
+	return database, nil
+}
+
+// getSlowQueryWindow returns the time window [from, to) in Unix seconds from the query parameters.
+// It defaults to the last 7 days.
+func getSlowQueryWindow(c echo.Context) (int64, int64, error) {
+	toTs := time.Now().Unix()
+	if toStr := c.QueryParam("to"); toStr != "" {
+		ts, err := strconv.ParseInt(toStr, 10, 64)
+		if err != nil {
+			return 0, 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter to is not a number: %s", toStr)).SetInternal(err)
+		}
+		toTs = ts
+	}
+	fromTs := toTs - int64(defaultSlowQueryWindow.Seconds())
+	if fromStr := c.QueryParam("from"); fromStr != "" {
+		ts, err := strconv.ParseInt(fromStr, 10, 64)
+		if err != nil {
+			return 0, 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter from is not a number: %s", fromStr)).SetInternal(err)
+		}
+		fromTs = ts
+	}
+	if fromTs >= toTs {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter from %d must be earlier than to %d", fromTs, toTs))
+	}
+	return fromTs, toTs, nil
+}
+
+// getSlowQueryReportItemList sums up the slow queries by the digest, and returns at most limit statements
+// with the highest total latency.
+func getSlowQueryReportItemList(slowQueryList []*api.SlowQueryRaw, limit int) []*api.SlowQueryReportItem {
+	var itemList []*api.SlowQueryReportItem
+	for digest, stat := range getSlowQueryStatMap(slowQueryList) {
+		itemList = append(itemList, &api.SlowQueryReportItem{
+			Digest:         digest,
+			Statement:      stat.statement,
+			Calls:          stat.calls,
+			TotalLatencyUs: stat.totalLatencyUs,
+			MeanLatencyUs:  stat.meanLatencyUs(),
+			RowsExamined:   stat.rowsExamined,
+			SchemaVersion:  stat.schemaVersion,
+			LastSeenTs:     stat.lastSeenTs,
+		})
+	}
+	sort.Slice(itemList, func(i, j int) bool {
+		if itemList[i].TotalLatencyUs != itemList[j].TotalLatencyUs {
+			return itemList[i].TotalLatencyUs > itemList[j].TotalLatencyUs
+		}
+		return itemList[i].Digest < itemList[j].Digest
+	})
+	if len(itemList) > limit {
+		itemList = itemList[:limit]
+	}
+	return itemList
+}
+
+// getSlowQueryTrendPointList sums up the slow queries by the collection, ordered from the earliest to the latest.
+// The slow queries of a collection share the same creation time.
+func getSlowQueryTrendPointList(slowQueryList []*api.SlowQueryRaw) []*api.SlowQueryTrendPoint {
+	pointMap := make(map[int64]*api.SlowQueryTrendPoint)
+	var pointList []*api.SlowQueryTrendPoint
+	for _, slowQuery := range slowQueryList {
+		point, ok := pointMap[slowQuery.CreatedTs]
+		if !ok {
+			point = &api.SlowQueryTrendPoint{
+				Ts:            slowQuery.CreatedTs,
+				SchemaVersion: slowQuery.SchemaVersion,
+			}
+			pointMap[slowQuery.CreatedTs] = point
+			pointList = append(pointList, point)
+		}
+		point.Calls += slowQuery.Calls
+		point.TotalLatencyUs += slowQuery.TotalLatencyUs
+		point.RowsExamined += slowQuery.RowsExamined
+	}
+	for _, point := range pointList {
+		if point.Calls > 0 {
+			point.MeanLatencyUs = point.TotalLatencyUs / point.Calls
+		}
+	}
+	sort.Slice(pointList, func(i, j int) bool {
+		return pointList[i].Ts < pointList[j].Ts
+	})
+	return pointList
+}
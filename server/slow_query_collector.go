@@ -0,0 +1,388 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+const (
+	slowQueryCollectInterval = time.Duration(1) * time.Hour
+	// slowQueryTopN is the number of the statements with the highest total latency stored for each database in each collection.
+	slowQueryTopN = 20
+	// slowQueryRetention is how long the collected slow queries are kept.
+	slowQueryRetention = time.Duration(30*24) * time.Hour
+	// slowQueryRegressionWindow is how far back the slow queries are looked up to detect the latency regression.
+	slowQueryRegressionWindow = time.Duration(7*24) * time.Hour
+	// slowQueryRegressionThresholdPercent is the minimum increase percentage of the mean latency to be flagged as a regression.
+	slowQueryRegressionThresholdPercent = 50
+	// slowQueryRegressionMinCalls is the minimum calls of a statement in both schema versions to compare the mean latency,
+	// otherwise a few outliers would be flagged.
+	slowQueryRegressionMinCalls = 10
+	// The statement of a slow query is truncated to this many characters.
+	maxSlowQueryStatementLength = 4096
+)
+
+// NewSlowQueryCollector creates a slow query collector.
+func NewSlowQueryCollector(logger *zap.Logger, server *Server) *SlowQueryCollector {
+	return &SlowQueryCollector{
+		l:           logger,
+		server:      server,
+		baselineMap: make(map[int]map[string]*db.QueryDigest),
+	}
+}
+
+// SlowQueryCollector is the slow query collector.
+// The engines accumulate the statement statistics since they are reset, so the collector keeps the statistics
+// of the last collection as the baseline and stores the increments within each collection interval.
+type SlowQueryCollector struct {
+	l      *zap.Logger
+	server *Server
+
+	baselineMu sync.Mutex
+	// baselineMap is the cumulative statement statistics of the last collection keyed by the instance ID,
+	// then by the database and digest.
+	baselineMap map[int]map[string]*db.QueryDigest
+}
+
+// Run will run the slow query collector once.
+func (s *SlowQueryCollector) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(slowQueryCollectInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	s.l.Debug(fmt.Sprintf("Slow query collector started and will run every %v", slowQueryCollectInterval))
+	runningTasks := make(map[int]bool)
+	mu := sync.RWMutex{}
+	for {
+		select {
+		case <-ticker.C:
+			s.l.Debug("New slow query collector round started...")
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err, ok := r.(error)
+						if !ok {
+							err = fmt.Errorf("%v", r)
+						}
+						s.l.Error("Slow query collector PANIC RECOVER", zap.Error(err), zap.Stack("stack"))
+					}
+				}()
+
+				ctx := context.Background()
+
+				if _, err := s.server.SlowQueryService.DeleteSlowQuery(ctx, &api.SlowQueryDelete{
+					CreatedTsBefore: time.Now().Add(-slowQueryRetention).Unix(),
+				}); err != nil {
+					s.l.Error("Failed to delete expired slow queries", zap.Error(err))
+				}
+
+				rowStatus := api.Normal
+				instanceFind := &api.InstanceFind{
+					RowStatus: &rowStatus,
+				}
+				instanceRawList, err := s.server.InstanceService.FindInstanceList(ctx, instanceFind)
+				if err != nil {
+					s.l.Error("Failed to retrieve instances", zap.Error(err))
+					return
+				}
+
+				for _, instanceRaw := range instanceRawList {
+					// Only MySQL and Postgres provide the statement statistics.
+					if instanceRaw.Engine != db.MySQL && instanceRaw.Engine != db.Postgres {
+						continue
+					}
+
+					mu.Lock()
+					if _, ok := runningTasks[instanceRaw.ID]; ok {
+						mu.Unlock()
+						continue
+					}
+					runningTasks[instanceRaw.ID] = true
+					mu.Unlock()
+
+					instance, err := s.server.composeInstanceRelationship(ctx, instanceRaw)
+					if err != nil {
+						s.l.Error(fmt.Sprintf("Failed to compose instance relationship, ID %v, name %q.", instanceRaw.ID, instanceRaw.Name), zap.Error(err))
+						mu.Lock()
+						delete(runningTasks, instanceRaw.ID)
+						mu.Unlock()
+						continue
+					}
+					go func(instance *api.Instance) {
+						s.l.Debug("Collect instance slow queries", zap.String("instance", instance.Name))
+						defer func() {
+							mu.Lock()
+							delete(runningTasks, instance.ID)
+							mu.Unlock()
+						}()
+						if err := s.collectInstanceSlowQuery(ctx, instance); err != nil {
+							s.l.Debug("Failed to collect instance slow queries",
+								zap.String("instance", instance.Name),
+								zap.Error(err))
+						}
+					}(instance)
+				}
+			}()
+		case <-ctx.Done(): // if cancel() execute
+			return
+		}
+	}
+}
+
+// collectInstanceSlowQuery stores the top statements of each database in the instance within the collection interval,
+// and checks the latency regression of the statements after the schema migrations.
+func (s *SlowQueryCollector) collectInstanceSlowQuery(ctx context.Context, instance *api.Instance) error {
+	driver, err := getAdminDatabaseDriver(ctx, instance, "", s.l)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	digestList, err := driver.GetQueryDigestList(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.baselineMu.Lock()
+	deltaList, baseline := getQueryDigestDeltaList(digestList, s.baselineMap[instance.ID])
+	if digestList == nil {
+		// The statistics aren't enabled, so start over once enabled.
+		delete(s.baselineMap, instance.ID)
+	} else {
+		s.baselineMap[instance.ID] = baseline
+	}
+	s.baselineMu.Unlock()
+
+	databaseFind := &api.DatabaseFind{
+		InstanceID: &instance.ID,
+	}
+	dbRawList, err := s.server.DatabaseService.FindDatabaseList(ctx, databaseFind)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve database list: %w", err)
+	}
+	topMap := getTopQueryDigestMap(deltaList, slowQueryTopN)
+	for _, dbRaw := range dbRawList {
+		database := dbRaw.ToDatabase()
+		var createList []*api.SlowQueryCreate
+		for _, digest := range topMap[database.Name] {
+			statement := []rune(digest.Statement)
+			if len(statement) > maxSlowQueryStatementLength {
+				statement = append(statement[:maxSlowQueryStatementLength], []rune("...")...)
+			}
+			createList = append(createList, &api.SlowQueryCreate{
+				CreatorID:      api.SystemBotID,
+				DatabaseID:     database.ID,
+				SchemaVersion:  database.SchemaVersion,
+				Digest:         digest.Digest,
+				Statement:      string(statement),
+				Calls:          digest.Calls,
+				TotalLatencyUs: digest.TotalLatencyUs,
+				RowsExamined:   digest.RowsExamined,
+			})
+		}
+		if len(createList) > 0 {
+			if _, err := s.server.SlowQueryService.CreateSlowQueryList(ctx, createList); err != nil {
+				s.l.Error("Failed to create slow queries",
+					zap.String("instance", instance.Name),
+					zap.String("database", database.Name),
+					zap.Error(err))
+				continue
+			}
+		}
+
+		s.checkSlowQueryRegressionAnomaly(ctx, instance, database)
+	}
+	return nil
+}
+
+// checkSlowQueryRegressionAnomaly checks whether the mean latency of the statements regresses
+// in the current schema version of the database against the previous one.
+func (s *SlowQueryCollector) checkSlowQueryRegressionAnomaly(ctx context.Context, instance *api.Instance, database *api.Database) {
+	createdTsAfter := time.Now().Add(-slowQueryRegressionWindow).Unix()
+	slowQueryList, err := s.server.SlowQueryService.FindSlowQueryList(ctx, &api.SlowQueryFind{
+		DatabaseID:     &database.ID,
+		CreatedTsAfter: &createdTsAfter,
+	})
+	if err != nil {
+		s.l.Error("Failed to retrieve slow queries",
+			zap.String("instance", instance.Name),
+			zap.String("database", database.Name),
+			zap.String("type", string(api.AnomalyDatabaseSlowQueryRegression)),
+			zap.Error(err))
+		return
+	}
+
+	var regressionPayload interface{}
+	previousVersion, itemList := getSlowQueryRegressionItemList(slowQueryList, database.SchemaVersion, slowQueryRegressionThresholdPercent)
+	if len(itemList) > 0 {
+		regressionPayload = &api.AnomalyDatabaseSlowQueryRegressionPayload{
+			Version:          database.SchemaVersion,
+			PreviousVersion:  previousVersion,
+			ThresholdPercent: slowQueryRegressionThresholdPercent,
+			ItemList:         itemList,
+		}
+	}
+	s.server.AnomalyScanner.upsertOrArchiveDatabaseAnomaly(ctx, instance, database, api.AnomalyDatabaseSlowQueryRegression, regressionPayload)
+}
+
+// getQueryDigestKey returns the key of a statement in the baseline.
+func getQueryDigestKey(digest *db.QueryDigest) string {
+	return fmt.Sprintf("%s/%s", digest.Database, digest.Digest)
+}
+
+// getQueryDigestDeltaList returns the increments of the cumulative statement statistics against the baseline,
+// and the statistics as the baseline of the next collection.
+// There is no increment without a baseline, since the statistics may be accumulated for an unknown period.
+// If the statistics of a statement are reset or evicted since the baseline, the whole statistics are the increment.
+func getQueryDigestDeltaList(digestList []*db.QueryDigest, baseline map[string]*db.QueryDigest) ([]*db.QueryDigest, map[string]*db.QueryDigest) {
+	var deltaList []*db.QueryDigest
+	updatedBaseline := make(map[string]*db.QueryDigest)
+	for _, digest := range digestList {
+		key := getQueryDigestKey(digest)
+		updatedBaseline[key] = digest
+		if baseline == nil {
+			continue
+		}
+		delta := *digest
+		if previous, ok := baseline[key]; ok && digest.Calls >= previous.Calls {
+			delta.Calls -= previous.Calls
+			delta.TotalLatencyUs -= previous.TotalLatencyUs
+			delta.RowsExamined -= previous.RowsExamined
+		}
+		if delta.Calls <= 0 {
+			continue
+		}
+		deltaList = append(deltaList, &delta)
+	}
+	return deltaList, updatedBaseline
+}
+
+// getTopQueryDigestMap returns at most n statements with the highest total latency for each database, keyed by the database name.
+func getTopQueryDigestMap(digestList []*db.QueryDigest, n int) map[string][]*db.QueryDigest {
+	topMap := make(map[string][]*db.QueryDigest)
+	for _, digest := range digestList {
+		topMap[digest.Database] = append(topMap[digest.Database], digest)
+	}
+	for database, list := range topMap {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].TotalLatencyUs != list[j].TotalLatencyUs {
+				return list[i].TotalLatencyUs > list[j].TotalLatencyUs
+			}
+			return list[i].Digest < list[j].Digest
+		})
+		if len(list) > n {
+			topMap[database] = list[:n]
+		}
+	}
+	return topMap
+}
+
+// slowQueryStat is the statistics of a statement summed up from the slow queries.
+type slowQueryStat struct {
+	statement      string
+	calls          int64
+	totalLatencyUs int64
+	rowsExamined   int64
+	schemaVersion  string
+	lastSeenTs     int64
+	lastSeenID     int
+}
+
+func (stat *slowQueryStat) add(slowQuery *api.SlowQueryRaw) {
+	stat.calls += slowQuery.Calls
+	stat.totalLatencyUs += slowQuery.TotalLatencyUs
+	stat.rowsExamined += slowQuery.RowsExamined
+	// Use the statement and the schema version from the latest collection.
+	if slowQuery.ID > stat.lastSeenID {
+		stat.statement = slowQuery.Statement
+		stat.schemaVersion = slowQuery.SchemaVersion
+		stat.lastSeenTs = slowQuery.CreatedTs
+		stat.lastSeenID = slowQuery.ID
+	}
+}
+
+func (stat *slowQueryStat) meanLatencyUs() int64 {
+	if stat.calls == 0 {
+		return 0
+	}
+	return stat.totalLatencyUs / stat.calls
+}
+
+// getSlowQueryStatMap sums up the slow queries by the digest.
+func getSlowQueryStatMap(slowQueryList []*api.SlowQueryRaw) map[string]*slowQueryStat {
+	statMap := make(map[string]*slowQueryStat)
+	for _, slowQuery := range slowQueryList {
+		stat, ok := statMap[slowQuery.Digest]
+		if !ok {
+			stat = &slowQueryStat{}
+			statMap[slowQuery.Digest] = stat
+		}
+		stat.add(slowQuery)
+	}
+	return statMap
+}
+
+// getSlowQueryRegressionItemList returns the previous schema version before the given version, and the statements
+// whose mean latency in the given version increases by at least thresholdPercent against the previous version.
+// The previous version is the version of the latest slow query collected in a version other than the given one.
+func getSlowQueryRegressionItemList(slowQueryList []*api.SlowQueryRaw, version string, thresholdPercent int) (string, []*api.AnomalySlowQueryItem) {
+	if version == "" {
+		return "", nil
+	}
+	var previous *api.SlowQueryRaw
+	for _, slowQuery := range slowQueryList {
+		if slowQuery.SchemaVersion == "" || slowQuery.SchemaVersion == version {
+			continue
+		}
+		if previous == nil || slowQuery.ID > previous.ID {
+			previous = slowQuery
+		}
+	}
+	if previous == nil {
+		return "", nil
+	}
+
+	var currentList, previousList []*api.SlowQueryRaw
+	for _, slowQuery := range slowQueryList {
+		switch slowQuery.SchemaVersion {
+		case version:
+			currentList = append(currentList, slowQuery)
+		case previous.SchemaVersion:
+			previousList = append(previousList, slowQuery)
+		}
+	}
+	currentStatMap, previousStatMap := getSlowQueryStatMap(currentList), getSlowQueryStatMap(previousList)
+
+	var itemList []*api.AnomalySlowQueryItem
+	for digest, current := range currentStatMap {
+		previousStat, ok := previousStatMap[digest]
+		if !ok || current.calls < slowQueryRegressionMinCalls || previousStat.calls < slowQueryRegressionMinCalls {
+			continue
+		}
+		meanLatencyUs, previousMeanLatencyUs := current.meanLatencyUs(), previousStat.meanLatencyUs()
+		if previousMeanLatencyUs <= 0 || (meanLatencyUs-previousMeanLatencyUs)*100 < previousMeanLatencyUs*int64(thresholdPercent) {
+			continue
+		}
+		itemList = append(itemList, &api.AnomalySlowQueryItem{
+			Digest:                digest,
+			Statement:             current.statement,
+			MeanLatencyUs:         meanLatencyUs,
+			Calls:                 current.calls,
+			PreviousMeanLatencyUs: previousMeanLatencyUs,
+			PreviousCalls:         previousStat.calls,
+		})
+	}
+	sort.Slice(itemList, func(i, j int) bool {
+		if itemList[i].MeanLatencyUs != itemList[j].MeanLatencyUs {
+			return itemList[i].MeanLatencyUs > itemList[j].MeanLatencyUs
+		}
+		return itemList[i].Digest < itemList[j].Digest
+	})
+	return previous.SchemaVersion, itemList
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetQueryDigestDeltaList(t *testing.T) {
+	digestList := []*db.QueryDigest{
+		{Database: "db1", Digest: "d1", Calls: 15, TotalLatencyUs: 1500, RowsExamined: 150},
+		// Reset since the baseline.
+		{Database: "db1", Digest: "d2", Calls: 3, TotalLatencyUs: 300, RowsExamined: 30},
+		// Not called since the baseline.
+		{Database: "db1", Digest: "d3", Calls: 10, TotalLatencyUs: 1000, RowsExamined: 100},
+		// New since the baseline.
+		{Database: "db2", Digest: "d1", Calls: 2, TotalLatencyUs: 200, RowsExamined: 20},
+	}
+	baseline := map[string]*db.QueryDigest{
+		"db1/d1": {Database: "db1", Digest: "d1", Calls: 10, TotalLatencyUs: 1000, RowsExamined: 100},
+		"db1/d2": {Database: "db1", Digest: "d2", Calls: 10, TotalLatencyUs: 1000, RowsExamined: 100},
+		"db1/d3": {Database: "db1", Digest: "d3", Calls: 10, TotalLatencyUs: 1000, RowsExamined: 100},
+		"db3/d1": {Database: "db3", Digest: "d1", Calls: 10, TotalLatencyUs: 1000, RowsExamined: 100},
+	}
+
+	deltaList, updatedBaseline := getQueryDigestDeltaList(digestList, baseline)
+	wantDeltaList := []*db.QueryDigest{
+		{Database: "db1", Digest: "d1", Calls: 5, TotalLatencyUs: 500, RowsExamined: 50},
+		{Database: "db1", Digest: "d2", Calls: 3, TotalLatencyUs: 300, RowsExamined: 30},
+		{Database: "db2", Digest: "d1", Calls: 2, TotalLatencyUs: 200, RowsExamined: 20},
+	}
+	if !reflect.DeepEqual(deltaList, wantDeltaList) {
+		t.Errorf("got delta list %+v, want %+v", deltaList, wantDeltaList)
+	}
+	wantBaseline := map[string]*db.QueryDigest{
+		"db1/d1": digestList[0],
+		"db1/d2": digestList[1],
+		"db1/d3": digestList[2],
+		"db2/d1": digestList[3],
+	}
+	if !reflect.DeepEqual(updatedBaseline, wantBaseline) {
+		t.Errorf("got baseline %+v, want %+v", updatedBaseline, wantBaseline)
+	}
+
+	// No increment without a baseline.
+	if deltaList, _ := getQueryDigestDeltaList(digestList, nil); deltaList != nil {
+		t.Errorf("got delta list %+v without a baseline, want nil", deltaList)
+	}
+}
+
+func TestGetTopQueryDigestMap(t *testing.T) {
+	digestList := []*db.QueryDigest{
+		{Database: "db1", Digest: "d1", TotalLatencyUs: 100},
+		{Database: "db1", Digest: "d2", TotalLatencyUs: 300},
+		{Database: "db1", Digest: "d3", TotalLatencyUs: 200},
+		{Database: "db1", Digest: "d4", TotalLatencyUs: 300},
+		{Database: "db2", Digest: "d1", TotalLatencyUs: 100},
+	}
+	want := map[string][]string{
+		"db1": {"d2", "d4"},
+		"db2": {"d1"},
+	}
+
+	got := make(map[string][]string)
+	for database, list := range getTopQueryDigestMap(digestList, 2) {
+		for _, digest := range list {
+			got[database] = append(got[database], digest.Digest)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getTopQueryDigestMap() = %v, want %v", got, want)
+	}
+}
+
+func TestGetSlowQueryRegressionItemList(t *testing.T) {
+	slowQueryList := []*api.SlowQueryRaw{
+		// The version before the previous one is ignored.
+		{ID: 101, SchemaVersion: "0001", Digest: "d1", Statement: "SELECT ?", Calls: 100, TotalLatencyUs: 1000},
+		{ID: 102, SchemaVersion: "0002", Digest: "d1", Statement: "SELECT ?", Calls: 100, TotalLatencyUs: 10000},
+		{ID: 103, SchemaVersion: "0002", Digest: "d2", Statement: "SELECT ? FROM t", Calls: 10, TotalLatencyUs: 1000},
+		{ID: 104, SchemaVersion: "0002", Digest: "d3", Statement: "UPDATE t", Calls: 100, TotalLatencyUs: 10000},
+		{ID: 105, SchemaVersion: "0002", Digest: "d4", Statement: "DELETE FROM t", Calls: 5, TotalLatencyUs: 500},
+		{ID: 106, SchemaVersion: "0003", Digest: "d1", Statement: "SELECT ?", Calls: 50, TotalLatencyUs: 7500},
+		{ID: 107, SchemaVersion: "0003", Digest: "d1", Statement: "SELECT ?", Calls: 50, TotalLatencyUs: 7500},
+		{ID: 108, SchemaVersion: "0003", Digest: "d2", Statement: "SELECT ? FROM t", Calls: 10, TotalLatencyUs: 1400},
+		{ID: 109, SchemaVersion: "0003", Digest: "d3", Statement: "UPDATE t", Calls: 100, TotalLatencyUs: 50000},
+		// Not called enough in the previous version.
+		{ID: 110, SchemaVersion: "0003", Digest: "d4", Statement: "DELETE FROM t", Calls: 100, TotalLatencyUs: 50000},
+	}
+
+	previousVersion, itemList := getSlowQueryRegressionItemList(slowQueryList, "0003", 50)
+	if previousVersion != "0002" {
+		t.Errorf("got previous version %q, want %q", previousVersion, "0002")
+	}
+	wantItemList := []*api.AnomalySlowQueryItem{
+		{Digest: "d3", Statement: "UPDATE t", MeanLatencyUs: 500, Calls: 100, PreviousMeanLatencyUs: 100, PreviousCalls: 100},
+		{Digest: "d1", Statement: "SELECT ?", MeanLatencyUs: 150, Calls: 100, PreviousMeanLatencyUs: 100, PreviousCalls: 100},
+	}
+	if !reflect.DeepEqual(itemList, wantItemList) {
+		t.Errorf("got item list %+v, want %+v", itemList, wantItemList)
+	}
+
+	// No previous version.
+	if previousVersion, itemList := getSlowQueryRegressionItemList(slowQueryList[:1], "0001", 50); previousVersion != "" || itemList != nil {
+		t.Errorf("got previous version %q and item list %+v, want none", previousVersion, itemList)
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetSlowQueryReportItemList(t *testing.T) {
+	slowQueryList := []*api.SlowQueryRaw{
+		{ID: 101, CreatedTs: 1000, SchemaVersion: "0001", Digest: "d1", Statement: "SELECT ?", Calls: 10, TotalLatencyUs: 1000, RowsExamined: 10},
+		{ID: 102, CreatedTs: 1000, SchemaVersion: "0001", Digest: "d2", Statement: "UPDATE t", Calls: 1, TotalLatencyUs: 100, RowsExamined: 1},
+		{ID: 103, CreatedTs: 1000, SchemaVersion: "0001", Digest: "d3", Statement: "DELETE FROM t", Calls: 5, TotalLatencyUs: 2000, RowsExamined: 5},
+		{ID: 104, CreatedTs: 4600, SchemaVersion: "0002", Digest: "d1", Statement: "SELECT ?", Calls: 30, TotalLatencyUs: 3000, RowsExamined: 30},
+	}
+	want := []*api.SlowQueryReportItem{
+		{Digest: "d1", Statement: "SELECT ?", Calls: 40, TotalLatencyUs: 4000, MeanLatencyUs: 100, RowsExamined: 40, SchemaVersion: "0002", LastSeenTs: 4600},
+		{Digest: "d3", Statement: "DELETE FROM t", Calls: 5, TotalLatencyUs: 2000, MeanLatencyUs: 400, RowsExamined: 5, SchemaVersion: "0001", LastSeenTs: 1000},
+	}
+	if got := getSlowQueryReportItemList(slowQueryList, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("getSlowQueryReportItemList() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetSlowQueryTrendPointList(t *testing.T) {
+	// The slow queries are ordered from the latest to the earliest.
+	slowQueryList := []*api.SlowQueryRaw{
+		{ID: 104, CreatedTs: 4600, SchemaVersion: "0002", Digest: "d1", Calls: 30, TotalLatencyUs: 3000, RowsExamined: 30},
+		{ID: 103, CreatedTs: 1000, SchemaVersion: "0001", Digest: "d3", Calls: 5, TotalLatencyUs: 2000, RowsExamined: 5},
+		{ID: 102, CreatedTs: 1000, SchemaVersion: "0001", Digest: "d2", Calls: 5, TotalLatencyUs: 1000, RowsExamined: 1},
+	}
+	want := []*api.SlowQueryTrendPoint{
+		{Ts: 1000, SchemaVersion: "0001", Calls: 10, TotalLatencyUs: 3000, MeanLatencyUs: 300, RowsExamined: 6},
+		{Ts: 4600, SchemaVersion: "0002", Calls: 30, TotalLatencyUs: 3000, MeanLatencyUs: 100, RowsExamined: 30},
+	}
+	if got := getSlowQueryTrendPointList(slowQueryList); !reflect.DeepEqual(got, want) {
+		t.Errorf("getSlowQueryTrendPointList() = %+v, want %+v", got, want)
+	}
+}
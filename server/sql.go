@@ -6,17 +6,28 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/masking"
+	"github.com/bytebase/bytebase/plugin/db/param"
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
 	"go.uber.org/zap"
 )
 
+var (
+	// postgresDollarQuoteRegexp matches the opening tag of the Postgres dollar-quoted string, e.g. $$ or $body$.
+	postgresDollarQuoteRegexp = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+)
+
 func (s *Server) registerSQLRoutes(g *echo.Group) {
 	g.POST("/sql/ping", func(c echo.Context) error {
 		ctx := context.Background()
@@ -90,7 +101,9 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", sync.InstanceID)).SetInternal(err)
 		}
 
-		resultSet := s.syncEngineVersionAndSchema(ctx, instance)
+		// The sync requested by the user always syncs all databases.
+		incremental := false
+		resultSet := s.syncEngineVersionAndSchema(ctx, instance, incremental)
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
@@ -106,522 +119,849 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request").SetInternal(err)
 		}
 
-		if exec.InstanceID == 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing instanceId")
+		resultSet, err := s.executeSQL(ctx, c, exec)
+		if err != nil {
+			return err
 		}
-		if len(exec.Statement) == 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing sql statement")
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
 		}
-		if !exec.Readonly {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support readonly sql statement")
+		return nil
+	})
+}
+
+// executeSQL executes the readonly SQL editor statement for the principal of the request, and records the activity
+// as well as the query history unless in the explain mode.
+// The query error is returned in the result set, while the returned error is an HTTP error for the malformatted request
+// or the server failure.
+func (s *Server) executeSQL(ctx context.Context, c echo.Context, exec *api.SQLExecute) (*api.SQLResultSet, error) {
+	if exec.InstanceID == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing instanceId")
+	}
+	if len(exec.Statement) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing sql statement")
+	}
+	if !exec.Readonly {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support readonly sql statement")
+	}
+	if !validateSQLSelectStatement(exec.Statement) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support SELECT sql statement")
+	}
+	if exec.Explain && hasExplainPrefix(exec.Statement) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, the statement to explain shouldn't start with EXPLAIN")
+	}
+
+	instance, err := s.composeInstanceByID(ctx, exec.InstanceID)
+	if err != nil {
+		if common.ErrorCode(err) == common.NotFound {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
 		}
-		if !validateSQLSelectStatement(exec.Statement) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support SELECT sql statement")
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exec.InstanceID)).SetInternal(err)
+	}
+	if exec.Explain && instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Explain is not supported for %s", instance.Engine))
+	}
+	// The statement is concatenated to EXPLAIN by the driver, so it must be exactly one SELECT statement.
+	if exec.Explain {
+		if err := validateSQLExplainStatement(instance.Engine, exec.Statement); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql execute request, %s", err.Error())).SetInternal(err)
 		}
+	}
 
-		instance, err := s.composeInstanceByID(ctx, exec.InstanceID)
+	statement, args, err := bindSQLParameterList(instance.Engine, exec.Statement, exec.ParameterList)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql execute request, %s", err.Error())).SetInternal(err)
+	}
+	if exec.Explain && len(args) > 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, explain doesn't support the statement with parameters")
+	}
+
+	// The access check and the masker only need the structure of the statement, which is parsed with the placeholders normalized.
+	normalizedStatement := param.Normalize(instance.Engine, exec.Statement)
+	policy, err := s.checkSQLQueryAccess(ctx, c.Get(getPrincipalIDContextKey()).(int), c.Get(getRoleContextKey()).(api.Role), instance, exec.DatabaseName, normalizedStatement)
+	if err != nil {
+		return nil, err
+	}
+	limit := exec.Limit
+	if !policy.IsExempted(c.Get(getRoleContextKey()).(api.Role)) {
+		limit = getSQLQueryAccessLimit(policy.MaxRowCount, limit)
+	}
+	timeout := time.Duration(policy.StatementTimeoutSeconds) * time.Second
+
+	var masker *masking.Masker
+	if !exec.Explain {
+		masker, err = s.getDataMasker(ctx, c.Get(getRoleContextKey()).(api.Role), instance, exec.DatabaseName, normalizedStatement)
 		if err != nil {
-			if common.ErrorCode(err) == common.NotFound {
-				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
-			}
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exec.InstanceID)).SetInternal(err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get data masking configuration").SetInternal(err)
 		}
+	}
 
-		start := time.Now().UnixNano()
+	var query *sqlQuery
+	if !exec.Explain {
+		query, err = s.sqlQueryManager.register(exec.QueryID, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			return nil, err
+		}
+	}
 
-		bytes, err := func() ([]byte, error) {
-			driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName, s.l)
-			if err != nil {
-				return nil, err
+	start := time.Now().UnixNano()
+
+	var plan *db.QueryPlan
+	hasMore := false
+	rowCount := 0
+	bytes, err := func() ([]byte, error) {
+		driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName, s.l)
+		if err != nil {
+			if query != nil {
+				s.sqlQueryManager.close(query)
 			}
-			defer driver.Close(ctx)
+			return nil, err
+		}
 
-			rowSet, err := driver.Query(ctx, exec.Statement, exec.Limit)
+		if exec.Explain {
+			defer driver.Close(ctx)
+			plan, err = driver.Explain(ctx, statement)
 			if err != nil {
 				return nil, err
 			}
+			if plan == nil {
+				return nil, fmt.Errorf("explain is not supported for %s", instance.Engine)
+			}
+			return []byte(plan.Raw), nil
+		}
 
-			return json.Marshal(rowSet)
-		}()
+		// The driver is closed along with the query, which stays open if there are more pages to fetch.
+		rowSet, more, err := s.sqlQueryManager.run(query, driver, statement, args, timeout, masker, limit, exec.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		hasMore = more
+		if data, ok := rowSet[2].([]interface{}); ok {
+			rowCount = len(data)
+		}
 
-		{
-			errMessage := ""
-			activityLevel := api.ActivityInfo
-			if err != nil {
-				errMessage = err.Error()
-				activityLevel = api.ActivityError
-			}
+		return json.Marshal(rowSet)
+	}()
+	durationNs := time.Now().UnixNano() - start
 
-			activityBytes, err := json.Marshal(api.ActivitySQLEditorQueryPayload{
-				Statement:    exec.Statement,
-				DurationNs:   time.Now().UnixNano() - start,
-				InstanceName: instance.Name,
-				DatabaseName: exec.DatabaseName,
-				Error:        errMessage,
-			})
+	{
+		errMessage := ""
+		activityLevel := api.ActivityInfo
+		if err != nil {
+			errMessage = err.Error()
+			activityLevel = api.ActivityError
+		}
 
-			if err != nil {
-				s.l.Warn("Failed to marshal activity after executing sql statement",
-					zap.String("database_name", exec.DatabaseName),
-					zap.String("instance_name", instance.Name),
-					zap.String("statement", exec.Statement),
-					zap.Error(err))
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
-			}
+		activityBytes, err := json.Marshal(api.ActivitySQLEditorQueryPayload{
+			Statement:    exec.Statement,
+			DurationNs:   durationNs,
+			InstanceName: instance.Name,
+			DatabaseName: exec.DatabaseName,
+			Error:        errMessage,
+		})
 
-			activityCreate := &api.ActivityCreate{
-				CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
-				Type:        api.ActivitySQLEditorQuery,
-				ContainerID: exec.InstanceID,
-				Level:       activityLevel,
-				Comment: fmt.Sprintf("Executed `%q` in database %q of instance %q.",
-					exec.Statement, exec.DatabaseName, instance.Name),
-				Payload: string(activityBytes),
-			}
+		if err != nil {
+			s.l.Warn("Failed to marshal activity after executing sql statement",
+				zap.String("database_name", exec.DatabaseName),
+				zap.String("instance_name", instance.Name),
+				zap.String("statement", exec.Statement),
+				zap.Error(err))
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
 
-			_, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
+		activityCreate := &api.ActivityCreate{
+			CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+			Type:        api.ActivitySQLEditorQuery,
+			ContainerID: exec.InstanceID,
+			Level:       activityLevel,
+			Comment: fmt.Sprintf("%s `%q` in database %q of instance %q.",
+				getSQLEditorQueryAction(exec.Explain), exec.Statement, exec.DatabaseName, instance.Name),
+			Payload: string(activityBytes),
+		}
 
-			if err != nil {
-				s.l.Warn("Failed to create activity after executing sql statement",
+		_, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
+
+		if err != nil {
+			s.l.Warn("Failed to create activity after executing sql statement",
+				zap.String("database_name", exec.DatabaseName),
+				zap.String("instance_name", instance.Name),
+				zap.String("statement", exec.Statement),
+				zap.Error(err))
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+		}
+
+		// The explained statement isn't recorded since it's not run.
+		if !exec.Explain {
+			queryHistoryCreate := &api.QueryHistoryCreate{
+				CreatorID:     c.Get(getPrincipalIDContextKey()).(int),
+				InstanceID:    exec.InstanceID,
+				DatabaseName:  exec.DatabaseName,
+				Statement:     exec.Statement,
+				ParameterList: exec.ParameterList,
+				DurationNs:    durationNs,
+				Error:         errMessage,
+			}
+			// The query result is still returned if it fails to record the history.
+			if _, err := s.QueryHistoryService.CreateQueryHistory(ctx, queryHistoryCreate); err != nil {
+				s.l.Warn("Failed to create query history after executing sql statement",
 					zap.String("database_name", exec.DatabaseName),
 					zap.String("instance_name", instance.Name),
 					zap.String("statement", exec.Statement),
 					zap.Error(err))
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
 			}
 		}
+	}
 
-		resultSet := &api.SQLResultSet{}
-		if err == nil {
-			resultSet.Data = string(bytes)
-			s.l.Debug("Query result",
+	resultSet := &api.SQLResultSet{}
+	if query != nil {
+		resultSet.QueryID = query.id
+	}
+	if err == nil {
+		resultSet.Data = string(bytes)
+		resultSet.HasMore = hasMore
+		if plan != nil {
+			resultSet.Plan = convertQueryPlanNode(plan.Root)
+			resultSet.FullTableScanList = getFullTableScanList(resultSet.Plan)
+		}
+		// Only the row count is logged, so that the sensitive data doesn't end up in the log.
+		s.l.Debug("Query result",
+			zap.String("statement", exec.Statement),
+			zap.Int("row_count", rowCount),
+		)
+	} else {
+		resultSet.Error = err.Error()
+		if s.mode == "dev" {
+			s.l.Error("Failed to execute query",
+				zap.Error(err),
 				zap.String("statement", exec.Statement),
-				zap.String("data", resultSet.Data),
 			)
 		} else {
-			resultSet.Error = err.Error()
-			if s.mode == "dev" {
-				s.l.Error("Failed to execute query",
-					zap.Error(err),
-					zap.String("statement", exec.Statement),
-				)
-			} else {
-				s.l.Debug("Failed to execute query",
-					zap.Error(err),
-					zap.String("statement", exec.Statement),
-				)
+			s.l.Debug("Failed to execute query",
+				zap.Error(err),
+				zap.String("statement", exec.Statement),
+			)
+		}
+	}
+	return resultSet, nil
+}
+
+// syncEngineVersionAndSchema syncs the engine version, the users and the database schemas of the instance.
+// If incremental is true, the databases whose DDL fingerprint is unchanged since the last successful sync are skipped.
+// Each database is synced separately with its sync status, error and duration recorded, see syncDatabaseSchema.
+func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.Instance, incremental bool) (rs *api.SQLResultSet) {
+	resultSet := &api.SQLResultSet{}
+	err := func() error {
+		var userList []*db.User
+		fingerprintMap, err := func() (map[string]string, error) {
+			driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "", s.l)
+			if err != nil {
+				return nil, err
+			}
+			defer driver.Close(ctx)
+
+			// Sync engine version
+			version, err := driver.GetVersion(ctx)
+			if err != nil {
+				return nil, err
 			}
+			// Underlying version may change due to upgrade, however it's a rare event, so we only update if it actually differs
+			// to avoid changing the updated_ts
+			if version != instance.EngineVersion {
+				_, err := s.InstanceService.PatchInstance(ctx, &api.InstancePatch{
+					ID:            instance.ID,
+					UpdaterID:     api.SystemBotID,
+					EngineVersion: &version,
+				})
+				if err != nil {
+					return nil, err
+				}
+				instance.EngineVersion = version
+			}
+
+			// The users are queried once for the instance instead of along with each database.
+			userList, err = driver.SyncUser(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			return driver.GetSchemaFingerprint(ctx)
+		}()
+		if err != nil {
+			return err
 		}
 
-		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+		// Compare the stored db info with the databases in the instance.
+		// Case 1: If item appears both in the stored db info and the instance, then we sync and UPDATE the corresponding record in the stored db.
+		//         For the incremental sync, we skip it if its DDL fingerprint is unchanged since the last successful sync.
+		// Case 2: If item only appears in the instance and not in the stored db, then we sync and CREATE the record in the stored db.
+		// Case 3: Conversely, if item only appears in the stored db, but not in the instance, then we MARK the record as NOT_FOUND.
+		//   	   We don't delete the entry because:
+		//   	   1. This entry has already been associated with other entities, we can't simply delete it.
+		//   	   2. The deletion in the schema might be a mistake, so it's better to surface as NOT_FOUND to let user review it.
+		databaseFind := &api.DatabaseFind{
+			InstanceID: &instance.ID,
+		}
+		dbRawList, err := s.DatabaseService.FindDatabaseList(ctx, databaseFind)
+		if err != nil {
+			return fmt.Errorf("Failed to sync database for instance: %s. Failed to find database list. Error %w", instance.Name, err)
+		}
+		dbRawMap := make(map[string]*api.DatabaseRaw)
+		for _, dbRaw := range dbRawList {
+			dbRawMap[dbRaw.Name] = dbRaw
+		}
+
+		var databaseNameList []string
+		for databaseName, fingerprint := range fingerprintMap {
+			// Skip the scratch databases restored for the backup verification, which are dropped afterwards.
+			if isBackupVerificationDatabase(databaseName) {
+				continue
+			}
+			if incremental && isSchemaFingerprintUnchanged(dbRawMap[databaseName], fingerprint) {
+				continue
+			}
+			databaseNameList = append(databaseNameList, databaseName)
+		}
+		sort.Strings(databaseNameList)
+
+		// The incremental sync skips the users as well if no database changes.
+		if !incremental || len(databaseNameList) > 0 {
+			if err := s.syncInstanceUser(ctx, instance, userList); err != nil {
+				return err
+			}
+		}
+
+		// Case 1 and Case 2, the databases are synced by a bounded pool of workers, while syncDatabaseSchema also limits
+		// the concurrency of each instance across the syncs running at the same time, e.g. the schema syncer and a task.
+		databaseNameCh := make(chan string)
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		var errorList []string
+		for i := 0; i < maxConcurrentSchemaSyncPerInstance && i < len(databaseNameList); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for databaseName := range databaseNameCh {
+					if err := s.syncDatabaseSchema(ctx, instance, databaseName, fingerprintMap[databaseName]); err != nil {
+						mu.Lock()
+						errorList = append(errorList, err.Error())
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		for _, databaseName := range databaseNameList {
+			databaseNameCh <- databaseName
+		}
+		close(databaseNameCh)
+		wg.Wait()
+
+		// Case 3, only appear in the bytebase metadata
+		for _, dbRaw := range dbRawList {
+			if _, ok := fingerprintMap[dbRaw.Name]; ok {
+				continue
+			}
+			syncStatus := api.NotFound
+			ts := time.Now().Unix()
+			databasePatch := &api.DatabasePatch{
+				ID:                   dbRaw.ID,
+				UpdaterID:            api.SystemBotID,
+				SyncStatus:           &syncStatus,
+				LastSuccessfulSyncTs: &ts,
+				// SchemaVersion will not be over-written.
+			}
+			if _, err := s.DatabaseService.PatchDatabase(ctx, databasePatch); err != nil {
+				if common.ErrorCode(err) == common.NotFound {
+					return fmt.Errorf("failed to sync database for instance: %s. Database not found: %s", instance.Name, dbRaw.Name)
+				}
+				return fmt.Errorf("failed to sync database for instance: %s. Failed to update database: %s. Error: %w", instance.Name, dbRaw.Name, err)
+			}
+		}
+
+		if len(errorList) > 0 {
+			sort.Strings(errorList)
+			return fmt.Errorf("failed to sync %d of %d databases for instance: %s. First error: %s", len(errorList), len(databaseNameList), instance.Name, errorList[0])
 		}
 		return nil
-	})
+	}()
+
+	if err != nil {
+		resultSet.Error = err.Error()
+	}
+
+	return resultSet
 }
 
-func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.Instance) (rs *api.SQLResultSet) {
-	resultSet := &api.SQLResultSet{}
-	err := func() error {
+// isSchemaFingerprintUnchanged returns whether the database was successfully synced with the same DDL fingerprint.
+// An empty fingerprint is never unchanged since the engine doesn't support it, or the last sync didn't record it.
+func isSchemaFingerprintUnchanged(database *api.DatabaseRaw, fingerprint string) bool {
+	return database != nil && fingerprint != "" && database.SyncStatus == api.OK && database.SchemaFingerprint == fingerprint
+}
+
+// syncDatabaseSchema syncs the schema of a single database in the instance, and creates the database if it's not stored yet.
+// The sync status, error and duration are recorded on the database, as well as the fingerprint for the incremental sync.
+// The fingerprint should be empty if unknown, so that the database will be synced again in the next incremental sync.
+// It waits for the sync slot of the instance.
+func (s *Server) syncDatabaseSchema(ctx context.Context, instance *api.Instance, databaseName string, fingerprint string) error {
+	release := s.schemaSyncLimiter.acquire(instance.ID)
+	defer release()
+
+	start := time.Now()
+	databaseFind := &api.DatabaseFind{
+		InstanceID: &instance.ID,
+		Name:       &databaseName,
+	}
+	dbRaw, err := s.DatabaseService.FindDatabase(ctx, databaseFind)
+	if err != nil {
+		return fmt.Errorf("failed to sync database for instance: %s. Failed to find database: %s. Error %w", instance.Name, databaseName, err)
+	}
+
+	var schemaVersion string
+	err = func() error {
 		driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, "", s.l)
 		if err != nil {
 			return err
 		}
 		defer driver.Close(ctx)
 
-		// Sync engine version
-		version, err := driver.GetVersion(ctx)
+		schemaList, err := driver.SyncSchema(ctx, databaseName)
+		if err != nil {
+			return err
+		}
+		var schema *db.Schema
+		for _, item := range schemaList {
+			if item.Name == databaseName {
+				schema = item
+				break
+			}
+		}
+		if schema == nil {
+			return fmt.Errorf("failed to sync database for instance: %s. Database not found in the instance: %s", instance.Name, databaseName)
+		}
+		schemaVersion, err = getLatestSchemaVersion(ctx, driver, schema.Name)
 		if err != nil {
 			return err
 		}
-		// Underlying version may change due to upgrade, however it's a rare event, so we only update if it actually differs
-		// to avoid changing the updated_ts
-		if version != instance.EngineVersion {
-			_, err := s.InstanceService.PatchInstance(ctx, &api.InstancePatch{
-				ID:            instance.ID,
-				UpdaterID:     api.SystemBotID,
-				EngineVersion: &version,
-			})
+
+		if dbRaw == nil {
+			databaseCreate := &api.DatabaseCreate{
+				CreatorID:     api.SystemBotID,
+				ProjectID:     api.DefaultProjectID,
+				InstanceID:    instance.ID,
+				EnvironmentID: instance.EnvironmentID,
+				Name:          schema.Name,
+				CharacterSet:  schema.CharacterSet,
+				Collation:     schema.Collation,
+				SchemaVersion: schemaVersion,
+			}
+			dbRaw, err = s.DatabaseService.CreateDatabase(ctx, databaseCreate)
 			if err != nil {
-				return err
+				if common.ErrorCode(err) == common.Conflict {
+					return fmt.Errorf("failed to sync database for instance: %s. Database name already exists: %s", instance.Name, databaseCreate.Name)
+				}
+				return fmt.Errorf("failed to sync database for instance: %s. Failed to import new database: %s. Error %w", instance.Name, databaseCreate.Name, err)
 			}
-			instance.EngineVersion = version
 		}
+		database, err := s.composeDatabaseRelationship(ctx, dbRaw)
+		if err != nil {
+			return fmt.Errorf("Failed to compose database relationship with ID %v, error: %v", dbRaw.ID, err)
+		}
+		if err := s.recreateDatabaseSchema(ctx, instance, database, schema); err != nil {
+			return err
+		}
+		s.createSchemaSnapshotOrWarn(ctx, database, schema, schemaVersion)
+		return nil
+	}()
+	if dbRaw == nil {
+		// The database is neither stored nor created, so there is nowhere to record the error.
+		return err
+	}
+
+	durationMs := time.Since(start).Milliseconds()
+	databasePatch := &api.DatabasePatch{
+		ID:             dbRaw.ID,
+		UpdaterID:      api.SystemBotID,
+		SyncDurationMs: &durationMs,
+	}
+	if err != nil {
+		syncStatus := api.SyncFailed
+		syncError := err.Error()
+		databasePatch.SyncStatus = &syncStatus
+		databasePatch.SyncError = &syncError
+	} else {
+		syncStatus := api.OK
+		ts := time.Now().Unix()
+		syncError := ""
+		databasePatch.SyncStatus = &syncStatus
+		databasePatch.LastSuccessfulSyncTs = &ts
+		databasePatch.SchemaVersion = &schemaVersion
+		databasePatch.SyncError = &syncError
+		databasePatch.SchemaFingerprint = &fingerprint
+	}
+	if _, patchErr := s.DatabaseService.PatchDatabase(ctx, databasePatch); patchErr != nil && err == nil {
+		if common.ErrorCode(patchErr) == common.NotFound {
+			return fmt.Errorf("failed to sync database for instance: %s. Database not found: %v", instance.Name, databaseName)
+		}
+		return fmt.Errorf("failed to sync database for instance: %s. Failed to update database: %s. Error %w", instance.Name, databaseName, patchErr)
+	}
+	return err
+}
 
-		// Sync schema
-		userList, schemaList, err := driver.SyncSchema(ctx)
+// recreateDatabaseSchema recreates the table, index, column, view, routine and sequence info of the database with the synced schema.
+// We just recreate them because we don't reference those objects and they are for information purpose.
+func (s *Server) recreateDatabaseSchema(ctx context.Context, instance *api.Instance, database *api.Database, schema *db.Schema) error {
+	var createTable = func(database *api.Database, tableCreate *api.TableCreate) (*api.Table, error) {
+		createTableRaw, err := s.TableService.CreateTable(ctx, tableCreate)
 		if err != nil {
-			fmt.Printf("sync schema error: %v\n", err)
-			resultSet.Error = err.Error()
-		} else {
-			var createTable = func(database *api.Database, tableCreate *api.TableCreate) (*api.Table, error) {
-				createTableRaw, err := s.TableService.CreateTable(ctx, tableCreate)
-				if err != nil {
-					if common.ErrorCode(err) == common.Conflict {
-						return nil, fmt.Errorf("failed to sync table for instance: %s, database: %s. Table name already exists: %s", instance.Name, database.Name, tableCreate.Name)
-					}
-					return nil, fmt.Errorf("failed to sync table for instance: %s, database: %s. Failed to import new table: %s. Error %w", instance.Name, database.Name, tableCreate.Name, err)
-				}
-				createTable, err := s.composeTableRelationship(ctx, createTableRaw)
-				if err != nil {
-					return nil, fmt.Errorf("failed to compose table with ID %d, error: %v", createTable.ID, err)
-				}
-				return createTable, nil
+			if common.ErrorCode(err) == common.Conflict {
+				return nil, fmt.Errorf("failed to sync table for instance: %s, database: %s. Table name already exists: %s", instance.Name, database.Name, tableCreate.Name)
 			}
+			return nil, fmt.Errorf("failed to sync table for instance: %s, database: %s. Failed to import new table: %s. Error %w", instance.Name, database.Name, tableCreate.Name, err)
+		}
+		createTable, err := s.composeTableRelationship(ctx, createTableRaw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose table with ID %d, error: %v", createTable.ID, err)
+		}
+		return createTable, nil
+	}
 
-			var createView = func(database *api.Database, viewCreate *api.ViewCreate) (*api.View, error) {
-				createView, err := s.ViewService.CreateView(ctx, viewCreate)
-				if err != nil {
-					if common.ErrorCode(err) == common.Conflict {
-						return nil, fmt.Errorf("failed to sync view for instance: %s, database: %s. View name already exists: %s", instance.Name, database.Name, viewCreate.Name)
-					}
-					return nil, fmt.Errorf("failed to sync view for instance: %s, database: %s. Failed to import new view: %s. Error %w", instance.Name, database.Name, viewCreate.Name, err)
-				}
-				return createView, nil
+	var createView = func(database *api.Database, viewCreate *api.ViewCreate) (*api.View, error) {
+		createView, err := s.ViewService.CreateView(ctx, viewCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return nil, fmt.Errorf("failed to sync view for instance: %s, database: %s. View name already exists: %s", instance.Name, database.Name, viewCreate.Name)
+			}
+			return nil, fmt.Errorf("failed to sync view for instance: %s, database: %s. Failed to import new view: %s. Error %w", instance.Name, database.Name, viewCreate.Name, err)
+		}
+		return createView, nil
+	}
+
+	var createColumn = func(database *api.Database, table *api.Table, columnCreate *api.ColumnCreate) error {
+		_, err := s.ColumnService.CreateColumn(ctx, columnCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return fmt.Errorf("failed to sync column for instance: %s, database: %s, table: %s. Column name already exists: %s", instance.Name, database.Name, table.Name, columnCreate.Name)
 			}
+			return fmt.Errorf("failed to sync column for instance: %s, database: %s, table: %s. Failed to import new column: %s. Error %w", instance.Name, database.Name, table.Name, columnCreate.Name, err)
+		}
+		return nil
+	}
 
-			var createColumn = func(database *api.Database, table *api.Table, columnCreate *api.ColumnCreate) error {
-				_, err := s.ColumnService.CreateColumn(ctx, columnCreate)
-				if err != nil {
-					if common.ErrorCode(err) == common.Conflict {
-						return fmt.Errorf("failed to sync column for instance: %s, database: %s, table: %s. Column name already exists: %s", instance.Name, database.Name, table.Name, columnCreate.Name)
-					}
-					return fmt.Errorf("failed to sync column for instance: %s, database: %s, table: %s. Failed to import new column: %s. Error %w", instance.Name, database.Name, table.Name, columnCreate.Name, err)
-				}
-				return nil
+	var createIndex = func(database *api.Database, table *api.Table, indexCreate *api.IndexCreate) error {
+		_, err := s.IndexService.CreateIndex(ctx, indexCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return fmt.Errorf("failed to sync index for instance: %s, database: %s, table: %s. index and expression already exists: %s(%s)", instance.Name, database.Name, table.Name, indexCreate.Name, indexCreate.Expression)
 			}
+			return fmt.Errorf("failed to sync index for instance: %s, database: %s, table: %s. Failed to import new index and expression: %s(%s). Error %w", instance.Name, database.Name, table.Name, indexCreate.Name, indexCreate.Expression, err)
+		}
+		return nil
+	}
 
-			var createIndex = func(database *api.Database, table *api.Table, indexCreate *api.IndexCreate) error {
-				_, err := s.IndexService.CreateIndex(ctx, indexCreate)
-				if err != nil {
-					if common.ErrorCode(err) == common.Conflict {
-						return fmt.Errorf("failed to sync index for instance: %s, database: %s, table: %s. index and expression already exists: %s(%s)", instance.Name, database.Name, table.Name, indexCreate.Name, indexCreate.Expression)
-					}
-					return fmt.Errorf("failed to sync index for instance: %s, database: %s, table: %s. Failed to import new index and expression: %s(%s). Error %w", instance.Name, database.Name, table.Name, indexCreate.Name, indexCreate.Expression, err)
-				}
-				return nil
-			}
-
-			var recreateTableSchema = func(database *api.Database, table db.Table) error {
-				// Table
-				tableCreate := &api.TableCreate{
-					CreatorID:     api.SystemBotID,
-					CreatedTs:     table.CreatedTs,
-					UpdatedTs:     table.UpdatedTs,
-					DatabaseID:    database.ID,
-					Name:          table.Name,
-					Type:          table.Type,
-					Engine:        table.Engine,
-					Collation:     table.Collation,
-					RowCount:      table.RowCount,
-					DataSize:      table.DataSize,
-					IndexSize:     table.IndexSize,
-					DataFree:      table.DataFree,
-					CreateOptions: table.CreateOptions,
-					Comment:       table.Comment,
-				}
-				upsertedTable, err := createTable(database, tableCreate)
-				if err != nil {
-					return err
-				}
+	var createForeignKey = func(database *api.Database, table *api.Table, foreignKeyCreate *api.ForeignKeyCreate) error {
+		_, err := s.ForeignKeyService.CreateForeignKey(ctx, foreignKeyCreate)
+		if err != nil {
+			if common.ErrorCode(err) == common.Conflict {
+				return fmt.Errorf("failed to sync foreign key for instance: %s, database: %s, table: %s. foreign key and column already exists: %s(%s)", instance.Name, database.Name, table.Name, foreignKeyCreate.Name, foreignKeyCreate.Column)
+			}
+			return fmt.Errorf("failed to sync foreign key for instance: %s, database: %s, table: %s. Failed to import new foreign key and column: %s(%s). Error %w", instance.Name, database.Name, table.Name, foreignKeyCreate.Name, foreignKeyCreate.Column, err)
+		}
+		return nil
+	}
 
-				// Column
-				for _, column := range table.ColumnList {
-					columnFind := &api.ColumnFind{
-						DatabaseID: &database.ID,
-						TableID:    &upsertedTable.ID,
-						Name:       &column.Name,
-					}
-					col, err := s.ColumnService.FindColumn(ctx, columnFind)
-					if err != nil {
-						return fmt.Errorf("failed to sync column for instance: %s, database: %s, table: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, err)
-					}
-					// Create column if not exists yet.
-					if col == nil {
-						columnCreate := &api.ColumnCreate{
-							CreatorID:    api.SystemBotID,
-							DatabaseID:   database.ID,
-							TableID:      upsertedTable.ID,
-							Name:         column.Name,
-							Position:     column.Position,
-							Default:      column.Default,
-							Nullable:     column.Nullable,
-							Type:         column.Type,
-							CharacterSet: column.CharacterSet,
-							Collation:    column.Collation,
-							Comment:      column.Comment,
-						}
-						if err := createColumn(database, upsertedTable, columnCreate); err != nil {
-							return err
-						}
-					}
-				}
+	var recreateTableSchema = func(database *api.Database, table db.Table) error {
+		// Table
+		tableCreate := &api.TableCreate{
+			CreatorID:     api.SystemBotID,
+			CreatedTs:     table.CreatedTs,
+			UpdatedTs:     table.UpdatedTs,
+			DatabaseID:    database.ID,
+			Name:          table.Name,
+			Type:          table.Type,
+			Engine:        table.Engine,
+			Collation:     table.Collation,
+			RowCount:      table.RowCount,
+			DataSize:      table.DataSize,
+			IndexSize:     table.IndexSize,
+			DataFree:      table.DataFree,
+			CreateOptions: table.CreateOptions,
+			Comment:       table.Comment,
+		}
+		upsertedTable, err := createTable(database, tableCreate)
+		if err != nil {
+			return err
+		}
 
-				// Index
-				for _, index := range table.IndexList {
-					indexFind := &api.IndexFind{
-						DatabaseID: &database.ID,
-						TableID:    &upsertedTable.ID,
-						Name:       &index.Name,
-						Expression: &index.Expression,
-					}
-					idx, err := s.IndexService.FindIndex(ctx, indexFind)
-					if err != nil {
-						return fmt.Errorf("failed to sync index for instance: %s, database: %s, table: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, err)
-					}
-					if idx == nil {
-						// Create index if not exists.
-						indexCreate := &api.IndexCreate{
-							CreatorID:  api.SystemBotID,
-							DatabaseID: database.ID,
-							TableID:    upsertedTable.ID,
-							Name:       index.Name,
-							Expression: index.Expression,
-							Position:   index.Position,
-							Type:       index.Type,
-							Unique:     index.Unique,
-							Visible:    index.Visible,
-							Comment:    index.Comment,
-						}
-						if err := createIndex(database, upsertedTable, indexCreate); err != nil {
-							return err
-						}
-					}
+		// Column
+		for _, column := range table.ColumnList {
+			columnFind := &api.ColumnFind{
+				DatabaseID: &database.ID,
+				TableID:    &upsertedTable.ID,
+				Name:       &column.Name,
+			}
+			col, err := s.ColumnService.FindColumn(ctx, columnFind)
+			if err != nil {
+				return fmt.Errorf("failed to sync column for instance: %s, database: %s, table: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, err)
+			}
+			// Create column if not exists yet.
+			if col == nil {
+				columnCreate := &api.ColumnCreate{
+					CreatorID:    api.SystemBotID,
+					DatabaseID:   database.ID,
+					TableID:      upsertedTable.ID,
+					Name:         column.Name,
+					Position:     column.Position,
+					Default:      column.Default,
+					Nullable:     column.Nullable,
+					Type:         column.Type,
+					CharacterSet: column.CharacterSet,
+					Collation:    column.Collation,
+					Comment:      column.Comment,
+				}
+				if err := createColumn(database, upsertedTable, columnCreate); err != nil {
+					return err
 				}
-				return nil
 			}
+		}
 
-			var recreateViewSchema = func(database *api.Database, view db.View) error {
-				// View
-				viewCreate := &api.ViewCreate{
+		// Index
+		for _, index := range table.IndexList {
+			indexFind := &api.IndexFind{
+				DatabaseID: &database.ID,
+				TableID:    &upsertedTable.ID,
+				Name:       &index.Name,
+				Expression: &index.Expression,
+			}
+			idx, err := s.IndexService.FindIndex(ctx, indexFind)
+			if err != nil {
+				return fmt.Errorf("failed to sync index for instance: %s, database: %s, table: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, err)
+			}
+			if idx == nil {
+				// Create index if not exists.
+				indexCreate := &api.IndexCreate{
 					CreatorID:  api.SystemBotID,
-					CreatedTs:  view.CreatedTs,
-					UpdatedTs:  view.UpdatedTs,
 					DatabaseID: database.ID,
-					Name:       view.Name,
-					Definition: view.Definition,
-					Comment:    view.Comment,
+					TableID:    upsertedTable.ID,
+					Name:       index.Name,
+					Expression: index.Expression,
+					Position:   index.Position,
+					Type:       index.Type,
+					Unique:     index.Unique,
+					Primary:    index.Primary,
+					Visible:    index.Visible,
+					Comment:    index.Comment,
 				}
-				_, err := createView(database, viewCreate)
-				if err != nil {
+				if err := createIndex(database, upsertedTable, indexCreate); err != nil {
 					return err
 				}
-				return nil
 			}
+		}
 
-			instanceUserFind := &api.InstanceUserFind{
-				InstanceID: instance.ID,
+		// Foreign key
+		for _, foreignKey := range table.ForeignKeyList {
+			foreignKeyFind := &api.ForeignKeyFind{
+				DatabaseID: &database.ID,
+				TableID:    &upsertedTable.ID,
+				Name:       &foreignKey.Name,
+				Column:     &foreignKey.Column,
 			}
-			instanceUserList, err := s.InstanceUserService.FindInstanceUserList(ctx, instanceUserFind)
+			fk, err := s.ForeignKeyService.FindForeignKey(ctx, foreignKeyFind)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch user list for instance: %v", instance.ID)).SetInternal(err)
+				return fmt.Errorf("failed to sync foreign key for instance: %s, database: %s, table: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, err)
 			}
-
-			// Upsert user found in the instance
-			for _, user := range userList {
-				userUpsert := &api.InstanceUserUpsert{
-					CreatorID:  api.SystemBotID,
-					InstanceID: instance.ID,
-					Name:       user.Name,
-					Grant:      user.Grant,
+			if fk == nil {
+				// Create foreign key if not exists.
+				foreignKeyCreate := &api.ForeignKeyCreate{
+					CreatorID:        api.SystemBotID,
+					DatabaseID:       database.ID,
+					TableID:          upsertedTable.ID,
+					Name:             foreignKey.Name,
+					Column:           foreignKey.Column,
+					Position:         foreignKey.Position,
+					ReferencedTable:  foreignKey.ReferencedTable,
+					ReferencedColumn: foreignKey.ReferencedColumn,
+					OnUpdate:         foreignKey.OnUpdate,
+					OnDelete:         foreignKey.OnDelete,
 				}
-				_, err := s.InstanceUserService.UpsertInstanceUser(ctx, userUpsert)
-				if err != nil {
-					return fmt.Errorf("failed to sync user for instance: %s. Failed to upsert user. Error %w", instance.Name, err)
+				if err := createForeignKey(database, upsertedTable, foreignKeyCreate); err != nil {
+					return err
 				}
 			}
+		}
 
-			// Delete user no longer found in the instance
-			for _, user := range instanceUserList {
-				found := false
-				for _, dbUser := range userList {
-					if user.Name == dbUser.Name {
-						found = true
-						break
-					}
-				}
+		// Check constraint
+		for _, checkConstraint := range table.CheckConstraintList {
+			checkConstraintCreate := &api.CheckConstraintCreate{
+				CreatorID:  api.SystemBotID,
+				DatabaseID: database.ID,
+				TableID:    upsertedTable.ID,
+				Name:       checkConstraint.Name,
+				Expression: checkConstraint.Expression,
+			}
+			if _, err := s.CheckConstraintService.CreateCheckConstraint(ctx, checkConstraintCreate); err != nil {
+				return fmt.Errorf("failed to sync check constraint for instance: %s, database: %s, table: %s. Failed to import new check constraint: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, checkConstraint.Name, err)
+			}
+		}
 
-				if !found {
-					userDelete := &api.InstanceUserDelete{
-						ID: user.ID,
-					}
-					err := s.InstanceUserService.DeleteInstanceUser(ctx, userDelete)
-					if err != nil {
-						return fmt.Errorf("failed to sync user for instance: %s. Failed to delete user: %s. Error %w", instance.Name, user.Name, err)
-					}
-				}
+		// Trigger
+		for _, trigger := range table.TriggerList {
+			triggerCreate := &api.TriggerCreate{
+				CreatorID:  api.SystemBotID,
+				DatabaseID: database.ID,
+				TableID:    upsertedTable.ID,
+				Name:       trigger.Name,
+				Timing:     trigger.Timing,
+				Event:      trigger.Event,
+				Statement:  trigger.Statement,
 			}
+			if _, err := s.TriggerService.CreateTrigger(ctx, triggerCreate); err != nil {
+				return fmt.Errorf("failed to sync trigger for instance: %s, database: %s, table: %s. Failed to import new trigger: %s. Error %w", instance.Name, database.Name, upsertedTable.Name, trigger.Name, err)
+			}
+		}
+		return nil
+	}
 
-			// Compare the stored db info with the just synced db schema.
-			// Case 1: If item appears both in the stored db info and the synced db schema, then we UPDATE the corresponding record in the stored db.
-			// Case 2: If item only appears in the synced schema and not in the stored db, then we CREATE the record in the stored db.
-			// Case 3: Conversely, if item only appears in the stored db, but not in the synced schema, then we MARK the record as NOT_FOUND.
-			//   	   We don't delete the entry because:
-			//   	   1. This entry has already been associated with other entities, we can't simply delete it.
-			//   	   2. The deletion in the schema might be a mistake, so it's better to surface as NOT_FOUND to let user review it.
-			//
-			// If we successfully synced a particular db schema, we just recreate its table, index, column info. We do this because
-			// we don't reference those objects and they are for information purpose.
+	var recreateViewSchema = func(database *api.Database, view db.View) error {
+		// View
+		viewCreate := &api.ViewCreate{
+			CreatorID:  api.SystemBotID,
+			CreatedTs:  view.CreatedTs,
+			UpdatedTs:  view.UpdatedTs,
+			DatabaseID: database.ID,
+			Name:       view.Name,
+			Definition: view.Definition,
+			Comment:    view.Comment,
+		}
+		_, err := createView(database, viewCreate)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
 
-			databaseFind := &api.DatabaseFind{
-				InstanceID: &instance.ID,
-			}
-			dbRawList, err := s.DatabaseService.FindDatabaseList(ctx, databaseFind)
-			if err != nil {
-				return fmt.Errorf("Failed to sync database for instance: %s. Failed to find database list. Error %w", instance.Name, err)
+	var recreateRoutineAndSequenceSchema = func(database *api.Database, schema *db.Schema) error {
+		routineDelete := &api.RoutineDelete{
+			DatabaseID: database.ID,
+		}
+		if err := s.RoutineService.DeleteRoutine(ctx, routineDelete); err != nil {
+			return fmt.Errorf("failed to sync database for instance: %s. Failed to reset routine info for database: %s. Error %w", instance.Name, database.Name, err)
+		}
+		for _, routine := range schema.RoutineList {
+			routineCreate := &api.RoutineCreate{
+				CreatorID:  api.SystemBotID,
+				DatabaseID: database.ID,
+				Name:       routine.Name,
+				Type:       routine.Type,
+				Definition: routine.Definition,
+				Comment:    routine.Comment,
 			}
-			var dbList []*api.Database
-			for _, dbRaw := range dbRawList {
-				db, err := s.composeDatabaseRelationship(ctx, dbRaw)
-				if err != nil {
-					return fmt.Errorf("Failed to compose database relationship with ID %v, error: %v", dbRaw.ID, err)
-				}
-				dbList = append(dbList, db)
+			if _, err := s.RoutineService.CreateRoutine(ctx, routineCreate); err != nil {
+				return fmt.Errorf("failed to sync routine for instance: %s, database: %s. Failed to import new routine: %s. Error %w", instance.Name, database.Name, routine.Name, err)
 			}
+		}
 
-			for _, schema := range schemaList {
-				// When there are too many databases, this might have performance issue and will
-				// cause frontend timeout since we set a 30s limit (INSTANCE_OPERATION_TIMEOUT).
-				schemaVersion, err := getLatestSchemaVersion(ctx, driver, schema.Name)
-				if err != nil {
-					return err
-				}
-
-				var matchedDb *api.Database
-				for _, db := range dbList {
-					if db.Name == schema.Name {
-						matchedDb = db
-						break
-					}
+		sequenceDelete := &api.SequenceDelete{
+			DatabaseID: database.ID,
+		}
+		if err := s.SequenceService.DeleteSequence(ctx, sequenceDelete); err != nil {
+			return fmt.Errorf("failed to sync database for instance: %s. Failed to reset sequence info for database: %s. Error %w", instance.Name, database.Name, err)
+		}
+		for _, sequence := range schema.SequenceList {
+			sequenceCreate := &api.SequenceCreate{
+				CreatorID:  api.SystemBotID,
+				DatabaseID: database.ID,
+				Name:       sequence.Name,
+				DataType:   sequence.DataType,
+				StartValue: sequence.StartValue,
+				Increment:  sequence.Increment,
+				MinValue:   sequence.MinValue,
+				MaxValue:   sequence.MaxValue,
+				Cycle:      sequence.Cycle,
+				CacheSize:  sequence.CacheSize,
+			}
+			if _, err := s.SequenceService.CreateSequence(ctx, sequenceCreate); err != nil {
+				if common.ErrorCode(err) == common.Conflict {
+					return fmt.Errorf("failed to sync sequence for instance: %s, database: %s. Sequence name already exists: %s", instance.Name, database.Name, sequence.Name)
 				}
-				if matchedDb != nil {
-					// Case 1, appear in both the bytebase metadata and the synced db schema
-					syncStatus := api.OK
-					ts := time.Now().Unix()
-					databasePatch := &api.DatabasePatch{
-						ID:                   matchedDb.ID,
-						UpdaterID:            api.SystemBotID,
-						SyncStatus:           &syncStatus,
-						LastSuccessfulSyncTs: &ts,
-						SchemaVersion:        &schemaVersion,
-					}
-					dbRawPatched, err := s.DatabaseService.PatchDatabase(ctx, databasePatch)
-					if err != nil {
-						if common.ErrorCode(err) == common.NotFound {
-							return fmt.Errorf("failed to sync database for instance: %s. Database not found: %v", instance.Name, matchedDb.Name)
-						}
-						return fmt.Errorf("failed to sync database for instance: %s. Failed to update database: %s. Error %w", instance.Name, matchedDb.Name, err)
-					}
-					dbPatched, err := s.composeDatabaseRelationship(ctx, dbRawPatched)
-					if err != nil {
-						return fmt.Errorf("Failed to compose database relationship with ID %v, error: %v", dbRawPatched.ID, err)
-					}
+				return fmt.Errorf("failed to sync sequence for instance: %s, database: %s. Failed to import new sequence: %s. Error %w", instance.Name, database.Name, sequence.Name, err)
+			}
+		}
+		return nil
+	}
 
-					tableDelete := &api.TableDelete{
-						DatabaseID: dbPatched.ID,
-					}
-					if err := s.TableService.DeleteTable(ctx, tableDelete); err != nil {
-						return fmt.Errorf("failed to sync database for instance: %s. Failed to reset table info for database: %s. Error %w", instance.Name, dbPatched.Name, err)
-					}
+	tableDelete := &api.TableDelete{
+		DatabaseID: database.ID,
+	}
+	if err := s.TableService.DeleteTable(ctx, tableDelete); err != nil {
+		return fmt.Errorf("failed to sync database for instance: %s. Failed to reset table info for database: %s. Error %w", instance.Name, database.Name, err)
+	}
+	for _, table := range schema.TableList {
+		if err := recreateTableSchema(database, table); err != nil {
+			return err
+		}
+	}
 
-					for _, table := range schema.TableList {
-						err = recreateTableSchema(dbPatched, table)
-						if err != nil {
-							return err
-						}
-					}
+	viewDelete := &api.ViewDelete{
+		DatabaseID: database.ID,
+	}
+	if err := s.ViewService.DeleteView(ctx, viewDelete); err != nil {
+		return fmt.Errorf("failed to sync database for instance: %s. Failed to reset view info for database: %s. Error %w", instance.Name, database.Name, err)
+	}
+	for _, view := range schema.ViewList {
+		if err := recreateViewSchema(database, view); err != nil {
+			return err
+		}
+	}
 
-					viewDelete := &api.ViewDelete{
-						DatabaseID: dbPatched.ID,
-					}
-					if err := s.ViewService.DeleteView(ctx, viewDelete); err != nil {
-						return fmt.Errorf("failed to sync database for instance: %s. Failed to reset view info for database: %s. Error %w", instance.Name, dbPatched.Name, err)
-					}
+	return recreateRoutineAndSequenceSchema(database, schema)
+}
 
-					for _, view := range schema.ViewList {
-						err = recreateViewSchema(dbPatched, view)
-						if err != nil {
-							return err
-						}
-					}
-				} else {
-					// Case 2, only appear in the synced db schema
-					databaseCreate := &api.DatabaseCreate{
-						CreatorID:     api.SystemBotID,
-						ProjectID:     api.DefaultProjectID,
-						InstanceID:    instance.ID,
-						EnvironmentID: instance.EnvironmentID,
-						Name:          schema.Name,
-						CharacterSet:  schema.CharacterSet,
-						Collation:     schema.Collation,
-						SchemaVersion: schemaVersion,
-					}
-					dbRaw, err := s.DatabaseService.CreateDatabase(ctx, databaseCreate)
-					if err != nil {
-						if common.ErrorCode(err) == common.Conflict {
-							return fmt.Errorf("failed to sync database for instance: %s. Database name already exists: %s", instance.Name, databaseCreate.Name)
-						}
-						return fmt.Errorf("failed to sync database for instance: %s. Failed to import new database: %s. Error %w", instance.Name, databaseCreate.Name, err)
-					}
-					db, err := s.composeDatabaseRelationship(ctx, dbRaw)
-					if err != nil {
-						return fmt.Errorf("Failed to compose database relationship with ID %v, error: %v", dbRaw.ID, err)
-					}
+// syncInstanceUser upserts the users found in the instance, and deletes the users no longer found.
+func (s *Server) syncInstanceUser(ctx context.Context, instance *api.Instance, userList []*db.User) error {
+	instanceUserFind := &api.InstanceUserFind{
+		InstanceID: instance.ID,
+	}
+	instanceUserList, err := s.InstanceUserService.FindInstanceUserList(ctx, instanceUserFind)
+	if err != nil {
+		return fmt.Errorf("failed to sync user for instance: %s. Failed to fetch user list. Error %w", instance.Name, err)
+	}
 
-					for _, table := range schema.TableList {
-						err = recreateTableSchema(db, table)
-						if err != nil {
-							return err
-						}
-					}
+	// Upsert user found in the instance
+	for _, user := range userList {
+		userUpsert := &api.InstanceUserUpsert{
+			CreatorID:  api.SystemBotID,
+			InstanceID: instance.ID,
+			Name:       user.Name,
+			Grant:      user.Grant,
+		}
+		_, err := s.InstanceUserService.UpsertInstanceUser(ctx, userUpsert)
+		if err != nil {
+			return fmt.Errorf("failed to sync user for instance: %s. Failed to upsert user. Error %w", instance.Name, err)
+		}
+	}
 
-					for _, view := range schema.ViewList {
-						err = recreateViewSchema(db, view)
-						if err != nil {
-							return err
-						}
-					}
-				}
+	// Delete user no longer found in the instance
+	for _, user := range instanceUserList {
+		found := false
+		for _, dbUser := range userList {
+			if user.Name == dbUser.Name {
+				found = true
+				break
 			}
+		}
 
-			// Case 3, only appear in the bytebase metadata
-			for _, db := range dbList {
-				found := false
-				for _, schema := range schemaList {
-					if db.Name == schema.Name {
-						found = true
-						break
-					}
-				}
-				if !found {
-					syncStatus := api.NotFound
-					ts := time.Now().Unix()
-					databasePatch := &api.DatabasePatch{
-						ID:                   db.ID,
-						UpdaterID:            api.SystemBotID,
-						SyncStatus:           &syncStatus,
-						LastSuccessfulSyncTs: &ts,
-						// SchemaVersion will not be over-written.
-					}
-					database, err := s.DatabaseService.PatchDatabase(ctx, databasePatch)
-					if err != nil {
-						if common.ErrorCode(err) == common.NotFound {
-							return fmt.Errorf("failed to sync database for instance: %s. Database not found: %s", instance.Name, database.Name)
-						}
-						return fmt.Errorf("failed to sync database for instance: %s. Failed to update database: %s. Error: %w", instance.Name, database.Name, err)
-					}
-				}
+		if !found {
+			userDelete := &api.InstanceUserDelete{
+				ID: user.ID,
+			}
+			err := s.InstanceUserService.DeleteInstanceUser(ctx, userDelete)
+			if err != nil {
+				return fmt.Errorf("failed to sync user for instance: %s. Failed to delete user: %s. Error %w", instance.Name, user.Name, err)
 			}
 		}
-		return nil
-	}()
-
-	if err != nil {
-		resultSet.Error = err.Error()
 	}
-
-	return resultSet
+	return nil
 }
 
 func getLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName string) (string, error) {
@@ -651,3 +991,181 @@ func validateSQLSelectStatement(sqlStatement string) bool {
 	}
 	return false
 }
+
+// validateSQLExplainStatement returns an error unless the statement is exactly one SELECT statement, which is parsed by
+// the TiDB parser for MySQL. As there is no Postgres parser, the Postgres statement is scanned for the top-level statement
+// separators and the keywords of the data-modifying or locking statements outside of the literals and comments instead.
+func validateSQLExplainStatement(engine db.Type, statement string) error {
+	switch engine {
+	case db.MySQL, db.TiDB:
+		nodeList, _, err := parser.New().Parse(statement, "", "")
+		if err != nil {
+			return fmt.Errorf("failed to parse the statement to explain: %w", err)
+		}
+		if len(nodeList) != 1 {
+			return fmt.Errorf("explain only supports exactly one SELECT statement, got %d statements", len(nodeList))
+		}
+		switch node := nodeList[0].(type) {
+		case *ast.SelectStmt:
+			if node.SelectIntoOpt != nil {
+				return fmt.Errorf("explain doesn't support SELECT ... INTO")
+			}
+			return nil
+		case *ast.SetOprStmt:
+			return nil
+		default:
+			return fmt.Errorf("explain only supports the SELECT statement")
+		}
+	case db.Postgres:
+		wordList := scanPostgresWordList(statement)
+		for len(wordList) > 0 && wordList[len(wordList)-1] == ";" {
+			wordList = wordList[:len(wordList)-1]
+		}
+		if len(wordList) == 0 || (wordList[0] != "SELECT" && wordList[0] != "WITH") {
+			return fmt.Errorf("explain only supports the SELECT statement")
+		}
+		for _, word := range wordList {
+			switch word {
+			case ";":
+				return fmt.Errorf("explain only supports exactly one SELECT statement")
+			case "INSERT", "UPDATE", "DELETE", "MERGE", "INTO":
+				return fmt.Errorf("explain doesn't support the SELECT statement with %s", word)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("explain is not supported for %s", engine)
+	}
+}
+
+// scanPostgresWordList returns the upper-cased keywords and identifiers, and the statement separators ";" of the Postgres statement,
+// skipping the literals, the quoted identifiers, the dollar-quoted strings and the comments.
+func scanPostgresWordList(statement string) []string {
+	var wordList []string
+	for i := 0; i < len(statement); i++ {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"':
+			// The backslash escapes are only honored in the E'...' escape string.
+			backslashEscape := c == '\'' && i > 0 && (statement[i-1] == 'E' || statement[i-1] == 'e')
+			for i++; i < len(statement); i++ {
+				if backslashEscape && statement[i] == '\\' {
+					i++
+				} else if statement[i] == c {
+					if i+1 < len(statement) && statement[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+		case c == '-' && strings.HasPrefix(statement[i:], "--"):
+			if end := strings.IndexByte(statement[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(statement)
+			}
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			// Postgres block comments nest.
+			depth := 0
+			for ; i < len(statement); i++ {
+				if strings.HasPrefix(statement[i:], "/*") {
+					depth++
+					i++
+				} else if strings.HasPrefix(statement[i:], "*/") {
+					depth--
+					i++
+					if depth == 0 {
+						break
+					}
+				}
+			}
+		case c == '$':
+			if tag := postgresDollarQuoteRegexp.FindString(statement[i:]); tag != "" {
+				if end := strings.Index(statement[i+len(tag):], tag); end >= 0 {
+					i += len(tag) + end + len(tag) - 1
+				} else {
+					i = len(statement)
+				}
+			}
+		case c == ';':
+			wordList = append(wordList, ";")
+		case c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+			end := i + 1
+			for end < len(statement) && (statement[end] == '_' || statement[end] == '$' || ('a' <= statement[end] && statement[end] <= 'z') || ('A' <= statement[end] && statement[end] <= 'Z') || ('0' <= statement[end] && statement[end] <= '9')) {
+				end++
+			}
+			wordList = append(wordList, strings.ToUpper(statement[i:end]))
+			i = end - 1
+		}
+	}
+	return wordList
+}
+
+// hasExplainPrefix returns true if the statement starts with EXPLAIN.
+func hasExplainPrefix(sqlStatement string) bool {
+	matchResult, _ := regexp.MatchString(`^EXPLAIN(\s|$)`, strings.ToUpper(strings.TrimSpace(sqlStatement)))
+	return matchResult
+}
+
+// bindSQLParameterList binds the named :param placeholders of the statement to the parameter values,
+// see param.Bind. The statement is returned as is if it has neither placeholders nor parameters.
+func bindSQLParameterList(engine db.Type, statement string, parameterList []api.SQLParameter) (string, []interface{}, error) {
+	if len(parameterList) == 0 && len(param.GetNameList(engine, statement)) == 0 {
+		return statement, nil, nil
+	}
+	valueMap := make(map[string]string)
+	for _, parameter := range parameterList {
+		if _, ok := valueMap[parameter.Name]; ok {
+			return "", nil, fmt.Errorf("duplicate parameter :%s", parameter.Name)
+		}
+		valueMap[parameter.Name] = parameter.Value
+	}
+	return param.Bind(engine, statement, valueMap)
+}
+
+func getSQLEditorQueryAction(explain bool) string {
+	if explain {
+		return "Explained"
+	}
+	return "Executed"
+}
+
+func convertQueryPlanNode(node *db.QueryPlanNode) *api.SQLPlanNode {
+	if node == nil {
+		return nil
+	}
+	planNode := &api.SQLPlanNode{
+		NodeType:      node.NodeType,
+		Table:         node.Table,
+		Index:         node.Index,
+		EstimatedRows: node.EstimatedRows,
+		Cost:          node.Cost,
+		FullTableScan: node.FullTableScan,
+	}
+	for _, child := range node.ChildList {
+		planNode.ChildList = append(planNode.ChildList, convertQueryPlanNode(child))
+	}
+	return planNode
+}
+
+// getFullTableScanList returns the distinct tables read by full table scans in the plan, in the order of a pre-order traversal.
+func getFullTableScanList(node *api.SQLPlanNode) []string {
+	var tableList []string
+	tableSet := make(map[string]bool)
+	var visit func(node *api.SQLPlanNode)
+	visit = func(node *api.SQLPlanNode) {
+		if node == nil {
+			return
+		}
+		if node.FullTableScan && !tableSet[node.Table] {
+			tableSet[node.Table] = true
+			tableList = append(tableList, node.Table)
+		}
+		for _, child := range node.ChildList {
+			visit(child)
+		}
+	}
+	visit(node)
+	return tableList
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/export"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func (s *Server) registerSQLExportRoutes(g *echo.Group) {
+	// Exports the result of a readonly / SELECT query as an attachment streamed from the database.
+	// The row count is capped by the SQL export policy of the instance environment, and the export is subject to
+	// the access and the daily quota of the SQL query access policy.
+	g.POST("/sql/export", func(c echo.Context) error {
+		ctx := context.Background()
+		exp := &api.SQLExport{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, exp); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql export request").SetInternal(err)
+		}
+
+		if exp.InstanceID == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql export request, missing instanceId")
+		}
+		if len(exp.Statement) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql export request, missing sql statement")
+		}
+		if !validateSQLSelectStatement(exp.Statement) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql export request, only support SELECT sql statement")
+		}
+		format, err := export.ParseFormat(exp.Format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql export request, %s", err.Error())).SetInternal(err)
+		}
+
+		instance, err := s.composeInstanceByID(ctx, exp.InstanceID)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exp.InstanceID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exp.InstanceID)).SetInternal(err)
+		}
+		policy, err := s.PolicyService.GetSQLExportPolicy(ctx, instance.EnvironmentID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch SQL export policy of environment ID: %v", instance.EnvironmentID)).SetInternal(err)
+		}
+		if policy.MaxRowCount == 0 {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("SQL export is disabled in environment %q", instance.Environment.Name))
+		}
+		// Only the access and the quota of the SQL query access policy apply, and the row count is capped by the SQL export policy.
+		accessPolicy, err := s.checkSQLQueryAccess(ctx, c.Get(getPrincipalIDContextKey()).(int), c.Get(getRoleContextKey()).(api.Role), instance, exp.DatabaseName, exp.Statement)
+		if err != nil {
+			return err
+		}
+		limit := getSQLExportLimit(policy.MaxRowCount, exp.Limit, format)
+		timeout := time.Duration(accessPolicy.StatementTimeoutSeconds) * time.Second
+		masker, err := s.getDataMasker(ctx, c.Get(getRoleContextKey()).(api.Role), instance, exp.DatabaseName, exp.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get data masking configuration").SetInternal(err)
+		}
+
+		start := time.Now()
+		writer := &sqlExportResponseWriter{
+			c:        c,
+			format:   format,
+			filename: getSQLExportFilename(instance.Name, exp.DatabaseName, format, start),
+		}
+		rowCount, err := func() (int, error) {
+			driver, err := tryGetReadOnlyDatabaseDriver(ctx, instance, exp.DatabaseName, s.l)
+			if err != nil {
+				return 0, err
+			}
+			defer driver.Close(ctx)
+
+			exportWriter, err := export.NewWriter(writer, format)
+			if err != nil {
+				return 0, err
+			}
+			var rowWriter db.QueryRowWriter = exportWriter
+			if masker != nil {
+				rowWriter = masker.NewWriter(exportWriter)
+			}
+			queryCtx := ctx
+			if timeout > 0 {
+				// The driver cancels the statement once the deadline is exceeded.
+				var cancel context.CancelFunc
+				queryCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			rowCount, err := driver.QueryStream(queryCtx, exp.Statement, limit, rowWriter)
+			if err != nil {
+				return rowCount, err
+			}
+			if err := exportWriter.Close(); err != nil {
+				return rowCount, err
+			}
+			// Nothing is written for an empty JSON Lines result.
+			writer.commit()
+			return rowCount, nil
+		}()
+
+		{
+			errMessage := ""
+			activityLevel := api.ActivityInfo
+			if err != nil {
+				errMessage = err.Error()
+				activityLevel = api.ActivityError
+			}
+			durationNs := time.Since(start).Nanoseconds()
+
+			// The export is recorded in the query history as well, so that it counts toward the daily quota of
+			// the SQL query access policy.
+			queryHistoryCreate := &api.QueryHistoryCreate{
+				CreatorID:    c.Get(getPrincipalIDContextKey()).(int),
+				InstanceID:   exp.InstanceID,
+				DatabaseName: exp.DatabaseName,
+				Statement:    exp.Statement,
+				DurationNs:   durationNs,
+				Error:        errMessage,
+			}
+			if _, err := s.QueryHistoryService.CreateQueryHistory(ctx, queryHistoryCreate); err != nil {
+				s.l.Warn("Failed to create query history after exporting sql statement",
+					zap.String("database_name", exp.DatabaseName),
+					zap.String("instance_name", instance.Name),
+					zap.String("statement", exp.Statement),
+					zap.Error(err))
+			}
+
+			activityBytes, err := json.Marshal(api.ActivitySQLEditorExportPayload{
+				Statement:    exp.Statement,
+				DurationNs:   durationNs,
+				InstanceName: instance.Name,
+				DatabaseName: exp.DatabaseName,
+				Format:       string(format),
+				Limit:        limit,
+				RowCount:     rowCount,
+				Error:        errMessage,
+			})
+			if err == nil {
+				activityCreate := &api.ActivityCreate{
+					CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
+					Type:        api.ActivitySQLEditorExport,
+					ContainerID: exp.InstanceID,
+					Level:       activityLevel,
+					Comment: fmt.Sprintf("Exported %d rows of `%q` as %s in database %q of instance %q.",
+						rowCount, exp.Statement, format, exp.DatabaseName, instance.Name),
+					Payload: string(activityBytes),
+				}
+				_, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
+			}
+
+			if err != nil {
+				s.l.Warn("Failed to create activity after exporting sql statement",
+					zap.String("database_name", exp.DatabaseName),
+					zap.String("instance_name", instance.Name),
+					zap.String("statement", exp.Statement),
+					zap.Error(err))
+				// The export can't be taken back once the response is committed.
+				if !c.Response().Committed {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+				}
+			}
+		}
+
+		if err != nil {
+			// The response is truncated if it fails in the middle of streaming, and there is no way to tell the client.
+			if c.Response().Committed {
+				s.l.Error("Failed to export query result after streaming started",
+					zap.Int("row_count", rowCount),
+					zap.String("statement", exp.Statement),
+					zap.Error(err),
+				)
+				return nil
+			}
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to export query result: %s", err.Error())).SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// sqlExportResponseWriter sets the attachment headers on the first write, so that an error
+// before anything is streamed can still be returned as an HTTP error.
+type sqlExportResponseWriter struct {
+	c        echo.Context
+	format   export.Format
+	filename string
+}
+
+func (w *sqlExportResponseWriter) Write(p []byte) (int, error) {
+	w.commit()
+	return w.c.Response().Write(p)
+}
+
+func (w *sqlExportResponseWriter) commit() {
+	res := w.c.Response()
+	if res.Committed {
+		return
+	}
+	res.Header().Set(echo.HeaderContentType, w.format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", w.filename))
+	res.WriteHeader(http.StatusOK)
+}
+
+// getSQLExportLimit returns the row count limit of the export, which is the requested limit capped by
+// the policy and the format. The policy limit is used if the requested limit <= 0.
+func getSQLExportLimit(maxRowCount int, limit int, format export.Format) int {
+	exportLimit := maxRowCount
+	if limit > 0 && limit < exportLimit {
+		exportLimit = limit
+	}
+	if formatLimit := format.MaxRowCount(); formatLimit > 0 && exportLimit > formatLimit {
+		exportLimit = formatLimit
+	}
+	return exportLimit
+}
+
+// getSQLExportFilename returns the attachment filename named after the database, or the instance for engines like MySQL
+// where the database name can be empty.
+func getSQLExportFilename(instanceName, databaseName string, format export.Format, t time.Time) string {
+	name := databaseName
+	if name == "" {
+		name = instanceName
+	}
+	return fmt.Sprintf("%s_%s%s", name, t.Format("20060102150405"), format.Extension())
+}
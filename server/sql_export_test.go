@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db/export"
+)
+
+func TestGetSQLExportLimit(t *testing.T) {
+	tests := []struct {
+		maxRowCount int
+		limit       int
+		format      export.Format
+		want        int
+	}{
+		{maxRowCount: 100000, limit: 0, format: export.CSV, want: 100000},
+		{maxRowCount: 100000, limit: 500, format: export.CSV, want: 500},
+		{maxRowCount: 100000, limit: 200000, format: export.JSONL, want: 100000},
+		{maxRowCount: 100000, limit: -1, format: export.XLSX, want: 100000},
+		// Capped by the Excel sheet size.
+		{maxRowCount: 5000000, limit: 0, format: export.XLSX, want: 1048575},
+		{maxRowCount: 5000000, limit: 0, format: export.CSV, want: 5000000},
+	}
+	for _, test := range tests {
+		if got := getSQLExportLimit(test.maxRowCount, test.limit, test.format); got != test.want {
+			t.Errorf("getSQLExportLimit(%d, %d, %s) = %d, want %d", test.maxRowCount, test.limit, test.format, got, test.want)
+		}
+	}
+}
+
+func TestGetSQLExportFilename(t *testing.T) {
+	ts := time.Date(2022, 4, 1, 8, 30, 5, 0, time.UTC)
+	if got, want := getSQLExportFilename("prod", "employee", export.XLSX, ts), "employee_20220401083005.xlsx"; got != want {
+		t.Errorf("getSQLExportFilename() = %q, want %q", got, want)
+	}
+	if got, want := getSQLExportFilename("prod", "", export.CSV, ts), "prod_20220401083005.csv"; got != want {
+		t.Errorf("getSQLExportFilename() without database = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/masking"
+	"github.com/google/jsonapi"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// sqlQueryCursorIdleTimeout is the duration after which the query cursor not fetched is closed to release its connection.
+	sqlQueryCursorIdleTimeout = 5 * time.Minute
+	// maxSQLQueryPerPrincipal is the maximum number of the running queries and the open query cursors of a principal.
+	maxSQLQueryPerPrincipal = 10
+)
+
+var (
+	// sqlQueryIDRegexp is the format of the query ID assigned by the client.
+	sqlQueryIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+)
+
+func (s *Server) registerSQLQueryRoutes(g *echo.Group) {
+	// Fetches the next page of the query of the current principal without re-running it.
+	g.POST("/sql/query/:id/next", func(c echo.Context) error {
+		pageSize, err := strconv.Atoi(c.QueryParam("pageSize"))
+		if err != nil || pageSize <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter pageSize is not a positive number: %s", c.QueryParam("pageSize"))).SetInternal(err)
+		}
+
+		// The query of others is not found, so that its existence isn't disclosed.
+		query := s.sqlQueryManager.get(c.Param("id"), c.Get(getPrincipalIDContextKey()).(int))
+		if query == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query not found or already closed: %s", c.Param("id")))
+		}
+
+		resultSet, err := s.sqlQueryManager.fetch(query, pageSize)
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Cancels the running query of the current principal, which is killed by the database, or closes its open cursor.
+	g.POST("/sql/query/:id/cancel", func(c echo.Context) error {
+		query := s.sqlQueryManager.get(c.Param("id"), c.Get(getPrincipalIDContextKey()).(int))
+		if query == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query not found or already closed: %s", c.Param("id")))
+		}
+		s.sqlQueryManager.close(query)
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+// sqlQuery is a running SQL editor query, or its open cursor to fetch the next pages.
+type sqlQuery struct {
+	id        string
+	creatorID int
+	// ctx is the context of the statement, which outlives the request starting the query.
+	ctx context.Context
+	// cancel kills the running statement by the database.
+	cancel context.CancelFunc
+
+	// mu serializes running the query, fetching the pages and closing the query.
+	mu     sync.Mutex
+	closed bool
+	driver db.Driver
+	cursor db.QueryCursor
+	masker *masking.Masker
+	// limit is the maximum row count of all pages, not enforced if limit <= 0.
+	limit     int
+	rowCount  int
+	idleTimer *time.Timer
+}
+
+// sqlQueryManager tracks the running SQL editor queries and the open query cursors by the query ID.
+type sqlQueryManager struct {
+	mu       sync.Mutex
+	queryMap map[string]*sqlQuery
+}
+
+func newSQLQueryManager() *sqlQueryManager {
+	return &sqlQueryManager{
+		queryMap: make(map[string]*sqlQuery),
+	}
+}
+
+// register registers the query before it runs, so that it can be canceled while running.
+// The query ID is generated if empty. The returned error is an HTTP error.
+func (m *sqlQueryManager) register(id string, creatorID int) (*sqlQuery, error) {
+	if id == "" {
+		id = uuid.New().String()
+	} else if !sqlQueryIDRegexp.MatchString(id) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql execute request, invalid queryId %q", id))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.queryMap[id]; ok {
+		return nil, echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Query ID already exists: %s", id))
+	}
+	count := 0
+	for _, query := range m.queryMap {
+		if query.creatorID == creatorID {
+			count++
+		}
+	}
+	if count >= maxSQLQueryPerPrincipal {
+		return nil, echo.NewHTTPError(http.StatusTooManyRequests, fmt.Sprintf("Exceeded the limit of %d running queries and open query cursors, cancel some of them first", maxSQLQueryPerPrincipal))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	query := &sqlQuery{
+		id:        id,
+		creatorID: creatorID,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	m.queryMap[id] = query
+	return query, nil
+}
+
+// get returns the query of the creator, or nil if not found.
+func (m *sqlQueryManager) get(id string, creatorID int) *sqlQuery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	query, ok := m.queryMap[id]
+	if !ok || query.creatorID != creatorID {
+		return nil
+	}
+	return query
+}
+
+// run runs the query with the driver owned by the query afterwards, and returns the first page of at most pageSize rows,
+// or all rows up to limit if pageSize <= 0. If there are more rows, the query cursor stays open to fetch the next pages
+// until the rows are exhausted, the query is canceled, or it's not fetched for sqlQueryCursorIdleTimeout.
+func (m *sqlQueryManager) run(query *sqlQuery, driver db.Driver, statement string, args []interface{}, timeout time.Duration, masker *masking.Masker, limit int, pageSize int) ([]interface{}, bool, error) {
+	query.mu.Lock()
+	defer query.mu.Unlock()
+
+	query.driver = driver
+	query.masker = masker
+	query.limit = limit
+	if query.closed {
+		m.closeLocked(query)
+		return nil, false, fmt.Errorf("query is canceled")
+	}
+
+	cursor, err := driver.QueryCursor(query.ctx, statement, timeout, args...)
+	if err != nil {
+		m.closeLocked(query)
+		return nil, false, err
+	}
+	query.cursor = cursor
+
+	return m.nextPageLocked(query, pageSize)
+}
+
+// fetch returns the next page of at most pageSize rows of the query. The returned error is an HTTP error.
+func (m *sqlQueryManager) fetch(query *sqlQuery, pageSize int) (*api.SQLResultSet, error) {
+	query.mu.Lock()
+	defer query.mu.Unlock()
+
+	if query.closed || query.cursor == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query not found or already closed: %s", query.id))
+	}
+	if query.idleTimer != nil {
+		query.idleTimer.Stop()
+	}
+
+	resultSet := &api.SQLResultSet{QueryID: query.id}
+	rowSet, hasMore, err := m.nextPageLocked(query, pageSize)
+	if err != nil {
+		resultSet.Error = err.Error()
+		return resultSet, nil
+	}
+	bytes, err := json.Marshal(rowSet)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal query result").SetInternal(err)
+	}
+	resultSet.Data = string(bytes)
+	resultSet.HasMore = hasMore
+	return resultSet, nil
+}
+
+// nextPageLocked returns the next page of the query in the same format as db.Driver.Query, and whether there are more rows.
+// The query is closed unless there are more rows. The caller should hold query.mu.
+func (m *sqlQueryManager) nextPageLocked(query *sqlQuery, pageSize int) ([]interface{}, bool, error) {
+	n := pageSize
+	if query.limit > 0 {
+		if remaining := query.limit - query.rowCount; n <= 0 || n > remaining {
+			n = remaining
+		}
+	}
+	data, hasMore, err := query.cursor.Next(n)
+	if err != nil {
+		m.closeLocked(query)
+		return nil, false, err
+	}
+	query.rowCount += len(data)
+	if query.limit > 0 && query.rowCount >= query.limit {
+		hasMore = false
+	}
+
+	columnNames, columnTypeNames := query.cursor.Header()
+	rowSet := []interface{}{columnNames, columnTypeNames, data}
+	if query.masker != nil {
+		maskQueryResult(query.masker, rowSet)
+	}
+
+	if !hasMore {
+		m.closeLocked(query)
+	} else if query.idleTimer == nil {
+		query.idleTimer = time.AfterFunc(sqlQueryCursorIdleTimeout, func() {
+			m.close(query)
+		})
+	} else {
+		query.idleTimer.Reset(sqlQueryCursorIdleTimeout)
+	}
+	return rowSet, hasMore, nil
+}
+
+// close cancels the query, which kills the running statement by the database first, and closes it
+// once the run or the fetch holding the query returns.
+func (m *sqlQueryManager) close(query *sqlQuery) {
+	query.cancel()
+
+	query.mu.Lock()
+	defer query.mu.Unlock()
+	m.closeLocked(query)
+}
+
+// closeLocked unregisters the query and releases its cursor and driver. The caller should hold query.mu.
+// It's safe to call more than once.
+func (m *sqlQueryManager) closeLocked(query *sqlQuery) {
+	m.mu.Lock()
+	if m.queryMap[query.id] == query {
+		delete(m.queryMap, query.id)
+	}
+	m.mu.Unlock()
+
+	query.closed = true
+	if query.idleTimer != nil {
+		query.idleTimer.Stop()
+	}
+	// The cursor is closed before the context is canceled, so that the exhausted statement isn't killed.
+	if query.cursor != nil {
+		query.cursor.Close()
+		query.cursor = nil
+	}
+	query.cancel()
+	if query.driver != nil {
+		query.driver.Close(context.Background())
+		query.driver = nil
+	}
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/labstack/echo/v4"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+)
+
+// checkSQLQueryAccess checks the SQL query access policy of the instance environment for the principal to query
+// or export the statement in the database, including the daily quota, and returns the policy.
+// The row limit isn't capped here, since the query and the export are capped by different policies.
+// The statement should have the named :param placeholders normalized, see param.Normalize.
+// The returned error is an HTTP error.
+func (s *Server) checkSQLQueryAccess(ctx context.Context, principalID int, role api.Role, instance *api.Instance, databaseName, statement string) (*api.SQLQueryAccessPolicy, error) {
+	policy, err := s.PolicyService.GetSQLQueryAccessPolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch SQL query access policy of environment ID: %v", instance.EnvironmentID)).SetInternal(err)
+	}
+	if policy.IsExempted(role) {
+		return policy, nil
+	}
+
+	if policy.RequireProjectMember || policy.RequireAccessRequest {
+		databaseNameList, err := getQueriedDatabaseNameList(instance.Engine, databaseName, statement)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Failed to find the databases queried by the statement for the SQL query access policy of environment %q: %s", instance.Environment.Name, err.Error())).SetInternal(err)
+		}
+		for _, name := range databaseNameList {
+			if err := s.checkSQLQueryDatabaseAccess(ctx, principalID, policy, instance, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Both the queries and the exports are recorded in the query history, and counted by the day in UTC,
+	// so that the quota doesn't depend on the time zone of the server.
+	if policy.MaxQueryCountPerDay > 0 {
+		year, month, day := time.Now().UTC().Date()
+		startOfDay := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix()
+		queryHistoryList, err := s.QueryHistoryService.FindQueryHistoryList(ctx, &api.QueryHistoryFind{
+			CreatorID:      &principalID,
+			EnvironmentID:  &instance.EnvironmentID,
+			CreatedTsAfter: &startOfDay,
+			Limit:          &policy.MaxQueryCountPerDay,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch query history list").SetInternal(err)
+		}
+		if len(queryHistoryList) >= policy.MaxQueryCountPerDay {
+			return nil, echo.NewHTTPError(http.StatusTooManyRequests, fmt.Sprintf("Exceeded the limit of %d queries and exports per day (UTC) in environment %q", policy.MaxQueryCountPerDay, instance.Environment.Name))
+		}
+	}
+
+	return policy, nil
+}
+
+// checkSQLQueryDatabaseAccess checks that the principal is a member of the database project, and has an approved
+// and unexpired data source request issue of the database if required by the policy.
+func (s *Server) checkSQLQueryDatabaseAccess(ctx context.Context, principalID int, policy *api.SQLQueryAccessPolicy, instance *api.Instance, databaseName string) error {
+	database, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{
+		InstanceID: &instance.ID,
+		Name:       &databaseName,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database %q of instance %q", databaseName, instance.Name)).SetInternal(err)
+	}
+	if database == nil {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Database %q is not found in instance %q, which is required by the SQL query access policy of environment %q", databaseName, instance.Name, instance.Environment.Name))
+	}
+
+	if policy.RequireProjectMember && !isProjectMember(database.Project, principalID) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Querying database %q requires the membership of project %q", database.Name, database.Project.Name))
+	}
+
+	if policy.RequireAccessRequest {
+		issueType := api.IssueDataSourceRequest
+		issueList, err := s.IssueService.FindIssueList(ctx, &api.IssueFind{
+			ProjectID:   &database.ProjectID,
+			Type:        &issueType,
+			PrincipalID: &principalID,
+			StatusList:  &[]api.IssueStatus{api.IssueDone},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch data source request issues of project ID: %v", database.ProjectID)).SetInternal(err)
+		}
+		if !hasApprovedDataSourceRequest(issueList, principalID, database.ID, policy.MaxAccessRequestDurationSeconds, time.Now().Unix()) {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Querying database %q requires an approved data source request issue", database.Name))
+		}
+	}
+	return nil
+}
+
+// hasApprovedDataSourceRequest returns whether any of the issues is a data source request of the database created
+// by the principal, which is approved and unexpired at ts. The request expires no later than maxDurationSeconds
+// after it's created if maxDurationSeconds > 0.
+func hasApprovedDataSourceRequest(issueList []*api.Issue, principalID int, databaseID int, maxDurationSeconds int64, ts int64) bool {
+	for _, issue := range issueList {
+		if issue.Type != api.IssueDataSourceRequest || issue.Status != api.IssueDone || issue.CreatorID != principalID {
+			continue
+		}
+		var payload api.DataSourceRequestContext
+		if err := json.Unmarshal([]byte(issue.Payload), &payload); err != nil {
+			continue
+		}
+		expireTs := payload.ExpireTs
+		if maxDurationSeconds > 0 && expireTs > issue.CreatedTs+maxDurationSeconds {
+			expireTs = issue.CreatedTs + maxDurationSeconds
+		}
+		if payload.DatabaseID == databaseID && expireTs > ts {
+			return true
+		}
+	}
+	return false
+}
+
+// getSQLQueryAccessLimit returns the row limit capped by the policy, which is maxRowCount if limit <= 0.
+// The limit isn't capped if maxRowCount is 0.
+func getSQLQueryAccessLimit(maxRowCount int, limit int) int {
+	if maxRowCount > 0 && (limit <= 0 || limit > maxRowCount) {
+		return maxRowCount
+	}
+	return limit
+}
+
+// getQueriedDatabaseNameList returns the databases queried by the statement in the connected database.
+// For MySQL, TiDB and ClickHouse, the tables can be qualified by other databases, so the statement is parsed to
+// find them. For other engines, only the connected database can be queried.
+func getQueriedDatabaseNameList(engine db.Type, databaseName, statement string) ([]string, error) {
+	var databaseNameList []string
+	if databaseName != "" {
+		databaseNameList = append(databaseNameList, databaseName)
+	}
+	if engine != db.MySQL && engine != db.TiDB && engine != db.ClickHouse {
+		if databaseName == "" {
+			return nil, fmt.Errorf("database must be specified for %s", engine)
+		}
+		return databaseNameList, nil
+	}
+
+	p := parser.New()
+	p.EnableWindowFunc(true)
+	nodeList, _, err := p.Parse(statement, "", "")
+	if err != nil {
+		return nil, err
+	}
+	v := &tableNameVisitor{}
+	for _, node := range nodeList {
+		node.Accept(v)
+	}
+	// The unqualified table is in the default database of the connection, which is unknown.
+	if databaseName == "" && v.hasUnqualified {
+		return nil, fmt.Errorf("database must be specified for the table without the database qualifier")
+	}
+	seen := make(map[string]bool)
+	for _, name := range databaseNameList {
+		seen[name] = true
+	}
+	for _, name := range v.schemaList {
+		if !seen[name] {
+			seen[name] = true
+			databaseNameList = append(databaseNameList, name)
+		}
+	}
+	return databaseNameList, nil
+}
+
+// tableNameVisitor collects the databases qualifying the table names.
+type tableNameVisitor struct {
+	schemaList []string
+	// hasUnqualified is true if any table name isn't qualified by the database, including the CTE references.
+	hasUnqualified bool
+}
+
+func (v *tableNameVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	if tableName, ok := in.(*ast.TableName); ok {
+		if tableName.Schema.O != "" {
+			v.schemaList = append(v.schemaList, tableName.Schema.O)
+		} else {
+			v.hasUnqualified = true
+		}
+	}
+	return in, false
+}
+
+func (v *tableNameVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
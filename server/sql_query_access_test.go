@@ -0,0 +1,161 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestGetSQLQueryAccessLimit(t *testing.T) {
+	tests := []struct {
+		maxRowCount int
+		limit       int
+		want        int
+	}{
+		{maxRowCount: 0, limit: 50, want: 50},
+		{maxRowCount: 0, limit: 0, want: 0},
+		{maxRowCount: 1000, limit: 50, want: 50},
+		{maxRowCount: 1000, limit: 5000, want: 1000},
+		{maxRowCount: 1000, limit: 0, want: 1000},
+		{maxRowCount: 1000, limit: -1, want: 1000},
+	}
+	for _, test := range tests {
+		if got := getSQLQueryAccessLimit(test.maxRowCount, test.limit); got != test.want {
+			t.Errorf("getSQLQueryAccessLimit(%d, %d) = %d, want %d", test.maxRowCount, test.limit, got, test.want)
+		}
+	}
+}
+
+func TestHasApprovedDataSourceRequest(t *testing.T) {
+	const now = int64(1650000000)
+	request := func(creatorID int, status api.IssueStatus, payload string) *api.Issue {
+		return &api.Issue{
+			CreatorID: creatorID,
+			CreatedTs: now - 3600,
+			Type:      api.IssueDataSourceRequest,
+			Status:    status,
+			Payload:   payload,
+		}
+	}
+	tests := []struct {
+		name               string
+		issueList          []*api.Issue
+		maxDurationSeconds int64
+		want               bool
+	}{
+		{
+			name:      "approved",
+			issueList: []*api.Issue{request(101, api.IssueDone, `{"databaseId":7,"expireTs":1650003600}`)},
+			want:      true,
+		},
+		{
+			name:      "expired",
+			issueList: []*api.Issue{request(101, api.IssueDone, `{"databaseId":7,"expireTs":1649996400}`)},
+			want:      false,
+		},
+		{
+			name:               "within max duration",
+			issueList:          []*api.Issue{request(101, api.IssueDone, `{"databaseId":7,"expireTs":1650003600}`)},
+			maxDurationSeconds: 7200,
+			want:               true,
+		},
+		{
+			name:               "cut short by max duration",
+			issueList:          []*api.Issue{request(101, api.IssueDone, `{"databaseId":7,"expireTs":1650003600}`)},
+			maxDurationSeconds: 3600,
+			want:               false,
+		},
+		{
+			name:      "open",
+			issueList: []*api.Issue{request(101, api.IssueOpen, `{"databaseId":7,"expireTs":1650003600}`)},
+			want:      false,
+		},
+		{
+			name:      "other database",
+			issueList: []*api.Issue{request(101, api.IssueDone, `{"databaseId":8,"expireTs":1650003600}`)},
+			want:      false,
+		},
+		{
+			name:      "other creator",
+			issueList: []*api.Issue{request(102, api.IssueDone, `{"databaseId":7,"expireTs":1650003600}`)},
+			want:      false,
+		},
+		{
+			name: "invalid payload skipped",
+			issueList: []*api.Issue{
+				request(101, api.IssueDone, `{}`),
+				request(101, api.IssueDone, `invalid`),
+				request(101, api.IssueDone, `{"databaseId":7,"expireTs":1650003600}`),
+			},
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		if got := hasApprovedDataSourceRequest(test.issueList, 101, 7, test.maxDurationSeconds, now); got != test.want {
+			t.Errorf("%s: hasApprovedDataSourceRequest() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetQueriedDatabaseNameList(t *testing.T) {
+	tests := []struct {
+		engine       db.Type
+		databaseName string
+		statement    string
+		want         []string
+		wantErr      bool
+	}{
+		{
+			engine:       db.Postgres,
+			databaseName: "employee",
+			statement:    "SELECT * FROM public.salary",
+			want:         []string{"employee"},
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SELECT 1",
+			wantErr:   true,
+		},
+		{
+			engine:       db.MySQL,
+			databaseName: "employee",
+			statement:    "SELECT * FROM salary s JOIN hr.title t ON s.emp_no = t.emp_no WHERE s.emp_no IN (SELECT emp_no FROM employee.dept_emp)",
+			want:         []string{"employee", "hr"},
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SELECT * FROM hr.title UNION SELECT * FROM finance.title WHERE id = ?",
+			want:      []string{"hr", "finance"},
+		},
+		{
+			// The unqualified table is in the unknown default database.
+			engine:    db.MySQL,
+			statement: "SELECT * FROM title",
+			wantErr:   true,
+		},
+		{
+			engine:       db.TiDB,
+			databaseName: "employee",
+			statement:    "SELECT * FROM",
+			wantErr:      true,
+		},
+	}
+	for _, test := range tests {
+		got, err := getQueriedDatabaseNameList(test.engine, test.databaseName, test.statement)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("getQueriedDatabaseNameList(%s, %q, %q) expects error, got %v", test.engine, test.databaseName, test.statement, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("getQueriedDatabaseNameList(%s, %q, %q) got error: %v", test.engine, test.databaseName, test.statement, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("getQueriedDatabaseNameList(%s, %q, %q) = %v, want %v", test.engine, test.databaseName, test.statement, got, test.want)
+		}
+	}
+}
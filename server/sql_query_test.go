@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// fakeQueryDriver returns the fakeQueryCursor of the rows, and only implements the methods used by sqlQueryManager.
+type fakeQueryDriver struct {
+	db.Driver
+	cursor *fakeQueryCursor
+	closed bool
+}
+
+func (d *fakeQueryDriver) QueryCursor(ctx context.Context, statement string, timeout time.Duration, args ...interface{}) (db.QueryCursor, error) {
+	return d.cursor, nil
+}
+
+func (d *fakeQueryDriver) Close(ctx context.Context) error {
+	d.closed = true
+	return nil
+}
+
+type fakeQueryCursor struct {
+	data   []interface{}
+	closed bool
+}
+
+func (c *fakeQueryCursor) Header() ([]string, []string) {
+	return []string{"id"}, []string{"INT"}
+}
+
+func (c *fakeQueryCursor) Next(n int) ([]interface{}, bool, error) {
+	if n <= 0 || n > len(c.data) {
+		n = len(c.data)
+	}
+	data := c.data[:n]
+	c.data = c.data[n:]
+	return data, len(c.data) > 0, nil
+}
+
+func (c *fakeQueryCursor) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newFakeQueryDriver(rowCount int) *fakeQueryDriver {
+	cursor := &fakeQueryCursor{data: []interface{}{}}
+	for i := 1; i <= rowCount; i++ {
+		cursor.data = append(cursor.data, []interface{}{int64(i)})
+	}
+	return &fakeQueryDriver{cursor: cursor}
+}
+
+func TestSQLQueryManagerRegister(t *testing.T) {
+	m := newSQLQueryManager()
+
+	query, err := m.register("", 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.id == "" {
+		t.Errorf("expect generated query ID")
+	}
+	if _, err := m.register("bad id!", 101); err == nil {
+		t.Errorf("expect error for invalid query ID")
+	}
+	if _, err := m.register("q1", 101); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.register("q1", 102); err == nil {
+		t.Errorf("expect error for duplicate query ID")
+	}
+	if got := m.get("q1", 102); got != nil {
+		t.Errorf("expect the query of others not found")
+	}
+	if got := m.get("q1", 101); got == nil {
+		t.Errorf("expect the query found")
+	}
+
+	for i := 2; i < maxSQLQueryPerPrincipal; i++ {
+		if _, err := m.register(fmt.Sprintf("q%d", i), 101); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.register("", 101); err == nil {
+		t.Errorf("expect error exceeding %d queries per principal", maxSQLQueryPerPrincipal)
+	}
+	if _, err := m.register("", 102); err != nil {
+		t.Errorf("expect other principal unaffected, got %v", err)
+	}
+}
+
+func TestSQLQueryManagerPagination(t *testing.T) {
+	m := newSQLQueryManager()
+	driver := newFakeQueryDriver(5)
+	query, err := m.register("q1", 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The limit caps the rows of all pages.
+	rowSet, hasMore, err := m.run(query, driver, "SELECT id FROM t", nil, 0, nil, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{[]interface{}{int64(1)}, []interface{}{int64(2)}, []interface{}{int64(3)}}; !reflect.DeepEqual(rowSet[2], want) {
+		t.Errorf("first page = %v, want %v", rowSet[2], want)
+	}
+	if !hasMore {
+		t.Errorf("expect more rows after the first page")
+	}
+	if m.get("q1", 101) == nil {
+		t.Fatalf("expect the query open for the next page")
+	}
+
+	resultSet, err := m.fetch(query, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[["id"],["INT"],[[4]]]`; resultSet.Data != want {
+		t.Errorf("second page = %s, want %s", resultSet.Data, want)
+	}
+	if resultSet.HasMore {
+		t.Errorf("expect no more rows after reaching the limit")
+	}
+	if m.get("q1", 101) != nil {
+		t.Errorf("expect the query unregistered after the last page")
+	}
+	if !driver.cursor.closed || !driver.closed {
+		t.Errorf("expect the cursor and the driver closed after the last page")
+	}
+	if _, err := m.fetch(query, 3); err == nil {
+		t.Errorf("expect error fetching the closed query")
+	}
+}
+
+func TestSQLQueryManagerClose(t *testing.T) {
+	m := newSQLQueryManager()
+	driver := newFakeQueryDriver(5)
+	query, err := m.register("q1", 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, hasMore, err := m.run(query, driver, "SELECT id FROM t", nil, 0, nil, 0, 2); err != nil || !hasMore {
+		t.Fatalf("run: hasMore = %v, err = %v", hasMore, err)
+	}
+
+	m.close(query)
+	if m.get("q1", 101) != nil {
+		t.Errorf("expect the query unregistered after close")
+	}
+	if !driver.cursor.closed || !driver.closed {
+		t.Errorf("expect the cursor and the driver closed after close")
+	}
+	if query.ctx.Err() == nil {
+		t.Errorf("expect the query context canceled after close")
+	}
+
+	// The query canceled before it runs isn't run.
+	query, err = m.register("q2", 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.close(query)
+	driver = newFakeQueryDriver(5)
+	if _, _, err := m.run(query, driver, "SELECT id FROM t", nil, 0, nil, 0, 2); err == nil {
+		t.Errorf("expect error running the canceled query")
+	}
+	if !driver.closed {
+		t.Errorf("expect the driver closed for the canceled query")
+	}
+}
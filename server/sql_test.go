@@ -1,7 +1,11 @@
 package server
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestValidateSQLSelectStatement(t *testing.T) {
@@ -90,3 +94,138 @@ func TestValidateSQLSelectStatement(t *testing.T) {
 		}
 	}
 }
+
+func TestIsSchemaFingerprintUnchanged(t *testing.T) {
+	tests := []struct {
+		name        string
+		database    *api.DatabaseRaw
+		fingerprint string
+		want        bool
+	}{
+		{
+			name:        "new database",
+			database:    nil,
+			fingerprint: "abc",
+			want:        false,
+		},
+		{
+			name:        "unchanged",
+			database:    &api.DatabaseRaw{SyncStatus: api.OK, SchemaFingerprint: "abc"},
+			fingerprint: "abc",
+			want:        true,
+		},
+		{
+			name:        "changed",
+			database:    &api.DatabaseRaw{SyncStatus: api.OK, SchemaFingerprint: "abc"},
+			fingerprint: "def",
+			want:        false,
+		},
+		{
+			name:        "last sync failed",
+			database:    &api.DatabaseRaw{SyncStatus: api.SyncFailed, SchemaFingerprint: "abc"},
+			fingerprint: "abc",
+			want:        false,
+		},
+		{
+			name:        "fingerprint not supported",
+			database:    &api.DatabaseRaw{SyncStatus: api.OK, SchemaFingerprint: ""},
+			fingerprint: "",
+			want:        false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := isSchemaFingerprintUnchanged(test.database, test.fingerprint); got != test.want {
+			t.Errorf("%s: isSchemaFingerprintUnchanged() got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestHasExplainPrefix(t *testing.T) {
+	tests := []struct {
+		sqlStatement string
+		want         bool
+	}{
+		{sqlStatement: "SELECT * FROM t", want: false},
+		{sqlStatement: " explain SELECT * FROM t", want: true},
+		{sqlStatement: "EXPLAIN\nSELECT 1", want: true},
+		{sqlStatement: "SELECT explain FROM t", want: false},
+	}
+
+	for _, test := range tests {
+		if got := hasExplainPrefix(test.sqlStatement); got != test.want {
+			t.Errorf("hasExplainPrefix(%q) = %v, want %v", test.sqlStatement, got, test.want)
+		}
+	}
+}
+
+func TestValidateSQLExplainStatement(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		wantErr   bool
+	}{
+		{engine: db.MySQL, statement: "SELECT * FROM t WHERE id = 1;", wantErr: false},
+		{engine: db.MySQL, statement: "SELECT a FROM t UNION SELECT a FROM u", wantErr: false},
+		{engine: db.MySQL, statement: "SELECT 1; DELETE FROM t", wantErr: true},
+		{engine: db.MySQL, statement: "SELECT * FROM t INTO OUTFILE '/tmp/t'", wantErr: true},
+		{engine: db.MySQL, statement: "SELECT * FROM", wantErr: true},
+		{engine: db.Postgres, statement: "SELECT * FROM t WHERE name = 'a; DELETE FROM t';", wantErr: false},
+		{engine: db.Postgres, statement: "WITH c AS (SELECT 1) SELECT * FROM c -- ; DELETE", wantErr: false},
+		{engine: db.Postgres, statement: "SELECT $$;update$$, 'it''s', E'\\';' /* ; /* nested */ ; */", wantErr: false},
+		{engine: db.Postgres, statement: "(SELECT 1)", wantErr: false},
+		{engine: db.Postgres, statement: "SELECT 1; DELETE FROM t", wantErr: true},
+		{engine: db.Postgres, statement: "WITH d AS (DELETE FROM t RETURNING *) SELECT * FROM d", wantErr: true},
+		{engine: db.Postgres, statement: "SELECT * INTO u FROM t", wantErr: true},
+		{engine: db.Postgres, statement: "UPDATE t SET a = 1", wantErr: true},
+		{engine: db.Snowflake, statement: "SELECT 1", wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := validateSQLExplainStatement(test.engine, test.statement)
+		if (err != nil) != test.wantErr {
+			t.Errorf("validateSQLExplainStatement(%s, %q) got error %v, want error %v", test.engine, test.statement, err, test.wantErr)
+		}
+	}
+}
+
+func TestGetFullTableScanList(t *testing.T) {
+	plan := &api.SQLPlanNode{
+		NodeType: "Hash Join",
+		ChildList: []*api.SQLPlanNode{
+			{NodeType: "Seq Scan", Table: "t2", FullTableScan: true},
+			{
+				NodeType: "Nested Loop",
+				ChildList: []*api.SQLPlanNode{
+					{NodeType: "Seq Scan", Table: "t1", FullTableScan: true},
+					{NodeType: "Index Scan", Table: "t3", Index: "t3_pkey"},
+					{NodeType: "Seq Scan", Table: "t2", FullTableScan: true},
+				},
+			},
+		},
+	}
+	want := []string{"t2", "t1"}
+	if got := getFullTableScanList(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("getFullTableScanList() = %v, want %v", got, want)
+	}
+}
+
+func TestBindSQLParameterList(t *testing.T) {
+	statement := "SELECT * FROM t WHERE id = :id"
+	if got, args, err := bindSQLParameterList(db.MySQL, "SELECT 1", nil); err != nil || got != "SELECT 1" || args != nil {
+		t.Errorf("bindSQLParameterList() = %q, %v, %v, want the statement as is", got, args, err)
+	}
+	got, args, err := bindSQLParameterList(db.Postgres, statement, []api.SQLParameter{{Name: "id", Value: "1"}})
+	if err != nil {
+		t.Fatalf("bindSQLParameterList() returns error: %v", err)
+	}
+	if want, wantArgs := "SELECT * FROM t WHERE id = $1", []interface{}{"1"}; got != want || !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("bindSQLParameterList() = %q, %v, want %q, %v", got, args, want, wantArgs)
+	}
+	if _, _, err := bindSQLParameterList(db.MySQL, statement, nil); err == nil {
+		t.Errorf("bindSQLParameterList() returns no error for the missing parameter")
+	}
+	if _, _, err := bindSQLParameterList(db.MySQL, statement, []api.SQLParameter{{Name: "id", Value: "1"}, {Name: "id", Value: "2"}}); err == nil {
+		t.Errorf("bindSQLParameterList() returns no error for the duplicate parameter")
+	}
+}
@@ -643,7 +643,7 @@ func (s *Server) changeTaskStatusWithPatch(ctx context.Context, task *api.Task,
 		taskPatched = scheduledTask
 	}
 
-	// If create database or schema update task completes, we sync the corresponding instance schema immediately.
+	// If create database or schema update task completes, we sync the corresponding database schema immediately.
 	if (taskPatched.Type == api.TaskDatabaseCreate || taskPatched.Type == api.TaskDatabaseSchemaUpdate) &&
 		taskPatched.Status == api.TaskDone {
 		// TODO(dragonly): remove this composition
@@ -651,7 +651,18 @@ func (s *Server) changeTaskStatusWithPatch(ctx context.Context, task *api.Task,
 		if err != nil {
 			return nil, fmt.Errorf("failed to sync instance schema after completing task: %w", err)
 		}
-		s.syncEngineVersionAndSchema(ctx, instance)
+		if taskPatched.Database != nil {
+			// The fingerprint is unknown here, so the database will be synced again in the next incremental sync.
+			if err := s.syncDatabaseSchema(ctx, instance, taskPatched.Database.Name, ""); err != nil {
+				s.l.Warn("Failed to sync database schema after completing task",
+					zap.Int("task_id", taskPatched.ID),
+					zap.String("database_name", taskPatched.Database.Name),
+					zap.Error(err))
+			}
+		} else {
+			incremental := true
+			s.syncEngineVersionAndSchema(ctx, instance, incremental)
+		}
 	}
 
 	// If this is the last task in the pipeline and just completed, and the assignee is system bot:
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/dictionary"
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
 	"go.uber.org/zap"
 )
@@ -159,7 +161,8 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 	}
 
 	// If VCS based and schema path template is specified, then we will write back the latest schema file after migration.
-	writeBack := (vcsPushEvent != nil) && (repoRawOutter.SchemaPathTemplate != "")
+	// Similarly for the data dictionary path template.
+	writeBack := (vcsPushEvent != nil) && (repoRawOutter.SchemaPathTemplate != "" || repoRawOutter.DictionaryPathTemplate != "")
 	// For tenant mode project, we will only write back latest schema file on the last task.
 	if writeBack && issue != nil {
 		project, err := server.composeProjectByID(ctx, task.Database.ProjectID)
@@ -183,15 +186,11 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 	}
 
 	if writeBack {
-		latestSchemaFile := filepath.Join(repoRawOutter.BaseDirectory, repoRawOutter.SchemaPathTemplate)
-		latestSchemaFile = strings.ReplaceAll(latestSchemaFile, "{{ENV_NAME}}", mi.Environment)
-		latestSchemaFile = strings.ReplaceAll(latestSchemaFile, "{{DB_NAME}}", mi.Database)
-
 		// TODO(dragonly): revisit the usage of a not-fully-composed Repository here
 		repo := repoRawOutter.ToRepository()
 		vcs, err := server.composeVCSByID(ctx, repoRawOutter.VCSID)
 		if err != nil {
-			return true, nil, fmt.Errorf("failed to sync schema file %s after applying migration %s to %q", latestSchemaFile, mi.Version, databaseName)
+			return true, nil, fmt.Errorf("failed to write back files after applying migration %s to %q", mi.Version, databaseName)
 		}
 		if vcs == nil {
 			return true, nil, fmt.Errorf("VCS ID not found: %d", repoRawOutter.VCSID)
@@ -209,26 +208,21 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 			bytebaseURL = fmt.Sprintf("%s:%d/issue/%s?stage=%d", server.frontendHost, server.frontendPort, api.IssueSlug(issue), task.StageID)
 		}
 
-		commitID, err := writeBackLatestSchema(ctx, server, repo, vcsPushEvent, mi, branch, latestSchemaFile, schema, bytebaseURL)
-		if err != nil {
-			return true, nil, err
-		}
-
 		// Create file commit activity
-		{
+		createFileCommitActivity := func(filePath, commitID, comment string) {
 			payload, err := json.Marshal(api.ActivityPipelineTaskFileCommitPayload{
 				TaskID:             task.ID,
 				VCSInstanceURL:     repo.VCS.InstanceURL,
 				RepositoryFullPath: vcsPushEvent.RepositoryFullPath,
 				Branch:             branch,
-				FilePath:           latestSchemaFile,
+				FilePath:           filePath,
 				CommitID:           commitID,
 			})
 			if err != nil {
-				l.Error("Failed to marshal file commit activity after writing back the latest schema",
+				l.Error("Failed to marshal file commit activity after writing back the latest file",
 					zap.Int("task_id", task.ID),
 					zap.String("repository", repoRawOutter.WebURL),
-					zap.String("file_path", latestSchemaFile),
+					zap.String("file_path", filePath),
 					zap.Error(err),
 				)
 			}
@@ -242,23 +236,48 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 				ContainerID: containerID,
 				Type:        api.ActivityPipelineTaskFileCommit,
 				Level:       api.ActivityInfo,
-				Comment: fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.",
-					mi.Version,
-					mi.Database,
-				),
-				Payload: string(payload),
+				Comment:     comment,
+				Payload:     string(payload),
 			}
 
 			_, err = server.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
 			if err != nil {
-				l.Error("Failed to create file commit activity after writing back the latest schema",
+				l.Error("Failed to create file commit activity after writing back the latest file",
 					zap.Int("task_id", task.ID),
 					zap.String("repository", repoRawOutter.WebURL),
-					zap.String("file_path", latestSchemaFile),
+					zap.String("file_path", filePath),
 					zap.Error(err),
 				)
 			}
 		}
+
+		if repoRawOutter.SchemaPathTemplate != "" {
+			latestSchemaFile := getLatestFilePath(repoRawOutter.BaseDirectory, repoRawOutter.SchemaPathTemplate, mi)
+			commitID, err := writeBackLatestFile(ctx, server, repo, vcsPushEvent, mi, branch, latestSchemaFile, "latest schema", schema, bytebaseURL)
+			if err != nil {
+				return true, nil, err
+			}
+			createFileCommitActivity(latestSchemaFile, commitID, fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.",
+				mi.Version,
+				mi.Database,
+			))
+		}
+
+		if repoRawOutter.DictionaryPathTemplate != "" {
+			dictionaryFile := getLatestFilePath(repoRawOutter.BaseDirectory, repoRawOutter.DictionaryPathTemplate, mi)
+			content, err := getLatestDataDictionary(ctx, driver, mi, dictionaryFile)
+			if err != nil {
+				return true, nil, err
+			}
+			commitID, err := writeBackLatestFile(ctx, server, repo, vcsPushEvent, mi, branch, dictionaryFile, "data dictionary", content, bytebaseURL)
+			if err != nil {
+				return true, nil, err
+			}
+			createFileCommitActivity(dictionaryFile, commitID, fmt.Sprintf("Committed the data dictionary after applying migration version %s to %q.",
+				mi.Version,
+				mi.Database,
+			))
+		}
 	}
 
 	detail := fmt.Sprintf("Applied migration version %s to database %q.", mi.Version, databaseName)
@@ -273,10 +292,45 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 	}, nil
 }
 
-// Writes back the latest schema to the repository after migration
+// getLatestFilePath returns the path of the file auto-generated by Bytebase after migration, e.g. the latest schema file.
+func getLatestFilePath(baseDirectory, pathTemplate string, mi *db.MigrationInfo) string {
+	filePath := filepath.Join(baseDirectory, pathTemplate)
+	filePath = strings.ReplaceAll(filePath, "{{ENV_NAME}}", mi.Environment)
+	return strings.ReplaceAll(filePath, "{{DB_NAME}}", mi.Database)
+}
+
+// getLatestDataDictionary returns the data dictionary of the migrated database, in the format decided by the file extension.
+func getLatestDataDictionary(ctx context.Context, driver db.Driver, mi *db.MigrationInfo, dictionaryFile string) (string, error) {
+	format, err := dictionary.FormatFromPath(dictionaryFile)
+	if err != nil {
+		return "", err
+	}
+	schemaList, err := driver.SyncSchema(ctx, mi.Database)
+	if err != nil {
+		return "", fmt.Errorf("failed to sync schema for the data dictionary after applying migration %s to %q: %w", mi.Version, mi.Database, err)
+	}
+	dict := &dictionary.Dictionary{Title: fmt.Sprintf("Data dictionary of %s", mi.Database)}
+	for _, schema := range schemaList {
+		if schema.Name == mi.Database {
+			database := dictionary.NewDatabase(schema)
+			database.Environment = mi.Environment
+			dict.DatabaseList = append(dict.DatabaseList, database)
+		}
+	}
+	if len(dict.DatabaseList) == 0 {
+		return "", fmt.Errorf("database %q not found when generating the data dictionary after applying migration %s", mi.Database, mi.Version)
+	}
+	var buf bytes.Buffer
+	if err := dictionary.Render(&buf, format, dict); err != nil {
+		return "", fmt.Errorf("failed to render the data dictionary after applying migration %s to %q: %w", mi.Version, mi.Database, err)
+	}
+	return buf.String(), nil
+}
+
+// Writes back the latest file such as the latest schema to the repository after migration.
 // Returns the commit id on success.
-func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.Repository, pushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, branch string, latestSchemaFile string, schema string, bytebaseURL string) (string, error) {
-	schemaFileMeta, err := vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{Logger: server.l}).ReadFileMeta(
+func writeBackLatestFile(ctx context.Context, server *Server, repository *api.Repository, pushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, branch string, latestFile string, subject string, content string, bytebaseURL string) (string, error) {
+	fileMeta, err := vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{Logger: server.l}).ReadFileMeta(
 		ctx,
 		common.OauthContext{
 			ClientID:     repository.VCS.ApplicationID,
@@ -287,22 +341,22 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 		},
 		repository.VCS.InstanceURL,
 		repository.ExternalID,
-		latestSchemaFile,
+		latestFile,
 		branch,
 	)
 
-	createSchemaFile := false
+	createFile := false
 	verb := "Update"
 	if err != nil {
 		if common.ErrorCode(err) == common.NotFound {
-			createSchemaFile = true
+			createFile = true
 			verb = "Create"
 		} else {
-			return "", fmt.Errorf("failed to fetch latest schema: %w", err)
+			return "", fmt.Errorf("failed to fetch %s: %w", subject, err)
 		}
 	}
 
-	commitTitle := fmt.Sprintf("[Bytebase] %s latest schema for %q after migration %s", verb, mi.Database, mi.Version)
+	commitTitle := fmt.Sprintf("[Bytebase] %s %s for %q after migration %s", verb, subject, mi.Database, mi.Version)
 	commitBody := "THIS COMMIT IS AUTO-GENERATED BY BYTEBASE"
 	if bytebaseURL != "" {
 		commitBody += "\n\n" + bytebaseURL
@@ -313,12 +367,12 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 		pushEvent.FileCommit.Message,
 	)
 
-	schemaFileCommit := vcsPlugin.FileCommitCreate{
+	fileCommit := vcsPlugin.FileCommitCreate{
 		Branch:        branch,
 		CommitMessage: fmt.Sprintf("%s\n\n%s", commitTitle, commitBody),
-		Content:       schema,
+		Content:       content,
 	}
-	if createSchemaFile {
+	if createFile {
 		err := vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{Logger: server.l}).CreateFile(
 			ctx,
 			common.OauthContext{
@@ -330,15 +384,15 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 			},
 			repository.VCS.InstanceURL,
 			repository.ExternalID,
-			latestSchemaFile,
-			schemaFileCommit,
+			latestFile,
+			fileCommit,
 		)
 
 		if err != nil {
 			return "", fmt.Errorf("failed to create file after applying migration %s to %q: %w", mi.Version, mi.Database, err)
 		}
 	} else {
-		schemaFileCommit.LastCommitID = schemaFileMeta.LastCommitID
+		fileCommit.LastCommitID = fileMeta.LastCommitID
 		err := vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{Logger: server.l}).OverwriteFile(
 			ctx,
 			common.OauthContext{
@@ -350,8 +404,8 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 			},
 			repository.VCS.InstanceURL,
 			repository.ExternalID,
-			latestSchemaFile,
-			schemaFileCommit,
+			latestFile,
+			fileCommit,
 		)
 		if err != nil {
 			return "", fmt.Errorf("failed to create file after applying migration %s to %q: %w", mi.Version, mi.Database, err)
@@ -359,7 +413,7 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 	}
 
 	// VCS such as GitLab API doesn't return the commit on write, so we have to call ReadFileMeta again
-	schemaFileMeta, err = vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{Logger: server.l}).ReadFileMeta(
+	fileMeta, err = vcsPlugin.Get(vcsPlugin.GitLabSelfHost, vcsPlugin.ProviderConfig{Logger: server.l}).ReadFileMeta(
 		ctx,
 		common.OauthContext{
 			ClientID:     repository.VCS.ApplicationID,
@@ -370,12 +424,12 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 		},
 		repository.VCS.InstanceURL,
 		repository.ExternalID,
-		latestSchemaFile,
+		latestFile,
 		branch,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch latest schema file %s after update: %w", latestSchemaFile, err)
+		return "", fmt.Errorf("failed to fetch %s file %s after update: %w", subject, latestFile, err)
 	}
-	return schemaFileMeta.LastCommitID, nil
+	return fileMeta.LastCommitID, nil
 }
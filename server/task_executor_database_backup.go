@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db/mysql"
 	"go.uber.org/zap"
 )
 
@@ -57,20 +59,29 @@ func (exec *DatabaseBackupTaskExecutor) RunOnce(ctx context.Context, server *Ser
 
 	// TODO(dragonly): refactor to get composed Backup
 	backup := backupRaw.ToBackup()
-	backupErr := exec.backupDatabase(ctx, task.Instance, task.Database.Name, backup, server.dataDir)
+	size, checksum, backupPayload, backupErr := exec.backupDatabase(ctx, server, task.Instance, task.Database.Name, backup)
 	// Update the status of the backup.
-	newBackupStatus := string(api.BackupStatusDone)
-	comment := ""
-	if backupErr != nil {
-		newBackupStatus = string(api.BackupStatusFailed)
-		comment = backupErr.Error()
-	}
-	if _, err := server.BackupService.PatchBackup(ctx, &api.BackupPatch{
+	backupPatch := &api.BackupPatch{
 		ID:        backupRaw.ID,
-		Status:    newBackupStatus,
+		Status:    string(api.BackupStatusDone),
 		UpdaterID: api.SystemBotID,
-		Comment:   comment,
-	}); err != nil {
+		Size:      &size,
+		Checksum:  &checksum,
+	}
+	if backupErr != nil {
+		backupPatch.Status = string(api.BackupStatusFailed)
+		backupPatch.Comment = backupErr.Error()
+		backupPatch.Size = nil
+		backupPatch.Checksum = nil
+	} else if backupPayload != nil {
+		payload, err := json.Marshal(backupPayload)
+		if err != nil {
+			return true, nil, fmt.Errorf("failed to marshal backup payload: %w", err)
+		}
+		payloadStr := string(payload)
+		backupPatch.Payload = &payloadStr
+	}
+	if _, err := server.BackupService.PatchBackup(ctx, backupPatch); err != nil {
 		return true, nil, fmt.Errorf("failed to patch backup: %w", err)
 	}
 
@@ -83,25 +94,58 @@ func (exec *DatabaseBackupTaskExecutor) RunOnce(ctx context.Context, server *Ser
 	}, nil
 }
 
-// backupDatabase will take a backup of a database.
-func (exec *DatabaseBackupTaskExecutor) backupDatabase(ctx context.Context, instance *api.Instance, databaseName string, backup *api.Backup, dataDir string) error {
+// backupDatabase will take a backup of a database, and returns the size and the checksum of the stored backup.
+// The returned payload records the table row counts for the backup verification if it's supported by the engine,
+// and the binlog coordinate for the point-in-time recovery if the binlog archiving is enabled.
+func (exec *DatabaseBackupTaskExecutor) backupDatabase(ctx context.Context, server *Server, instance *api.Instance, databaseName string, backup *api.Backup) (int64, string, *api.BackupPayload, error) {
 	driver, err := getAdminDatabaseDriver(ctx, instance, databaseName, exec.l)
 	if err != nil {
-		return err
+		return 0, "", nil, err
 	}
 	defer driver.Close(ctx)
 
-	f, err := os.Create(filepath.Join(dataDir, backup.Path))
-	if err != nil {
-		return fmt.Errorf("failed to open backup path: %s", backup.Path)
+	var mysqlDriver *mysql.Driver
+	if isPointInTimeRecoverySupported(instance.Engine) {
+		policy, err := server.PolicyService.GetBackupPlanPolicy(ctx, instance.EnvironmentID)
+		if err != nil {
+			return 0, "", nil, fmt.Errorf("failed to get backup plan policy for environment ID %v: %w", instance.EnvironmentID, err)
+		}
+		if policy.ArchiveBinlog {
+			mysqlDriver, _ = driver.(*mysql.Driver)
+		}
 	}
-	defer f.Close()
 
-	if err := driver.Dump(ctx, databaseName, f, false /* schemaOnly */); err != nil {
+	// The table rows are counted in the same snapshot as the dump.
+	var binlogInfo *mysql.BinlogInfo
+	var tableRowCount map[string]int64
+	size, checksum, err := server.writeBackup(ctx, backup, func(w io.Writer) error {
+		var err error
+		switch {
+		case mysqlDriver != nil:
+			binlogInfo, tableRowCount, err = mysqlDriver.DumpWithBinlogInfo(ctx, databaseName, w)
+		case isBackupVerificationSupported(instance.Engine):
+			tableRowCount, err = driver.DumpWithTableRowCount(ctx, databaseName, w)
+		default:
+			err = driver.Dump(ctx, databaseName, w, false /* schemaOnly */)
+		}
 		return err
+	})
+	if err != nil {
+		return 0, "", nil, err
 	}
-
-	return nil
+	if tableRowCount == nil && binlogInfo == nil {
+		return size, checksum, nil, nil
+	}
+	payload := &api.BackupPayload{TableRowCount: tableRowCount}
+	if binlogInfo != nil {
+		payload.BinlogInfo = &api.BackupBinlogInfo{
+			FileName:   binlogInfo.FileName,
+			Position:   binlogInfo.Position,
+			Ts:         binlogInfo.Ts,
+			ServerUUID: binlogInfo.ServerUUID,
+		}
+	}
+	return size, checksum, payload, nil
 }
 
 // getAndCreateBackupDirectory returns the path of a database backup.
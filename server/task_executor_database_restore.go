@@ -5,8 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strconv"
 
 	"github.com/bytebase/bytebase/api"
@@ -88,13 +86,27 @@ func (exec *DatabaseRestoreTaskExecutor) RunOnce(ctx context.Context, server *Se
 	backup := backupRaw.ToBackup()
 
 	// Restore the database to the target database.
-	if err := exec.restoreDatabase(ctx, targetDatabase.Instance, targetDatabase.Name, backup, server.dataDir); err != nil {
+	if err := exec.restoreDatabase(ctx, server, targetDatabase.Instance, targetDatabase.Name, backup); err != nil {
 		return true, nil, err
 	}
 
+	// Replay the binlog on top of the backup for the point-in-time recovery.
+	if payload.PointInTimeTs != 0 {
+		binlogInfo, err := getBackupBinlogInfo(backup.Payload)
+		if err != nil {
+			return true, nil, err
+		}
+		if binlogInfo == nil {
+			return true, nil, fmt.Errorf("backup %q doesn't have the binlog coordinate for the point-in-time recovery", backup.Name)
+		}
+		if err := server.replayBinlog(ctx, sourceDatabase, targetDatabase, backup, binlogInfo, payload.PointInTimeTs); err != nil {
+			return true, nil, err
+		}
+	}
+
 	// TODO(tianzhou): This should be done in the same transaction as restoreDatabase to guarantee consistency.
 	// For now, we do this after restoreDatabase, since this one is unlikely to fail.
-	migrationID, version, err := createBranchMigrationHistory(ctx, server, sourceDatabase, targetDatabase, backup, task, exec.l)
+	migrationID, version, err := createBranchMigrationHistory(ctx, server, sourceDatabase, targetDatabase, backup, payload.PointInTimeTs, task, exec.l)
 	if err != nil {
 		return true, nil, err
 	}
@@ -113,34 +125,37 @@ func (exec *DatabaseRestoreTaskExecutor) RunOnce(ctx context.Context, server *Se
 	}
 
 	// Sync database schema after restore is completed.
-	server.syncEngineVersionAndSchema(ctx, targetDatabase.Instance)
+	if err := server.syncDatabaseSchema(ctx, targetDatabase.Instance, targetDatabase.Name, ""); err != nil {
+		exec.l.Warn("Failed to sync database schema after restore",
+			zap.String("database_name", targetDatabase.Name),
+			zap.Error(err))
+	}
 
+	detail := fmt.Sprintf("Restored database %q from backup %q", targetDatabase.Name, backupRaw.Name)
+	if payload.PointInTimeTs != 0 {
+		detail = fmt.Sprintf("Restored database %q to %s from backup %q", targetDatabase.Name, formatPointInTime(payload.PointInTimeTs), backupRaw.Name)
+	}
 	return true, &api.TaskRunResultPayload{
-		Detail:      fmt.Sprintf("Restored database %q from backup %q", targetDatabase.Name, backupRaw.Name),
+		Detail:      detail,
 		MigrationID: migrationID,
 		Version:     version,
 	}, nil
 }
 
 // restoreDatabase will restore the database from a backup
-func (exec *DatabaseRestoreTaskExecutor) restoreDatabase(ctx context.Context, instance *api.Instance, databaseName string, backup *api.Backup, dataDir string) error {
+func (exec *DatabaseRestoreTaskExecutor) restoreDatabase(ctx context.Context, server *Server, instance *api.Instance, databaseName string, backup *api.Backup) error {
 	driver, err := getAdminDatabaseDriver(ctx, instance, databaseName, exec.l)
 	if err != nil {
 		return err
 	}
 	defer driver.Close(ctx)
 
-	backupPath := backup.Path
-	if !filepath.IsAbs(backupPath) {
-		backupPath = filepath.Join(dataDir, backupPath)
-	}
-
-	f, err := os.OpenFile(backupPath, os.O_RDONLY, os.ModePerm)
+	r, err := server.openBackup(ctx, backup)
 	if err != nil {
-		return fmt.Errorf("failed to open backup file at %s: %w", backupPath, err)
+		return err
 	}
-	defer f.Close()
-	sc := bufio.NewScanner(f)
+	defer r.Close()
+	sc := bufio.NewScanner(r)
 
 	if err := driver.Restore(ctx, sc); err != nil {
 		return fmt.Errorf("failed to restore backup: %w", err)
@@ -151,8 +166,9 @@ func (exec *DatabaseRestoreTaskExecutor) restoreDatabase(ctx context.Context, in
 // createBranchMigrationHistory creates a migration history with "BRANCH" type. We choose NOT to copy over
 // all migrationhistory from source database because that might be expensive (e.g. we may use restore to
 // create many ephemeral databases from backup for testing purpose)
+// pointInTimeTs is the point in time the source database is recovered to, 0 if only the backup is restored.
 // Returns migration history id and the version on success
-func createBranchMigrationHistory(ctx context.Context, server *Server, sourceDatabase, targetDatabase *api.Database, backup *api.Backup, task *api.Task, logger *zap.Logger) (int64, string, error) {
+func createBranchMigrationHistory(ctx context.Context, server *Server, sourceDatabase, targetDatabase *api.Database, backup *api.Backup, pointInTimeTs int64, task *api.Task, logger *zap.Logger) (int64, string, error) {
 	targetDriver, err := getAdminDatabaseDriver(ctx, targetDatabase.Instance, targetDatabase.Name, logger)
 	if err != nil {
 		return -1, "", err
@@ -176,6 +192,12 @@ func createBranchMigrationHistory(ctx context.Context, server *Server, sourceDat
 	if sourceDatabase.InstanceID != targetDatabase.InstanceID {
 		description = fmt.Sprintf("Restored from backup %q of database %q in instance %q.", backup.Name, sourceDatabase.Name, sourceDatabase.Instance.Name)
 	}
+	if pointInTimeTs != 0 {
+		description = fmt.Sprintf("Recovered database %q to %s from backup %q.", sourceDatabase.Name, formatPointInTime(pointInTimeTs), backup.Name)
+		if sourceDatabase.InstanceID != targetDatabase.InstanceID {
+			description = fmt.Sprintf("Recovered database %q in instance %q to %s from backup %q.", sourceDatabase.Name, sourceDatabase.Instance.Name, formatPointInTime(pointInTimeTs), backup.Name)
+		}
+	}
 	m := &db.MigrationInfo{
 		ReleaseVersion: server.version,
 		Version:        defaultMigrationVersionFromTaskID(),
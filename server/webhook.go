@@ -325,21 +325,26 @@ func (s *Server) createTenantSchemaUpdateIssue(ctx context.Context, repository *
 }
 
 func isSkipGeneratedSchemaFile(repository *api.Repository, added string, logger *zap.Logger) bool {
-	if repository.SchemaPathTemplate != "" {
+	// Both the latest schema file and the data dictionary are generated by Bytebase after migration.
+	for _, template := range []string{repository.SchemaPathTemplate, repository.DictionaryPathTemplate} {
+		if template == "" {
+			continue
+		}
 		placeholderList := []string{
 			"ENV_NAME",
 			"DB_NAME",
 		}
-		schemafilePathRegex := repository.SchemaPathTemplate
+		schemafilePathRegex := template
 		for _, placeholder := range placeholderList {
 			schemafilePathRegex = strings.ReplaceAll(schemafilePathRegex, fmt.Sprintf("{{%s}}", placeholder), fmt.Sprintf("(?P<%s>[a-zA-Z0-9+-=/_#?!$. ]+)", placeholder))
 		}
 		myRegex, err := regexp.Compile(schemafilePathRegex)
 		if err != nil {
 			logger.Warn("Invalid schema path template.", zap.String("schema_path_template",
-				repository.SchemaPathTemplate),
+				template),
 				zap.Error(err),
 			)
+			continue
 		}
 		if myRegex.MatchString(added) {
 			return true
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
@@ -92,8 +93,29 @@ func (s *AnomalyService) FindAnomalyList(ctx context.Context, find *api.AnomalyF
 	return list, nil
 }
 
-// ArchiveAnomaly archives an existing anomaly by ID.
-// Returns ENOTFOUND if anomaly does not exist.
+// PatchAnomalyStatus changes the status of an unresolved anomaly, and archives the anomaly once RESOLVED.
+// Returns ENOTFOUND if the unresolved anomaly does not exist.
+func (s *AnomalyService) PatchAnomalyStatus(ctx context.Context, patch *api.AnomalyStatusPatch) (*api.AnomalyRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	anomaly, err := patchAnomalyStatus(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return anomaly, nil
+}
+
+// ArchiveAnomaly archives an existing anomaly as RESOLVED.
+// Returns ENOTFOUND if the unresolved anomaly does not exist.
 func (s *AnomalyService) ArchiveAnomaly(ctx context.Context, archive *api.AnomalyArchive) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -128,7 +150,7 @@ func createAnomaly(ctx context.Context, tx *sql.Tx, upsert *api.AnomalyUpsert) (
 			payload
 		)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, type, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, type, payload, status, acknowledger_id, acknowledged_ts, comment, snooze_until_ts, resolved_ts
 	`,
 		upsert.CreatorID,
 		upsert.CreatorID,
@@ -146,6 +168,7 @@ func createAnomaly(ctx context.Context, tx *sql.Tx, upsert *api.AnomalyUpsert) (
 	row.Next()
 	var anomalyRaw api.AnomalyRaw
 	databaseID := sql.NullInt32{}
+	acknowledgerID := sql.NullInt32{}
 	if err := row.Scan(
 		&anomalyRaw.ID,
 		&anomalyRaw.CreatorID,
@@ -156,6 +179,12 @@ func createAnomaly(ctx context.Context, tx *sql.Tx, upsert *api.AnomalyUpsert) (
 		&databaseID,
 		&anomalyRaw.Type,
 		&anomalyRaw.Payload,
+		&anomalyRaw.Status,
+		&acknowledgerID,
+		&anomalyRaw.AcknowledgedTs,
+		&anomalyRaw.Comment,
+		&anomalyRaw.SnoozeUntilTs,
+		&anomalyRaw.ResolvedTs,
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -163,7 +192,12 @@ func createAnomaly(ctx context.Context, tx *sql.Tx, upsert *api.AnomalyUpsert) (
 		value := int(databaseID.Int32)
 		anomalyRaw.DatabaseID = &value
 	}
+	if acknowledgerID.Valid {
+		value := int(acknowledgerID.Int32)
+		anomalyRaw.AcknowledgerID = &value
+	}
 	anomalyRaw.Severity = api.AnomalySeverityFromType(anomalyRaw.Type)
+	anomalyRaw.Status = getAnomalyStatus(anomalyRaw.Status, anomalyRaw.SnoozeUntilTs, time.Now())
 
 	return &anomalyRaw, err
 }
@@ -171,6 +205,9 @@ func createAnomaly(ctx context.Context, tx *sql.Tx, upsert *api.AnomalyUpsert) (
 func findAnomalyList(ctx context.Context, tx *sql.Tx, find *api.AnomalyFind) ([]*api.AnomalyRaw, error) {
 	// Build WHERE clause.
 	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.InstanceID; v != nil {
 		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
 		if find.InstanceOnly {
@@ -188,8 +225,12 @@ func findAnomalyList(ctx context.Context, tx *sql.Tx, find *api.AnomalyFind) ([]
 	if v := find.Type; v != nil {
 		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
 	}
+	now := time.Now()
+	if find.ExcludeSnoozed {
+		where, args = append(where, fmt.Sprintf("snooze_until_ts <= $%d", len(args)+1)), append(args, now.Unix())
+	}
 
-	rows, err := tx.QueryContext(ctx, `
+	var query = `
 		SELECT
 			id,
 			creator_id,
@@ -199,10 +240,20 @@ func findAnomalyList(ctx context.Context, tx *sql.Tx, find *api.AnomalyFind) ([]
 			instance_id,
 			database_id,
 			type,
-			payload
+			payload,
+			status,
+			acknowledger_id,
+			acknowledged_ts,
+			comment,
+			snooze_until_ts,
+			resolved_ts
 		FROM anomaly
-		WHERE `+strings.Join(where, " AND ")+`
-		`,
+		WHERE ` + strings.Join(where, " AND ")
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" ORDER BY id DESC LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
 		args...,
 	)
 	if err != nil {
@@ -215,6 +266,7 @@ func findAnomalyList(ctx context.Context, tx *sql.Tx, find *api.AnomalyFind) ([]
 	for rows.Next() {
 		var anomalyRaw api.AnomalyRaw
 		databaseID := sql.NullInt32{}
+		acknowledgerID := sql.NullInt32{}
 		if err := rows.Scan(
 			&anomalyRaw.ID,
 			&anomalyRaw.CreatorID,
@@ -225,6 +277,12 @@ func findAnomalyList(ctx context.Context, tx *sql.Tx, find *api.AnomalyFind) ([]
 			&databaseID,
 			&anomalyRaw.Type,
 			&anomalyRaw.Payload,
+			&anomalyRaw.Status,
+			&acknowledgerID,
+			&anomalyRaw.AcknowledgedTs,
+			&anomalyRaw.Comment,
+			&anomalyRaw.SnoozeUntilTs,
+			&anomalyRaw.ResolvedTs,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -232,7 +290,12 @@ func findAnomalyList(ctx context.Context, tx *sql.Tx, find *api.AnomalyFind) ([]
 			value := int(databaseID.Int32)
 			anomalyRaw.DatabaseID = &value
 		}
+		if acknowledgerID.Valid {
+			value := int(acknowledgerID.Int32)
+			anomalyRaw.AcknowledgerID = &value
+		}
 		anomalyRaw.Severity = api.AnomalySeverityFromType(anomalyRaw.Type)
+		anomalyRaw.Status = getAnomalyStatus(anomalyRaw.Status, anomalyRaw.SnoozeUntilTs, now)
 
 		anomalyRawList = append(anomalyRawList, &anomalyRaw)
 	}
@@ -268,7 +331,7 @@ func patchAnomaly(ctx context.Context, tx *sql.Tx, patch *anomalyPatch) (*api.An
 		UPDATE anomaly
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $3
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, type, payload
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, type, payload, status, acknowledger_id, acknowledged_ts, comment, snooze_until_ts, resolved_ts
 	`,
 		args...,
 	)
@@ -285,6 +348,7 @@ func patchAnomaly(ctx context.Context, tx *sql.Tx, patch *anomalyPatch) (*api.An
 	row.Next()
 	var anomalyRaw api.AnomalyRaw
 	databaseID := sql.NullInt32{}
+	acknowledgerID := sql.NullInt32{}
 	if err := row.Scan(
 		&anomalyRaw.ID,
 		&anomalyRaw.CreatorID,
@@ -295,6 +359,12 @@ func patchAnomaly(ctx context.Context, tx *sql.Tx, patch *anomalyPatch) (*api.An
 		&databaseID,
 		&anomalyRaw.Type,
 		&anomalyRaw.Payload,
+		&anomalyRaw.Status,
+		&acknowledgerID,
+		&anomalyRaw.AcknowledgedTs,
+		&anomalyRaw.Comment,
+		&anomalyRaw.SnoozeUntilTs,
+		&anomalyRaw.ResolvedTs,
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -302,12 +372,106 @@ func patchAnomaly(ctx context.Context, tx *sql.Tx, patch *anomalyPatch) (*api.An
 		value := int(databaseID.Int32)
 		anomalyRaw.DatabaseID = &value
 	}
+	if acknowledgerID.Valid {
+		value := int(acknowledgerID.Int32)
+		anomalyRaw.AcknowledgerID = &value
+	}
 	anomalyRaw.Severity = api.AnomalySeverityFromType(anomalyRaw.Type)
+	anomalyRaw.Status = getAnomalyStatus(anomalyRaw.Status, anomalyRaw.SnoozeUntilTs, time.Now())
 
 	return &anomalyRaw, err
 }
 
-// archiveAnomaly archives an anomaly by ID.
+// patchAnomalyStatus changes the status of an unresolved anomaly by ID.
+func patchAnomalyStatus(ctx context.Context, tx *sql.Tx, patch *api.AnomalyStatusPatch) (*api.AnomalyRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	switch patch.Status {
+	case api.AnomalyOpen:
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, api.AnomalyOpen)
+		set = append(set, "acknowledger_id = NULL", "acknowledged_ts = 0", "snooze_until_ts = 0")
+	case api.AnomalyAcknowledged:
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, api.AnomalyAcknowledged)
+		set, args = append(set, fmt.Sprintf("acknowledger_id = $%d", len(args)+1)), append(args, patch.UpdaterID)
+		set = append(set, "acknowledged_ts = extract(epoch from now())", "snooze_until_ts = 0")
+	case api.AnomalySnoozed:
+		set, args = append(set, fmt.Sprintf("snooze_until_ts = $%d", len(args)+1)), append(args, patch.SnoozeUntilTs)
+	case api.AnomalyResolved:
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, api.AnomalyResolved)
+		set, args = append(set, fmt.Sprintf("row_status = $%d", len(args)+1)), append(args, api.Archived)
+		set = append(set, "resolved_ts = extract(epoch from now())")
+	default:
+		return nil, &common.Error{Code: common.Invalid, Err: fmt.Errorf("invalid anomaly status %q", patch.Status)}
+	}
+	if v := patch.Comment; v != nil {
+		set, args = append(set, fmt.Sprintf("comment = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID, api.Normal)
+
+	// Execute update query with RETURNING.
+	row, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		UPDATE anomaly
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d AND row_status = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, type, payload, status, acknowledger_id, acknowledged_ts, comment, snooze_until_ts, resolved_ts
+	`, len(args)-1, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	if row.Next() {
+		var anomalyRaw api.AnomalyRaw
+		databaseID := sql.NullInt32{}
+		acknowledgerID := sql.NullInt32{}
+		if err := row.Scan(
+			&anomalyRaw.ID,
+			&anomalyRaw.CreatorID,
+			&anomalyRaw.CreatedTs,
+			&anomalyRaw.UpdaterID,
+			&anomalyRaw.UpdatedTs,
+			&anomalyRaw.InstanceID,
+			&databaseID,
+			&anomalyRaw.Type,
+			&anomalyRaw.Payload,
+			&anomalyRaw.Status,
+			&acknowledgerID,
+			&anomalyRaw.AcknowledgedTs,
+			&anomalyRaw.Comment,
+			&anomalyRaw.SnoozeUntilTs,
+			&anomalyRaw.ResolvedTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if databaseID.Valid {
+			value := int(databaseID.Int32)
+			anomalyRaw.DatabaseID = &value
+		}
+		if acknowledgerID.Valid {
+			value := int(acknowledgerID.Int32)
+			anomalyRaw.AcknowledgerID = &value
+		}
+		anomalyRaw.Severity = api.AnomalySeverityFromType(anomalyRaw.Type)
+		anomalyRaw.Status = getAnomalyStatus(anomalyRaw.Status, anomalyRaw.SnoozeUntilTs, time.Now())
+
+		return &anomalyRaw, nil
+	}
+
+	return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("unresolved anomaly ID not found: %d", patch.ID)}
+}
+
+// getAnomalyStatus returns SNOOZED for the unresolved anomaly snoozed at the moment, otherwise the stored status.
+func getAnomalyStatus(status api.AnomalyStatus, snoozeUntilTs int64, now time.Time) api.AnomalyStatus {
+	if status != api.AnomalyResolved && snoozeUntilTs > now.Unix() {
+		return api.AnomalySnoozed
+	}
+	return status
+}
+
+// archiveAnomaly archives an unresolved anomaly as RESOLVED.
 func archiveAnomaly(ctx context.Context, tx *sql.Tx, archive *api.AnomalyArchive) error {
 	if archive.InstanceID == nil && archive.DatabaseID == nil {
 		return &common.Error{Code: common.Internal, Err: fmt.Errorf("failed to close anomaly, should specify either instanceID or databaseID")}
@@ -318,10 +482,12 @@ func archiveAnomaly(ctx context.Context, tx *sql.Tx, archive *api.AnomalyArchive
 	// Remove row from database.
 	if archive.InstanceID != nil {
 		result, err := tx.ExecContext(ctx,
-			`UPDATE anomaly SET row_status = $1 WHERE instance_id = $2 AND database_id IS NULL AND type = $3`,
+			`UPDATE anomaly SET row_status = $1, status = $2, resolved_ts = extract(epoch from now()) WHERE instance_id = $3 AND database_id IS NULL AND type = $4 AND row_status = $5`,
 			api.Archived,
+			api.AnomalyResolved,
 			*archive.InstanceID,
 			archive.Type,
+			api.Normal,
 		)
 		if err != nil {
 			return FormatError(err)
@@ -333,10 +499,12 @@ func archiveAnomaly(ctx context.Context, tx *sql.Tx, archive *api.AnomalyArchive
 		}
 	} else if archive.DatabaseID != nil {
 		result, err := tx.ExecContext(ctx,
-			`UPDATE anomaly SET row_status = $1 WHERE database_id = $2 AND type = $3`,
+			`UPDATE anomaly SET row_status = $1, status = $2, resolved_ts = extract(epoch from now()) WHERE database_id = $3 AND type = $4 AND row_status = $5`,
 			api.Archived,
+			api.AnomalyResolved,
 			*archive.DatabaseID,
 			archive.Type,
+			api.Normal,
 		)
 		if err != nil {
 			return FormatError(err)
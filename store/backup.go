@@ -85,6 +85,44 @@ func (s *BackupService) FindBackupList(ctx context.Context, find *api.BackupFind
 	return backupRawList, nil
 }
 
+// FindEncryptionKeyFingerprintList returns the distinct key fingerprints of the encrypted backups neither failed nor expired.
+func (s *BackupService) FindEncryptionKeyFingerprintList(ctx context.Context) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	return findEncryptionKeyFingerprintList(ctx, tx.PTx, `
+		SELECT DISTINCT encryption_key_fingerprint
+		FROM backup
+		WHERE encryption <> 'NONE' AND status NOT IN ('FAILED', 'EXPIRED')`,
+	)
+}
+
+// PatchEncryptionKeyFingerprint records the key fingerprint of the encrypted backups encrypted before the fingerprint is recorded.
+func (s *BackupService) PatchEncryptionKeyFingerprint(ctx context.Context, fingerprint string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `
+		UPDATE backup
+		SET encryption_key_fingerprint = $1
+		WHERE encryption <> 'NONE' AND encryption_key_fingerprint = ''`,
+		fingerprint,
+	); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
 // PatchBackup updates an existing backup by ID.
 // Returns ENOTFOUND if backup does not exist.
 func (s *BackupService) PatchBackup(ctx context.Context, patch *api.BackupPatch) (*api.BackupRaw, error) {
@@ -119,10 +157,13 @@ func (s *BackupService) createBackup(ctx context.Context, tx *sql.Tx, create *ap
 			type,
 			storage_backend,
 			migration_history_version,
-			path
+			path,
+			compression,
+			encryption,
+			encryption_key_fingerprint
 		)
-		VALUES ($1, $2, $3, $4, 'PENDING_CREATE', $5, $6, $7, $8)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, status, type, storage_backend, migration_history_version, path, comment
+		VALUES ($1, $2, $3, $4, 'PENDING_CREATE', $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, status, type, storage_backend, migration_history_version, path, comment, compression, encryption, encryption_key_fingerprint, size, checksum, payload, verification_status, verified_ts, verification_detail
 	`,
 		create.CreatorID,
 		create.CreatorID,
@@ -132,6 +173,9 @@ func (s *BackupService) createBackup(ctx context.Context, tx *sql.Tx, create *ap
 		create.StorageBackend,
 		create.MigrationHistoryVersion,
 		create.Path,
+		create.Compression,
+		create.Encryption,
+		create.EncryptionKeyFingerprint,
 	)
 
 	if err != nil {
@@ -155,6 +199,15 @@ func (s *BackupService) createBackup(ctx context.Context, tx *sql.Tx, create *ap
 		&backupRaw.MigrationHistoryVersion,
 		&backupRaw.Path,
 		&backupRaw.Comment,
+		&backupRaw.Compression,
+		&backupRaw.Encryption,
+		&backupRaw.EncryptionKeyFingerprint,
+		&backupRaw.Size,
+		&backupRaw.Checksum,
+		&backupRaw.Payload,
+		&backupRaw.VerificationStatus,
+		&backupRaw.VerifiedTs,
+		&backupRaw.VerificationDetail,
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -177,6 +230,9 @@ func (s *BackupService) findBackupList(ctx context.Context, tx *sql.Tx, find *ap
 	if v := find.Status; v != nil {
 		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Type; v != nil {
+		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
+	}
 
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
@@ -192,7 +248,16 @@ func (s *BackupService) findBackupList(ctx context.Context, tx *sql.Tx, find *ap
 			storage_backend,
 			migration_history_version,
 			path,
-			comment
+			comment,
+			compression,
+			encryption,
+			encryption_key_fingerprint,
+			size,
+			checksum,
+			payload,
+			verification_status,
+			verified_ts,
+			verification_detail
 		FROM backup
 		WHERE `+strings.Join(where, " AND ")+` ORDER BY updated_ts DESC`,
 		args...,
@@ -220,6 +285,15 @@ func (s *BackupService) findBackupList(ctx context.Context, tx *sql.Tx, find *ap
 			&backupRaw.MigrationHistoryVersion,
 			&backupRaw.Path,
 			&backupRaw.Comment,
+			&backupRaw.Compression,
+			&backupRaw.Encryption,
+			&backupRaw.EncryptionKeyFingerprint,
+			&backupRaw.Size,
+			&backupRaw.Checksum,
+			&backupRaw.Payload,
+			&backupRaw.VerificationStatus,
+			&backupRaw.VerifiedTs,
+			&backupRaw.VerificationDetail,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -239,16 +313,34 @@ func (s *BackupService) patchBackup(ctx context.Context, tx *sql.Tx, patch *api.
 	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
 	set, args = append(set, "status = $2"), append(args, patch.Status)
 	set, args = append(set, "comment = $3"), append(args, patch.Comment)
+	if v := patch.Size; v != nil {
+		set, args = append(set, fmt.Sprintf("size = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Checksum; v != nil {
+		set, args = append(set, fmt.Sprintf("checksum = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Payload; v != nil {
+		set, args = append(set, fmt.Sprintf("payload = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.VerificationStatus; v != nil {
+		set, args = append(set, fmt.Sprintf("verification_status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.VerifiedTs; v != nil {
+		set, args = append(set, fmt.Sprintf("verified_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.VerificationDetail; v != nil {
+		set, args = append(set, fmt.Sprintf("verification_detail = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
 	// Execute update query with RETURNING.
-	row, err := tx.QueryContext(ctx, `
+	row, err := tx.QueryContext(ctx, fmt.Sprintf(`
 		UPDATE backup
 		SET `+strings.Join(set, ", ")+`
-		WHERE id = $4
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, status, type, storage_backend, migration_history_version, path, comment
-	`,
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, status, type, storage_backend, migration_history_version, path, comment, compression, encryption, encryption_key_fingerprint, size, checksum, payload, verification_status, verified_ts, verification_detail
+	`, len(args)),
 		args...,
 	)
 	if err != nil {
@@ -272,6 +364,15 @@ func (s *BackupService) patchBackup(ctx context.Context, tx *sql.Tx, patch *api.
 			&backupRaw.MigrationHistoryVersion,
 			&backupRaw.Path,
 			&backupRaw.Comment,
+			&backupRaw.Compression,
+			&backupRaw.Encryption,
+			&backupRaw.EncryptionKeyFingerprint,
+			&backupRaw.Size,
+			&backupRaw.Checksum,
+			&backupRaw.Payload,
+			&backupRaw.VerificationStatus,
+			&backupRaw.VerifiedTs,
+			&backupRaw.VerificationDetail,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -523,3 +624,25 @@ func (s *BackupService) FindBackupSettingsMatch(ctx context.Context, match *api.
 
 	return backupSettingRawList, nil
 }
+
+// findEncryptionKeyFingerprintList returns the key fingerprints selected by the query.
+func findEncryptionKeyFingerprintList(ctx context.Context, tx *sql.Tx, query string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var fingerprintList []string
+	for rows.Next() {
+		var fingerprint string
+		if err := rows.Scan(&fingerprint); err != nil {
+			return nil, FormatError(err)
+		}
+		fingerprintList = append(fingerprintList, fingerprint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return fingerprintList, nil
+}
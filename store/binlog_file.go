@@ -0,0 +1,285 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.BinlogFileService = (*BinlogFileService)(nil)
+)
+
+// BinlogFileService represents a service for managing binlog file.
+type BinlogFileService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewBinlogFileService returns a new instance of BinlogFileService.
+func NewBinlogFileService(logger *zap.Logger, db *DB) *BinlogFileService {
+	return &BinlogFileService{l: logger, db: db}
+}
+
+// CreateBinlogFile creates a new binlog file.
+func (s *BinlogFileService) CreateBinlogFile(ctx context.Context, create *api.BinlogFileCreate) (*api.BinlogFile, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	binlogFile, err := createBinlogFile(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return binlogFile, nil
+}
+
+// FindBinlogFileList retrieves a list of binlog files based on find.
+func (s *BinlogFileService) FindBinlogFileList(ctx context.Context, find *api.BinlogFileFind) ([]*api.BinlogFile, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findBinlogFileList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// FindEncryptionKeyFingerprintList returns the distinct key fingerprints of the encrypted binlog files.
+func (s *BinlogFileService) FindEncryptionKeyFingerprintList(ctx context.Context) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	return findEncryptionKeyFingerprintList(ctx, tx.PTx, `
+		SELECT DISTINCT encryption_key_fingerprint
+		FROM binlog_file
+		WHERE encryption <> 'NONE'`,
+	)
+}
+
+// PatchEncryptionKeyFingerprint records the key fingerprint of the encrypted binlog files encrypted before the fingerprint is recorded.
+func (s *BinlogFileService) PatchEncryptionKeyFingerprint(ctx context.Context, fingerprint string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if _, err := tx.PTx.ExecContext(ctx, `
+		UPDATE binlog_file
+		SET encryption_key_fingerprint = $1
+		WHERE encryption <> 'NONE' AND encryption_key_fingerprint = ''`,
+		fingerprint,
+	); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
+
+// DeleteBinlogFile deletes an existing binlog file by ID.
+// Returns ENOTFOUND if binlog file does not exist.
+func (s *BinlogFileService) DeleteBinlogFile(ctx context.Context, delete *api.BinlogFileDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteBinlogFile(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// createBinlogFile creates a new binlog file.
+func createBinlogFile(ctx context.Context, tx *sql.Tx, create *api.BinlogFileCreate) (*api.BinlogFile, error) {
+	// Insert row into database.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO binlog_file (
+			creator_id,
+			updater_id,
+			instance_id,
+			name,
+			seq,
+			server_uuid,
+			file_size,
+			storage_backend,
+			path,
+			compression,
+			encryption,
+			encryption_key_fingerprint,
+			size,
+			checksum
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, name, seq, server_uuid, file_size, storage_backend, path, compression, encryption, encryption_key_fingerprint, size, checksum
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.InstanceID,
+		create.Name,
+		create.Seq,
+		create.ServerUUID,
+		create.FileSize,
+		create.StorageBackend,
+		create.Path,
+		create.Compression,
+		create.Encryption,
+		create.EncryptionKeyFingerprint,
+		create.Size,
+		create.Checksum,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var binlogFile api.BinlogFile
+	if err := row.Scan(
+		&binlogFile.ID,
+		&binlogFile.CreatorID,
+		&binlogFile.CreatedTs,
+		&binlogFile.UpdaterID,
+		&binlogFile.UpdatedTs,
+		&binlogFile.InstanceID,
+		&binlogFile.Name,
+		&binlogFile.Seq,
+		&binlogFile.ServerUUID,
+		&binlogFile.FileSize,
+		&binlogFile.StorageBackend,
+		&binlogFile.Path,
+		&binlogFile.Compression,
+		&binlogFile.Encryption,
+		&binlogFile.EncryptionKeyFingerprint,
+		&binlogFile.Size,
+		&binlogFile.Checksum,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &binlogFile, nil
+}
+
+func findBinlogFileList(ctx context.Context, tx *sql.Tx, find *api.BinlogFileFind) ([]*api.BinlogFile, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			instance_id,
+			name,
+			seq,
+			server_uuid,
+			file_size,
+			storage_backend,
+			path,
+			compression,
+			encryption,
+			encryption_key_fingerprint,
+			size,
+			checksum
+		FROM binlog_file
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY instance_id, seq, id`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into binlogFileList.
+	var binlogFileList []*api.BinlogFile
+	for rows.Next() {
+		var binlogFile api.BinlogFile
+		if err := rows.Scan(
+			&binlogFile.ID,
+			&binlogFile.CreatorID,
+			&binlogFile.CreatedTs,
+			&binlogFile.UpdaterID,
+			&binlogFile.UpdatedTs,
+			&binlogFile.InstanceID,
+			&binlogFile.Name,
+			&binlogFile.Seq,
+			&binlogFile.ServerUUID,
+			&binlogFile.FileSize,
+			&binlogFile.StorageBackend,
+			&binlogFile.Path,
+			&binlogFile.Compression,
+			&binlogFile.Encryption,
+			&binlogFile.EncryptionKeyFingerprint,
+			&binlogFile.Size,
+			&binlogFile.Checksum,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		binlogFileList = append(binlogFileList, &binlogFile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return binlogFileList, nil
+}
+
+// deleteBinlogFile permanently deletes a binlog file by ID.
+func deleteBinlogFile(ctx context.Context, tx *sql.Tx, delete *api.BinlogFileDelete) error {
+	// Remove row from database.
+	result, err := tx.ExecContext(ctx, `DELETE FROM binlog_file WHERE id = $1`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: fmt.Errorf("binlog file ID not found: %d", delete.ID)}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.CheckConstraintService = (*CheckConstraintService)(nil)
+)
+
+// CheckConstraintService represents a service for managing check constraint.
+type CheckConstraintService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewCheckConstraintService returns a new instance of CheckConstraintService.
+func NewCheckConstraintService(logger *zap.Logger, db *DB) *CheckConstraintService {
+	return &CheckConstraintService{l: logger, db: db}
+}
+
+// CreateCheckConstraint creates a new check constraint.
+func (s *CheckConstraintService) CreateCheckConstraint(ctx context.Context, create *api.CheckConstraintCreate) (*api.CheckConstraint, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	checkConstraint, err := createCheckConstraint(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return checkConstraint, nil
+}
+
+// FindCheckConstraintList retrieves a list of check constraints based on find.
+func (s *CheckConstraintService) FindCheckConstraintList(ctx context.Context, find *api.CheckConstraintFind) ([]*api.CheckConstraint, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findCheckConstraintList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// createCheckConstraint creates a new check constraint.
+func createCheckConstraint(ctx context.Context, tx *sql.Tx, create *api.CheckConstraintCreate) (*api.CheckConstraint, error) {
+	// Insert row into chk.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO chk (
+			creator_id,
+			updater_id,
+			database_id,
+			table_id,
+			name,
+			expression
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, expression
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.TableID,
+		create.Name,
+		create.Expression,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var checkConstraint api.CheckConstraint
+	if err := row.Scan(
+		&checkConstraint.ID,
+		&checkConstraint.CreatorID,
+		&checkConstraint.CreatedTs,
+		&checkConstraint.UpdaterID,
+		&checkConstraint.UpdatedTs,
+		&checkConstraint.DatabaseID,
+		&checkConstraint.TableID,
+		&checkConstraint.Name,
+		&checkConstraint.Expression,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &checkConstraint, nil
+}
+
+func findCheckConstraintList(ctx context.Context, tx *sql.Tx, find *api.CheckConstraintFind) ([]*api.CheckConstraint, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TableID; v != nil {
+		where, args = append(where, fmt.Sprintf("table_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			table_id,
+			name,
+			expression
+		FROM chk
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, table_id, name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into checkConstraintList.
+	var checkConstraintList []*api.CheckConstraint
+	for rows.Next() {
+		var checkConstraint api.CheckConstraint
+		if err := rows.Scan(
+			&checkConstraint.ID,
+			&checkConstraint.CreatorID,
+			&checkConstraint.CreatedTs,
+			&checkConstraint.UpdaterID,
+			&checkConstraint.UpdatedTs,
+			&checkConstraint.DatabaseID,
+			&checkConstraint.TableID,
+			&checkConstraint.Name,
+			&checkConstraint.Expression,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		checkConstraintList = append(checkConstraintList, &checkConstraint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return checkConstraintList, nil
+}
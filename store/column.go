@@ -8,6 +8,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -191,14 +192,20 @@ func (s *ColumnService) findColumnList(ctx context.Context, tx *sql.Tx, find *ap
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.DatabaseIDList; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = ANY($%d)", len(args)+1)), append(args, pq.Array(*v))
+	}
 	if v := find.TableID; v != nil {
 		where, args = append(where, fmt.Sprintf("table_id = $%d", len(args)+1)), append(args, *v)
 	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Pattern; v != nil {
+		where, args = append(where, fmt.Sprintf("(name ~* $%d OR type ~* $%d OR comment ~* $%d)", len(args)+1, len(args)+1, len(args)+1)), append(args, *v)
+	}
 
-	rows, err := tx.QueryContext(ctx, `
+	query := `
 		SELECT
 			id,
 			creator_id,
@@ -216,8 +223,13 @@ func (s *ColumnService) findColumnList(ctx context.Context, tx *sql.Tx, find *ap
 			"collation",
 			comment
 		FROM col
-		WHERE `+strings.Join(where, " AND ")+`
-		ORDER BY database_id, table_id, position ASC`,
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY database_id, table_id, position ASC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
 		args...,
 	)
 	if err != nil {
@@ -213,7 +213,10 @@ func (s *DatabaseService) createDatabase(ctx context.Context, tx *sql.Tx, create
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			schema_version,
+			sync_error,
+			sync_duration_ms,
+			schema_fingerprint
 	`,
 		create.CreatorID,
 		create.CreatorID,
@@ -245,6 +248,9 @@ func (s *DatabaseService) createDatabase(ctx context.Context, tx *sql.Tx, create
 		&databaseRaw.SyncStatus,
 		&databaseRaw.LastSuccessfulSyncTs,
 		&databaseRaw.SchemaVersion,
+		&databaseRaw.SyncError,
+		&databaseRaw.SyncDurationMs,
+		&databaseRaw.SchemaFingerprint,
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -286,7 +292,10 @@ func (s *DatabaseService) findDatabaseList(ctx context.Context, tx *sql.Tx, find
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			schema_version,
+			sync_error,
+			sync_duration_ms,
+			schema_fingerprint
 		FROM db
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -316,6 +325,9 @@ func (s *DatabaseService) findDatabaseList(ctx context.Context, tx *sql.Tx, find
 			&databaseRaw.SyncStatus,
 			&databaseRaw.LastSuccessfulSyncTs,
 			&databaseRaw.SchemaVersion,
+			&databaseRaw.SyncError,
+			&databaseRaw.SyncDurationMs,
+			&databaseRaw.SchemaFingerprint,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -351,6 +363,15 @@ func (s *DatabaseService) patchDatabase(ctx context.Context, tx *sql.Tx, patch *
 	if v := patch.LastSuccessfulSyncTs; v != nil {
 		set, args = append(set, fmt.Sprintf("last_successful_sync_ts = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.SyncError; v != nil {
+		set, args = append(set, fmt.Sprintf("sync_error = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SyncDurationMs; v != nil {
+		set, args = append(set, fmt.Sprintf("sync_duration_ms = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SchemaFingerprint; v != nil {
+		set, args = append(set, fmt.Sprintf("schema_fingerprint = $%d", len(args)+1)), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -373,7 +394,10 @@ func (s *DatabaseService) patchDatabase(ctx context.Context, tx *sql.Tx, patch *
 			"collation",
 			sync_status,
 			last_successful_sync_ts,
-			schema_version
+			schema_version,
+			sync_error,
+			sync_duration_ms,
+			schema_fingerprint
 	`, len(args)),
 		args...,
 	)
@@ -400,6 +424,9 @@ func (s *DatabaseService) patchDatabase(ctx context.Context, tx *sql.Tx, patch *
 			&databaseRaw.SyncStatus,
 			&databaseRaw.LastSuccessfulSyncTs,
 			&databaseRaw.SchemaVersion,
+			&databaseRaw.SyncError,
+			&databaseRaw.SyncDurationMs,
+			&databaseRaw.SchemaFingerprint,
 		); err != nil {
 			return nil, FormatError(err)
 		}
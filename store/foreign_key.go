@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.ForeignKeyService = (*ForeignKeyService)(nil)
+)
+
+// ForeignKeyService represents a service for managing foreign key.
+type ForeignKeyService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewForeignKeyService returns a new instance of ForeignKeyService.
+func NewForeignKeyService(logger *zap.Logger, db *DB) *ForeignKeyService {
+	return &ForeignKeyService{l: logger, db: db}
+}
+
+// CreateForeignKey creates a new foreign key.
+func (s *ForeignKeyService) CreateForeignKey(ctx context.Context, create *api.ForeignKeyCreate) (*api.ForeignKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	foreignKey, err := s.createForeignKey(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return foreignKey, nil
+}
+
+// FindForeignKeyList retrieves a list of foreign keys based on find.
+func (s *ForeignKeyService) FindForeignKeyList(ctx context.Context, find *api.ForeignKeyFind) ([]*api.ForeignKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := s.findForeignKeyList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// FindForeignKey retrieves a single foreign key based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *ForeignKeyService) FindForeignKey(ctx context.Context, find *api.ForeignKeyFind) (*api.ForeignKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := s.findForeignKeyList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d foreign keys with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// createForeignKey creates a new foreign key.
+func (s *ForeignKeyService) createForeignKey(ctx context.Context, tx *sql.Tx, create *api.ForeignKeyCreate) (*api.ForeignKey, error) {
+	// Insert row into fk.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO fk (
+			creator_id,
+			updater_id,
+			database_id,
+			table_id,
+			name,
+			column_name,
+			position,
+			referenced_table_name,
+			referenced_column_name,
+			on_update,
+			on_delete
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, column_name, position, referenced_table_name, referenced_column_name, on_update, on_delete
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.TableID,
+		create.Name,
+		create.Column,
+		create.Position,
+		create.ReferencedTable,
+		create.ReferencedColumn,
+		create.OnUpdate,
+		create.OnDelete,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var foreignKey api.ForeignKey
+	if err := row.Scan(
+		&foreignKey.ID,
+		&foreignKey.CreatorID,
+		&foreignKey.CreatedTs,
+		&foreignKey.UpdaterID,
+		&foreignKey.UpdatedTs,
+		&foreignKey.DatabaseID,
+		&foreignKey.TableID,
+		&foreignKey.Name,
+		&foreignKey.Column,
+		&foreignKey.Position,
+		&foreignKey.ReferencedTable,
+		&foreignKey.ReferencedColumn,
+		&foreignKey.OnUpdate,
+		&foreignKey.OnDelete,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &foreignKey, nil
+}
+
+func (s *ForeignKeyService) findForeignKeyList(ctx context.Context, tx *sql.Tx, find *api.ForeignKeyFind) ([]*api.ForeignKey, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.TableID; v != nil {
+		where, args = append(where, fmt.Sprintf("table_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Column; v != nil {
+		where, args = append(where, fmt.Sprintf("column_name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			table_id,
+			name,
+			column_name,
+			position,
+			referenced_table_name,
+			referenced_column_name,
+			on_update,
+			on_delete
+		FROM fk
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, table_id, name ASC, position ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into foreignKeyList.
+	var foreignKeyList []*api.ForeignKey
+	for rows.Next() {
+		var foreignKey api.ForeignKey
+		if err := rows.Scan(
+			&foreignKey.ID,
+			&foreignKey.CreatorID,
+			&foreignKey.CreatedTs,
+			&foreignKey.UpdaterID,
+			&foreignKey.UpdatedTs,
+			&foreignKey.DatabaseID,
+			&foreignKey.TableID,
+			&foreignKey.Name,
+			&foreignKey.Column,
+			&foreignKey.Position,
+			&foreignKey.ReferencedTable,
+			&foreignKey.ReferencedColumn,
+			&foreignKey.OnUpdate,
+			&foreignKey.OnDelete,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		foreignKeyList = append(foreignKeyList, &foreignKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return foreignKeyList, nil
+}
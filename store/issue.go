@@ -196,6 +196,9 @@ func (s *IssueService) findIssueList(ctx context.Context, tx *sql.Tx, find *api.
 	if v := find.PipelineID; v != nil {
 		where, args = append(where, fmt.Sprintf("pipeline_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.Type; v != nil {
+		where, args = append(where, fmt.Sprintf("type = $%d", len(args)+1)), append(args, *v)
+	}
 	if v := find.ProjectID; v != nil {
 		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
 	}
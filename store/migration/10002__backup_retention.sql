@@ -0,0 +1,4 @@
+-- Backups removed by the retention policy of the backup plan are marked as EXPIRED.
+ALTER TABLE backup DROP CONSTRAINT backup_status_check;
+
+ALTER TABLE backup ADD CONSTRAINT backup_status_check CHECK (status IN ('PENDING_CREATE', 'DONE', 'FAILED', 'EXPIRED'));
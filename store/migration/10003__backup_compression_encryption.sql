@@ -0,0 +1,9 @@
+-- Backups can be compressed and encrypted, and we record the size and checksum of the stored backup for verification.
+ALTER TABLE backup ADD COLUMN compression TEXT NOT NULL DEFAULT 'NONE' CHECK (compression IN ('NONE', 'GZIP', 'ZSTD'));
+
+ALTER TABLE backup ADD COLUMN encryption TEXT NOT NULL DEFAULT 'NONE' CHECK (encryption IN ('NONE', 'AES_256_GCM'));
+
+ALTER TABLE backup ADD COLUMN size BIGINT NOT NULL DEFAULT 0;
+
+-- checksum is the hex encoded SHA-256 checksum of the stored backup, empty for backups taken before it's recorded.
+ALTER TABLE backup ADD COLUMN checksum TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- payload records the table row counts when taking the backup, which are compared with the restored ones by the backup verification.
+ALTER TABLE backup ADD COLUMN payload JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE backup ADD COLUMN verification_status TEXT NOT NULL DEFAULT 'UNVERIFIED' CHECK (verification_status IN ('UNVERIFIED', 'PASSED', 'FAILED'));
+
+ALTER TABLE backup ADD COLUMN verified_ts BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE backup ADD COLUMN verification_detail TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,29 @@
+-- binlog_file table stores the MySQL binlog files archived to the backup storage for the point-in-time recovery.
+CREATE TABLE binlog_file (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    -- name is the binlog file name on the MySQL server, e.g. binlog.000012.
+    name TEXT NOT NULL,
+    -- seq is the sequence number in the binlog file name.
+    seq BIGINT NOT NULL,
+    storage_backend TEXT NOT NULL CHECK (storage_backend IN ('LOCAL', 'S3')),
+    path TEXT NOT NULL,
+    compression TEXT NOT NULL CHECK (compression IN ('NONE', 'GZIP', 'ZSTD')),
+    encryption TEXT NOT NULL CHECK (encryption IN ('NONE', 'AES_256_GCM')),
+    size BIGINT NOT NULL,
+    checksum TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_binlog_file_unique_instance_id_name ON binlog_file(instance_id, name);
+
+ALTER SEQUENCE binlog_file_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_binlog_file_updated_ts
+BEFORE
+UPDATE
+    ON binlog_file FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
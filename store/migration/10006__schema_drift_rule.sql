@@ -0,0 +1,24 @@
+-- schema_drift_rule table stores the rules to ignore the expected schema differences in the schema drift detection.
+CREATE TABLE schema_drift_rule (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    -- database_id is NULL if the rule applies to all databases in the project.
+    database_id INTEGER NULL REFERENCES db (id),
+    type TEXT NOT NULL CHECK (type IN ('TABLE_GLOB', 'OBJECT_TYPE', 'NORMALIZER')),
+    pattern TEXT NOT NULL,
+    replacement TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_schema_drift_rule_project_id ON schema_drift_rule(project_id);
+
+ALTER SEQUENCE schema_drift_rule_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_drift_rule_updated_ts
+BEFORE
+UPDATE
+    ON schema_drift_rule FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
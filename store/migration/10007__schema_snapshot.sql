@@ -0,0 +1,26 @@
+-- schema_snapshot table stores the versioned schema snapshots of a database.
+-- A snapshot is only stored if the schema changes since the latest snapshot of the database.
+CREATE TABLE schema_snapshot (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- schema_version is the version of the latest migration history when the snapshot is taken.
+    schema_version TEXT NOT NULL,
+    -- checksum is the SHA-256 checksum of the metadata.
+    checksum TEXT NOT NULL,
+    -- metadata is the JSON encoded schema metadata. We use TEXT instead of JSONB to keep the checksum stable.
+    metadata TEXT NOT NULL
+);
+
+CREATE INDEX idx_schema_snapshot_database_id ON schema_snapshot(database_id);
+
+ALTER SEQUENCE schema_snapshot_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_schema_snapshot_updated_ts
+BEFORE
+UPDATE
+    ON schema_snapshot FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
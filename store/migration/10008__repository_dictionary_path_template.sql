@@ -0,0 +1,3 @@
+-- The file path template for storing the data dictionary auto-generated by Bytebase after migration.
+-- The file extension decides the format, i.e. ".md", ".html" or ".json". If empty, then Bytebase won't auto generate it.
+ALTER TABLE repository ADD COLUMN dictionary_path_template TEXT NOT NULL DEFAULT '';
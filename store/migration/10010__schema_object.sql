@@ -0,0 +1,111 @@
+-- chk stores the check constraint for a particular table from a particular database
+-- data is synced periodically from the instance
+CREATE TABLE chk (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    table_id INTEGER NOT NULL REFERENCES tbl (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    expression TEXT NOT NULL
+);
+
+CREATE INDEX idx_chk_database_id_table_id ON chk(database_id, table_id);
+
+ALTER SEQUENCE chk_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_chk_updated_ts
+BEFORE
+UPDATE
+    ON chk FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- trg stores the trigger for a particular table from a particular database
+-- data is synced periodically from the instance
+CREATE TABLE trg (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    table_id INTEGER NOT NULL REFERENCES tbl (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- timing is BEFORE, AFTER or INSTEAD OF.
+    timing TEXT NOT NULL,
+    -- event is one or more of INSERT, UPDATE, DELETE and TRUNCATE joined with " OR ".
+    event TEXT NOT NULL,
+    statement TEXT NOT NULL
+);
+
+CREATE INDEX idx_trg_database_id_table_id ON trg(database_id, table_id);
+
+ALTER SEQUENCE trg_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_trg_updated_ts
+BEFORE
+UPDATE
+    ON trg FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- routine stores the function and procedure for a particular database
+-- data is synced periodically from the instance
+-- Postgres functions can be overloaded, so the name isn't unique.
+CREATE TABLE routine (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- type is either FUNCTION or PROCEDURE.
+    type TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    comment TEXT NOT NULL
+);
+
+CREATE INDEX idx_routine_database_id ON routine(database_id);
+
+ALTER SEQUENCE routine_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_routine_updated_ts
+BEFORE
+UPDATE
+    ON routine FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- seq stores the sequence for a particular database
+-- data is synced periodically from the instance
+CREATE TABLE seq (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    data_type TEXT NOT NULL,
+    start_value TEXT NOT NULL,
+    increment TEXT NOT NULL,
+    min_value TEXT NOT NULL,
+    max_value TEXT NOT NULL,
+    cycle BOOLEAN NOT NULL,
+    cache_size TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_seq_unique_database_id_name ON seq(database_id, name);
+
+ALTER SEQUENCE seq_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_seq_updated_ts
+BEFORE
+UPDATE
+    ON seq FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,7 @@
+-- The schema is synced incrementally, the database sync is skipped if its DDL fingerprint is unchanged since the last successful sync.
+-- A failed database sync is marked as FAILED with the error, and is retried in the next round.
+ALTER TABLE db DROP CONSTRAINT db_sync_status_check;
+ALTER TABLE db ADD CONSTRAINT db_sync_status_check CHECK (sync_status IN ('OK', 'NOT_FOUND', 'FAILED'));
+ALTER TABLE db ADD COLUMN sync_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE db ADD COLUMN sync_duration_ms BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE db ADD COLUMN schema_fingerprint TEXT NOT NULL DEFAULT '';
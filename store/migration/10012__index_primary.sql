@@ -0,0 +1,4 @@
+-- The primary flag marks the indexes backing the primary key, which is used to detect tables without a primary key.
+ALTER TABLE idx ADD COLUMN "primary" BOOLEAN NOT NULL DEFAULT false;
+-- Reset the DDL fingerprint so that the next schema sync backfills the primary flag.
+UPDATE db SET schema_fingerprint = '';
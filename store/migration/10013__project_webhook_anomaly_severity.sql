@@ -0,0 +1,2 @@
+-- The anomaly severities posted to the webhook for the anomaly activities.
+ALTER TABLE project_webhook ADD COLUMN anomaly_severity_list TEXT ARRAY NOT NULL DEFAULT '{MEDIUM,HIGH,CRITICAL}';
@@ -0,0 +1,10 @@
+-- An anomaly is OPEN once detected, ACKNOWLEDGED by a user with a comment, and RESOLVED once archived.
+-- An unresolved anomaly can be snoozed until snooze_until_ts to suppress it from the notifications and the dashboards.
+ALTER TABLE anomaly ADD COLUMN status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'ACKNOWLEDGED', 'RESOLVED'));
+ALTER TABLE anomaly ADD COLUMN acknowledger_id INTEGER NULL REFERENCES principal (id);
+ALTER TABLE anomaly ADD COLUMN acknowledged_ts BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE anomaly ADD COLUMN comment TEXT NOT NULL DEFAULT '';
+ALTER TABLE anomaly ADD COLUMN snooze_until_ts BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE anomaly ADD COLUMN resolved_ts BIGINT NOT NULL DEFAULT 0;
+
+UPDATE anomaly SET status = 'RESOLVED', resolved_ts = updated_ts WHERE row_status = 'ARCHIVED';
@@ -0,0 +1,29 @@
+-- slow_query table stores the top statements of a database collected from the engine statement statistics,
+-- i.e. MySQL performance_schema.events_statements_summary_by_digest and Postgres pg_stat_statements.
+-- Each row is the statistics of a statement within the collection interval ending at created_ts.
+CREATE TABLE slow_query (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- schema_version is the schema version of the database when the statistics are collected.
+    schema_version TEXT NOT NULL,
+    -- digest identifies the normalized statement within the database.
+    digest TEXT NOT NULL,
+    statement TEXT NOT NULL,
+    calls BIGINT NOT NULL,
+    total_latency_us BIGINT NOT NULL,
+    rows_examined BIGINT NOT NULL
+);
+
+CREATE INDEX idx_slow_query_database_id_created_ts ON slow_query(database_id, created_ts);
+
+ALTER SEQUENCE slow_query_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_slow_query_updated_ts
+BEFORE
+UPDATE
+    ON slow_query FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
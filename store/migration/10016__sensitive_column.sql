@@ -0,0 +1,24 @@
+-- sensitive_column table stores the columns marked sensitive, whose values are masked in the SQL editor
+-- query and export results.
+CREATE TABLE sensitive_column (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- table_name is qualified by the schema for Postgres, e.g. public.user.
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    masking_type TEXT NOT NULL CHECK (masking_type IN ('FULL', 'PARTIAL', 'HASH'))
+);
+
+CREATE UNIQUE INDEX idx_sensitive_column_unique_database_id_table_name_column_name ON sensitive_column(database_id, table_name, column_name);
+
+ALTER SEQUENCE sensitive_column_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_sensitive_column_updated_ts
+BEFORE
+UPDATE
+    ON sensitive_column FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,25 @@
+-- query_history table stores the queries executed in the SQL editor, which are searched and re-run by the creator.
+CREATE TABLE query_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL,
+    -- statement is unbound, and parameter_list stores the values of its named :param placeholders, e.g. [{"name":"id","value":"1"}].
+    statement TEXT NOT NULL,
+    parameter_list JSONB NOT NULL DEFAULT '[]',
+    duration_ns BIGINT NOT NULL,
+    error TEXT NOT NULL
+);
+
+CREATE INDEX idx_query_history_creator_id_created_ts ON query_history(creator_id, created_ts);
+
+ALTER SEQUENCE query_history_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_query_history_updated_ts
+BEFORE
+UPDATE
+    ON query_history FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,5 @@
+-- encryption_key_fingerprint identifies the key encrypting the backup or the binlog file, so that it's restored with the same key
+-- after the key is rotated. It's empty if not encrypted, or encrypted before the fingerprint is recorded, by the current key then.
+ALTER TABLE backup ADD COLUMN encryption_key_fingerprint TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE binlog_file ADD COLUMN encryption_key_fingerprint TEXT NOT NULL DEFAULT '';
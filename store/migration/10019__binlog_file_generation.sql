@@ -0,0 +1,10 @@
+-- The binlog file names are reused after RESET MASTER or the MySQL server is rebuilt, so server_uuid and file_size
+-- tell apart the binlog files with the same name. They're empty for the binlog files archived before.
+ALTER TABLE binlog_file ADD COLUMN server_uuid TEXT NOT NULL DEFAULT '';
+
+-- file_size is the size of the binlog file on the MySQL server, while size is the one in the backup storage.
+ALTER TABLE binlog_file ADD COLUMN file_size BIGINT NOT NULL DEFAULT 0;
+
+DROP INDEX idx_binlog_file_unique_instance_id_name;
+
+CREATE UNIQUE INDEX idx_binlog_file_unique_instance_id_server_uuid_name_file_size ON binlog_file(instance_id, server_uuid, name, file_size);
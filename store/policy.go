@@ -231,3 +231,55 @@ func (s *PolicyService) GetPipelineApprovalPolicy(ctx context.Context, environme
 	}
 	return api.UnmarshalPipelineApprovalPolicy(policy.Payload)
 }
+
+// GetInstanceHealthPolicy will get the instance health policy for an environment.
+func (s *PolicyService) GetInstanceHealthPolicy(ctx context.Context, environmentID int) (*api.InstanceHealthPolicy, error) {
+	pType := api.PolicyTypeInstanceHealth
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalInstanceHealthPolicy(policy.Payload)
+}
+
+// GetSQLExportPolicy will get the SQL export policy for an environment.
+func (s *PolicyService) GetSQLExportPolicy(ctx context.Context, environmentID int) (*api.SQLExportPolicy, error) {
+	pType := api.PolicyTypeSQLExport
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalSQLExportPolicy(policy.Payload)
+}
+
+// GetDataMaskingPolicy will get the data masking policy for an environment.
+func (s *PolicyService) GetDataMaskingPolicy(ctx context.Context, environmentID int) (*api.DataMaskingPolicy, error) {
+	pType := api.PolicyTypeDataMasking
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalDataMaskingPolicy(policy.Payload)
+}
+
+// GetSQLQueryAccessPolicy will get the SQL query access policy for an environment.
+func (s *PolicyService) GetSQLQueryAccessPolicy(ctx context.Context, environmentID int) (*api.SQLQueryAccessPolicy, error) {
+	pType := api.PolicyTypeSQLQueryAccess
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalSQLQueryAccessPolicy(policy.Payload)
+}
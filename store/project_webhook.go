@@ -127,6 +127,11 @@ func (s *ProjectWebhookService) DeleteProjectWebhook(ctx context.Context, delete
 
 // createProjectWebhook creates a new projectWebhook.
 func createProjectWebhook(ctx context.Context, tx *sql.Tx, create *api.ProjectWebhookCreate) (*api.ProjectWebhookRaw, error) {
+	// Post the anomaly activities of all severities by default.
+	anomalySeverityList := create.AnomalySeverityList
+	if len(anomalySeverityList) == 0 {
+		anomalySeverityList = []string{string(api.AnomalySeverityMedium), string(api.AnomalySeverityHigh), string(api.AnomalySeverityCritical)}
+	}
 	// Insert row into database.
 	row, err := tx.QueryContext(ctx, `
 		INSERT INTO project_webhook (
@@ -136,10 +141,11 @@ func createProjectWebhook(ctx context.Context, tx *sql.Tx, create *api.ProjectWe
 			type,
 			name,
 			url,
-			activity_list
+			activity_list,
+			anomaly_severity_list
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list, anomaly_severity_list
 	`,
 		create.CreatorID,
 		create.CreatorID,
@@ -148,6 +154,7 @@ func createProjectWebhook(ctx context.Context, tx *sql.Tx, create *api.ProjectWe
 		create.Name,
 		create.URL,
 		pq.StringArray(create.ActivityList),
+		pq.StringArray(anomalySeverityList),
 	)
 
 	if err != nil {
@@ -168,6 +175,7 @@ func createProjectWebhook(ctx context.Context, tx *sql.Tx, create *api.ProjectWe
 		&projectWebhookRaw.Name,
 		&projectWebhookRaw.URL,
 		pq.Array(&projectWebhookRaw.ActivityList),
+		pq.Array(&projectWebhookRaw.AnomalySeverityList),
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -196,7 +204,8 @@ func findProjectWebhookList(ctx context.Context, tx *sql.Tx, find *api.ProjectWe
 			type,
 			name,
 			url,
-			activity_list
+			activity_list,
+			anomaly_severity_list
 		FROM project_webhook
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -221,20 +230,18 @@ func findProjectWebhookList(ctx context.Context, tx *sql.Tx, find *api.ProjectWe
 			&projectWebhookRaw.Name,
 			&projectWebhookRaw.URL,
 			pq.Array(&projectWebhookRaw.ActivityList),
+			pq.Array(&projectWebhookRaw.AnomalySeverityList),
 		); err != nil {
 			return nil, FormatError(err)
 		}
 
-		if v := find.ActivityType; v != nil {
-			for _, activity := range projectWebhookRaw.ActivityList {
-				if api.ActivityType(activity) == *v {
-					projectWebhookRawList = append(projectWebhookRawList, &projectWebhookRaw)
-					break
-				}
-			}
-		} else {
-			projectWebhookRawList = append(projectWebhookRawList, &projectWebhookRaw)
+		if v := find.ActivityType; v != nil && !containsString(projectWebhookRaw.ActivityList, string(*v)) {
+			continue
+		}
+		if v := find.AnomalySeverity; v != nil && !containsString(projectWebhookRaw.AnomalySeverityList, string(*v)) {
+			continue
 		}
+		projectWebhookRawList = append(projectWebhookRawList, &projectWebhookRaw)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, FormatError(err)
@@ -257,6 +264,10 @@ func patchProjectWebhook(ctx context.Context, tx *sql.Tx, patch *api.ProjectWebh
 		activities := pq.StringArray(strings.Split(*v, ","))
 		set, args = append(set, fmt.Sprintf("activity_list = $%d", len(args)+1)), append(args, activities)
 	}
+	if v := patch.AnomalySeverityList; v != nil {
+		severities := pq.StringArray(strings.Split(*v, ","))
+		set, args = append(set, fmt.Sprintf("anomaly_severity_list = $%d", len(args)+1)), append(args, severities)
+	}
 
 	args = append(args, patch.ID)
 
@@ -265,7 +276,7 @@ func patchProjectWebhook(ctx context.Context, tx *sql.Tx, patch *api.ProjectWebh
 		UPDATE project_webhook
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = $%d
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, type, name, url, activity_list, anomaly_severity_list
 	`, len(args)),
 		args...,
 	)
@@ -287,6 +298,7 @@ func patchProjectWebhook(ctx context.Context, tx *sql.Tx, patch *api.ProjectWebh
 			&projectWebhookRaw.Name,
 			&projectWebhookRaw.URL,
 			pq.Array(&projectWebhookRaw.ActivityList),
+			pq.Array(&projectWebhookRaw.AnomalySeverityList),
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -305,3 +317,12 @@ func deleteProjectWebhook(ctx context.Context, tx *sql.Tx, delete *api.ProjectWe
 	}
 	return nil
 }
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.QueryHistoryService = (*QueryHistoryService)(nil)
+)
+
+// QueryHistoryService represents a service for managing queryHistory.
+type QueryHistoryService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewQueryHistoryService returns a new instance of QueryHistoryService.
+func NewQueryHistoryService(logger *zap.Logger, db *DB) *QueryHistoryService {
+	return &QueryHistoryService{l: logger, db: db}
+}
+
+// CreateQueryHistory creates a new queryHistory.
+func (s *QueryHistoryService) CreateQueryHistory(ctx context.Context, create *api.QueryHistoryCreate) (*api.QueryHistoryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	queryHistory, err := createQueryHistory(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return queryHistory, nil
+}
+
+// FindQueryHistoryList retrieves a list of queryHistories based on find.
+func (s *QueryHistoryService) FindQueryHistoryList(ctx context.Context, find *api.QueryHistoryFind) ([]*api.QueryHistoryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findQueryHistoryList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// FindQueryHistory retrieves a single queryHistory based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *QueryHistoryService) FindQueryHistory(ctx context.Context, find *api.QueryHistoryFind) (*api.QueryHistoryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findQueryHistoryList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d query histories with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// createQueryHistory creates a new queryHistory.
+func createQueryHistory(ctx context.Context, tx *sql.Tx, create *api.QueryHistoryCreate) (*api.QueryHistoryRaw, error) {
+	parameterList := create.ParameterList
+	if parameterList == nil {
+		parameterList = []api.SQLParameter{}
+	}
+	parameterListBytes, err := json.Marshal(parameterList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal parameter list, error: %w", err)
+	}
+
+	// Insert row into database.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO query_history (
+			creator_id,
+			updater_id,
+			instance_id,
+			database_name,
+			statement,
+			parameter_list,
+			duration_ns,
+			error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_name, statement, parameter_list, duration_ns, error
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.InstanceID,
+		create.DatabaseName,
+		create.Statement,
+		string(parameterListBytes),
+		create.DurationNs,
+		create.Error,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	return scanQueryHistory(row)
+}
+
+func findQueryHistoryList(ctx context.Context, tx *sql.Tx, find *api.QueryHistoryFind) ([]*api.QueryHistoryRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.EnvironmentID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id IN (SELECT id FROM instance WHERE environment_id = $%d)", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseName; v != nil {
+		where, args = append(where, fmt.Sprintf("database_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Search; v != nil {
+		where, args = append(where, fmt.Sprintf("strpos(lower(statement), lower($%d)) > 0", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+
+	var query = `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			instance_id,
+			database_name,
+			statement,
+			parameter_list,
+			duration_ns,
+			error
+		FROM query_history
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY created_ts DESC, id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into queryHistoryRawList.
+	var queryHistoryRawList []*api.QueryHistoryRaw
+	for rows.Next() {
+		queryHistoryRaw, err := scanQueryHistory(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		queryHistoryRawList = append(queryHistoryRawList, queryHistoryRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return queryHistoryRawList, nil
+}
+
+func scanQueryHistory(rows *sql.Rows) (*api.QueryHistoryRaw, error) {
+	var queryHistoryRaw api.QueryHistoryRaw
+	var parameterList string
+	if err := rows.Scan(
+		&queryHistoryRaw.ID,
+		&queryHistoryRaw.CreatorID,
+		&queryHistoryRaw.CreatedTs,
+		&queryHistoryRaw.UpdaterID,
+		&queryHistoryRaw.UpdatedTs,
+		&queryHistoryRaw.InstanceID,
+		&queryHistoryRaw.DatabaseName,
+		&queryHistoryRaw.Statement,
+		&parameterList,
+		&queryHistoryRaw.DurationNs,
+		&queryHistoryRaw.Error,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	if err := json.Unmarshal([]byte(parameterList), &queryHistoryRaw.ParameterList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameter list of query history ID %v, error: %w", queryHistoryRaw.ID, err)
+	}
+	return &queryHistoryRaw, nil
+}
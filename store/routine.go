@@ -0,0 +1,203 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.RoutineService = (*RoutineService)(nil)
+)
+
+// RoutineService represents a service for managing routine.
+type RoutineService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewRoutineService returns a new instance of RoutineService.
+func NewRoutineService(logger *zap.Logger, db *DB) *RoutineService {
+	return &RoutineService{l: logger, db: db}
+}
+
+// CreateRoutine creates a new routine.
+func (s *RoutineService) CreateRoutine(ctx context.Context, create *api.RoutineCreate) (*api.Routine, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	routine, err := createRoutine(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return routine, nil
+}
+
+// FindRoutineList retrieves a list of routines based on find.
+func (s *RoutineService) FindRoutineList(ctx context.Context, find *api.RoutineFind) ([]*api.Routine, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findRoutineList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteRoutine deletes all routines of a database.
+func (s *RoutineService) DeleteRoutine(ctx context.Context, delete *api.RoutineDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteRoutine(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// createRoutine creates a new routine.
+func createRoutine(ctx context.Context, tx *sql.Tx, create *api.RoutineCreate) (*api.Routine, error) {
+	// Insert row into routine.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO routine (
+			creator_id,
+			updater_id,
+			database_id,
+			name,
+			type,
+			definition,
+			comment
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, type, definition, comment
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.Name,
+		create.Type,
+		create.Definition,
+		create.Comment,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var routine api.Routine
+	if err := row.Scan(
+		&routine.ID,
+		&routine.CreatorID,
+		&routine.CreatedTs,
+		&routine.UpdaterID,
+		&routine.UpdatedTs,
+		&routine.DatabaseID,
+		&routine.Name,
+		&routine.Type,
+		&routine.Definition,
+		&routine.Comment,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &routine, nil
+}
+
+func findRoutineList(ctx context.Context, tx *sql.Tx, find *api.RoutineFind) ([]*api.Routine, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			type,
+			definition,
+			comment
+		FROM routine
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into routineList.
+	var routineList []*api.Routine
+	for rows.Next() {
+		var routine api.Routine
+		if err := rows.Scan(
+			&routine.ID,
+			&routine.CreatorID,
+			&routine.CreatedTs,
+			&routine.UpdaterID,
+			&routine.UpdatedTs,
+			&routine.DatabaseID,
+			&routine.Name,
+			&routine.Type,
+			&routine.Definition,
+			&routine.Comment,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		routineList = append(routineList, &routine)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return routineList, nil
+}
+
+// deleteRoutine permanently deletes routines from a database.
+func deleteRoutine(ctx context.Context, tx *sql.Tx, delete *api.RoutineDelete) error {
+	// Remove row from database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM routine WHERE database_id = $1`, delete.DatabaseID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.SchemaDriftRuleService = (*SchemaDriftRuleService)(nil)
+)
+
+// SchemaDriftRuleService represents a service for managing schemaDriftRule.
+type SchemaDriftRuleService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewSchemaDriftRuleService returns a new instance of SchemaDriftRuleService.
+func NewSchemaDriftRuleService(logger *zap.Logger, db *DB) *SchemaDriftRuleService {
+	return &SchemaDriftRuleService{l: logger, db: db}
+}
+
+// CreateSchemaDriftRule creates a new schemaDriftRule.
+func (s *SchemaDriftRuleService) CreateSchemaDriftRule(ctx context.Context, create *api.SchemaDriftRuleCreate) (*api.SchemaDriftRuleRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	schemaDriftRule, err := createSchemaDriftRule(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return schemaDriftRule, nil
+}
+
+// FindSchemaDriftRuleList retrieves a list of schemaDriftRules based on find.
+func (s *SchemaDriftRuleService) FindSchemaDriftRuleList(ctx context.Context, find *api.SchemaDriftRuleFind) ([]*api.SchemaDriftRuleRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSchemaDriftRuleList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// FindSchemaDriftRule retrieves a single schemaDriftRule based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *SchemaDriftRuleService) FindSchemaDriftRule(ctx context.Context, find *api.SchemaDriftRuleFind) (*api.SchemaDriftRuleRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSchemaDriftRuleList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d schema drift rules with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// PatchSchemaDriftRule updates an existing schemaDriftRule by ID.
+// Returns ENOTFOUND if schemaDriftRule does not exist.
+func (s *SchemaDriftRuleService) PatchSchemaDriftRule(ctx context.Context, patch *api.SchemaDriftRulePatch) (*api.SchemaDriftRuleRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	schemaDriftRule, err := patchSchemaDriftRule(ctx, tx.PTx, patch)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return schemaDriftRule, nil
+}
+
+// DeleteSchemaDriftRule deletes an existing schemaDriftRule by ID.
+func (s *SchemaDriftRuleService) DeleteSchemaDriftRule(ctx context.Context, delete *api.SchemaDriftRuleDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteSchemaDriftRule(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// createSchemaDriftRule creates a new schemaDriftRule.
+func createSchemaDriftRule(ctx context.Context, tx *sql.Tx, create *api.SchemaDriftRuleCreate) (*api.SchemaDriftRuleRaw, error) {
+	// Insert row into database.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO schema_drift_rule (
+			creator_id,
+			updater_id,
+			project_id,
+			database_id,
+			type,
+			pattern,
+			replacement
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, database_id, type, pattern, replacement
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectID,
+		create.DatabaseID,
+		create.Type,
+		create.Pattern,
+		create.Replacement,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var schemaDriftRuleRaw api.SchemaDriftRuleRaw
+	var databaseID sql.NullInt32
+	if err := row.Scan(
+		&schemaDriftRuleRaw.ID,
+		&schemaDriftRuleRaw.CreatorID,
+		&schemaDriftRuleRaw.CreatedTs,
+		&schemaDriftRuleRaw.UpdaterID,
+		&schemaDriftRuleRaw.UpdatedTs,
+		&schemaDriftRuleRaw.ProjectID,
+		&databaseID,
+		&schemaDriftRuleRaw.Type,
+		&schemaDriftRuleRaw.Pattern,
+		&schemaDriftRuleRaw.Replacement,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+	if databaseID.Valid {
+		value := int(databaseID.Int32)
+		schemaDriftRuleRaw.DatabaseID = &value
+	}
+
+	return &schemaDriftRuleRaw, nil
+}
+
+func findSchemaDriftRuleList(ctx context.Context, tx *sql.Tx, find *api.SchemaDriftRuleFind) ([]*api.SchemaDriftRuleRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+		if v := find.DatabaseID; v != nil {
+			where, args = append(where, fmt.Sprintf("(database_id IS NULL OR database_id = $%d)", len(args)+1)), append(args, *v)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			database_id,
+			type,
+			pattern,
+			replacement
+		FROM schema_drift_rule
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into schemaDriftRuleRawList.
+	var schemaDriftRuleRawList []*api.SchemaDriftRuleRaw
+	for rows.Next() {
+		var schemaDriftRuleRaw api.SchemaDriftRuleRaw
+		var databaseID sql.NullInt32
+		if err := rows.Scan(
+			&schemaDriftRuleRaw.ID,
+			&schemaDriftRuleRaw.CreatorID,
+			&schemaDriftRuleRaw.CreatedTs,
+			&schemaDriftRuleRaw.UpdaterID,
+			&schemaDriftRuleRaw.UpdatedTs,
+			&schemaDriftRuleRaw.ProjectID,
+			&databaseID,
+			&schemaDriftRuleRaw.Type,
+			&schemaDriftRuleRaw.Pattern,
+			&schemaDriftRuleRaw.Replacement,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if databaseID.Valid {
+			value := int(databaseID.Int32)
+			schemaDriftRuleRaw.DatabaseID = &value
+		}
+
+		schemaDriftRuleRawList = append(schemaDriftRuleRawList, &schemaDriftRuleRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return schemaDriftRuleRawList, nil
+}
+
+// patchSchemaDriftRule updates a schemaDriftRule by ID. Returns the new state of the schemaDriftRule after update.
+func patchSchemaDriftRule(ctx context.Context, tx *sql.Tx, patch *api.SchemaDriftRulePatch) (*api.SchemaDriftRuleRaw, error) {
+	// Build UPDATE clause.
+	set, args := []string{"updater_id = $1"}, []interface{}{patch.UpdaterID}
+	if v := patch.Pattern; v != nil {
+		set, args = append(set, fmt.Sprintf("pattern = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Replacement; v != nil {
+		set, args = append(set, fmt.Sprintf("replacement = $%d", len(args)+1)), append(args, *v)
+	}
+
+	args = append(args, patch.ID)
+
+	// Execute update query with RETURNING.
+	row, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		UPDATE schema_drift_rule
+		SET `+strings.Join(set, ", ")+`
+		WHERE id = $%d
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, project_id, database_id, type, pattern, replacement
+	`, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	if row.Next() {
+		var schemaDriftRuleRaw api.SchemaDriftRuleRaw
+		var databaseID sql.NullInt32
+		if err := row.Scan(
+			&schemaDriftRuleRaw.ID,
+			&schemaDriftRuleRaw.CreatorID,
+			&schemaDriftRuleRaw.CreatedTs,
+			&schemaDriftRuleRaw.UpdaterID,
+			&schemaDriftRuleRaw.UpdatedTs,
+			&schemaDriftRuleRaw.ProjectID,
+			&databaseID,
+			&schemaDriftRuleRaw.Type,
+			&schemaDriftRuleRaw.Pattern,
+			&schemaDriftRuleRaw.Replacement,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		if databaseID.Valid {
+			value := int(databaseID.Int32)
+			schemaDriftRuleRaw.DatabaseID = &value
+		}
+
+		return &schemaDriftRuleRaw, nil
+	}
+
+	return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("schema drift rule ID not found: %d", patch.ID)}
+}
+
+// deleteSchemaDriftRule permanently deletes a schemaDriftRule by ID.
+func deleteSchemaDriftRule(ctx context.Context, tx *sql.Tx, delete *api.SchemaDriftRuleDelete) error {
+	// Remove row from database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_drift_rule WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
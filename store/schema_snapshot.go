@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.SchemaSnapshotService = (*SchemaSnapshotService)(nil)
+)
+
+// SchemaSnapshotService represents a service for managing schemaSnapshot.
+type SchemaSnapshotService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewSchemaSnapshotService returns a new instance of SchemaSnapshotService.
+func NewSchemaSnapshotService(logger *zap.Logger, db *DB) *SchemaSnapshotService {
+	return &SchemaSnapshotService{l: logger, db: db}
+}
+
+// CreateSchemaSnapshot creates a new schemaSnapshot.
+func (s *SchemaSnapshotService) CreateSchemaSnapshot(ctx context.Context, create *api.SchemaSnapshotCreate) (*api.SchemaSnapshotRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	schemaSnapshot, err := createSchemaSnapshot(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return schemaSnapshot, nil
+}
+
+// FindSchemaSnapshotList retrieves a list of schemaSnapshots based on find.
+func (s *SchemaSnapshotService) FindSchemaSnapshotList(ctx context.Context, find *api.SchemaSnapshotFind) ([]*api.SchemaSnapshotRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSchemaSnapshotList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// FindSchemaSnapshot retrieves a single schemaSnapshot based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *SchemaSnapshotService) FindSchemaSnapshot(ctx context.Context, find *api.SchemaSnapshotFind) (*api.SchemaSnapshotRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSchemaSnapshotList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d schema snapshots with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// createSchemaSnapshot creates a new schemaSnapshot.
+func createSchemaSnapshot(ctx context.Context, tx *sql.Tx, create *api.SchemaSnapshotCreate) (*api.SchemaSnapshotRaw, error) {
+	// Insert row into database.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO schema_snapshot (
+			creator_id,
+			updater_id,
+			database_id,
+			schema_version,
+			checksum,
+			metadata
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, schema_version, checksum, metadata
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.SchemaVersion,
+		create.Checksum,
+		create.Metadata,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var schemaSnapshotRaw api.SchemaSnapshotRaw
+	if err := row.Scan(
+		&schemaSnapshotRaw.ID,
+		&schemaSnapshotRaw.CreatorID,
+		&schemaSnapshotRaw.CreatedTs,
+		&schemaSnapshotRaw.UpdaterID,
+		&schemaSnapshotRaw.UpdatedTs,
+		&schemaSnapshotRaw.DatabaseID,
+		&schemaSnapshotRaw.SchemaVersion,
+		&schemaSnapshotRaw.Checksum,
+		&schemaSnapshotRaw.Metadata,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &schemaSnapshotRaw, nil
+}
+
+func findSchemaSnapshotList(ctx context.Context, tx *sql.Tx, find *api.SchemaSnapshotFind) ([]*api.SchemaSnapshotRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			schema_version,
+			checksum,
+			metadata
+		FROM schema_snapshot
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into schemaSnapshotRawList.
+	var schemaSnapshotRawList []*api.SchemaSnapshotRaw
+	for rows.Next() {
+		var schemaSnapshotRaw api.SchemaSnapshotRaw
+		if err := rows.Scan(
+			&schemaSnapshotRaw.ID,
+			&schemaSnapshotRaw.CreatorID,
+			&schemaSnapshotRaw.CreatedTs,
+			&schemaSnapshotRaw.UpdaterID,
+			&schemaSnapshotRaw.UpdatedTs,
+			&schemaSnapshotRaw.DatabaseID,
+			&schemaSnapshotRaw.SchemaVersion,
+			&schemaSnapshotRaw.Checksum,
+			&schemaSnapshotRaw.Metadata,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		schemaSnapshotRawList = append(schemaSnapshotRawList, &schemaSnapshotRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return schemaSnapshotRawList, nil
+}
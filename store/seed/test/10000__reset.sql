@@ -62,6 +62,24 @@ DELETE FROM
 DELETE FROM
     tbl;
 
+DELETE FROM
+    binlog_file;
+
+DELETE FROM
+    schema_drift_rule;
+
+DELETE FROM
+    schema_snapshot;
+
+DELETE FROM
+    slow_query;
+
+DELETE FROM
+    sensitive_column;
+
+DELETE FROM
+    query_history;
+
 DELETE FROM
     backup;
 
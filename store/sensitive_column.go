@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.SensitiveColumnService = (*SensitiveColumnService)(nil)
+)
+
+// SensitiveColumnService represents a service for managing sensitiveColumn.
+type SensitiveColumnService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewSensitiveColumnService returns a new instance of SensitiveColumnService.
+func NewSensitiveColumnService(logger *zap.Logger, db *DB) *SensitiveColumnService {
+	return &SensitiveColumnService{l: logger, db: db}
+}
+
+// UpsertSensitiveColumn creates a new sensitiveColumn, or updates the masking type of the existing one.
+func (s *SensitiveColumnService) UpsertSensitiveColumn(ctx context.Context, upsert *api.SensitiveColumnUpsert) (*api.SensitiveColumnRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	sensitiveColumn, err := upsertSensitiveColumn(ctx, tx.PTx, upsert)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return sensitiveColumn, nil
+}
+
+// FindSensitiveColumnList retrieves a list of sensitiveColumns based on find.
+func (s *SensitiveColumnService) FindSensitiveColumnList(ctx context.Context, find *api.SensitiveColumnFind) ([]*api.SensitiveColumnRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSensitiveColumnList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// FindSensitiveColumn retrieves a single sensitiveColumn based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *SensitiveColumnService) FindSensitiveColumn(ctx context.Context, find *api.SensitiveColumnFind) (*api.SensitiveColumnRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSensitiveColumnList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d sensitive columns with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// DeleteSensitiveColumn deletes an existing sensitiveColumn by ID.
+func (s *SensitiveColumnService) DeleteSensitiveColumn(ctx context.Context, delete *api.SensitiveColumnDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteSensitiveColumn(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// upsertSensitiveColumn upserts a new sensitiveColumn.
+func upsertSensitiveColumn(ctx context.Context, tx *sql.Tx, upsert *api.SensitiveColumnUpsert) (*api.SensitiveColumnRaw, error) {
+	// Upsert row into database.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO sensitive_column (
+			creator_id,
+			updater_id,
+			database_id,
+			table_name,
+			column_name,
+			masking_type
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (database_id, table_name, column_name) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			masking_type = excluded.masking_type
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_name, column_name, masking_type
+	`,
+		upsert.CreatorID,
+		upsert.CreatorID,
+		upsert.DatabaseID,
+		upsert.TableName,
+		upsert.ColumnName,
+		upsert.MaskingType,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var sensitiveColumnRaw api.SensitiveColumnRaw
+	if err := row.Scan(
+		&sensitiveColumnRaw.ID,
+		&sensitiveColumnRaw.CreatorID,
+		&sensitiveColumnRaw.CreatedTs,
+		&sensitiveColumnRaw.UpdaterID,
+		&sensitiveColumnRaw.UpdatedTs,
+		&sensitiveColumnRaw.DatabaseID,
+		&sensitiveColumnRaw.TableName,
+		&sensitiveColumnRaw.ColumnName,
+		&sensitiveColumnRaw.MaskingType,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &sensitiveColumnRaw, nil
+}
+
+func findSensitiveColumnList(ctx context.Context, tx *sql.Tx, find *api.SensitiveColumnFind) ([]*api.SensitiveColumnRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id IN (SELECT id FROM db WHERE instance_id = $%d)", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			table_name,
+			column_name,
+			masking_type
+		FROM sensitive_column
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id ASC, table_name ASC, column_name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into sensitiveColumnRawList.
+	var sensitiveColumnRawList []*api.SensitiveColumnRaw
+	for rows.Next() {
+		var sensitiveColumnRaw api.SensitiveColumnRaw
+		if err := rows.Scan(
+			&sensitiveColumnRaw.ID,
+			&sensitiveColumnRaw.CreatorID,
+			&sensitiveColumnRaw.CreatedTs,
+			&sensitiveColumnRaw.UpdaterID,
+			&sensitiveColumnRaw.UpdatedTs,
+			&sensitiveColumnRaw.DatabaseID,
+			&sensitiveColumnRaw.TableName,
+			&sensitiveColumnRaw.ColumnName,
+			&sensitiveColumnRaw.MaskingType,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		sensitiveColumnRawList = append(sensitiveColumnRawList, &sensitiveColumnRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return sensitiveColumnRawList, nil
+}
+
+// deleteSensitiveColumn permanently deletes a sensitiveColumn by ID.
+func deleteSensitiveColumn(ctx context.Context, tx *sql.Tx, delete *api.SensitiveColumnDelete) error {
+	// Remove row from database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM sensitive_column WHERE id = $1`, delete.ID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.SequenceService = (*SequenceService)(nil)
+)
+
+// SequenceService represents a service for managing sequence.
+type SequenceService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewSequenceService returns a new instance of SequenceService.
+func NewSequenceService(logger *zap.Logger, db *DB) *SequenceService {
+	return &SequenceService{l: logger, db: db}
+}
+
+// CreateSequence creates a new sequence.
+func (s *SequenceService) CreateSequence(ctx context.Context, create *api.SequenceCreate) (*api.Sequence, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	sequence, err := createSequence(ctx, tx.PTx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return sequence, nil
+}
+
+// FindSequenceList retrieves a list of sequences based on find.
+func (s *SequenceService) FindSequenceList(ctx context.Context, find *api.SequenceFind) ([]*api.Sequence, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSequenceList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteSequence deletes all sequences of a database.
+func (s *SequenceService) DeleteSequence(ctx context.Context, delete *api.SequenceDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	if err := deleteSequence(ctx, tx.PTx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// createSequence creates a new sequence.
+func createSequence(ctx context.Context, tx *sql.Tx, create *api.SequenceCreate) (*api.Sequence, error) {
+	// Insert row into seq.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO seq (
+			creator_id,
+			updater_id,
+			database_id,
+			name,
+			data_type,
+			start_value,
+			increment,
+			min_value,
+			max_value,
+			cycle,
+			cache_size
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, name, data_type, start_value, increment, min_value, max_value, cycle, cache_size
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.Name,
+		create.DataType,
+		create.StartValue,
+		create.Increment,
+		create.MinValue,
+		create.MaxValue,
+		create.Cycle,
+		create.CacheSize,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var sequence api.Sequence
+	if err := row.Scan(
+		&sequence.ID,
+		&sequence.CreatorID,
+		&sequence.CreatedTs,
+		&sequence.UpdaterID,
+		&sequence.UpdatedTs,
+		&sequence.DatabaseID,
+		&sequence.Name,
+		&sequence.DataType,
+		&sequence.StartValue,
+		&sequence.Increment,
+		&sequence.MinValue,
+		&sequence.MaxValue,
+		&sequence.Cycle,
+		&sequence.CacheSize,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &sequence, nil
+}
+
+func findSequenceList(ctx context.Context, tx *sql.Tx, find *api.SequenceFind) ([]*api.Sequence, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Name; v != nil {
+		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			name,
+			data_type,
+			start_value,
+			increment,
+			min_value,
+			max_value,
+			cycle,
+			cache_size
+		FROM seq
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY database_id, name ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into sequenceList.
+	var sequenceList []*api.Sequence
+	for rows.Next() {
+		var sequence api.Sequence
+		if err := rows.Scan(
+			&sequence.ID,
+			&sequence.CreatorID,
+			&sequence.CreatedTs,
+			&sequence.UpdaterID,
+			&sequence.UpdatedTs,
+			&sequence.DatabaseID,
+			&sequence.Name,
+			&sequence.DataType,
+			&sequence.StartValue,
+			&sequence.Increment,
+			&sequence.MinValue,
+			&sequence.MaxValue,
+			&sequence.Cycle,
+			&sequence.CacheSize,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		sequenceList = append(sequenceList, &sequence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return sequenceList, nil
+}
+
+// deleteSequence permanently deletes sequences from a database.
+func deleteSequence(ctx context.Context, tx *sql.Tx, delete *api.SequenceDelete) error {
+	// Remove row from database.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM seq WHERE database_id = $1`, delete.DatabaseID); err != nil {
+		return FormatError(err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.SlowQueryService = (*SlowQueryService)(nil)
+)
+
+// SlowQueryService represents a service for managing slowQuery.
+type SlowQueryService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewSlowQueryService returns a new instance of SlowQueryService.
+func NewSlowQueryService(logger *zap.Logger, db *DB) *SlowQueryService {
+	return &SlowQueryService{l: logger, db: db}
+}
+
+// CreateSlowQueryList creates new slowQueries in a single transaction.
+// The creation time is the same for all the slowQueries as it's the transaction start time.
+func (s *SlowQueryService) CreateSlowQueryList(ctx context.Context, createList []*api.SlowQueryCreate) ([]*api.SlowQueryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	var list []*api.SlowQueryRaw
+	for _, create := range createList {
+		slowQuery, err := createSlowQuery(ctx, tx.PTx, create)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, slowQuery)
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return list, nil
+}
+
+// FindSlowQueryList retrieves a list of slowQueries based on find.
+func (s *SlowQueryService) FindSlowQueryList(ctx context.Context, find *api.SlowQueryFind) ([]*api.SlowQueryRaw, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	list, err := findSlowQueryList(ctx, tx.PTx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteSlowQuery deletes the slowQueries collected before the given time.
+func (s *SlowQueryService) DeleteSlowQuery(ctx context.Context, delete *api.SlowQueryDelete) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	defer tx.PTx.Rollback()
+
+	count, err := deleteSlowQuery(ctx, tx.PTx, delete)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.PTx.Commit(); err != nil {
+		return 0, FormatError(err)
+	}
+
+	return count, nil
+}
+
+// createSlowQuery creates a new slowQuery.
+func createSlowQuery(ctx context.Context, tx *sql.Tx, create *api.SlowQueryCreate) (*api.SlowQueryRaw, error) {
+	// Insert row into database.
+	row, err := tx.QueryContext(ctx, `
+		INSERT INTO slow_query (
+			creator_id,
+			updater_id,
+			database_id,
+			schema_version,
+			digest,
+			statement,
+			calls,
+			total_latency_us,
+			rows_examined
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, schema_version, digest, statement, calls, total_latency_us, rows_examined
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.SchemaVersion,
+		create.Digest,
+		create.Statement,
+		create.Calls,
+		create.TotalLatencyUs,
+		create.RowsExamined,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var slowQueryRaw api.SlowQueryRaw
+	if err := row.Scan(
+		&slowQueryRaw.ID,
+		&slowQueryRaw.CreatorID,
+		&slowQueryRaw.CreatedTs,
+		&slowQueryRaw.UpdaterID,
+		&slowQueryRaw.UpdatedTs,
+		&slowQueryRaw.DatabaseID,
+		&slowQueryRaw.SchemaVersion,
+		&slowQueryRaw.Digest,
+		&slowQueryRaw.Statement,
+		&slowQueryRaw.Calls,
+		&slowQueryRaw.TotalLatencyUs,
+		&slowQueryRaw.RowsExamined,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &slowQueryRaw, nil
+}
+
+func findSlowQueryList(ctx context.Context, tx *sql.Tx, find *api.SlowQueryFind) ([]*api.SlowQueryRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Digest; v != nil {
+		where, args = append(where, fmt.Sprintf("digest = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsAfter; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts < $%d", len(args)+1)), append(args, *v)
+	}
+
+	query := `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			schema_version,
+			digest,
+			statement,
+			calls,
+			total_latency_us,
+			rows_examined
+		FROM slow_query
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id DESC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into slowQueryRawList.
+	var slowQueryRawList []*api.SlowQueryRaw
+	for rows.Next() {
+		var slowQueryRaw api.SlowQueryRaw
+		if err := rows.Scan(
+			&slowQueryRaw.ID,
+			&slowQueryRaw.CreatorID,
+			&slowQueryRaw.CreatedTs,
+			&slowQueryRaw.UpdaterID,
+			&slowQueryRaw.UpdatedTs,
+			&slowQueryRaw.DatabaseID,
+			&slowQueryRaw.SchemaVersion,
+			&slowQueryRaw.Digest,
+			&slowQueryRaw.Statement,
+			&slowQueryRaw.Calls,
+			&slowQueryRaw.TotalLatencyUs,
+			&slowQueryRaw.RowsExamined,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		slowQueryRawList = append(slowQueryRawList, &slowQueryRaw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return slowQueryRawList, nil
+}
+
+// deleteSlowQuery permanently deletes the slowQueries collected before the given time.
+func deleteSlowQuery(ctx context.Context, tx *sql.Tx, delete *api.SlowQueryDelete) (int64, error) {
+	result, err := tx.ExecContext(ctx, `DELETE FROM slow_query WHERE created_ts < $1`, delete.CreatedTsBefore)
+	if err != nil {
+		return 0, FormatError(err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, FormatError(err)
+	}
+	return count, nil
+}
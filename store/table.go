@@ -8,6 +8,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -181,14 +182,23 @@ func (s *TableService) findTableList(ctx context.Context, tx *sql.Tx, find *api.
 	if v := find.ID; v != nil {
 		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.IDList; v != nil {
+		where, args = append(where, fmt.Sprintf("id = ANY($%d)", len(args)+1)), append(args, pq.Array(*v))
+	}
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.DatabaseIDList; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = ANY($%d)", len(args)+1)), append(args, pq.Array(*v))
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.NamePattern; v != nil {
+		where, args = append(where, fmt.Sprintf("name ~* $%d", len(args)+1)), append(args, *v)
+	}
 
-	rows, err := tx.QueryContext(ctx, `
+	query := `
 		SELECT
 			id,
 			creator_id,
@@ -207,7 +217,13 @@ func (s *TableService) findTableList(ctx context.Context, tx *sql.Tx, find *api.
 			create_options,
 			comment
 		FROM tbl
-		WHERE `+strings.Join(where, " AND "),
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY database_id, name ASC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
 		args...,
 	)
 	if err != nil {
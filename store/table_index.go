@@ -8,6 +8,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -98,11 +99,12 @@ func (s *IndexService) createIndex(ctx context.Context, tx *sql.Tx, create *api.
 			position,
 			type,
 			"unique",
+			"primary",
 			visible,
 			comment
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, expression, position, type, "unique", visible, comment
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, expression, position, type, "unique", "primary", visible, comment
 	`,
 		create.CreatorID,
 		create.CreatorID,
@@ -113,6 +115,7 @@ func (s *IndexService) createIndex(ctx context.Context, tx *sql.Tx, create *api.
 		create.Position,
 		create.Type,
 		create.Unique,
+		create.Primary,
 		create.Visible,
 		create.Comment,
 	)
@@ -137,6 +140,7 @@ func (s *IndexService) createIndex(ctx context.Context, tx *sql.Tx, create *api.
 		&index.Position,
 		&index.Type,
 		&index.Unique,
+		&index.Primary,
 		&index.Visible,
 		&index.Comment,
 	); err != nil {
@@ -155,6 +159,9 @@ func (s *IndexService) findIndexList(ctx context.Context, tx *sql.Tx, find *api.
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.DatabaseIDList; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = ANY($%d)", len(args)+1)), append(args, pq.Array(*v))
+	}
 	if v := find.TableID; v != nil {
 		where, args = append(where, fmt.Sprintf("table_id = $%d", len(args)+1)), append(args, *v)
 	}
@@ -164,8 +171,11 @@ func (s *IndexService) findIndexList(ctx context.Context, tx *sql.Tx, find *api.
 	if v := find.Expression; v != nil {
 		where, args = append(where, fmt.Sprintf("expression = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.NamePattern; v != nil {
+		where, args = append(where, fmt.Sprintf("name ~* $%d", len(args)+1)), append(args, *v)
+	}
 
-	rows, err := tx.QueryContext(ctx, `
+	query := `
 		SELECT
 			id,
 			creator_id,
@@ -179,11 +189,17 @@ func (s *IndexService) findIndexList(ctx context.Context, tx *sql.Tx, find *api.
 			position,
 			type,
 			"unique",
+			"primary",
 			visible,
 			comment
 		FROM idx
-		WHERE `+strings.Join(where, " AND ")+`
-		ORDER BY database_id, table_id, CASE name WHEN 'PRIMARY' THEN 1 ELSE 2 END, name ASC, position ASC`,
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY database_id, table_id, CASE name WHEN 'PRIMARY' THEN 1 ELSE 2 END, name ASC, position ASC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
 		args...,
 	)
 	if err != nil {
@@ -208,6 +224,7 @@ func (s *IndexService) findIndexList(ctx context.Context, tx *sql.Tx, find *api.
 			&index.Position,
 			&index.Type,
 			&index.Unique,
+			&index.Primary,
 			&index.Visible,
 			&index.Comment,
 		); err != nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -163,11 +164,17 @@ func (s *ViewService) findViewList(ctx context.Context, tx *sql.Tx, find *api.Vi
 	if v := find.DatabaseID; v != nil {
 		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.DatabaseIDList; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = ANY($%d)", len(args)+1)), append(args, pq.Array(*v))
+	}
 	if v := find.Name; v != nil {
 		where, args = append(where, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := find.NamePattern; v != nil {
+		where, args = append(where, fmt.Sprintf("name ~* $%d", len(args)+1)), append(args, *v)
+	}
 
-	rows, err := tx.QueryContext(ctx, `
+	query := `
 		SELECT
 			id,
 			creator_id,
@@ -179,8 +186,13 @@ func (s *ViewService) findViewList(ctx context.Context, tx *sql.Tx, find *api.Vi
 			definition,
 			comment
 		FROM vw
-		WHERE `+strings.Join(where, " AND ")+`
-		ORDER BY database_id, name ASC`,
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY database_id, name ASC`
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, query,
 		args...,
 	)
 	if err != nil {
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/resources/mysql"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+func TestPointInTimeRecovery(t *testing.T) {
+	t.Parallel()
+	const (
+		mysqlPort    = 13307
+		databaseName = "testPITR"
+	)
+	recoveredDatabase := "testPITRRecovered"
+	_, stopInstance := mysql.SetupTestInstance(t, mysqlPort)
+	defer stopInstance()
+
+	err := func() error {
+		ctx := context.Background()
+		ctl := &controller{}
+		dataDir := t.TempDir()
+		if err := ctl.StartMain(ctx, dataDir, getTestPort(t.Name())); err != nil {
+			return err
+		}
+		defer ctl.Close()
+		if err := ctl.Login(); err != nil {
+			return err
+		}
+		if err := ctl.setLicense(); err != nil {
+			return err
+		}
+
+		project, err := ctl.createProject(api.ProjectCreate{
+			Name: "Test Project",
+			Key:  "TestPITR",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create project, error: %v", err)
+		}
+		environments, err := ctl.getEnvironments()
+		if err != nil {
+			return err
+		}
+		prodEnvironment, err := findEnvironment(environments, "Prod")
+		if err != nil {
+			return err
+		}
+
+		// Enable the binlog archiving so that the backups record the binlog coordinate.
+		policyPayload, err := json.Marshal(api.BackupPlanPolicy{
+			Schedule:      api.BackupPlanPolicyScheduleUnset,
+			ArchiveBinlog: true,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := ctl.upsertPolicy(api.PolicyUpsert{
+			EnvironmentID: prodEnvironment.ID,
+			Type:          api.PolicyTypeBackupPlan,
+			Payload:       string(policyPayload),
+		}); err != nil {
+			return fmt.Errorf("failed to upsert backup plan policy, error: %v", err)
+		}
+
+		instance, err := ctl.addInstance(api.InstanceCreate{
+			EnvironmentID: prodEnvironment.ID,
+			Name:          "mysqlInstance",
+			Engine:        db.MySQL,
+			Host:          "127.0.0.1",
+			Port:          fmt.Sprintf("%d", mysqlPort),
+			Username:      "root",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add instance, error: %v", err)
+		}
+
+		if err := ctl.createDatabaseWithContext(project, api.CreateDatabaseContext{
+			InstanceID:   instance.ID,
+			DatabaseName: databaseName,
+			CharacterSet: "utf8mb4",
+			Collation:    "utf8mb4_general_ci",
+		}); err != nil {
+			return err
+		}
+		databases, err := ctl.getDatabases(api.DatabaseFind{ProjectID: &project.ID})
+		if err != nil {
+			return fmt.Errorf("failed to get databases, error: %v", err)
+		}
+		if len(databases) != 1 {
+			return fmt.Errorf("invalid number of databases %v in project %v, expecting one database", len(databases), project.ID)
+		}
+		database := databases[0]
+
+		mysqlDB, err := sql.Open("mysql", fmt.Sprintf("root@tcp(127.0.0.1:%d)/", mysqlPort))
+		if err != nil {
+			return err
+		}
+		defer mysqlDB.Close()
+		if _, err := mysqlDB.Exec(fmt.Sprintf("CREATE TABLE `%s`.t (id INT PRIMARY KEY)", databaseName)); err != nil {
+			return err
+		}
+		insertRows := func(begin, end int) error {
+			for i := begin; i < end; i++ {
+				if _, err := mysqlDB.Exec(fmt.Sprintf("INSERT INTO `%s`.t VALUES (%d)", databaseName, i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err := insertRows(0, 10); err != nil {
+			return err
+		}
+
+		backup, err := ctl.createBackup(api.BackupCreate{
+			DatabaseID:     database.ID,
+			Name:           "pitr-base",
+			Type:           api.BackupTypeManual,
+			StorageBackend: api.BackupStorageBackendLocal,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create backup, error %v", err)
+		}
+		if err := ctl.waitBackup(backup.DatabaseID, backup.ID); err != nil {
+			return fmt.Errorf("failed to wait for backup, error %v", err)
+		}
+
+		// The rows inserted after the backup and before the point in time are recovered by replaying the binlog.
+		if err := insertRows(10, 20); err != nil {
+			return err
+		}
+		time.Sleep(time.Second)
+		pointInTimeTs := time.Now().Unix()
+		time.Sleep(time.Second)
+		if err := insertRows(20, 30); err != nil {
+			return err
+		}
+		// Rotate the binlog so that the replay reads both an archived or closed file and the file being written.
+		if _, err := mysqlDB.Exec("FLUSH BINARY LOGS"); err != nil {
+			return err
+		}
+		if err := insertRows(30, 40); err != nil {
+			return err
+		}
+
+		if err := ctl.createDatabaseWithContext(project, api.CreateDatabaseContext{
+			InstanceID:       instance.ID,
+			DatabaseName:     recoveredDatabase,
+			CharacterSet:     "utf8mb4",
+			Collation:        "utf8mb4_general_ci",
+			SourceDatabaseID: database.ID,
+			PointInTimeTs:    pointInTimeTs,
+		}); err != nil {
+			return err
+		}
+
+		var count int
+		if err := mysqlDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM `%s`.t", recoveredDatabase)).Scan(&count); err != nil {
+			return err
+		}
+		if count != 20 {
+			return fmt.Errorf("recovered database has %d rows, want 20", count)
+		}
+
+		histories, err := ctl.getInstanceMigrationHistory(db.MigrationHistoryFind{ID: &instance.ID, Database: &recoveredDatabase})
+		if err != nil {
+			return err
+		}
+		if len(histories) == 0 || histories[0].Type != db.Branch {
+			return fmt.Errorf("expect the latest migration history of the recovered database to be %s, got %+v", db.Branch, histories)
+		}
+		return nil
+	}()
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -118,6 +118,8 @@ func getTestPort(testName string) int {
 		return 1246
 	case "TestTenantDatabaseNameTemplate":
 		return 1249
+	case "TestPointInTimeRecovery":
+		return 1252
 	}
 	panic(fmt.Sprintf("test %q doesn't have assigned port, please set it in getTestPort()", testName))
 }
@@ -787,11 +789,17 @@ func (ctl *controller) createDatabase(project *api.Project, instance *api.Instan
 		return err
 	}
 
-	createContext, err := json.Marshal(&api.CreateDatabaseContext{
+	return ctl.createDatabaseWithContext(project, api.CreateDatabaseContext{
 		InstanceID:   instance.ID,
 		DatabaseName: databaseName,
 		Labels:       labels,
 	})
+}
+
+// createDatabaseWithContext creates a database with the database creation context.
+func (ctl *controller) createDatabaseWithContext(project *api.Project, createDatabaseContext api.CreateDatabaseContext) error {
+	databaseName := createDatabaseContext.DatabaseName
+	createContext, err := json.Marshal(&createDatabaseContext)
 	if err != nil {
 		return fmt.Errorf("failed to construct database creation issue CreateContext payload, error: %w", err)
 	}
@@ -1060,6 +1068,25 @@ func (ctl *controller) waitBackup(databaseID, backupID int) error {
 	return fmt.Errorf("failed to wait for backup as this condition should never be reached")
 }
 
+// upsertPolicy upserts the policy of an environment.
+func (ctl *controller) upsertPolicy(policyUpsert api.PolicyUpsert) (*api.Policy, error) {
+	buf := new(bytes.Buffer)
+	if err := jsonapi.MarshalPayload(buf, &policyUpsert); err != nil {
+		return nil, fmt.Errorf("failed to marshal policyUpsert, error: %w", err)
+	}
+
+	body, err := ctl.patch(fmt.Sprintf("/policy/environment/%d?type=%s", policyUpsert.EnvironmentID, policyUpsert.Type), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := new(api.Policy)
+	if err = jsonapi.UnmarshalPayload(body, policy); err != nil {
+		return nil, fmt.Errorf("fail to unmarshal policy response, error: %w", err)
+	}
+	return policy, nil
+}
+
 // createSheet creates a sheet.
 func (ctl *controller) createSheet(sheetCreate api.SheetCreate) (*api.Sheet, error) {
 	buf := new(bytes.Buffer)